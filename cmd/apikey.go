@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/config"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// stringList — флаг командной строки, который можно указать несколько раз.
+type stringList []string
+
+// String возвращает значения флага через запятую.
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set добавляет очередное значение флага.
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// runAPIKey выполняет административные команды управления API-ключами.
+//
+// Подкоманды:
+//   - create -name NAME -scopes read,transfer,admin [-wallet ADDRESS ...] [-all-wallets] — создать ключ;
+//   - list — вывести список ключей;
+//   - revoke -id ID — отозвать ключ.
+//
+// Открытое значение ключа выводится только при создании, в базе хранится лишь его хеш.
+func runAPIKey(cfg *config.Config, args []string) {
+	if len(args) == 0 {
+		log.Fatalf("Usage: apikey create|list|revoke [flags]")
+	}
+
+	// Подключаемся к базе данных и применяем миграции
+	db := openDatabase(cfg.Database)
+	defer closeDatabase(db)
+	authService := services.NewService(db, nil, nil).AuthService
+
+	switch args[0] {
+	case "create":
+		var wallets stringList
+		flags := flag.NewFlagSet("apikey create", flag.ExitOnError)
+		name := flags.String("name", "", "название ключа")
+		scopes := flags.String("scopes", "read", "области доступа через запятую: read, transfer, admin")
+		allWallets := flags.Bool("all-wallets", false, "разрешить списание с любого кошелька")
+		flags.Var(&wallets, "wallet", "кошелёк, с которого разрешено списание (можно указать несколько раз)")
+		_ = flags.Parse(args[1:])
+
+		resp, err := authService.CreateAPIKey(dto.APIKeyReq{
+			Name:       *name,
+			Scopes:     strings.Split(*scopes, ","),
+			Wallets:    wallets,
+			AllWallets: *allWallets,
+		})
+		if err != nil {
+			log.Fatalf("Failed to create api key: %v", err)
+		}
+
+		fmt.Printf("Created api key %d (%s)\n", resp.ID, resp.Name)
+		fmt.Printf("Key: %s\n", resp.Key)
+		fmt.Println("Store the key securely: it cannot be shown again.")
+
+	case "list":
+		keys, err := authService.ListAPIKeys()
+		if err != nil {
+			log.Fatalf("Failed to list api keys: %v", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tSCOPES\tWALLETS\tCREATED\tREVOKED")
+		for _, key := range keys {
+			wallets := strings.Join(key.Wallets, ",")
+			if key.AllWallets {
+				wallets = "*"
+			}
+			revoked := "-"
+			if key.RevokedAt != nil {
+				revoked = key.RevokedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", key.ID, key.Name, strings.Join(key.Scopes, ","),
+				wallets, key.CreatedAt.Format("2006-01-02 15:04:05"), revoked)
+		}
+		_ = w.Flush()
+
+	case "revoke":
+		flags := flag.NewFlagSet("apikey revoke", flag.ExitOnError)
+		id := flags.Int64("id", 0, "идентификатор ключа")
+		_ = flags.Parse(args[1:])
+
+		if err := authService.RevokeAPIKey(*id); err != nil {
+			log.Fatalf("Failed to revoke api key: %v", err)
+		}
+		fmt.Printf("Revoked api key %d\n", *id)
+
+	default:
+		log.Fatalf("Unknown apikey command %q, expected one of: create, list, revoke", args[0])
+	}
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/auth"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/config"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultBackupDir — каталог резервных копий базы данных по умолчанию.
+	defaultBackupDir = "./backups"
+	// backupPrefix и backupSuffix образуют имя файла резервной копии: database-<время UTC>.db.
+	backupPrefix = "database-"
+	backupSuffix = ".db"
+	// backupTimeLayout — формат времени создания в имени файла резервной копии.
+	backupTimeLayout = "20060102T150405Z"
+)
+
+// restoreReport — результат восстановления базы данных из резервной копии и её проверки.
+type restoreReport struct {
+	Backup    string               `json:"backup"`             // Файл восстановленной резервной копии
+	Previous  string               `json:"previous,omitempty"` // Копия базы данных, заменённой при восстановлении
+	Integrity []string             `json:"integrity"`          // Нарушения PRAGMA integrity_check
+	Ledger    dto.LedgerVerifyResp `json:"ledger"`             // Проверка цепочки хешей журнала транзакций
+	Reconcile dto.ReconcileReport  `json:"reconcile"`          // Сверка балансов с журналом транзакций
+	Verified  bool                 `json:"verified"`           // Все проверки пройдены
+}
+
+// runBackup создаёт резервную копию базы данных через online backup API SQLite.
+// Копию можно создавать, не останавливая сервер: она согласована на момент создания.
+//
+// Флаги:
+//   - -dir <каталог> — каталог резервных копий (по умолчанию ./backups); файл получает имя
+//     database-<время UTC>.db;
+//   - -keep <N> — оставить в каталоге только N последних копий (0 — не удалять);
+//   - -out <файл> — записать копию в указанный файл вместо каталога.
+func runBackup(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("backup", flag.ExitOnError)
+	dir := flags.String("dir", defaultBackupDir, "каталог резервных копий")
+	keep := flags.Int("keep", 0, "количество хранимых копий; 0 — не удалять")
+	out := flags.String("out", "", "файл резервной копии")
+	_ = flags.Parse(args)
+
+	db := openDatabase(cfg.Database)
+	defer closeDatabase(db)
+
+	if *out != "" {
+		if err := storage.Backup(context.Background(), db, *out); err != nil {
+			log.Fatalf("Failed to back up database: %v", err)
+		}
+		fmt.Printf("Database backed up to %s\n", *out)
+		return
+	}
+
+	path, err := createBackup(context.Background(), db, *dir, *keep)
+	if err != nil {
+		log.Fatalf("Failed to back up database: %v", err)
+	}
+	fmt.Printf("Database backed up to %s\n", path)
+}
+
+// runRestore восстанавливает базу данных из резервной копии и проверяет результат.
+// Перед восстановлением сервер должен быть остановлен.
+//
+// Порядок восстановления:
+//  1. проверка целостности резервной копии (PRAGMA integrity_check);
+//  2. копирование текущей базы данных в файл database.db.pre-restore-<время UTC>;
+//  3. замена содержимого базы данных содержимым резервной копии через online backup API;
+//  4. применение миграций, если копия создана более старой версией;
+//  5. проверка целостности восстановленной базы, цепочки хешей журнала транзакций
+//     и сверка балансов кошельков с журналом без исправления расхождений.
+//
+// Флаги:
+//   - -from <файл> — файл резервной копии (обязательный).
+//
+// Результат проверок выводится в JSON; команда завершается с кодом 1, если какая-либо проверка не пройдена.
+func runRestore(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	from := flags.String("from", "", "файл резервной копии")
+	_ = flags.Parse(args)
+
+	if *from == "" {
+		log.Fatal("-from is required")
+	}
+
+	report, err := restoreDatabase(context.Background(), cfg, *from)
+	if err != nil {
+		log.Fatalf("Failed to restore database: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatalf("Failed to encode report: %v", err)
+	}
+	if !report.Verified {
+		os.Exit(1)
+	}
+}
+
+// restoreDatabase восстанавливает базу данных из резервной копии и проверяет её (см. runRestore).
+func restoreDatabase(ctx context.Context, cfg *config.Config, from string) (restoreReport, error) {
+	report := restoreReport{Backup: from}
+
+	backup, err := storage.ConnectReadOnly(from)
+	if err != nil {
+		return report, err
+	}
+	defer closeDatabase(backup)
+
+	// Повреждённую копию не восстанавливаем
+	problems, err := storage.IntegrityCheck(backup)
+	if err != nil {
+		return report, err
+	}
+	if len(problems) > 0 {
+		return report, fmt.Errorf("backup %s is corrupted: %s", from, strings.Join(problems, "; "))
+	}
+
+	db, err := storage.Connect(cfg.Database.Path, cfg.Database.BusyTimeout)
+	if err != nil {
+		return report, err
+	}
+	defer closeDatabase(db)
+
+	// Сохраняем заменяемую базу данных, чтобы восстановление можно было отменить
+	if info, err := os.Stat(cfg.Database.Path); err == nil && info.Size() > 0 {
+		report.Previous = cfg.Database.Path + ".pre-restore-" + time.Now().UTC().Format(backupTimeLayout)
+		if err := storage.Backup(ctx, db, report.Previous); err != nil {
+			return report, fmt.Errorf("failed to save current database: %w", err)
+		}
+	}
+
+	if err := storage.CopyDatabase(ctx, db, backup); err != nil {
+		return report, err
+	}
+	if err := storage.ApplyMigrations(db, cfg.Database.Migrations); err != nil {
+		return report, err
+	}
+
+	return verifyDatabase(ctx, db, cfg.Ledger.Key, report)
+}
+
+// verifyDatabase проверяет целостность файла базы данных, цепочку хешей журнала транзакций
+// и согласованность балансов кошельков с журналом и дополняет отчёт результатами.
+// Подписи контрольных точек проверяются ключом из файла ledgerKeyPath, если он существует.
+//
+// Проверки только читают базу данных. Сверка не считается успешной и при наличии кошельков
+// без начального баланса, которые сверить с журналом нельзя.
+func verifyDatabase(ctx context.Context, db *sql.DB, ledgerKeyPath string, report restoreReport) (restoreReport, error) {
+	var err error
+	if report.Integrity, err = storage.IntegrityCheck(db); err != nil {
+		return report, err
+	}
+
+	// Ключ не обязателен: без него подписи сверяются с ключами из контрольных точек
+	ledgerKey, err := loadLedgerKey(ledgerKeyPath, false)
+	if err != nil {
+		return report, err
+	}
+	service := services.NewService(db, nil, ledgerKey)
+
+	if report.Ledger, err = service.LedgerService.Verify(ctx); err != nil {
+		return report, err
+	}
+
+	adminCtx := auth.WithPrincipal(ctx, cliPrincipal())
+	if report.Reconcile, err = service.ReconcileService.Reconcile(adminCtx, dto.ReconcileReq{}); err != nil {
+		return report, err
+	}
+
+	report.Verified = len(report.Integrity) == 0 && report.Ledger.Valid && report.Reconcile.Consistent
+	return report, nil
+}
+
+// runBackups периодически создаёт резервные копии базы данных до отмены контекста.
+//
+// Аргументы:
+//   - ctx: контекст, отмена которого останавливает создание копий.
+//   - db: подключение к базе данных.
+//   - dir: каталог резервных копий.
+//   - interval: интервал между копиями.
+//   - keep: количество хранимых копий (0 — не удалять).
+func runBackups(ctx context.Context, db *sql.DB, dir string, interval time.Duration, keep int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			path, err := createBackup(ctx, db, dir, keep)
+			if err != nil {
+				slog.Error("failed to back up database", "error", err)
+				continue
+			}
+			slog.Info("database backed up", "path", path)
+		}
+	}
+}
+
+// createBackup создаёт резервную копию базы данных в каталоге и удаляет устаревшие копии.
+//
+// Аргументы:
+//   - ctx: контекст, отмена которого прерывает создание копии.
+//   - db: подключение к базе данных.
+//   - dir: каталог резервных копий; создаётся, если не существует.
+//   - keep: количество хранимых копий (0 — не удалять).
+//
+// Возвращает:
+//   - путь к созданной копии и ошибку создания; ошибки удаления устаревших копий только записываются в журнал.
+func createBackup(ctx context.Context, db *sql.DB, dir string, keep int) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, backupPrefix+time.Now().UTC().Format(backupTimeLayout)+backupSuffix)
+	if err := storage.Backup(ctx, db, path); err != nil {
+		return "", err
+	}
+
+	if keep > 0 {
+		if err := pruneBackups(dir, keep); err != nil {
+			slog.Warn("failed to remove old backups", "error", err)
+		}
+	}
+	return path, nil
+}
+
+// pruneBackups удаляет из каталога все резервные копии, кроме keep последних.
+// Копии упорядочиваются по времени создания из имени файла; другие файлы не затрагиваются.
+func pruneBackups(dir string, keep int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	backups := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && strings.HasPrefix(name, backupPrefix) && strings.HasSuffix(name, backupSuffix) {
+			if _, err := time.Parse(backupTimeLayout, strings.TrimSuffix(strings.TrimPrefix(name, backupPrefix), backupSuffix)); err == nil {
+				backups = append(backups, name)
+			}
+		}
+	}
+	if len(backups) <= keep {
+		return nil
+	}
+
+	// Время в имени имеет фиксированную ширину, поэтому лексикографический порядок совпадает с хронологическим
+	slices.Sort(backups)
+
+	var errs []error
+	for _, name := range backups[:len(backups)-keep] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/config"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/utils"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/shopspring/decimal"
+)
+
+// legacyVersion — последняя версия схемы до учёта начальных балансов кошельков (миграция 000009).
+const legacyVersion = 8
+
+// legacyBackup создаёт резервную копию базы данных со схемой версии legacyVersion: два кошелька,
+// созданных при первом запуске с балансом 100, и перевод amount между ними. Сохранённый баланс
+// получателя дополнительно увеличивается на drift.
+func legacyBackup(t *testing.T, amount, drift decimal.Decimal) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "backup.db")
+	db, err := storage.Connect(path, time.Second)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer db.Close()
+
+	driver, err := sqlite.WithInstance(db, &sqlite.Config{})
+	if err != nil {
+		t.Fatalf("failed to create migration driver: %v", err)
+	}
+	m, err := migrate.NewWithDatabaseInstance("file://../migrations", "sqlite", driver)
+	if err != nil {
+		t.Fatalf("failed to open migrations: %v", err)
+	}
+	if err := m.Migrate(legacyVersion); err != nil {
+		t.Fatalf("failed to migrate to version %d: %v", legacyVersion, err)
+	}
+
+	from, _, err := utils.GenerateWalletKey()
+	if err != nil {
+		t.Fatalf("failed to generate wallet key: %v", err)
+	}
+	to, _, err := utils.GenerateWalletKey()
+	if err != nil {
+		t.Fatalf("failed to generate wallet key: %v", err)
+	}
+	initial := decimal.NewFromInt(100)
+	insertLegacyWallet(t, db, from, initial.Sub(amount))
+	insertLegacyWallet(t, db, to, initial.Add(amount).Add(drift))
+
+	record := dto.TransactionRecord{
+		Kind:      dto.TransactionKindTransfer,
+		From:      from,
+		To:        to,
+		Amount:    amount,
+		Nonce:     1,
+		Signature: "ab",
+		CreatedAt: time.Now().UTC(),
+	}
+	record.Hash = utils.HashTransaction(record)
+	if err := storage.NewTransactionRepository(db).Insert(record); err != nil {
+		t.Fatalf("failed to insert transfer: %v", err)
+	}
+	return path
+}
+
+// insertLegacyWallet добавляет кошелёк так, как его создавал сервер до миграции 000009.
+func insertLegacyWallet(t *testing.T, db *sql.DB, address string, balance decimal.Decimal) {
+	t.Helper()
+	if _, err := db.Exec("INSERT INTO wallets (address, balance) VALUES (?, ?)", address, balance.String()); err != nil {
+		t.Fatalf("failed to insert wallet: %v", err)
+	}
+}
+
+// restoreConfig возвращает конфигурацию с базой данных во временном каталоге.
+func restoreConfig(t *testing.T) *config.Config {
+	t.Helper()
+	dir := t.TempDir()
+	return &config.Config{
+		Database: config.DatabaseConfig{
+			Path:        filepath.Join(dir, "database.db"),
+			Migrations:  "../migrations",
+			BusyTimeout: time.Second,
+		},
+		Ledger: config.LedgerConfig{Key: filepath.Join(dir, "ledger.key")},
+	}
+}
+
+func TestRestoreLegacyBackup(t *testing.T) {
+	backup := legacyBackup(t, decimal.RequireFromString("12.5"), decimal.Zero)
+
+	report, err := restoreDatabase(context.Background(), restoreConfig(t), backup)
+	if err != nil {
+		t.Fatalf("restoreDatabase: %v", err)
+	}
+	if !report.Verified {
+		t.Fatalf("restored backup is not verified: %+v", report)
+	}
+	if len(report.Reconcile.Unseeded) != 0 {
+		t.Errorf("unseeded wallets after migrations: %v", report.Reconcile.Unseeded)
+	}
+	if report.Reconcile.Transactions != 1 || report.Ledger.Checked != 1 {
+		t.Errorf("checked %d ledger records and reconciled %d, want 1", report.Ledger.Checked, report.Reconcile.Transactions)
+	}
+	want := decimal.NewFromInt(200)
+	if !report.Reconcile.InitialSupply.Equal(want) || !report.Reconcile.ActualSupply.Equal(want) {
+		t.Errorf("initial supply %s, actual supply %s, want %s", report.Reconcile.InitialSupply, report.Reconcile.ActualSupply, want)
+	}
+}
+
+func TestRestoreLegacyBackupWithDrift(t *testing.T) {
+	backup := legacyBackup(t, decimal.RequireFromString("12.5"), decimal.NewFromInt(3))
+
+	report, err := restoreDatabase(context.Background(), restoreConfig(t), backup)
+	if err != nil {
+		t.Fatalf("restoreDatabase: %v", err)
+	}
+	if report.Verified {
+		t.Fatal("backup with a drifted balance is verified")
+	}
+	if !report.Ledger.Valid {
+		t.Errorf("ledger is reported broken: %+v", report.Ledger.Break)
+	}
+	if len(report.Reconcile.Mismatches) != 1 || !report.Reconcile.Mismatches[0].Difference.Equal(decimal.NewFromInt(3)) {
+		t.Errorf("mismatches = %+v, want a single difference of 3", report.Reconcile.Mismatches)
+	}
+	if report.Reconcile.SupplyConserved {
+		t.Error("supply is reported conserved despite the drift")
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/config"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/logging"
+	"log"
+	"os"
+)
+
+// runConfig выполняет подкоманды работы с конфигурацией.
+//
+// Подкоманды:
+//   - print [-format yaml|toml] [-config <файл>] [-<ключ> <значение> ...] — выводит итоговые
+//     значения конфигурации с учётом файла, переменных окружения и флагов; для каждого ключа
+//     в комментарии указан источник значения. Вывод можно сохранить и использовать как файл конфигурации.
+func runConfig(args []string) {
+	if len(args) == 0 || args[0] != "print" {
+		log.Fatal("Usage: config print [-format yaml|toml] [-config file] [flags]")
+	}
+
+	flags := flag.NewFlagSet("config print", flag.ExitOnError)
+	format := flags.String("format", config.FormatYAML, "формат вывода: yaml или toml")
+	cfg := loadConfig(flags, args[1:])
+
+	if err := cfg.Print(os.Stdout, *format); err != nil {
+		log.Fatalf("Failed to print configuration: %v", err)
+	}
+}
+
+// loadConfig загружает конфигурацию (см. config.Load) и устанавливает журнал с заданным уровнем.
+// При ошибке завершает процесс.
+//
+// Аргументы:
+//   - flags: набор флагов команды, в котором регистрируются флаги ключей конфигурации;
+//     nil — конфигурация читается только из файла и переменных окружения.
+//   - args: аргументы команды.
+func loadConfig(flags *flag.FlagSet, args []string) *config.Config {
+	cfg, err := config.Load(flags, args)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	logging.Setup(cfg.Logging)
+	return cfg
+}
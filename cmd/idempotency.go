@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+	"log/slog"
+	"time"
+)
+
+// runIdempotencyCleanup периодически удаляет ключи идемпотентности старше ttl, пока не отменён ctx.
+func runIdempotencyCleanup(
+	ctx context.Context, idempotencyService services.IdempotencyInteractor, ttl, interval time.Duration,
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := idempotencyService.DeleteExpired(ctx, ttl)
+			if err != nil {
+				slog.Error("failed to delete expired idempotency keys", "error", err)
+				continue
+			}
+			if deleted > 0 {
+				slog.Info("deleted expired idempotency keys", "count", deleted)
+			}
+		}
+	}
+}
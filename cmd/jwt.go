@@ -0,0 +1,152 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/auth"
+	"github.com/golang-jwt/jwt/v5"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// signingKey — закрытый ключ для выпуска тестовых JWT, сохраняемый командой jwt keygen.
+type signingKey struct {
+	Kid  string `json:"kid"`
+	Seed string `json:"seed"` // seed Ed25519 в hex
+}
+
+// runJWT выполняет подкоманды для локальной проверки аутентификации по JWT без внешнего SSO.
+//
+// Подкоманды:
+//   - keygen -jwks <файл> -key <файл> — создаёт ключ Ed25519, записывает открытую часть
+//     в JWKS (передаётся серверу через переменную JWKS), а закрытую — в файл ключа;
+//   - issue -key <файл> -sub <идентификатор> [-roles viewer,operator,admin] [-wallet <адрес>]...
+//     [-ttl 1h] [-iss <издатель>] [-aud <аудитория>] — выпускает подписанный токен.
+func runJWT(args []string) {
+	if len(args) == 0 {
+		log.Fatal("Usage: jwt keygen|issue [flags]")
+	}
+
+	switch args[0] {
+	case "keygen":
+		flags := flag.NewFlagSet("jwt keygen", flag.ExitOnError)
+		jwksPath := flags.String("jwks", "jwks.json", "файл для набора открытых ключей")
+		keyPath := flags.String("key", "jwt_signing_key.json", "файл для закрытого ключа")
+		_ = flags.Parse(args[1:])
+
+		if err := generateSigningKey(*jwksPath, *keyPath); err != nil {
+			log.Fatalf("Failed to generate signing key: %v", err)
+		}
+		fmt.Printf("JWKS written to %s, signing key written to %s\n", *jwksPath, *keyPath)
+
+	case "issue":
+		flags := flag.NewFlagSet("jwt issue", flag.ExitOnError)
+		keyPath := flags.String("key", "jwt_signing_key.json", "файл закрытого ключа")
+		subject := flags.String("sub", "", "идентификатор пользователя")
+		roles := flags.String("roles", string(auth.RoleViewer), "роли через запятую")
+		var wallets stringList
+		flags.Var(&wallets, "wallet", "кошелёк пользователя (можно указать несколько раз)")
+		ttl := flags.Duration("ttl", time.Hour, "срок действия токена")
+		issuer := flags.String("iss", "", "издатель токена")
+		audience := flags.String("aud", "", "аудитория токена")
+		_ = flags.Parse(args[1:])
+
+		if *subject == "" {
+			log.Fatal("-sub is required")
+		}
+
+		now := time.Now()
+		claims := jwt.MapClaims{
+			"sub":   *subject,
+			"iat":   now.Unix(),
+			"exp":   now.Add(*ttl).Unix(),
+			"roles": strings.Split(*roles, ","),
+		}
+		if len(wallets) > 0 {
+			claims["wallets"] = []string(wallets)
+		}
+		if *issuer != "" {
+			claims["iss"] = *issuer
+		}
+		if *audience != "" {
+			claims["aud"] = *audience
+		}
+
+		token, err := issueToken(*keyPath, claims)
+		if err != nil {
+			log.Fatalf("Failed to issue token: %v", err)
+		}
+		fmt.Println(token)
+
+	default:
+		log.Fatalf("Unknown jwt command %q, expected one of: keygen, issue", args[0])
+	}
+}
+
+// generateSigningKey создаёт ключ Ed25519 со случайным kid и записывает JWKS и закрытый ключ.
+// Существующие файлы не перезаписываются.
+func generateSigningKey(jwksPath, keyPath string) error {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	kidBytes := make([]byte, 8)
+	if _, err := rand.Read(kidBytes); err != nil {
+		return err
+	}
+	kid := hex.EncodeToString(kidBytes)
+
+	if err := writeJSONFile(keyPath, 0o600, signingKey{Kid: kid, Seed: hex.EncodeToString(privateKey.Seed())}); err != nil {
+		return err
+	}
+	return writeJSONFile(jwksPath, 0o644, auth.JWKS{Keys: []auth.JWK{{
+		Kty: "OKP",
+		Kid: kid,
+		Use: "sig",
+		Alg: "EdDSA",
+		Crv: "Ed25519",
+		X:   base64.RawURLEncoding.EncodeToString(publicKey),
+	}}})
+}
+
+// issueToken подписывает claims ключом из файла алгоритмом EdDSA.
+func issueToken(keyPath string, claims jwt.MapClaims) (string, error) {
+	raw, err := os.ReadFile(keyPath)
+	if err != nil {
+		return "", err
+	}
+	var key signingKey
+	if err := json.Unmarshal(raw, &key); err != nil {
+		return "", err
+	}
+	seed, err := hex.DecodeString(key.Seed)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return "", fmt.Errorf("malformed signing key in %s", keyPath)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = key.Kid
+	return token.SignedString(ed25519.NewKeyFromSeed(seed))
+}
+
+// writeJSONFile записывает значение в новый JSON-файл с указанными правами.
+func writeJSONFile(path string, perm os.FileMode, value any) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/config"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+	"log"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// ledgerKeyFile — формат файла ключа подписи контрольных точек.
+type ledgerKeyFile struct {
+	PublicKey  string `json:"public_key"`  // Открытый ключ Ed25519 в hex
+	PrivateKey string `json:"private_key"` // seed Ed25519 в hex
+}
+
+// runLedger выполняет подкоманды проверки журнала транзакций.
+//
+// Подкоманды:
+//   - verify — пересчитывает цепочку хешей и проверяет контрольные точки; выводит результат в JSON
+//     и завершается с кодом 1, если найдено нарушение;
+//   - checkpoint — создаёт подписанную контрольную точку, если журнал изменился с момента предыдущей.
+//
+// Ключ подписи берётся из файла, указанного ключом конфигурации ledger.key (по умолчанию ./ledger_key.json).
+func runLedger(cfg *config.Config, args []string) {
+	if len(args) == 0 {
+		log.Fatal("Usage: ledger verify|checkpoint")
+	}
+
+	flags := flag.NewFlagSet("ledger "+args[0], flag.ExitOnError)
+	_ = flags.Parse(args[1:])
+
+	db := openDatabase(cfg.Database)
+	defer closeDatabase(db)
+
+	switch args[0] {
+	case "verify":
+		// Для проверки ключ не обязателен: без него подписи сверяются с ключами из контрольных точек
+		ledgerKey, err := loadLedgerKey(cfg.Ledger.Key, false)
+		if err != nil {
+			log.Fatalf("Failed to load ledger key: %v", err)
+		}
+
+		resp, err := services.NewService(db, nil, ledgerKey).LedgerService.Verify(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to verify ledger: %v", err)
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(resp); err != nil {
+			log.Fatalf("Failed to encode result: %v", err)
+		}
+		if !resp.Valid {
+			closeDatabase(db)
+			os.Exit(1)
+		}
+
+	case "checkpoint":
+		ledgerKey, err := loadLedgerKey(cfg.Ledger.Key, true)
+		if err != nil {
+			log.Fatalf("Failed to load ledger key: %v", err)
+		}
+
+		checkpoint, created, err := services.NewService(db, nil, ledgerKey).LedgerService.CreateCheckpoint(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to create checkpoint: %v", err)
+		}
+		switch {
+		case checkpoint.ID == 0:
+			fmt.Println("Ledger is empty, no checkpoint created")
+		case !created:
+			fmt.Printf("Ledger unchanged since checkpoint %d\n", checkpoint.ID)
+		default:
+			fmt.Printf("Created checkpoint %d at transaction %d, head %s\n", checkpoint.ID, checkpoint.LastTxID, checkpoint.HeadHash)
+		}
+
+	default:
+		log.Fatalf("Unknown ledger command %q, expected one of: verify, checkpoint", args[0])
+	}
+}
+
+// loadLedgerKey загружает ключ подписи контрольных точек из файла.
+//
+// Аргументы:
+//   - path: путь к файлу ключа.
+//   - create: создать новый ключ (с правами 0600), если файл не существует.
+//
+// Возвращает:
+//   - закрытый ключ Ed25519; nil, если файл не существует и create == false.
+//   - ошибку чтения, разбора или создания ключа.
+func loadLedgerKey(path string, create bool) (ed25519.PrivateKey, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if !create {
+			return nil, nil
+		}
+		return generateLedgerKey(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file ledgerKeyFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, err
+	}
+	seed, err := hex.DecodeString(file.PrivateKey)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("malformed ledger key in %s", path)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// generateLedgerKey создаёт новый ключ подписи контрольных точек и сохраняет его в файл.
+func generateLedgerKey(path string) (ed25519.PrivateKey, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeJSONFile(path, 0o600, ledgerKeyFile{
+		PublicKey:  hex.EncodeToString(publicKey),
+		PrivateKey: hex.EncodeToString(privateKey.Seed()),
+	}); err != nil {
+		return nil, err
+	}
+
+	slog.Info("generated ledger signing key", "path", path)
+	return privateKey, nil
+}
+
+// runCheckpoints периодически создаёт контрольные точки журнала до отмены контекста.
+//
+// Аргументы:
+//   - ctx: контекст, отмена которого останавливает создание контрольных точек.
+//   - ledgerService: сервис журнала транзакций.
+//   - interval: период создания контрольных точек.
+func runCheckpoints(ctx context.Context, ledgerService services.LedgerInteractor, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			checkpoint, created, err := ledgerService.CreateCheckpoint(ctx)
+			if err != nil {
+				slog.Error("failed to create ledger checkpoint", "error", err)
+				continue
+			}
+			if created {
+				slog.Info("created ledger checkpoint", "checkpoint", checkpoint.ID, "last_tx_id", checkpoint.LastTxID)
+			}
+		}
+	}
+}
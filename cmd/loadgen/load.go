@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/utils"
+)
+
+// minAmount — минимальная сумма перевода и шаг случайных сумм.
+var minAmount = decimal.New(1, -2)
+
+// wallet — кошелёк, с которого выполняются переводы. Переводы с одного кошелька выполняются
+// по очереди: каждый следующий использует nonce на единицу больше предыдущего.
+type wallet struct {
+	mu    sync.Mutex
+	key   dto.WalletKey
+	nonce uint64
+	// synced — известен ли последний использованный nonce. После ошибки с неизвестным исходом
+	// перевод мог быть выполнен, поэтому nonce запрашивается заново.
+	synced bool
+}
+
+// sample — результат одного перевода.
+type sample struct {
+	latency time.Duration // Время выполнения перевода; 0, если перевод не был отправлен
+	code    string        // Машинный код ошибки; пустой при успешном переводе
+}
+
+// generateLoad выполняет переводы между кошельками из opts.concurrency потоков в течение opts.duration
+// или до выполнения opts.requests переводов и собирает их результаты.
+func generateLoad(ctx context.Context, t target, keys []dto.WalletKey, opts options) *report {
+	wallets := make([]*wallet, len(keys))
+	for i, key := range keys {
+		wallets[i] = &wallet{key: key}
+	}
+	maxCents := opts.maxAmount.Div(minAmount).IntPart()
+
+	ctx, cancel := context.WithTimeout(ctx, opts.duration)
+	defer cancel()
+
+	var (
+		started atomic.Int64
+		wg      sync.WaitGroup
+		results = make([][]sample, opts.concurrency)
+	)
+	start := time.Now()
+	for worker := range opts.concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				if opts.requests > 0 && started.Add(1) > int64(opts.requests) {
+					return
+				}
+				amount := decimal.New(rand.Int64N(maxCents)+1, minAmount.Exponent())
+				if s, ok := transfer(ctx, t, wallets, amount); ok {
+					results[worker] = append(results[worker], s)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return newReport(results, time.Since(start))
+}
+
+// transfer выполняет перевод суммы amount между двумя случайными кошельками.
+//
+// Возвращает:
+//   - результат перевода и false, если тест завершился раньше, чем перевод был отправлен,
+//     или перевод прерван окончанием теста: такие переводы в отчёт не попадают.
+func transfer(ctx context.Context, t target, wallets []*wallet, amount decimal.Decimal) (sample, bool) {
+	from := lockRandom(wallets)
+	defer from.mu.Unlock()
+	to := wallets[rand.IntN(len(wallets)-1)]
+	if to == from {
+		to = wallets[len(wallets)-1]
+	}
+
+	if !from.synced {
+		nonce, err := t.nonce(ctx, from.key.Address)
+		if err != nil {
+			// Ошибка получения nonce учитывается как неудачный перевод, чтобы она была видна в отчёте
+			code, _ := t.classify(err)
+			return sample{code: "get_nonce:" + code}, ctx.Err() == nil
+		}
+		from.nonce, from.synced = nonce, true
+	}
+
+	transaction := dto.TransactionReq{From: from.key.Address, To: to.key.Address, Amount: amount, Nonce: from.nonce + 1}
+	signature, err := utils.SignTransfer(from.key.PrivateKey, transaction.From, transaction.To, amount, transaction.Nonce)
+	if err != nil {
+		return sample{code: "invalid_private_key"}, true
+	}
+	transaction.Signature = signature
+
+	begin := time.Now()
+	err = t.send(ctx, transaction)
+	latency := time.Since(begin)
+	if err == nil {
+		from.nonce = transaction.Nonce
+		return sample{latency: latency}, true
+	}
+
+	code, rejected := t.classify(err)
+	if !rejected || code == "invalid_nonce" {
+		from.synced = false
+	}
+	// Ошибка после окончания теста вызвана отменой контекста, а не нагрузкой
+	if ctx.Err() != nil {
+		return sample{}, false
+	}
+	return sample{latency: latency, code: code}, true
+}
+
+// lockRandom блокирует и возвращает случайный кошелёк, предпочитая свободные: если все кошельки
+// заняты другими потоками, ожидает освобождения случайного.
+func lockRandom(wallets []*wallet) *wallet {
+	first := rand.IntN(len(wallets))
+	for i := range wallets {
+		w := wallets[(first+i)%len(wallets)]
+		if w.mu.TryLock() {
+			return w
+		}
+	}
+	w := wallets[first]
+	w.mu.Lock()
+	return w
+}
@@ -0,0 +1,193 @@
+// Команда loadgen — нагрузочный тест переводов между кошельками.
+//
+// Использование:
+//
+//	loadgen [флаги]
+//
+// loadgen создаёт -wallets кошельков, в течение -duration (или до выполнения -requests переводов)
+// выполняет случайные подписанные переводы между ними из -concurrency параллельных потоков и выводит
+// пропускную способность, перцентили задержки и количество ошибок по машинным кодам. В конце проверяется,
+// что сумма балансов созданных кошельков не изменилась: переводы только перераспределяют средства.
+//
+// Режимы (-mode):
+//   - http — переводы через API работающего сервера (-server); API-ключу нужны области доступа
+//     transfer и admin и право списания с любого кошелька (apikey create -all-wallets);
+//   - direct — переводы напрямую через TransferService без HTTP: база данных -db (по умолчанию временная)
+//     с миграциями из каталога -migrations.
+//
+// Команда завершается с кодом 1, если сумма балансов изменилась.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	modeHTTP   = "http"
+	modeDirect = "direct"
+)
+
+// options — параметры нагрузочного теста.
+type options struct {
+	mode        string
+	server      string
+	apiKey      string
+	token       string
+	db          string
+	migrations  string
+	busyTimeout time.Duration
+	wallets     int
+	concurrency int
+	duration    time.Duration
+	requests    int
+	maxAmount   decimal.Decimal
+	reason      string
+	output      string
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("loadgen: ")
+
+	opts, err := parseOptions(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	rep, err := run(ctx, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := rep.print(os.Stdout, opts.output); err != nil {
+		log.Fatal(err)
+	}
+	if !rep.Conserved {
+		os.Exit(1)
+	}
+}
+
+// parseOptions разбирает флаги командной строки. Адрес сервера и учётные данные по умолчанию
+// берутся из переменных LOADGEN_SERVER, LOADGEN_API_KEY и LOADGEN_TOKEN.
+func parseOptions(args []string) (options, error) {
+	opts := options{}
+	flags := flag.NewFlagSet("loadgen", flag.ContinueOnError)
+	flags.StringVar(&opts.mode, "mode", modeHTTP, "режим: http — через API сервера, direct — напрямую через TransferService")
+	flags.StringVar(&opts.server, "server", envOr("LOADGEN_SERVER", "http://localhost:8080"), "адрес сервера (режим http)")
+	flags.StringVar(&opts.apiKey, "api-key", os.Getenv("LOADGEN_API_KEY"), "API-ключ с областями доступа transfer и admin (режим http)")
+	flags.StringVar(&opts.token, "token", os.Getenv("LOADGEN_TOKEN"), "JWT с ролью admin (режим http)")
+	flags.StringVar(&opts.db, "db", "", "файл базы данных (режим direct); по умолчанию — временная база")
+	flags.StringVar(&opts.migrations, "migrations", "migrations", "каталог миграций (режим direct)")
+	flags.DurationVar(&opts.busyTimeout, "busy-timeout", 5*time.Second, "время ожидания блокировки базы данных (режим direct)")
+	flags.IntVar(&opts.wallets, "wallets", 10, "количество создаваемых кошельков")
+	flags.IntVar(&opts.concurrency, "concurrency", 8, "количество параллельных потоков переводов")
+	flags.DurationVar(&opts.duration, "duration", 10*time.Second, "продолжительность теста")
+	flags.IntVar(&opts.requests, "requests", 0, "количество переводов; 0 — без ограничения в пределах -duration")
+	maxAmount := flags.String("max-amount", "1", "максимальная сумма перевода; суммы выбираются случайно с шагом 0.01")
+	flags.StringVar(&opts.reason, "reason", "loadgen", "основание создания кошельков для журнала административных действий")
+	flags.StringVar(&opts.output, "o", outputText, "формат отчёта: text или json")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintf(flags.Output(), "Usage: loadgen [flags]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return options{}, err
+	}
+
+	if opts.mode != modeHTTP && opts.mode != modeDirect {
+		return options{}, fmt.Errorf("unknown mode %q, expected %s or %s", opts.mode, modeHTTP, modeDirect)
+	}
+	if opts.output != outputText && opts.output != outputJSON {
+		return options{}, fmt.Errorf("unknown output format %q, expected %s or %s", opts.output, outputText, outputJSON)
+	}
+	if opts.wallets < 2 {
+		return options{}, errors.New("-wallets must be at least 2")
+	}
+	if opts.concurrency < 1 {
+		return options{}, errors.New("-concurrency must be positive")
+	}
+	if opts.duration <= 0 {
+		return options{}, errors.New("-duration must be positive")
+	}
+	if opts.requests < 0 {
+		return options{}, errors.New("-requests must not be negative")
+	}
+	var err error
+	if opts.maxAmount, err = decimal.NewFromString(*maxAmount); err != nil {
+		return options{}, fmt.Errorf("invalid -max-amount %q: %w", *maxAmount, err)
+	}
+	if opts.maxAmount.LessThan(minAmount) {
+		return options{}, fmt.Errorf("-max-amount must be at least %s", minAmount)
+	}
+	return opts, nil
+}
+
+// envOr возвращает значение переменной окружения name или fallback, если она не задана.
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// run создаёт кошельки, выполняет нагрузочный тест и проверяет сохранение суммы балансов.
+func run(ctx context.Context, opts options) (*report, error) {
+	var (
+		t   target
+		err error
+	)
+	switch opts.mode {
+	case modeHTTP:
+		t, err = newHTTPTarget(opts)
+	case modeDirect:
+		t, err = newDirectTarget(opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := t.close(); err != nil {
+			log.Printf("failed to close %s target: %v", opts.mode, err)
+		}
+	}()
+
+	wallets, err := createWallets(ctx, t, opts.wallets, opts.reason)
+	if err != nil {
+		return nil, err
+	}
+	before, err := totalBalance(ctx, t, wallets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balances before the test: %w", err)
+	}
+
+	log.Printf("created %d wallets with total balance %s, running %d workers for %s",
+		len(wallets), before, opts.concurrency, opts.duration)
+	rep := generateLoad(ctx, t, wallets, opts)
+
+	// Итоговые балансы читаются и после прерывания теста: прерывание не должно нарушать сохранение средств
+	after, err := totalBalance(context.WithoutCancel(ctx), t, wallets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balances after the test: %w", err)
+	}
+	rep.Mode = opts.mode
+	rep.Wallets = len(wallets)
+	rep.Concurrency = opts.concurrency
+	rep.BalanceBefore = before
+	rep.BalanceAfter = after
+	rep.Conserved = before.Equal(after)
+	return rep, nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Форматы отчёта.
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// latencyReport — распределение задержек переводов.
+type latencyReport struct {
+	Min time.Duration `json:"min"`
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+// report — итоги нагрузочного теста. Длительности в JSON выводятся в наносекундах.
+type report struct {
+	Mode          string          `json:"mode"`           // Режим: http или direct
+	Wallets       int             `json:"wallets"`        // Количество кошельков
+	Concurrency   int             `json:"concurrency"`    // Количество параллельных потоков
+	Elapsed       time.Duration   `json:"elapsed"`        // Продолжительность теста
+	Requests      int             `json:"requests"`       // Выполненные переводы, включая неудачные
+	Succeeded     int             `json:"succeeded"`      // Успешные переводы
+	Failed        int             `json:"failed"`         // Неудачные переводы
+	Throughput    float64         `json:"throughput"`     // Успешных переводов в секунду
+	Latency       latencyReport   `json:"latency"`        // Задержки отправленных переводов
+	Errors        map[string]int  `json:"errors"`         // Количество ошибок по машинным кодам
+	BalanceBefore decimal.Decimal `json:"balance_before"` // Сумма балансов кошельков до теста
+	BalanceAfter  decimal.Decimal `json:"balance_after"`  // Сумма балансов кошельков после теста
+	Conserved     bool            `json:"conserved"`      // Сохранилась ли сумма балансов
+}
+
+// newReport сводит результаты переводов всех потоков.
+func newReport(results [][]sample, elapsed time.Duration) *report {
+	rep := &report{Elapsed: elapsed, Errors: map[string]int{}}
+	var latencies []time.Duration
+	for _, samples := range results {
+		for _, s := range samples {
+			rep.Requests++
+			if s.latency > 0 {
+				latencies = append(latencies, s.latency)
+			}
+			if s.code != "" {
+				rep.Errors[s.code]++
+				rep.Failed++
+			}
+		}
+	}
+	rep.Succeeded = rep.Requests - rep.Failed
+	if elapsed > 0 {
+		rep.Throughput = float64(rep.Succeeded) / elapsed.Seconds()
+	}
+
+	slices.Sort(latencies)
+	if len(latencies) > 0 {
+		rep.Latency = latencyReport{
+			Min: latencies[0],
+			P50: percentile(latencies, 0.50),
+			P90: percentile(latencies, 0.90),
+			P99: percentile(latencies, 0.99),
+			Max: latencies[len(latencies)-1],
+		}
+	}
+	return rep
+}
+
+// percentile возвращает перцентиль p (от 0 до 1) отсортированных значений методом ближайшего ранга.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// print выводит отчёт в формате format.
+func (r *report) print(w io.Writer, format string) error {
+	if format == outputJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "mode\t%s, %d wallets, %d workers\n", r.Mode, r.Wallets, r.Concurrency)
+	_, _ = fmt.Fprintf(tw, "elapsed\t%s\n", r.Elapsed.Round(time.Millisecond))
+	_, _ = fmt.Fprintf(tw, "requests\t%d (%.1f/s)\n", r.Requests, float64(r.Requests)/r.Elapsed.Seconds())
+	_, _ = fmt.Fprintf(tw, "succeeded\t%d (%.1f/s)\n", r.Succeeded, r.Throughput)
+	_, _ = fmt.Fprintf(tw, "failed\t%d\n", r.Failed)
+	_, _ = fmt.Fprintf(tw, "latency\tmin %s, p50 %s, p90 %s, p99 %s, max %s\n",
+		roundLatency(r.Latency.Min), roundLatency(r.Latency.P50), roundLatency(r.Latency.P90),
+		roundLatency(r.Latency.P99), roundLatency(r.Latency.Max))
+
+	// Ошибки выводятся от самых частых
+	codes := slices.SortedFunc(maps.Keys(r.Errors), func(a, b string) int {
+		return cmp.Or(cmp.Compare(r.Errors[b], r.Errors[a]), cmp.Compare(a, b))
+	})
+	for _, code := range codes {
+		_, _ = fmt.Fprintf(tw, "  %s\t%d\n", code, r.Errors[code])
+	}
+
+	conserved := "conserved"
+	if !r.Conserved {
+		conserved = "NOT CONSERVED"
+	}
+	_, _ = fmt.Fprintf(tw, "total balance\t%s -> %s, %s\n", r.BalanceBefore, r.BalanceAfter, conserved)
+	return tw.Flush()
+}
+
+// roundLatency округляет задержку для вывода в отчёте.
+func roundLatency(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
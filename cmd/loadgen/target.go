@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"github.com/shopspring/decimal"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/auth"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
+	"github.com/coffee-realist/infotecs_transaction_system/pkg/client"
+)
+
+// maxWalletsPerRequest — максимальное количество кошельков, создаваемых одной административной операцией.
+const maxWalletsPerRequest = 100
+
+// target — система, на которую подаётся нагрузка: сервер через API или сервисы напрямую.
+type target interface {
+	// generateWallets создаёт count кошельков и возвращает их ключевые пары.
+	generateWallets(ctx context.Context, count int, reason string) ([]dto.WalletKey, error)
+	// send выполняет подписанный перевод.
+	send(ctx context.Context, transaction dto.TransactionReq) error
+	// nonce возвращает последний использованный nonce кошелька.
+	nonce(ctx context.Context, address string) (uint64, error)
+	// balance возвращает баланс кошелька.
+	balance(ctx context.Context, address string) (decimal.Decimal, error)
+	// classify возвращает машинный код ошибки для отчёта и признак того, что перевод отклонён:
+	// не выполнен и не израсходовал nonce. При ошибке с неизвестным исходом (сбой соединения,
+	// внутренняя ошибка сервера) перевод мог быть выполнен.
+	classify(err error) (code string, rejected bool)
+	// close освобождает ресурсы.
+	close() error
+}
+
+// httpTarget подаёт нагрузку на работающий сервер через API.
+type httpTarget struct {
+	// api выполняет переводы без повторов: временные ошибки, например превышение лимита запросов,
+	// попадают в отчёт.
+	api *client.Client
+	// setup создаёт кошельки и читает балансы с повторами, чтобы лимиты запросов не прерывали
+	// подготовку и проверку результатов теста.
+	setup *client.Client
+}
+
+// setupRetryPolicy — политика повторов запросов подготовки и проверки результатов теста.
+var setupRetryPolicy = client.RetryPolicy{MaxAttempts: 10, MinBackoff: 100 * time.Millisecond, MaxBackoff: 10 * time.Second}
+
+// newHTTPTarget создаёт клиенты API сервера opts.server.
+func newHTTPTarget(opts options) (*httpTarget, error) {
+	if opts.apiKey == "" && opts.token == "" {
+		return nil, errors.New("-api-key or -token is required in http mode")
+	}
+	newClient := func(policy client.RetryPolicy) (*client.Client, error) {
+		return client.New(opts.server,
+			client.WithAPIKey(opts.apiKey),
+			client.WithBearerToken(opts.token),
+			client.WithUserAgent("loadgen"),
+			client.WithRetryPolicy(policy),
+		)
+	}
+
+	api, err := newClient(client.NoRetries)
+	if err != nil {
+		return nil, err
+	}
+	setup, err := newClient(setupRetryPolicy)
+	if err != nil {
+		return nil, err
+	}
+	return &httpTarget{api: api, setup: setup}, nil
+}
+
+func (t *httpTarget) generateWallets(ctx context.Context, count int, reason string) ([]dto.WalletKey, error) {
+	return t.setup.GenerateWallets(ctx, client.GenerateWalletsReq{Count: count, Reason: reason})
+}
+
+func (t *httpTarget) send(ctx context.Context, transaction dto.TransactionReq) error {
+	return t.api.Send(ctx, transaction)
+}
+
+func (t *httpTarget) nonce(ctx context.Context, address string) (uint64, error) {
+	resp, err := t.api.GetNonce(ctx, dto.BalanceReq{Address: address})
+	return resp.Nonce, err
+}
+
+func (t *httpTarget) balance(ctx context.Context, address string) (decimal.Decimal, error) {
+	resp, err := t.setup.GetBalance(ctx, dto.BalanceReq{Address: address})
+	return resp.Amount, err
+}
+
+// classify возвращает машинный код ошибки из ответа сервера; сбои соединения учитываются как
+// timeout (истёк контекст запроса) или network. Отклонёнными считаются ответы 4xx.
+func (t *httpTarget) classify(err error) (string, bool) {
+	var apiErr *client.Error
+	var netErr net.Error
+	switch {
+	case errors.As(err, &apiErr) && apiErr.Code != "":
+		return apiErr.Code, apiErr.Status < http.StatusInternalServerError
+	case errors.As(err, &apiErr):
+		return fmt.Sprintf("http_%d", apiErr.Status), apiErr.Status < http.StatusInternalServerError
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout", false
+	default:
+		return "network", false
+	}
+}
+
+func (t *httpTarget) close() error {
+	return nil
+}
+
+// directTarget подаёт нагрузку напрямую на TransferService, минуя HTTP.
+type directTarget struct {
+	db        *sql.DB
+	transfers services.TransferInteractor
+	admin     services.AdminInteractor
+	principal *auth.Principal
+	tempDir   string
+}
+
+// newDirectTarget подключается к базе данных opts.db и применяет миграции. Если база не указана,
+// создаётся временная база, удаляемая по завершении теста.
+func newDirectTarget(opts options) (*directTarget, error) {
+	t := &directTarget{
+		principal: &auth.Principal{
+			Subject:    "loadgen",
+			Name:       "loadgen",
+			Method:     "cli",
+			Scopes:     auth.Scopes,
+			AllWallets: true,
+		},
+	}
+
+	path := opts.db
+	if path == "" {
+		dir, err := os.MkdirTemp("", "loadgen-")
+		if err != nil {
+			return nil, err
+		}
+		t.tempDir = dir
+		path = filepath.Join(dir, "database.db")
+	}
+
+	db, err := storage.Connect(path, opts.busyTimeout)
+	if err != nil {
+		_ = t.close()
+		return nil, fmt.Errorf("failed to connect to the database: %w", err)
+	}
+	t.db = db
+	if err := storage.ApplyMigrations(db, opts.migrations); err != nil {
+		_ = t.close()
+		return nil, fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	service := services.NewService(db, nil, nil)
+	t.transfers = service.TransferService
+	t.admin = service.AdminService
+	return t, nil
+}
+
+func (t *directTarget) generateWallets(ctx context.Context, count int, reason string) ([]dto.WalletKey, error) {
+	return t.admin.GenerateWallets(auth.WithPrincipal(ctx, t.principal), dto.GenerateWalletsReq{Count: count, Reason: reason})
+}
+
+func (t *directTarget) send(ctx context.Context, transaction dto.TransactionReq) error {
+	return t.transfers.Send(auth.WithPrincipal(ctx, t.principal), transaction)
+}
+
+func (t *directTarget) nonce(ctx context.Context, address string) (uint64, error) {
+	resp, err := t.transfers.GetNonce(ctx, dto.BalanceReq{Address: address})
+	return resp.Nonce, err
+}
+
+func (t *directTarget) balance(ctx context.Context, address string) (decimal.Decimal, error) {
+	resp, err := t.transfers.GetBalance(ctx, dto.BalanceReq{Address: address})
+	return resp.Amount, err
+}
+
+// classify возвращает имя типа ошибки errorx без пространства имён, например "insufficient_funds".
+// Отклонёнными считаются ошибки клиента (services.IsClientErr).
+func (t *directTarget) classify(err error) (string, bool) {
+	e := errorx.Cast(err)
+	if e == nil {
+		return "internal", false
+	}
+	name := e.Type().FullName()
+	return name[len(e.Type().Namespace().FullName())+1:], services.IsClientErr(e)
+}
+
+func (t *directTarget) close() error {
+	var err error
+	if t.db != nil {
+		err = t.db.Close()
+	}
+	if t.tempDir != "" {
+		err = errors.Join(err, os.RemoveAll(t.tempDir))
+	}
+	return err
+}
+
+// createWallets создаёт count кошельков, разбивая создание на административные операции
+// не более чем по maxWalletsPerRequest кошельков.
+func createWallets(ctx context.Context, t target, count int, reason string) ([]dto.WalletKey, error) {
+	keys := make([]dto.WalletKey, 0, count)
+	for len(keys) < count {
+		batch, err := t.generateWallets(ctx, min(count-len(keys), maxWalletsPerRequest), reason)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create wallets: %w", err)
+		}
+		keys = append(keys, batch...)
+	}
+	return keys, nil
+}
+
+// totalBalance возвращает сумму балансов кошельков.
+func totalBalance(ctx context.Context, t target, wallets []dto.WalletKey) (decimal.Decimal, error) {
+	total := decimal.Zero
+	for _, wallet := range wallets {
+		balance, err := t.balance(ctx, wallet.Address)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		total = total.Add(balance)
+	}
+	return total, nil
+}
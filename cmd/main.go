@@ -3,99 +3,334 @@ package main
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"flag"
 	"github.com/coffee-realist/infotecs_transaction_system/internal/api"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/auth"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/config"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/logging"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/metrics"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/ratelimit"
 	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
 	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/tracing"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 )
 
-// Функция main - точка входа в приложение
+// Функция main - точка входа в приложение.
+//
+// Первый аргумент командной строки задаёт команду:
+//   - serve (по умолчанию) — запуск HTTP-сервера; параметры можно переопределить флагами (см. config.Load);
+//   - config — вывод итоговой конфигурации (см. runConfig);
+//   - apikey — управление API-ключами (см. runAPIKey);
+//   - sign — подпись перевода закрытым ключом кошелька (см. runSign);
+//   - jwt — генерация ключей и выпуск тестовых JWT (см. runJWT);
+//   - ledger — проверка журнала транзакций и создание контрольных точек (см. runLedger);
+//   - snapshot — снимки балансов и доказательства включения (см. runSnapshot);
+//   - reconcile — сверка балансов кошельков с журналом транзакций (см. runReconcile);
+//   - projections — проекции журнала транзакций (см. runProjections);
+//   - backup — резервная копия базы данных (см. runBackup);
+//   - restore — восстановление базы данных из резервной копии с проверкой (см. runRestore);
+//   - seed — заполнение базы кошельками и импорт фикстуры (см. runSeed).
+//
+// Конфигурация читается из файла (переменная CONFIG_FILE или флаг -config команд serve и config)
+// и переменных окружения; флаги ключей конфигурации принимают только команды serve и config.
 func main() {
-	// Подключаемся к базе данных
-	db, err := storage.Connect()
-	if err != nil {
-		log.Fatalf("Failed to connect to the database: %v", err)
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		serve(loadConfig(flag.NewFlagSet("serve", flag.ExitOnError), args))
+	case "config":
+		runConfig(args)
+	case "apikey":
+		runAPIKey(loadConfig(nil, nil), args)
+	case "sign":
+		runSign(args)
+	case "jwt":
+		runJWT(args)
+	case "ledger":
+		runLedger(loadConfig(nil, nil), args)
+	case "snapshot":
+		runSnapshot(loadConfig(nil, nil), args)
+	case "reconcile":
+		runReconcile(loadConfig(nil, nil), args)
+	case "projections":
+		runProjections(loadConfig(nil, nil), args)
+	case "backup":
+		runBackup(loadConfig(nil, nil), args)
+	case "restore":
+		runRestore(loadConfig(nil, nil), args)
+	case "seed":
+		runSeed(loadConfig(nil, nil), args)
+	default:
+		log.Fatalf("Unknown command %q, expected one of: serve, config, apikey, sign, jwt, ledger, snapshot, reconcile, projections, backup, restore, seed", command)
+	}
+}
+
+// serve запускает HTTP-сервер и ожидает сигнала завершения.
+func serve(cfg *config.Config) {
+	// Закрытые ключи кошельков, выведенные из seed.key_seed, может вычислить любой, кто знает строку,
+	// поэтому сервер такие кошельки не создаёт: детерминированное заполнение доступно только команде seed
+	if cfg.Seed.KeySeed != "" {
+		log.Fatal("seed.key_seed (SEED_KEY_SEED) is only supported by the seed command; unset it to start the server")
 	}
 
+	// Подключаемся к базе данных и применяем миграции
+	db := openDatabase(cfg.Database)
+
 	// Гарантируем закрытие
-	defer func() {
-		err := db.Close()
-		if err != nil {
-			log.Fatalf("Failed to close the database: %v", err)
-		}
-	}()
+	defer closeDatabase(db)
 
-	// Применяем миграции
-	if err := applyMigrations(db); err != nil {
-		log.Fatalf("Failed to apply migrations: %v", err)
+	// Настраиваем экспорт трассировок
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+
+	// Учитываем длительность запросов к базе данных и состояние пула подключений, записываем спаны запросов
+	// и записи журнала уровня debug
+	appMetrics := metrics.New()
+	storage.ObserveQueries(appMetrics.ObserveQuery, tracing.ObserveQuery, logging.ObserveQuery)
+	if err := appMetrics.RegisterDB(db, "main"); err != nil {
+		log.Fatalf("Failed to register database metrics: %v", err)
+	}
+
+	// Настраиваем проверку JWT, если задан источник ключей JWKS
+	tokenVerifier, err := newTokenVerifier(cfg.Auth)
+	if err != nil {
+		log.Fatalf("Failed to load JWKS: %v", err)
+	}
+
+	// Загружаем (или создаём) ключ подписи контрольных точек журнала транзакций
+	ledgerKey, err := loadLedgerKey(cfg.Ledger.Key, true)
+	if err != nil {
+		log.Fatalf("Failed to load ledger key: %v", err)
 	}
 
 	// Создаем сервисы
-	service := services.NewService(db)
+	service := services.NewService(db, tokenVerifier, ledgerKey)
+
+	// Публикуем объём средств в системе
+	if err := appMetrics.RegisterTotalSupply(service.TransferService.GetTotalSupply); err != nil {
+		log.Fatalf("Failed to register total supply metric: %v", err)
+	}
+
+	// Включаем в цепочку хешей транзакции, созданные до её появления
+	sealed, err := service.LedgerService.SealLegacy(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to seal legacy transactions: %v", err)
+	}
+	if sealed > 0 {
+		slog.Info("sealed legacy transactions into the ledger hash chain", "count", sealed)
+	}
+
+	// Заполняем пустую базу кошельками из фикстуры или генерируем их и сохраняем закрытые ключи
+	if err := seedWallets(context.Background(), service.TransferService, cfg.Seed, true); err != nil {
+		log.Fatalf("Failed to seed wallets: %v", err)
+	}
+
+	// Периодически публикуем подписанные контрольные точки журнала
+	checkpointsCtx, stopCheckpoints := context.WithCancel(context.Background())
+	defer stopCheckpoints()
+	go runCheckpoints(checkpointsCtx, service.LedgerService, cfg.Ledger.CheckpointInterval)
+
+	// Фиксируем текущие балансы и затем периодически публикуем снимки с корнями деревьев Меркла
+	createSnapshot(checkpointsCtx, service.SnapshotService)
+	go runSnapshots(checkpointsCtx, service.SnapshotService, cfg.Snapshots.Interval)
+
+	// Догоняем журнал проекциями и затем периодически применяем к ним новые записи
+	updateProjections(checkpointsCtx, service.ProjectionService)
+	go runProjectionUpdates(checkpointsCtx, service.ProjectionService, cfg.Projections.Interval)
+
+	// Периодически удаляем устаревшие ответы на запросы с ключом идемпотентности
+	go runIdempotencyCleanup(checkpointsCtx, service.IdempotencyService, cfg.Idempotency.TTL, cfg.Idempotency.CleanupInterval)
+
+	// Периодически создаём резервные копии базы данных, если задан каталог для них
+	if cfg.Backup.Dir != "" {
+		go runBackups(checkpointsCtx, db, cfg.Backup.Dir, cfg.Backup.Interval, cfg.Backup.Keep)
+	}
 
-	// Генерируем кошельки
-	if err := service.TransferService.GenerateWallets(); err != nil {
-		log.Fatalf("Failed to generate wallets: %v", err)
+	// Проверки состояния для оркестратора: готовность требует схемы с последней миграцией из каталога
+	migrationVersion, err := latestMigration(cfg.Database.Migrations)
+	if err != nil {
+		log.Fatalf("Failed to read migrations: %v", err)
 	}
+	healthService := services.NewHealthService(db, migrationVersion, buildInfo())
 
 	// Настраиваем маршруты
-	handler := api.NewHandler(service.TransferService)
+	handler := api.NewHandler(
+		tracing.InstrumentTransfers(metrics.InstrumentTransfers(service.TransferService, appMetrics)),
+		service.AuthService,
+		service.AdminService,
+		service.LedgerService,
+		service.SnapshotService,
+		service.ReconcileService,
+		service.ProjectionService,
+		service.IdempotencyService,
+		healthService,
+		appMetrics,
+		cfg.HTTP,
+		cfg.RateLimit,
+		ratelimit.NewMemoryStore(),
+	)
 	router := handler.InitRoutes()
 
 	// Запускаем сервер
 	server := new(api.Server)
 	go func() {
-		if err := server.Run("8080", router); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		if err := server.Run(cfg.Server, cfg.TLS, router); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Fatalf("Server failed: %v", err)
 		}
 	}()
-	log.Println("Server started at :8080")
+	slog.Info("server started", "port", cfg.Server.Port)
 
 	// Ожидаем сигнал для завершения
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Println("Shutting down server...")
+
+	// Сообщаем о завершении через /readyz и даём балансировщику время вывести сервер из ротации;
+	// повторный сигнал прерывает ожидание
+	healthService.Drain()
+	slog.Info("shutting down server", "drain_delay", cfg.Server.DrainDelay.String())
+	select {
+	case <-time.After(cfg.Server.DrainDelay):
+	case <-quit:
+	}
 
 	// Завершаем работу
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 	if err := server.ShutDown(ctx); err != nil {
 		log.Fatalf("Server shutdown failed: %v", err)
 	}
-	log.Println("Server exited properly")
+
+	// Фиксируем итоговое состояние журнала контрольной точкой
+	stopCheckpoints()
+	if _, _, err := service.LedgerService.CreateCheckpoint(ctx); err != nil {
+		slog.Error("failed to create ledger checkpoint", "error", err)
+	}
+
+	// Отправляем накопленные спаны
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
+	}
+	slog.Info("server exited properly")
 }
 
-// applyMigrations применяет миграцию базы данных, используя предоставленное подключение к базе данных SQL.
-// Обеспечивает актуальность схемы базы данных. Возвращает ошибку в случае сбоя миграции.
-func applyMigrations(db *sql.DB) error {
-	// Настройка миграции для sqlite
-	driver, err := sqlite.WithInstance(db, &sqlite.Config{})
+// newTokenVerifier создаёт проверку JWT по параметрам конфигурации:
+//   - jwks — путь к файлу JWKS или URL (если не задан, аутентификация по JWT отключена);
+//   - issuer, audience — ожидаемые издатель и аудитория;
+//   - roles_claim, wallets_claim — claims с ролями и кошельками пользователя.
+//
+// Возвращает:
+//   - *auth.JWTVerifier или nil, если JWKS не задан, и ошибку загрузки ключей.
+func newTokenVerifier(cfg config.AuthConfig) (*auth.JWTVerifier, error) {
+	if cfg.JWKS == "" {
+		return nil, nil
+	}
+
+	verifier, err := auth.NewJWTVerifier(auth.JWTConfig{
+		JWKS:         cfg.JWKS,
+		Issuer:       cfg.Issuer,
+		Audience:     cfg.Audience,
+		RolesClaim:   cfg.RolesClaim,
+		WalletsClaim: cfg.WalletsClaim,
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://migrations", // Путь к миграциям
-		"sqlite", driver)
+	slog.Info("JWT authentication enabled", "jwks", cfg.JWKS)
+	return verifier, nil
+}
+
+// saveWalletKeys сохраняет ключевые пары кошельков в JSON-файл, доступный только владельцу.
+// Существующий файл не перезаписывается, чтобы не потерять ранее выданные ключи:
+// в этом случае к имени файла добавляется отметка времени.
+//
+// Возвращает:
+//   - путь к файлу, в который записаны ключи, и ошибку, если запись не удалась.
+func saveWalletKeys(path string, keys []dto.WalletKey) (string, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		path = strings.TrimSuffix(path, ".json") + "-" + time.Now().Format("20060102150405") + ".json"
+		file, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	}
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	// Применение миграций
-	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return err
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(keys); err != nil {
+		_ = file.Close()
+		return "", err
+	}
+
+	return path, file.Close()
+}
+
+// openDatabase подключается к базе данных и применяет миграции.
+// При ошибке завершает процесс.
+func openDatabase(cfg config.DatabaseConfig) *sql.DB {
+	// Подключаемся к базе данных
+	db, err := storage.Connect(cfg.Path, cfg.BusyTimeout)
+	if err != nil {
+		log.Fatalf("Failed to connect to the database: %v", err)
 	}
 
-	log.Println("Migrations applied successfully")
-	return nil
+	// Применяем миграции
+	if err := storage.ApplyMigrations(db, cfg.Migrations); err != nil {
+		log.Fatalf("Failed to apply migrations: %v", err)
+	}
+
+	return db
+}
+
+// closeDatabase закрывает подключение к базе данных.
+func closeDatabase(db *sql.DB) {
+	if err := db.Close(); err != nil {
+		log.Fatalf("Failed to close the database: %v", err)
+	}
+}
+
+// latestMigration возвращает версию последней миграции в каталоге dir — версию схемы,
+// которую ожидает сервер.
+func latestMigration(dir string) (uint, error) {
+	migrations, err := source.Open("file://" + dir)
+	if err != nil {
+		return 0, err
+	}
+	defer migrations.Close()
+
+	version, err := migrations.First()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		next, err := migrations.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
 }
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/auth"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/config"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+	"log"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// runProjections выполняет подкоманды работы с проекциями журнала транзакций и выводит результат в JSON.
+//
+// Подкоманды:
+//   - status — состояние проекций: контрольные точки и отставание от журнала;
+//   - run — применяет к проекциям записи журнала, появившиеся после их контрольных точек;
+//   - rebuild -name <проекция> -reason <основание> — перестраивает проекцию с нуля;
+//     перестроение записывается в журнал административных действий от имени cli:<пользователь ОС>.
+func runProjections(cfg *config.Config, args []string) {
+	if len(args) == 0 {
+		log.Fatal("Usage: projections status|run|rebuild [flags]")
+	}
+
+	flags := flag.NewFlagSet("projections "+args[0], flag.ExitOnError)
+	name := flags.String("name", "", "имя проекции")
+	reason := flags.String("reason", "", "основание перестроения")
+	_ = flags.Parse(args[1:])
+
+	db := openDatabase(cfg.Database)
+	defer closeDatabase(db)
+	service := services.NewService(db, nil, nil)
+	ctx := context.Background()
+
+	var result any
+	switch args[0] {
+	case "status":
+		statuses, err := service.ProjectionService.GetStatus(ctx)
+		if err != nil {
+			log.Fatalf("Failed to get projection status: %v", err)
+		}
+		result = statuses
+
+	case "run":
+		statuses, err := service.ProjectionService.Run(ctx)
+		if err != nil {
+			log.Fatalf("Failed to run projections: %v", err)
+		}
+		result = statuses
+
+	case "rebuild":
+		if *name == "" {
+			log.Fatal("-name is required")
+		}
+
+		resp, err := service.ProjectionService.Rebuild(
+			auth.WithPrincipal(ctx, cliPrincipal()),
+			dto.ProjectionRebuildReq{Name: *name, Reason: *reason},
+		)
+		if err != nil {
+			log.Fatalf("Failed to rebuild projection: %v", err)
+		}
+		result = resp
+
+	default:
+		log.Fatalf("Unknown projections command %q, expected one of: status, run, rebuild", args[0])
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		log.Fatalf("Failed to encode result: %v", err)
+	}
+}
+
+// runProjectionUpdates периодически догоняет журнал транзакций проекциями до отмены контекста.
+//
+// Аргументы:
+//   - ctx: контекст, отмена которого останавливает обновление проекций.
+//   - projectionService: движок проекций журнала транзакций.
+//   - interval: интервал между обновлениями.
+func runProjectionUpdates(ctx context.Context, projectionService services.ProjectionInteractor, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			updateProjections(ctx, projectionService)
+		}
+	}
+}
+
+// updateProjections догоняет журнал транзакций проекциями и записывает ошибку в журнал сервера.
+func updateProjections(ctx context.Context, projectionService services.ProjectionInteractor) {
+	if _, err := projectionService.Run(ctx); err != nil {
+		slog.Error("failed to update projections", "error", err)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/auth"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/config"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+	"log"
+	"os"
+	"os/user"
+)
+
+// runReconcile сверяет балансы кошельков с журналом транзакций и выводит отчёт в JSON.
+//
+// Флаги:
+//   - -repair — исправить расхождения корректировками в журнале транзакций;
+//   - -reason <основание> — основание исправления, обязательно вместе с -repair.
+//
+// Исправление записывается в журнал административных действий от имени cli:<пользователь ОС>.
+// Команда завершается с кодом 1, если найдены неисправленные расхождения или есть кошельки
+// без начального баланса, которые сверить нельзя.
+func runReconcile(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	repair := flags.Bool("repair", false, "исправить расхождения корректировками")
+	reason := flags.String("reason", "", "основание исправления")
+	_ = flags.Parse(args)
+
+	db := openDatabase(cfg.Database)
+	defer closeDatabase(db)
+	reconcileService := services.NewService(db, nil, nil).ReconcileService
+
+	ctx := auth.WithPrincipal(context.Background(), cliPrincipal())
+	report, err := reconcileService.Reconcile(ctx, dto.ReconcileReq{Repair: *repair, Reason: *reason})
+	if err != nil {
+		log.Fatalf("Failed to reconcile balances: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatalf("Failed to encode report: %v", err)
+	}
+	if len(report.Unseeded) > 0 || (!report.Consistent && !report.Repaired) {
+		closeDatabase(db)
+		os.Exit(1)
+	}
+}
+
+// cliPrincipal возвращает клиента с областью доступа admin для административных действий,
+// выполняемых из командной строки с прямым доступом к базе данных.
+func cliPrincipal() *auth.Principal {
+	name := "unknown"
+	if current, err := user.Current(); err == nil {
+		name = current.Username
+	}
+	return &auth.Principal{
+		Subject: "cli:" + name,
+		Name:    name,
+		Method:  "cli",
+		Scopes:  []auth.Scope{auth.ScopeAdmin},
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/auth"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/config"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+	"github.com/shopspring/decimal"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// runSeed добавляет в базу кошельки независимо от того, есть ли в ней кошельки.
+//
+// Параметры по умолчанию берутся из конфигурации seed.* и переопределяются флагами:
+//   - -count, -balance, -distribution — количество кошельков, средний начальный баланс и его распределение;
+//   - -key-seed — строка для детерминированного вывода ключей и балансов;
+//   - -fixture — файл CSV или JSON с кошельками и балансами, импортируемыми вместо создания новых;
+//   - -wallet-keys — файл для закрытых ключей созданных кошельков.
+//
+// Кошельки создаются в одной транзакции БД. Если в базе уже есть кошельки, начальные балансы
+// зачисляются эмиссией от имени cli:<пользователь ОС>.
+func runSeed(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	flags.IntVar(&cfg.Seed.Count, "count", cfg.Seed.Count, "количество кошельков")
+	flags.TextVar(&cfg.Seed.Balance, "balance", cfg.Seed.Balance, "средний начальный баланс")
+	flags.StringVar(&cfg.Seed.Distribution, "distribution", cfg.Seed.Distribution, "распределение балансов: fixed, uniform или exponential")
+	flags.StringVar(&cfg.Seed.KeySeed, "key-seed", cfg.Seed.KeySeed, "строка для детерминированного вывода ключей и балансов")
+	flags.StringVar(&cfg.Seed.Fixture, "fixture", cfg.Seed.Fixture, "файл CSV или JSON с кошельками и балансами")
+	flags.StringVar(&cfg.Seed.WalletKeys, "wallet-keys", cfg.Seed.WalletKeys, "файл для закрытых ключей созданных кошельков")
+	_ = flags.Parse(args)
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid seed parameters: %v", err)
+	}
+
+	db := openDatabase(cfg.Database)
+	defer closeDatabase(db)
+	transferService := services.NewService(db, nil, nil).TransferService
+
+	ctx := auth.WithPrincipal(context.Background(), cliPrincipal())
+	if err := seedWallets(ctx, transferService, cfg.Seed, false); err != nil {
+		log.Fatalf("Failed to seed wallets: %v", err)
+	}
+}
+
+// seedWallets заполняет базу кошельками по параметрам seed.*: импортирует фикстуру, если она задана,
+// иначе создаёт новые кошельки и сохраняет их закрытые ключи (см. saveWalletKeys).
+//
+// Аргументы:
+//   - ctx: контекст с клиентом, от имени которого записываются эмиссии.
+//   - transferService: сервис, создающий кошельки.
+//   - cfg: параметры заполнения.
+//   - onlyIfEmpty: заполнять, только если в базе ещё нет кошельков (первый запуск сервера).
+//
+// Возвращает:
+//   - ошибку чтения фикстуры, создания кошельков или сохранения ключей.
+func seedWallets(ctx context.Context, transferService services.TransferInteractor, cfg config.SeedConfig, onlyIfEmpty bool) error {
+	if cfg.Fixture != "" {
+		wallets, err := readFixture(cfg.Fixture)
+		if err != nil {
+			return err
+		}
+		count, err := transferService.ImportWallets(ctx, dto.ImportWalletsReq{Wallets: wallets, OnlyIfEmpty: onlyIfEmpty})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			slog.Info("imported wallets", "count", count, "fixture", cfg.Fixture)
+		}
+		return nil
+	}
+
+	keys, err := transferService.GenerateWallets(ctx, dto.SeedReq{
+		Count:        cfg.Count,
+		Balance:      cfg.Balance,
+		Distribution: cfg.Distribution,
+		KeySeed:      cfg.KeySeed,
+		OnlyIfEmpty:  onlyIfEmpty,
+	})
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	path, err := saveWalletKeys(cfg.WalletKeys, keys)
+	if err != nil {
+		return fmt.Errorf("failed to save wallet keys: %w", err)
+	}
+	slog.Info("generated wallets", "count", len(keys), "distribution", cfg.Distribution,
+		"deterministic", cfg.KeySeed != "", "keys_path", path)
+	return nil
+}
+
+// readFixture читает кошельки с начальными балансами из файла фикстуры.
+//
+// Формат определяется расширением файла:
+//   - .json — массив объектов {"address": ..., "private_key": ..., "balance": ...};
+//   - .csv — строка заголовка с колонками address, private_key и balance в любом порядке и строки кошельков.
+//
+// Колонки address и private_key необязательны, но у каждого кошелька должен быть задан хотя бы один из них.
+func readFixture(path string) ([]dto.SeedWallet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var wallets []dto.SeedWallet
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(file)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&wallets); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+		}
+	case ".csv":
+		if wallets, err = readFixtureCSV(file); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported fixture format %q, expected .csv or .json", filepath.Ext(path))
+	}
+	return wallets, nil
+}
+
+// readFixtureCSV читает кошельки фикстуры в формате CSV (см. readFixture).
+func readFixtureCSV(r io.Reader) ([]dto.SeedWallet, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	columns := map[string]int{"address": -1, "private_key": -1, "balance": -1}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("unknown column %q, expected address, private_key and balance", name)
+		}
+		columns[name] = i
+	}
+	if columns["balance"] < 0 {
+		return nil, errors.New("balance column is required")
+	}
+	if columns["address"] < 0 && columns["private_key"] < 0 {
+		return nil, errors.New("address or private_key column is required")
+	}
+	field := func(record []string, name string) string {
+		if i := columns[name]; i >= 0 {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var wallets []dto.SeedWallet
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return wallets, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if slices.Equal(record, []string{""}) {
+			continue
+		}
+
+		balance, err := decimal.NewFromString(field(record, "balance"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid balance %q", line, field(record, "balance"))
+		}
+		wallets = append(wallets, dto.SeedWallet{
+			Address:    field(record, "address"),
+			PrivateKey: field(record, "private_key"),
+			Balance:    balance,
+		})
+	}
+}
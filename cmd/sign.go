@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/utils"
+	"github.com/shopspring/decimal"
+	"log"
+	"os"
+)
+
+// runSign подписывает перевод закрытым ключом кошелька отправителя и выводит
+// готовое тело запроса POST /api/send.
+//
+// Флаги:
+//   - -key: закрытый ключ отправителя (seed Ed25519 в hex);
+//   - -from, -to: адреса отправителя и получателя;
+//   - -amount: сумма перевода;
+//   - -nonce: nonce перевода (больше последнего использованного, см. GET /api/wallet/{address}/nonce).
+func runSign(args []string) {
+	flags := flag.NewFlagSet("sign", flag.ExitOnError)
+	key := flags.String("key", "", "закрытый ключ отправителя (hex)")
+	from := flags.String("from", "", "адрес отправителя")
+	to := flags.String("to", "", "адрес получателя")
+	amountFlag := flags.String("amount", "", "сумма перевода")
+	nonce := flags.Uint64("nonce", 0, "nonce перевода")
+	_ = flags.Parse(args)
+
+	amount, err := decimal.NewFromString(*amountFlag)
+	if err != nil {
+		log.Fatalf("Invalid amount %q: %v", *amountFlag, err)
+	}
+
+	signature, err := utils.SignTransfer(*key, *from, *to, amount, *nonce)
+	if err != nil {
+		log.Fatalf("Failed to sign transfer: %v", err)
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(dto.TransactionReq{
+		From:      *from,
+		To:        *to,
+		Amount:    amount,
+		Nonce:     *nonce,
+		Signature: signature,
+	}); err != nil {
+		log.Fatalf("Failed to encode request: %v", err)
+	}
+}
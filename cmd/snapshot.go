@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/config"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/utils"
+	"log"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// runSnapshot выполняет подкоманды работы со снимками балансов.
+//
+// Подкоманды:
+//   - create — создаёт снимок балансов с корнем дерева Меркла, если журнал изменился
+//     с момента предыдущего снимка;
+//   - proof -address <адрес> [-id <снимок>] — выводит доказательство включения баланса кошелька
+//     в снимок (по умолчанию в последний) и проверяет его; завершается с кодом 1,
+//     если доказательство не сходится с корнем.
+func runSnapshot(cfg *config.Config, args []string) {
+	if len(args) == 0 {
+		log.Fatal("Usage: snapshot create|proof [flags]")
+	}
+
+	flags := flag.NewFlagSet("snapshot "+args[0], flag.ExitOnError)
+	address := flags.String("address", "", "адрес кошелька")
+	id := flags.Int64("id", 0, "идентификатор снимка; 0 — последний")
+	_ = flags.Parse(args[1:])
+
+	db := openDatabase(cfg.Database)
+	defer closeDatabase(db)
+	snapshotService := services.NewService(db, nil, nil).SnapshotService
+
+	switch args[0] {
+	case "create":
+		snapshot, created, err := snapshotService.CreateSnapshot(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to create snapshot: %v", err)
+		}
+		if !created {
+			fmt.Printf("Ledger unchanged since snapshot %d\n", snapshot.ID)
+			return
+		}
+		fmt.Printf("Created snapshot %d at transaction %d: %d wallets, total %s, merkle root %s\n",
+			snapshot.ID, snapshot.LastTxID, snapshot.WalletCount, snapshot.Total, snapshot.MerkleRoot)
+
+	case "proof":
+		if *address == "" {
+			log.Fatal("-address is required")
+		}
+
+		resp, err := snapshotService.GetProof(context.Background(), dto.BalanceProofReq{Address: *address, SnapshotID: *id})
+		if err != nil {
+			log.Fatalf("Failed to build balance proof: %v", err)
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(resp); err != nil {
+			log.Fatalf("Failed to encode proof: %v", err)
+		}
+		if utils.MerkleLeafHash(resp.Address, resp.Balance) != resp.LeafHash ||
+			!utils.VerifyMerkleProof(resp.LeafHash, resp.Proof, resp.MerkleRoot) {
+			closeDatabase(db)
+			os.Exit(1)
+		}
+
+	default:
+		log.Fatalf("Unknown snapshot command %q, expected one of: create, proof", args[0])
+	}
+}
+
+// runSnapshots периодически создаёт снимки балансов до отмены контекста.
+//
+// Аргументы:
+//   - ctx: контекст, отмена которого останавливает создание снимков.
+//   - snapshotService: сервис снимков балансов.
+//   - interval: интервал между снимками.
+func runSnapshots(ctx context.Context, snapshotService services.SnapshotInteractor, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			createSnapshot(ctx, snapshotService)
+		}
+	}
+}
+
+// createSnapshot создаёт снимок балансов и записывает результат в журнал сервера.
+func createSnapshot(ctx context.Context, snapshotService services.SnapshotInteractor) {
+	snapshot, created, err := snapshotService.CreateSnapshot(ctx)
+	if err != nil {
+		slog.Error("failed to create balance snapshot", "error", err)
+		return
+	}
+	if created {
+		slog.Info("created balance snapshot",
+			"snapshot", snapshot.ID, "last_tx_id", snapshot.LastTxID, "merkle_root", snapshot.MerkleRoot)
+	}
+}
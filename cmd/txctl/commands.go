@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/shopspring/decimal"
+)
+
+var sendCommand = &command{
+	name:    "send",
+	summary: "подписать перевод ключом кошелька отправителя и выполнить его",
+	setup: func(flags *flag.FlagSet) runFunc {
+		from := flags.String("from", "", "адрес отправителя")
+		to := flags.String("to", "", "адрес получателя")
+		amountFlag := flags.String("amount", "", "сумма перевода")
+		key := flags.String("key", "", "закрытый ключ отправителя (hex); по умолчанию — из файла ключей кошельков")
+		return func(ctx context.Context, env *environment, _ []string) error {
+			if *from == "" || *to == "" {
+				return errors.New("-from and -to are required")
+			}
+			amount, err := decimal.NewFromString(*amountFlag)
+			if err != nil {
+				return fmt.Errorf("invalid amount %q: %w", *amountFlag, err)
+			}
+
+			privateKey := *key
+			if privateKey == "" {
+				if privateKey, err = env.walletKey(*from); err != nil {
+					return err
+				}
+			}
+
+			api, err := env.api()
+			if err != nil {
+				return err
+			}
+			transaction, err := api.Transfer(ctx, privateKey, *from, *to, amount)
+			if err != nil {
+				return err
+			}
+			return env.out.print(transaction, []string{"FROM", "TO", "AMOUNT", "NONCE"}, [][]string{
+				{transaction.From, transaction.To, transaction.Amount.String(), strconv.FormatUint(transaction.Nonce, 10)},
+			})
+		}
+	},
+}
+
+var balanceCommand = &command{
+	name:    "balance",
+	args:    "<address>...",
+	summary: "вывести баланс кошельков",
+	setup: func(*flag.FlagSet) runFunc {
+		return func(ctx context.Context, env *environment, args []string) error {
+			if len(args) == 0 {
+				return errors.New("expected at least one wallet address")
+			}
+			balances, err := env.balances(ctx, args)
+			if err != nil {
+				return err
+			}
+			return env.out.print(balances, []string{"ADDRESS", "BALANCE"}, balanceRows(balances))
+		}
+	},
+}
+
+var historyCommand = &command{
+	name:    "history",
+	summary: "вывести последние транзакции",
+	setup: func(flags *flag.FlagSet) runFunc {
+		count := flags.Int("count", 10, "количество транзакций")
+		return func(ctx context.Context, env *environment, _ []string) error {
+			api, err := env.api()
+			if err != nil {
+				return err
+			}
+			transactions, err := api.GetLastN(ctx, *count)
+			if err != nil {
+				return err
+			}
+
+			rows := make([][]string, 0, len(transactions))
+			for _, tx := range transactions {
+				rows = append(rows, []string{
+					tx.CreatedAt.Local().Format(time.DateTime), tx.Kind, orDash(tx.From), orDash(tx.To), tx.Amount.String(),
+				})
+			}
+			return env.out.print(transactions, []string{"CREATED", "KIND", "FROM", "TO", "AMOUNT"}, rows)
+		}
+	},
+}
+
+var walletsListCommand = &command{
+	name:    "list",
+	summary: "вывести кошельки из файла ключей с текущими балансами",
+	setup: func(*flag.FlagSet) runFunc {
+		return func(ctx context.Context, env *environment, _ []string) error {
+			keys, err := readWalletKeys(env.profile.WalletKeys)
+			if err != nil {
+				return err
+			}
+			addresses := make([]string, 0, len(keys))
+			for _, key := range keys {
+				addresses = append(addresses, key.Address)
+			}
+			balances, err := env.balances(ctx, addresses)
+			if err != nil {
+				return err
+			}
+			return env.out.print(balances, []string{"ADDRESS", "BALANCE"}, balanceRows(balances))
+		}
+	},
+}
+
+var walletsCreateCommand = &command{
+	name:    "create",
+	summary: "создать кошельки (требует области доступа admin) и сохранить их ключи в файл ключей",
+	setup: func(flags *flag.FlagSet) runFunc {
+		count := flags.Int("count", 1, "количество кошельков")
+		reason := flags.String("reason", "", "основание операции для журнала аудита")
+		return func(ctx context.Context, env *environment, _ []string) error {
+			if *reason == "" {
+				return errors.New("-reason is required")
+			}
+			api, err := env.api()
+			if err != nil {
+				return err
+			}
+			created, err := api.GenerateWallets(ctx, dto.GenerateWalletsReq{Count: *count, Reason: *reason})
+			if err != nil {
+				return err
+			}
+
+			// Ключи сохраняются до вывода: сервер не хранит закрытые ключи и не покажет их повторно.
+			keys, err := readWalletKeys(env.profile.WalletKeys)
+			if err != nil {
+				return err
+			}
+			if err := writeWalletKeys(env.profile.WalletKeys, append(keys, created...)); err != nil {
+				return fmt.Errorf("wallets were created but their keys could not be saved: %w; keys: %s", err, mustJSON(created))
+			}
+
+			addresses := make([]string, 0, len(created))
+			rows := make([][]string, 0, len(created))
+			for _, key := range created {
+				addresses = append(addresses, key.Address)
+				rows = append(rows, []string{key.Address})
+			}
+			if err := env.out.print(addresses, []string{"ADDRESS"}, rows); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(os.Stderr, "Saved keys of %d wallets to %s\n", len(created), env.profile.WalletKeys)
+			return nil
+		}
+	},
+}
+
+// walletBalance — баланс кошелька для вывода.
+type walletBalance struct {
+	Address string          `json:"address"`
+	Amount  decimal.Decimal `json:"amount"`
+}
+
+// balances запрашивает балансы кошельков по адресам.
+func (e *environment) balances(ctx context.Context, addresses []string) ([]walletBalance, error) {
+	api, err := e.api()
+	if err != nil {
+		return nil, err
+	}
+	balances := make([]walletBalance, 0, len(addresses))
+	for _, address := range addresses {
+		balance, err := api.GetBalance(ctx, dto.BalanceReq{Address: address})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", address, err)
+		}
+		balances = append(balances, walletBalance{Address: address, Amount: balance.Amount})
+	}
+	return balances, nil
+}
+
+// balanceRows формирует строки таблицы балансов.
+func balanceRows(balances []walletBalance) [][]string {
+	rows := make([][]string, 0, len(balances))
+	for _, balance := range balances {
+		rows = append(rows, []string{balance.Address, balance.Amount.String()})
+	}
+	return rows
+}
+
+// walletKey возвращает закрытый ключ кошелька из файла ключей профиля.
+func (e *environment) walletKey(address string) (string, error) {
+	keys, err := readWalletKeys(e.profile.WalletKeys)
+	if err != nil {
+		return "", err
+	}
+	for _, key := range keys {
+		if key.Address == address {
+			return key.PrivateKey, nil
+		}
+	}
+	return "", fmt.Errorf("no key for wallet %s in %s; pass it with -key", address, e.profile.WalletKeys)
+}
+
+// readWalletKeys читает файл ключей кошельков в формате wallet_keys.json сервера.
+// Отсутствующий файл считается пустым.
+func readWalletKeys(path string) ([]dto.WalletKey, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var keys []dto.WalletKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return keys, nil
+}
+
+// writeWalletKeys записывает файл ключей кошельков, доступный только владельцу.
+func writeWalletKeys(path string, keys []dto.WalletKey) error {
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// mustJSON кодирует значение в JSON для сообщения об ошибке.
+func mustJSON(value any) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// orDash заменяет пустое значение прочерком в таблице.
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// completeCommand — скрытая команда, которую вызывают скрипты автодополнения: по уже введённым
+// словам она выводит варианты следующего слова, по одному на строку.
+const completeCommand = "__complete"
+
+// Скрипты автодополнения. Варианты вычисляет сам txctl (команда __complete), поэтому скрипты
+// не нужно обновлять при добавлении команд и флагов.
+const (
+	bashCompletion = `# bash completion for txctl
+_txctl() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    COMPREPLY=($(compgen -W "$(txctl __complete "${COMP_WORDS[@]:1:COMP_CWORD-1}" 2>/dev/null)" -- "$cur"))
+}
+complete -o default -F _txctl txctl
+`
+	zshCompletion = `#compdef txctl
+_txctl() {
+    local -a candidates
+    candidates=(${(f)"$(txctl __complete ${words[2,CURRENT-1]} 2>/dev/null)"})
+    compadd -a candidates
+}
+compdef _txctl txctl
+`
+	fishCompletion = `# fish completion for txctl
+complete -c txctl -f -a '(txctl __complete (commandline -opc)[2..-1] 2>/dev/null)'
+`
+)
+
+var completionCommand = &command{
+	name:    "completion",
+	args:    "bash|zsh|fish",
+	summary: "вывести скрипт автодополнения для командной оболочки",
+	local:   true,
+	setup: func(*flag.FlagSet) runFunc {
+		return func(_ context.Context, _ *environment, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("expected shell: bash, zsh or fish")
+			}
+			scripts := map[string]string{"bash": bashCompletion, "zsh": zshCompletion, "fish": fishCompletion}
+			script, ok := scripts[args[0]]
+			if !ok {
+				return fmt.Errorf("unsupported shell %q, expected bash, zsh or fish", args[0])
+			}
+			_, err := fmt.Print(script)
+			return err
+		}
+	},
+}
+
+// complete выводит в w варианты слова, следующего за words.
+func complete(w io.Writer, words []string) {
+	candidates := commands
+	var cmd *command
+	for len(words) > 0 && cmd == nil {
+		found := findCommand(candidates, words[0])
+		if found == nil {
+			return
+		}
+		words = words[1:]
+		if len(found.subcommands) > 0 {
+			candidates = found.subcommands
+			continue
+		}
+		cmd = found
+	}
+
+	if cmd == nil {
+		for _, candidate := range candidates {
+			_, _ = fmt.Fprintln(w, candidate.name)
+		}
+		return
+	}
+
+	// Значения флагов, варианты которых известны заранее.
+	previous := ""
+	if len(words) > 0 {
+		previous = words[len(words)-1]
+	}
+	switch strings.TrimLeft(previous, "-") {
+	case "o":
+		_, _ = fmt.Fprintf(w, "%s\n%s\n", outputTable, outputJSON)
+		return
+	case "profile":
+		printProfileNames(w)
+		return
+	}
+	if cmd == profileUseCommand || cmd == profileDeleteCommand || cmd == profileSetCommand {
+		printProfileNames(w)
+	}
+	if cmd == completionCommand {
+		_, _ = fmt.Fprint(w, "bash\nzsh\nfish\n")
+		return
+	}
+
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	if !cmd.local {
+		registerGlobalOptions(flags)
+	}
+	cmd.setup(flags)
+	flags.VisitAll(func(f *flag.Flag) {
+		_, _ = fmt.Fprintln(w, "-"+f.Name)
+	})
+}
+
+// printProfileNames выводит имена профилей для автодополнения.
+func printProfileNames(w io.Writer) {
+	profiles, err := loadProfiles()
+	if err != nil {
+		return
+	}
+	for _, name := range profiles.names() {
+		_, _ = fmt.Fprintln(w, name)
+	}
+}
+
+// runComplete выполняет скрытую команду автодополнения.
+func runComplete(args []string) {
+	complete(os.Stdout, args)
+}
@@ -0,0 +1,145 @@
+// Команда txctl — клиент командной строки системы транзакций.
+//
+// Использование:
+//
+//	txctl <команда> [флаги] [аргументы]
+//
+// Команды:
+//   - send — подписать и выполнить перевод;
+//   - balance — баланс кошельков;
+//   - history — последние транзакции;
+//   - wallets list|create — кошельки из локального файла ключей и создание новых;
+//   - profile list|set|use|delete — профили с адресом сервера и учётными данными;
+//   - completion bash|zsh|fish — скрипт автодополнения для командной оболочки.
+//
+// Флаги указываются до аргументов: txctl balance -o json <адрес>.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+// runFunc выполняет команду с разобранными флагами и оставшимися аргументами.
+type runFunc func(ctx context.Context, env *environment, args []string) error
+
+// command описывает команду txctl.
+type command struct {
+	name    string
+	args    string // Описание аргументов для справки
+	summary string
+	// setup регистрирует флаги команды и возвращает функцию её выполнения, использующую их значения.
+	setup func(flags *flag.FlagSet) runFunc
+	// local — команда не обращается к серверу и не принимает общие флаги (-profile, -server, -o, ...).
+	local       bool
+	subcommands []*command
+}
+
+// commands — дерево команд txctl.
+var commands = []*command{
+	sendCommand,
+	balanceCommand,
+	historyCommand,
+	{
+		name:        "wallets",
+		summary:     "кошельки из локального файла ключей",
+		subcommands: []*command{walletsListCommand, walletsCreateCommand},
+	},
+	{
+		name:    "profile",
+		summary: "профили с адресом сервера и учётными данными",
+		subcommands: []*command{
+			profileListCommand, profileSetCommand, profileUseCommand, profileDeleteCommand,
+		},
+	},
+	completionCommand,
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("txctl: ")
+
+	if len(os.Args) > 1 && os.Args[1] == completeCommand {
+		runComplete(os.Args[2:])
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := execute(ctx, commands, "txctl", os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		log.Fatal(err)
+	}
+}
+
+// execute находит команду по первому аргументу, разбирает её флаги и выполняет её.
+func execute(ctx context.Context, commands []*command, path string, args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		printUsage(path, commands)
+		return flag.ErrHelp
+	}
+
+	cmd := findCommand(commands, args[0])
+	if cmd == nil {
+		printUsage(path, commands)
+		return fmt.Errorf("unknown command %q", strings.TrimSpace(path+" "+args[0]))
+	}
+	path += " " + cmd.name
+	if len(cmd.subcommands) > 0 {
+		return execute(ctx, cmd.subcommands, path, args[1:])
+	}
+
+	flags := flag.NewFlagSet(path, flag.ContinueOnError)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintf(flags.Output(), "Usage: %s [flags] %s\n\n%s\n\nFlags:\n", path, cmd.args, cmd.summary)
+		flags.PrintDefaults()
+	}
+	var options *globalOptions
+	if !cmd.local {
+		options = registerGlobalOptions(flags)
+	}
+	run := cmd.setup(flags)
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	env, err := newEnvironment(options)
+	if err != nil {
+		return err
+	}
+	if options != nil && options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.timeout)
+		defer cancel()
+	}
+	return run(ctx, env, flags.Args())
+}
+
+// findCommand возвращает команду с указанным именем или nil.
+func findCommand(commands []*command, name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// printUsage выводит список команд.
+func printUsage(path string, commands []*command) {
+	out := os.Stderr
+	_, _ = fmt.Fprintf(out, "Usage: %s <command> [flags] [arguments]\n\nCommands:\n", path)
+	for _, cmd := range commands {
+		_, _ = fmt.Fprintf(out, "  %-12s %s\n", cmd.name, cmd.summary)
+	}
+	_, _ = fmt.Fprintf(out, "\nRun '%s <command> -h' for the flags of a command.\n", path)
+}
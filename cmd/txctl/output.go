@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Форматы вывода.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// printer выводит результат команды таблицей или JSON.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+// newPrinter создаёт printer для формата outputTable или outputJSON.
+func newPrinter(w io.Writer, format string) *printer {
+	return &printer{w: w, format: format}
+}
+
+// print выводит value в формате JSON или строки rows таблицей с заголовком header.
+func (p *printer) print(value any, header []string, rows [][]string) error {
+	switch p.format {
+	case outputJSON:
+		encoder := json.NewEncoder(p.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case outputTable:
+		w := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, strings.Join(header, "\t"))
+		for _, row := range rows {
+			_, _ = fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	}
+	return fmt.Errorf("unknown output format %q, expected %s or %s", p.format, outputTable, outputJSON)
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/coffee-realist/infotecs_transaction_system/pkg/client"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultProfile — имя профиля, используемого, если профиль не выбран.
+const defaultProfile = "default"
+
+// defaultServer — адрес сервера, если он не задан ни профилем, ни переменной окружения, ни флагом.
+const defaultServer = "http://localhost:8080"
+
+// profile — адрес сервера и учётные данные, сохранённые под именем.
+type profile struct {
+	Server     string `yaml:"server"`                // Адрес сервера
+	APIKey     string `yaml:"api_key,omitempty"`     // API-ключ
+	Token      string `yaml:"token,omitempty"`       // JWT внутреннего SSO
+	CA         string `yaml:"ca,omitempty"`          // Корневые сертификаты для проверки сервера (PEM)
+	Cert       string `yaml:"cert,omitempty"`        // Клиентский сертификат (mTLS)
+	Key        string `yaml:"key,omitempty"`         // Закрытый ключ клиентского сертификата
+	WalletKeys string `yaml:"wallet_keys,omitempty"` // Файл закрытых ключей кошельков
+}
+
+// profileFile — файл профилей txctl.
+type profileFile struct {
+	Current  string             `yaml:"current,omitempty"` // Профиль по умолчанию
+	Profiles map[string]profile `yaml:"profiles"`
+
+	path string
+}
+
+// profilesPath возвращает путь к файлу профилей: TXCTL_CONFIG или <каталог настроек>/txctl/config.yaml.
+func profilesPath() (string, error) {
+	if path := os.Getenv("TXCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "txctl", "config.yaml"), nil
+}
+
+// loadProfiles читает файл профилей. Отсутствующий файл считается пустым.
+func loadProfiles() (*profileFile, error) {
+	path, err := profilesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	file := &profileFile{Profiles: map[string]profile{}, path: path}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if file.Profiles == nil {
+		file.Profiles = map[string]profile{}
+	}
+	return file, nil
+}
+
+// save записывает файл профилей с правами 0600: профили содержат учётные данные.
+func (f *profileFile) save() error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(f.path, data, 0o600)
+}
+
+// names возвращает имена профилей по алфавиту.
+func (f *profileFile) names() []string {
+	return slices.Sorted(maps.Keys(f.Profiles))
+}
+
+// globalOptions — общие флаги команд, обращающихся к серверу.
+type globalOptions struct {
+	profile    string
+	server     string
+	apiKey     string
+	token      string
+	walletKeys string
+	output     string
+	timeout    time.Duration
+}
+
+// registerGlobalOptions регистрирует общие флаги. Значения по умолчанию берутся из переменных окружения.
+func registerGlobalOptions(flags *flag.FlagSet) *globalOptions {
+	options := &globalOptions{}
+	flags.StringVar(&options.profile, "profile", os.Getenv("TXCTL_PROFILE"), "профиль (по умолчанию текущий, см. profile use)")
+	flags.StringVar(&options.server, "server", os.Getenv("TXCTL_SERVER"), "адрес сервера, переопределяет профиль")
+	flags.StringVar(&options.apiKey, "api-key", os.Getenv("TXCTL_API_KEY"), "API-ключ, переопределяет профиль")
+	flags.StringVar(&options.token, "token", os.Getenv("TXCTL_TOKEN"), "JWT, переопределяет профиль")
+	flags.StringVar(&options.walletKeys, "wallet-keys", os.Getenv("TXCTL_WALLET_KEYS"), "файл закрытых ключей кошельков, переопределяет профиль")
+	flags.StringVar(&options.output, "o", outputTable, "формат вывода: table или json")
+	flags.DurationVar(&options.timeout, "timeout", 30*time.Second, "время на выполнение команды")
+	return options
+}
+
+// environment — параметры выполнения команды: выбранный профиль с учётом флагов и формат вывода.
+type environment struct {
+	profileName string
+	profile     profile
+	profiles    *profileFile
+	out         *printer
+	client      *client.Client
+}
+
+// newEnvironment загружает профили и применяет к выбранному профилю общие флаги.
+// Для команд без общих флагов (options == nil) загружаются только профили.
+func newEnvironment(options *globalOptions) (*environment, error) {
+	profiles, err := loadProfiles()
+	if err != nil {
+		return nil, err
+	}
+	env := &environment{profiles: profiles, out: newPrinter(os.Stdout, outputTable)}
+	if options == nil {
+		return env, nil
+	}
+
+	if options.output != outputTable && options.output != outputJSON {
+		return nil, fmt.Errorf("unknown output format %q, expected %s or %s", options.output, outputTable, outputJSON)
+	}
+	env.out = newPrinter(os.Stdout, options.output)
+
+	env.profileName = options.profile
+	if env.profileName == "" {
+		env.profileName = profiles.Current
+	}
+	if env.profileName == "" {
+		env.profileName = defaultProfile
+	}
+	selected, ok := profiles.Profiles[env.profileName]
+	if !ok && options.profile != "" {
+		return nil, fmt.Errorf("unknown profile %q", options.profile)
+	}
+
+	if options.server != "" {
+		selected.Server = options.server
+	}
+	if selected.Server == "" {
+		selected.Server = defaultServer
+	}
+	if options.apiKey != "" || options.token != "" {
+		selected.APIKey, selected.Token = options.apiKey, options.token
+	}
+	if options.walletKeys != "" {
+		selected.WalletKeys = options.walletKeys
+	}
+	if selected.WalletKeys == "" {
+		dir := filepath.Dir(profiles.path)
+		selected.WalletKeys = filepath.Join(dir, "wallets-"+env.profileName+".json")
+	}
+	env.profile = selected
+	return env, nil
+}
+
+// api возвращает клиент API для выбранного профиля.
+func (e *environment) api() (*client.Client, error) {
+	if e.client != nil {
+		return e.client, nil
+	}
+
+	httpClient, err := e.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	options := []client.Option{client.WithHTTPClient(httpClient), client.WithUserAgent("txctl")}
+	if e.profile.APIKey != "" {
+		options = append(options, client.WithAPIKey(e.profile.APIKey))
+	}
+	if e.profile.Token != "" {
+		options = append(options, client.WithBearerToken(e.profile.Token))
+	}
+
+	e.client, err = client.New(e.profile.Server, options...)
+	return e.client, err
+}
+
+// httpClient создаёт http.Client с корневыми сертификатами и клиентским сертификатом профиля.
+// Время выполнения запросов ограничивается контекстом команды (флаг -timeout).
+func (e *environment) httpClient() (*http.Client, error) {
+	if e.profile.CA == "" && e.profile.Cert == "" {
+		return &http.Client{}, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if e.profile.CA != "" {
+		pem, err := os.ReadFile(e.profile.CA)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", e.profile.CA)
+		}
+	}
+	if e.profile.Cert != "" {
+		certificate, err := tls.LoadX509KeyPair(e.profile.Cert, e.profile.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport}, nil
+}
+
+var profileListCommand = &command{
+	name:    "list",
+	summary: "вывести профили",
+	local:   true,
+	setup: func(flags *flag.FlagSet) runFunc {
+		output := flags.String("o", outputTable, "формат вывода: table или json")
+		return func(_ context.Context, env *environment, _ []string) error {
+			// Учётные данные не выводятся: только способ аутентификации.
+			type profileInfo struct {
+				Name    string `json:"name"`
+				Current bool   `json:"current"`
+				Server  string `json:"server"`
+				Auth    string `json:"auth"`
+			}
+
+			var infos []profileInfo
+			var rows [][]string
+			for _, name := range env.profiles.names() {
+				p := env.profiles.Profiles[name]
+				info := profileInfo{Name: name, Current: name == env.profiles.Current, Server: p.Server, Auth: p.credentials()}
+				current := ""
+				if info.Current {
+					current = "*"
+				}
+				infos = append(infos, info)
+				rows = append(rows, []string{current, name, p.Server, info.Auth})
+			}
+			return newPrinter(os.Stdout, *output).print(infos, []string{"CURRENT", "NAME", "SERVER", "AUTH"}, rows)
+		}
+	},
+}
+
+var profileSetCommand = &command{
+	name:    "set",
+	args:    "<name>",
+	summary: "создать или изменить профиль; заданные флаги заменяют значения профиля",
+	local:   true,
+	setup: func(flags *flag.FlagSet) runFunc {
+		var values profile
+		flags.StringVar(&values.Server, "server", "", "адрес сервера, например https://transactions.example.com")
+		flags.StringVar(&values.APIKey, "api-key", "", "API-ключ")
+		flags.StringVar(&values.Token, "token", "", "JWT внутреннего SSO")
+		flags.StringVar(&values.CA, "ca", "", "корневые сертификаты для проверки сервера (PEM)")
+		flags.StringVar(&values.Cert, "cert", "", "клиентский сертификат (mTLS)")
+		flags.StringVar(&values.Key, "key", "", "закрытый ключ клиентского сертификата")
+		flags.StringVar(&values.WalletKeys, "wallet-keys", "", "файл закрытых ключей кошельков")
+		use := flags.Bool("use", false, "сделать профиль текущим")
+		return func(_ context.Context, env *environment, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("expected profile name")
+			}
+			name := args[0]
+			p := env.profiles.Profiles[name]
+			flags.Visit(func(f *flag.Flag) {
+				switch f.Name {
+				case "server":
+					p.Server = values.Server
+				case "api-key":
+					p.APIKey = values.APIKey
+				case "token":
+					p.Token = values.Token
+				case "ca":
+					p.CA = values.CA
+				case "cert":
+					p.Cert = values.Cert
+				case "key":
+					p.Key = values.Key
+				case "wallet-keys":
+					p.WalletKeys = values.WalletKeys
+				}
+			})
+			env.profiles.Profiles[name] = p
+			if *use || env.profiles.Current == "" {
+				env.profiles.Current = name
+			}
+			if err := env.profiles.save(); err != nil {
+				return err
+			}
+			fmt.Printf("Saved profile %s to %s\n", name, env.profiles.path)
+			return nil
+		}
+	},
+}
+
+var profileUseCommand = &command{
+	name:    "use",
+	args:    "<name>",
+	summary: "сделать профиль текущим",
+	local:   true,
+	setup: func(*flag.FlagSet) runFunc {
+		return func(_ context.Context, env *environment, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("expected profile name")
+			}
+			if _, ok := env.profiles.Profiles[args[0]]; !ok {
+				return fmt.Errorf("unknown profile %q", args[0])
+			}
+			env.profiles.Current = args[0]
+			return env.profiles.save()
+		}
+	},
+}
+
+var profileDeleteCommand = &command{
+	name:    "delete",
+	args:    "<name>",
+	summary: "удалить профиль",
+	local:   true,
+	setup: func(*flag.FlagSet) runFunc {
+		return func(_ context.Context, env *environment, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("expected profile name")
+			}
+			if _, ok := env.profiles.Profiles[args[0]]; !ok {
+				return fmt.Errorf("unknown profile %q", args[0])
+			}
+			delete(env.profiles.Profiles, args[0])
+			if env.profiles.Current == args[0] {
+				env.profiles.Current = ""
+			}
+			return env.profiles.save()
+		}
+	},
+}
+
+// credentials описывает способ аутентификации профиля, не раскрывая учётные данные.
+func (p profile) credentials() string {
+	switch {
+	case p.APIKey != "":
+		return "api key"
+	case p.Token != "":
+		return "token"
+	case p.Cert != "":
+		return "certificate"
+	}
+	return "-"
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"runtime"
+	"runtime/debug"
+)
+
+// Сведения о сборке, задаваемые при компоновке:
+//
+//	go build -ldflags "-X main.version=v1.4.0 -X main.commit=$(git rev-parse HEAD)" ./cmd
+var (
+	version = "dev"
+	commit  = ""
+)
+
+// buildInfo возвращает сведения о сборке. Если коммит не задан при компоновке, он берётся
+// из данных системы контроля версий, которые Go встраивает при сборке из репозитория.
+func buildInfo() dto.VersionResp {
+	info := dto.VersionResp{Version: version, Commit: commit, GoVersion: runtime.Version()}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if info.Version == "dev" && build.Main.Version != "" && build.Main.Version != "(devel)" {
+		info.Version = build.Main.Version
+	}
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			info.CommitTime = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
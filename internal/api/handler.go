@@ -2,13 +2,36 @@ package api
 
 import (
 	"github.com/coffee-realist/infotecs_transaction_system/internal/api/handlers"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/api/middleware"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/api/openapi"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/auth"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/config"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/metrics"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/ratelimit"
 	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/tracing"
+	"log/slog"
+	"maps"
 	"net/http"
+	"slices"
+	"time"
 )
 
 // Handler агрегирует зависимости для HTTP-обработчиков API.
 type Handler struct {
-	transferService services.TransferInteractor
+	transferService    services.TransferInteractor
+	authService        services.AuthInteractor
+	adminService       services.AdminInteractor
+	ledgerService      services.LedgerInteractor
+	snapshotService    services.SnapshotInteractor
+	reconcileService   services.ReconcileInteractor
+	projectionService  services.ProjectionInteractor
+	idempotencyService services.IdempotencyInteractor
+	healthService      services.HealthInteractor
+	metrics            *metrics.Metrics
+	http               config.HTTPConfig
+	rateLimit          config.RateLimitConfig
+	rateStore          ratelimit.Store
 }
 
 // NewHandler создаёт новый экземпляр Handler.
@@ -16,11 +39,51 @@ type Handler struct {
 // Аргументы:
 //   - transferService: реализация интерфейса TransferInteractor,
 //     обеспечивающая доступ к операциям с транзакциями и кошельками.
+//   - authService: реализация интерфейса AuthInteractor для аутентификации клиентов.
+//   - adminService: реализация интерфейса AdminInteractor для административных операций.
+//   - ledgerService: реализация интерфейса LedgerInteractor для проверки журнала транзакций.
+//   - snapshotService: реализация интерфейса SnapshotInteractor для снимков балансов.
+//   - reconcileService: реализация интерфейса ReconcileInteractor для сверки балансов.
+//   - projectionService: реализация интерфейса ProjectionInteractor для проекций журнала транзакций.
+//   - idempotencyService: реализация интерфейса IdempotencyInteractor для запросов с ключом идемпотентности.
+//   - healthService: реализация интерфейса HealthInteractor для проверок состояния сервера.
+//   - m: метрики приложения, публикуемые по адресу /metrics.
+//   - httpConfig: параметры обработки запросов: лимит тела, таймауты, CORS, сжатие.
+//   - rateLimit: лимиты запросов клиентов к операциям и переводов с кошелька.
+//   - rateStore: хранилище состояния лимитов запросов.
 //
 // Возвращает:
-//   - Указатель на Handler, содержащий переданный сервис.
-func NewHandler(transferService services.TransferInteractor) *Handler {
-	return &Handler{transferService: transferService}
+//   - Указатель на Handler, содержащий переданные сервисы.
+func NewHandler(
+	transferService services.TransferInteractor,
+	authService services.AuthInteractor,
+	adminService services.AdminInteractor,
+	ledgerService services.LedgerInteractor,
+	snapshotService services.SnapshotInteractor,
+	reconcileService services.ReconcileInteractor,
+	projectionService services.ProjectionInteractor,
+	idempotencyService services.IdempotencyInteractor,
+	healthService services.HealthInteractor,
+	m *metrics.Metrics,
+	httpConfig config.HTTPConfig,
+	rateLimit config.RateLimitConfig,
+	rateStore ratelimit.Store,
+) *Handler {
+	return &Handler{
+		transferService:    transferService,
+		authService:        authService,
+		adminService:       adminService,
+		ledgerService:      ledgerService,
+		snapshotService:    snapshotService,
+		reconcileService:   reconcileService,
+		projectionService:  projectionService,
+		idempotencyService: idempotencyService,
+		healthService:      healthService,
+		metrics:            m,
+		http:               httpConfig,
+		rateLimit:          rateLimit,
+		rateStore:          rateStore,
+	}
 }
 
 // InitRoutes инициализирует маршруты HTTP-сервера.
@@ -29,16 +92,220 @@ func NewHandler(transferService services.TransferInteractor) *Handler {
 //   - POST /api/send — отправка транзакции
 //   - GET /api/transactions — получение последних N транзакций
 //   - GET /api/wallet/{address}/balance — получение баланса кошелька
+//   - GET /api/wallet/{address}/nonce — получение последнего использованного nonce кошелька
+//   - GET /api/wallet/{address}/statement — выписка по кошельку за период (CSV или JSON)
+//   - GET /api/ledger/verify — проверка целостности журнала транзакций
+//   - GET /api/ledger/checkpoints — подписанные контрольные точки журнала
+//   - GET /api/snapshots — снимки балансов с корнями деревьев Меркла
+//   - GET /api/wallet/{address}/proof — доказательство включения баланса кошелька в снимок
+//   - GET /api/wallet/{address}/counterparties — самые частые контрагенты кошелька
+//   - GET /api/stats/daily — обороты за сутки по видам записей журнала
+//   - GET /api/openapi.json — спецификация OpenAPI
+//   - GET /metrics — метрики в формате Prometheus (без аутентификации)
+//   - GET /healthz — проверка работоспособности процесса (без аутентификации)
+//   - GET /readyz — проверка готовности принимать запросы (без аутентификации)
+//   - GET /version — сведения о сборке сервера (без аутентификации)
+//   - POST /admin/mint — эмиссия средств на кошелёк
+//   - POST /admin/adjust — корректировка баланса кошелька
+//   - POST /admin/wallets/{address}/freeze — заморозка кошелька
+//   - POST /admin/wallets/{address}/unfreeze — разморозка кошелька
+//   - POST /admin/wallets/generate — создание новых кошельков
+//   - GET /admin/audit — журнал административных действий
+//   - POST /admin/reconcile — сверка балансов кошельков с журналом транзакций
+//   - GET /admin/projections — состояние проекций журнала транзакций
+//   - POST /admin/projections/{name}/rebuild — перестроение проекции с нуля
+//
+// Каждый маршрут API описывается в спецификации, и входящие запросы проверяются на соответствие ей.
+// Для каждого маршрута API учитываются количество и длительность запросов по кодам ответа,
+// а каждый запрос записывается спаном трассировки "<метод> <шаблон пути>", продолжающим
+// трассировку клиента из заголовка traceparent.
+// Каждому запросу назначается идентификатор (заголовок X-Request-ID), который попадает в ответы с ошибками
+// и во все записи журнала, сделанные при обработке запроса. По завершении запроса в журнал записывается
+// запись доступа с кодом ответа, длительностью и адресами затронутых кошельков. Запросы проверок
+// состояния (/healthz, /readyz, /version), которые оркестратор выполняет каждые несколько секунд,
+// в журнал доступа не записываются.
+//
+// Маршруты API требуют аутентификации по заголовку X-API-Key или JWT в заголовке Authorization:
+// операции чтения — области доступа read, перевод — области transfer и права на списание
+// с кошелька отправителя. Маршруты /admin требуют области доступа admin (роль SSO admin
+// или API-ключ с этой областью); каждое действие требует основания и записывается в журнал.
+//
+// Запросы к API проходят цепочку middleware (внешние — первыми): идентификатор запроса, журнал доступа,
+// перехват паник (ответ HTTP 500 вместо разрыва соединения), CORS, сжатие gzip, ограничение размера
+// тела (HTTP 413), лимит запросов с IP-адреса и аутентификация. Время обработки запроса к каждой
+// операции ограничено общим таймаутом http.timeout или таймаутом операции из http.route_timeouts.
+//
+// Частота запросов клиента к каждой операции ограничена лимитом rate_limit.client или лимитом
+// операции из rate_limit.routes, а частота переводов с одного кошелька — лимитом rate_limit.wallet
+// независимо от клиента. Все запросы с одного IP-адреса до аутентификации ограничены лимитом
+// rate_limit.ip, поэтому ограничиваются и запросы с неверными учётными данными.
+// При превышении лимита возвращается HTTP 429 с заголовком Retry-After.
+//
+// Операции POST принимают заголовок Idempotency-Key: ответ на первый запрос клиента с ключом сохраняется
+// на время idempotency.ttl и возвращается на повторные запросы с ним без повторного выполнения операции,
+// поэтому клиент может безопасно повторить запрос после сбоя соединения.
 //
 // Возвращает:
 //   - http.Handler с зарегистрированными маршрутами.
 func (h *Handler) InitRoutes() http.Handler {
 	mux := http.NewServeMux()
+	spec := openapi.New(apiInfo, handlers.ValidationError)
+	spec.AddSecurityScheme(apiKeyScheme, openapi.SecurityScheme{
+		Type:        "apiKey",
+		Description: "API-ключ, выданный командой apikey create",
+		Name:        middleware.APIKeyHeader,
+		In:          "header",
+	})
+	spec.AddSecurityScheme(bearerScheme, openapi.SecurityScheme{
+		Type:         "http",
+		Description:  "JWT внутреннего SSO; роли viewer, operator, admin",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+	})
+
+	// Регистрируем обработчики с новым синтаксисом.
+	// Порядок проверок: область доступа → соответствие спецификации → обработчик.
+	registered := make(map[string]bool)
+	route := func(op openapi.Operation, scope auth.Scope, handler http.Handler) {
+		registered[op.ID] = true
+		op.Security = []string{apiKeyScheme, bearerScheme}
+		limit := h.clientLimit(op.ID)
+		if !limit.Unlimited() || !h.ipLimit().Unlimited() {
+			op.Responses = maps.Clone(op.Responses)
+			op.Responses[http.StatusTooManyRequests] = problem("Превышен лимит запросов")
+		}
+		if op.Method == http.MethodPost {
+			op = idempotent(op)
+			handler = middleware.Idempotency(h.idempotencyService, op.ID, handlers.HTTPError)(handler)
+		}
+		requireScope := middleware.RequireScope(scope, handlers.HTTPError)
+		handler = requireScope(spec.Register(op, handler))
+		handler = middleware.LimitClient(h.rateStore, op.ID, limit, handlers.HTTPError)(handler)
+		handler = logPathWallet(handler)
+		handler = tracing.InstrumentHandler(op.Method, op.Path, h.metrics.InstrumentHandler(op.Path, handler))
+		mux.Handle(op.Pattern(), middleware.Timeout(h.timeout(op.ID))(handler))
+	}
+	authorizeDebit := middleware.AuthorizeDebit(handlers.HTTPError)
+	limitWallet := middleware.LimitWallet(h.rateStore, h.walletLimit(), handlers.HTTPError)
+
+	route(sendOperation, auth.ScopeTransfer, authorizeDebit(limitWallet(handlers.Send(h.transferService))))
+	route(getTransactionsOperation, auth.ScopeRead, handlers.GetTransactions(h.transferService))
+	route(getBalanceOperation, auth.ScopeRead, handlers.GetBalance(h.transferService))
+	route(getNonceOperation, auth.ScopeRead, handlers.GetNonce(h.transferService))
+	route(getStatementOperation, auth.ScopeRead, handlers.GetStatement(h.transferService))
+	route(verifyLedgerOperation, auth.ScopeRead, handlers.VerifyLedger(h.ledgerService))
+	route(getCheckpointsOperation, auth.ScopeRead, handlers.GetCheckpoints(h.ledgerService))
+	route(getSnapshotsOperation, auth.ScopeRead, handlers.GetSnapshots(h.snapshotService))
+	route(getBalanceProofOperation, auth.ScopeRead, handlers.GetBalanceProof(h.snapshotService))
+	route(getCounterpartiesOperation, auth.ScopeRead, handlers.GetCounterparties(h.projectionService))
+	route(getDailyVolumesOperation, auth.ScopeRead, handlers.GetDailyVolumes(h.projectionService))
+
+	route(mintOperation, auth.ScopeAdmin, handlers.Mint(h.adminService))
+	route(adjustOperation, auth.ScopeAdmin, handlers.Adjust(h.adminService))
+	route(freezeWalletOperation, auth.ScopeAdmin, handlers.SetWalletFrozen(h.adminService, true))
+	route(unfreezeWalletOperation, auth.ScopeAdmin, handlers.SetWalletFrozen(h.adminService, false))
+	route(generateWalletsOperation, auth.ScopeAdmin, handlers.GenerateWallets(h.adminService))
+	route(getAuditLogOperation, auth.ScopeAdmin, handlers.GetAuditLog(h.adminService))
+	route(reconcileOperation, auth.ScopeAdmin, handlers.Reconcile(h.reconcileService))
+	route(getProjectionsOperation, auth.ScopeAdmin, handlers.GetProjections(h.projectionService))
+	route(rebuildProjectionOperation, auth.ScopeAdmin, handlers.RebuildProjection(h.projectionService))
+
+	mux.Handle("GET /api/openapi.json", spec)
+	mux.Handle("GET /metrics", h.metrics.Handler())
+
+	for _, id := range slices.Sorted(maps.Keys(h.http.RouteTimeouts)) {
+		if !registered[id] {
+			slog.Warn("unknown operation in http.route_timeouts", "operation", id)
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(h.rateLimit.Routes)) {
+		if !registered[id] {
+			slog.Warn("unknown operation in rate_limit.routes", "operation", id)
+		}
+	}
+
+	api := middleware.Chain(
+		middleware.RequestID,
+		middleware.AccessLog,
+		middleware.Recover(handlers.HTTPError),
+		middleware.CORS(h.http.CORSOrigins),
+		middleware.Gzip(h.http.Gzip),
+		middleware.LimitBody(int64(h.http.MaxBodyBytes), handlers.HTTPError),
+		middleware.LimitIP(h.rateStore, h.ipLimit(), handlers.HTTPError),
+		middleware.Authenticate(h.authService, handlers.HTTPError),
+	)
+	root := http.NewServeMux()
+	root.Handle("GET /healthz", handlers.Healthz())
+	root.Handle("GET /readyz", handlers.Readyz(h.healthService))
+	root.Handle("GET /version", handlers.Version(h.healthService))
+	root.Handle("/", api(mux))
+	return root
+}
 
-	// Регистрируем обработчики с новым синтаксисом
-	mux.HandleFunc("POST /api/send", handlers.Send(h.transferService))
-	mux.HandleFunc("GET /api/transactions", handlers.GetTransactions(h.transferService))
-	mux.HandleFunc("GET /api/wallet/{address}/balance", handlers.GetBalance(h.transferService))
+// timeout возвращает время на обработку запроса к операции: из http.route_timeouts или общее http.timeout.
+func (h *Handler) timeout(operationID string) time.Duration {
+	if timeout, ok := h.http.RouteTimeouts[operationID]; ok {
+		return timeout
+	}
+	return h.http.Timeout
+}
+
+// clientLimit возвращает лимит запросов клиента к операции: из rate_limit.routes или общий rate_limit.client.
+// Если ограничение частоты запросов отключено, лимит снят.
+func (h *Handler) clientLimit(operationID string) ratelimit.Limit {
+	if !h.rateLimit.Enabled {
+		return ratelimit.Limit{}
+	}
+	if limit, ok := h.rateLimit.Routes[operationID]; ok {
+		return rateLimit(limit)
+	}
+	return rateLimit(h.rateLimit.Client)
+}
+
+// walletLimit возвращает лимит переводов с одного кошелька или снятый лимит,
+// если ограничение частоты запросов отключено.
+func (h *Handler) walletLimit() ratelimit.Limit {
+	if !h.rateLimit.Enabled {
+		return ratelimit.Limit{}
+	}
+	return rateLimit(h.rateLimit.Wallet)
+}
+
+// ipLimit возвращает лимит всех запросов с одного IP-адреса или снятый лимит,
+// если ограничение частоты запросов отключено.
+func (h *Handler) ipLimit() ratelimit.Limit {
+	if !h.rateLimit.Enabled {
+		return ratelimit.Limit{}
+	}
+	return rateLimit(h.rateLimit.IP)
+}
+
+// rateLimit преобразует лимит из конфигурации в лимит ratelimit.
+func rateLimit(limit config.RateLimit) ratelimit.Limit {
+	return ratelimit.Limit{Requests: limit.Requests, Period: limit.Period}
+}
+
+// idempotent дополняет описание операции заголовком Idempotency-Key и ответами на ошибки его использования.
+func idempotent(op openapi.Operation) openapi.Operation {
+	op.Params = append(slices.Clone(op.Params), openapi.HeaderParam(middleware.IdempotencyKeyHeader,
+		"Ключ идемпотентности: повторный запрос с тем же ключом получает ответ на первый запрос без повторного выполнения",
+		false, openapi.IdempotencyKeySchema()))
+
+	op.Responses = maps.Clone(op.Responses)
+	if reply, ok := op.Responses[http.StatusConflict]; ok {
+		reply.Description += "; запрос с тем же ключом идемпотентности ещё обрабатывается"
+		op.Responses[http.StatusConflict] = reply
+	} else {
+		op.Responses[http.StatusConflict] = problem("Запрос с тем же ключом идемпотентности ещё обрабатывается")
+	}
+	op.Responses[http.StatusUnprocessableEntity] = problem("Ключ идемпотентности уже использован с другим запросом")
+	return op
+}
 
-	return mux
+// logPathWallet добавляет адрес кошелька из параметра пути address в запись журнала доступа.
+func logPathWallet(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		middleware.LogWallets(r.Context(), r.PathValue("address"))
+		next.ServeHTTP(w, r)
+	})
 }
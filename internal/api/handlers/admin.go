@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/api/middleware"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+)
+
+// Mint обрабатывает HTTP-запрос на эмиссию средств на кошелёк.
+//
+// Декодирует тело запроса в dto.MintReq, вызывает adminService.Mint
+// и возвращает новый баланс кошелька в формате JSON.
+//
+// Параметры:
+//   - adminService: интерфейс административных операций.
+//
+// Пример запроса:
+//
+//	POST 127.0.0.1:8080/admin/mint
+//	{"address": "...", "amount": 50, "reason": "INC-42: compensation"}
+func Mint(adminService services.AdminInteractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.MintReq
+		if !decodeJSON(w, r, &req) {
+			return
+		}
+
+		middleware.LogWallets(r.Context(), req.Address)
+		balanceResp, err := adminService.Mint(r.Context(), req)
+		if err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+		writeJSON(w, r, http.StatusOK, balanceResp)
+	}
+}
+
+// Adjust обрабатывает HTTP-запрос на корректировку баланса кошелька.
+//
+// Декодирует тело запроса в dto.AdjustReq, вызывает adminService.Adjust
+// и возвращает новый баланс кошелька в формате JSON.
+//
+// Параметры:
+//   - adminService: интерфейс административных операций.
+//
+// Пример запроса:
+//
+//	POST 127.0.0.1:8080/admin/adjust
+//	{"address": "...", "amount": -12.5, "reason": "INC-43: duplicate payout"}
+func Adjust(adminService services.AdminInteractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.AdjustReq
+		if !decodeJSON(w, r, &req) {
+			return
+		}
+
+		middleware.LogWallets(r.Context(), req.Address)
+		balanceResp, err := adminService.Adjust(r.Context(), req)
+		if err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+		writeJSON(w, r, http.StatusOK, balanceResp)
+	}
+}
+
+// SetWalletFrozen обрабатывает HTTP-запрос на заморозку или разморозку кошелька.
+//
+// Адрес кошелька берётся из параметра пути "address", основание — из тела запроса.
+//
+// Параметры:
+//   - adminService: интерфейс административных операций.
+//   - frozen: true для заморозки кошелька, false для разморозки.
+//
+// Пример запроса:
+//
+//	POST 127.0.0.1:8080/admin/wallets/{address}/freeze
+//	{"reason": "INC-44: suspected compromise"}
+func SetWalletFrozen(adminService services.AdminInteractor, frozen bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.FreezeReq
+		if !decodeJSON(w, r, &req) {
+			return
+		}
+		req.Address, req.Frozen = r.PathValue("address"), frozen
+
+		statusResp, err := adminService.SetFrozen(r.Context(), req)
+		if err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+		writeJSON(w, r, http.StatusOK, statusResp)
+	}
+}
+
+// GenerateWallets обрабатывает HTTP-запрос на создание новых кошельков.
+//
+// Возвращает HTTP 201 и JSON-массив ключевых пар созданных кошельков.
+// Закрытые ключи в базе не сохраняются и возвращаются только в этом ответе.
+//
+// Параметры:
+//   - adminService: интерфейс административных операций.
+//
+// Пример запроса:
+//
+//	POST 127.0.0.1:8080/admin/wallets/generate
+//	{"count": 5, "reason": "INC-45: onboarding"}
+func GenerateWallets(adminService services.AdminInteractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.GenerateWalletsReq
+		if !decodeJSON(w, r, &req) {
+			return
+		}
+
+		keys, err := adminService.GenerateWallets(r.Context(), req)
+		if err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+		writeJSON(w, r, http.StatusCreated, keys)
+	}
+}
+
+// GetAuditLog обрабатывает HTTP-запрос на получение последних N записей журнала
+// административных действий.
+//
+// Параметры:
+//   - adminService: интерфейс административных операций.
+//
+// Пример запроса:
+//
+//	GET 127.0.0.1:8080/admin/audit?count=20
+func GetAuditLog(adminService services.AdminInteractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		count, err := strconv.Atoi(r.URL.Query().Get("count"))
+		if err != nil || count <= 0 {
+			HTTPError(w, r, http.StatusBadRequest, CodeInvalidRequest, "Invalid count parameter")
+			return
+		}
+
+		entries, err := adminService.GetAuditLog(r.Context(), count)
+		if err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+		writeJSON(w, r, http.StatusOK, entries)
+	}
+}
+
+// Reconcile обрабатывает HTTP-запрос на сверку балансов кошельков с журналом транзакций.
+//
+// Декодирует тело запроса в dto.ReconcileReq, вызывает reconcileService.Reconcile
+// и возвращает отчёт сверки в формате JSON. Найденные расхождения не являются ошибкой запроса,
+// поэтому ответ имеет статус 200.
+//
+// Параметры:
+//   - reconcileService: интерфейс сверки балансов.
+//
+// Пример запроса:
+//
+//	POST 127.0.0.1:8080/admin/reconcile
+//	{"repair": true, "reason": "INC-44: balance drift after manual DB fix"}
+func Reconcile(reconcileService services.ReconcileInteractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.ReconcileReq
+		if !decodeJSON(w, r, &req) {
+			return
+		}
+
+		report, err := reconcileService.Reconcile(r.Context(), req)
+		if err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+		writeJSON(w, r, http.StatusOK, report)
+	}
+}
+
+// writeJSON отправляет ответ в формате JSON с указанным HTTP-статусом.
+func writeJSON(w http.ResponseWriter, r *http.Request, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		HTTPError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to encode response")
+	}
+}
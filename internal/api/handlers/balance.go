@@ -3,6 +3,8 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
 	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
@@ -11,32 +13,78 @@ import (
 // GetBalance возвращает http.HandlerFunc, обрабатывающий запрос на получение баланса кошелька по адресу.
 //
 // Параметры:
-//   - transferService: интерфейс, предоставляющий метод получения баланса.
+//   - transferService: интерфейс, предоставляющий методы получения баланса.
 //
 // Обрабатывает GET-запрос по пути, содержащему параметр "address".
-// В случае успеха возвращает JSON-ответ с балансом, в противном случае — сообщение об ошибке.
+// Необязательные параметры строки запроса задают момент в прошлом, на который вычисляется баланс:
+//   - at — момент времени в формате RFC 3339;
+//   - as_of_tx — идентификатор последней учитываемой транзакции.
 //
-// Пример пути: 127.0.0.1:8080/api/wallet/{address}/balance
+// Параметры взаимоисключающие. В случае успеха возвращает JSON-ответ с балансом,
+// в противном случае — сообщение об ошибке.
+//
+// Пример пути: 127.0.0.1:8080/api/wallet/{address}/balance?at=2025-01-01T00:00:00Z
 func GetBalance(transferService services.TransferInteractor) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Извлекаем адрес из пути
 		address := r.PathValue("address")
 		if address == "" {
-			HTTPError(w, "Wallet address is required", http.StatusBadRequest)
+			HTTPError(w, r, http.StatusBadRequest, CodeInvalidRequest, "Wallet address is required")
 			return
 		}
 
-		// Получаем баланс через сервис
-		balanceResp, err := transferService.GetBalance(dto.BalanceReq{Address: address})
+		query := r.URL.Query()
+		var (
+			balanceResp dto.BalanceResp
+			err         error
+		)
+		if query.Has("at") || query.Has("as_of_tx") {
+			req, ok := parseBalanceAtReq(w, r, address)
+			if !ok {
+				return
+			}
+			balanceResp, err = transferService.GetBalanceAt(r.Context(), req)
+		} else {
+			// Получаем текущий баланс через сервис
+			balanceResp, err = transferService.GetBalance(r.Context(), dto.BalanceReq{Address: address})
+		}
 		if err != nil {
-			handleServiceError(w, err)
+			handleServiceError(w, r, err)
 			return
 		}
 
 		// Формируем ответ
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(balanceResp); err != nil {
-			HTTPError(w, "Failed to encode response", http.StatusInternalServerError)
+			HTTPError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to encode response")
+		}
+	}
+}
+
+// parseBalanceAtReq разбирает параметры запроса баланса на момент в прошлом.
+// При некорректных параметрах отправляет ответ с ошибкой и возвращает false.
+func parseBalanceAtReq(w http.ResponseWriter, r *http.Request, address string) (dto.BalanceAtReq, bool) {
+	query := r.URL.Query()
+	if query.Has("at") && query.Has("as_of_tx") {
+		HTTPError(w, r, http.StatusBadRequest, CodeInvalidRequest, "Parameters at and as_of_tx are mutually exclusive")
+		return dto.BalanceAtReq{}, false
+	}
+
+	req := dto.BalanceAtReq{Address: address}
+	if query.Has("at") {
+		at, err := time.Parse(time.RFC3339, query.Get("at"))
+		if err != nil {
+			HTTPError(w, r, http.StatusBadRequest, CodeInvalidRequest, "Invalid at parameter")
+			return dto.BalanceAtReq{}, false
+		}
+		req.At = &at
+	} else {
+		txID, err := strconv.ParseInt(query.Get("as_of_tx"), 10, 64)
+		if err != nil || txID < 0 {
+			HTTPError(w, r, http.StatusBadRequest, CodeInvalidRequest, "Invalid as_of_tx parameter")
+			return dto.BalanceAtReq{}, false
 		}
+		req.AsOfTx = &txID
 	}
+	return req, true
 }
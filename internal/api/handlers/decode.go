@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/api/middleware"
+)
+
+// decodeJSON строго разбирает JSON-тело запроса в v: неизвестные поля и данные после
+// JSON-значения считаются ошибкой. При ошибке отправляет клиенту ответ HTTP 400
+// (HTTP 413, если тело превышает лимит middleware.LimitBody).
+//
+// Возвращает:
+//   - true, если тело разобрано и обработку запроса можно продолжать.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(v)
+	if err == nil {
+		if extra := decoder.Decode(new(json.RawMessage)); !errors.Is(extra, io.EOF) {
+			err = errors.New("unexpected data after JSON value")
+		}
+	}
+	if err == nil {
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		HTTPError(w, r, http.StatusRequestEntityTooLarge, middleware.CodeRequestTooLarge,
+			fmt.Sprintf("Request body must not exceed %d bytes", tooLarge.Limit))
+	case errors.Is(err, io.EOF):
+		HTTPError(w, r, http.StatusBadRequest, CodeInvalidRequest, "Request body is empty")
+	default:
+		HTTPError(w, r, http.StatusBadRequest, CodeInvalidRequest, "Invalid request payload: "+err.Error())
+	}
+	return false
+}
@@ -1,47 +1,175 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/api/middleware"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/api/openapi"
 	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
 	"github.com/joomcode/errorx"
-	"log"
+	"log/slog"
 	"net/http"
 )
 
-// HTTPError отправляет HTTP-ответ с указанным статусом и сообщением об ошибке в формате JSON.
+// ProblemContentType — тип содержимого ответов с ошибками (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// Стабильные машинные коды ошибок API. Клиенты должны ориентироваться на них, а не на текст ошибки.
+const (
+	CodeInvalidRequest       = "invalid_request"
+	CodeValidationFailed     = "validation_failed"
+	CodeSameWallet           = "same_wallet"
+	CodeInsufficientFunds    = "insufficient_funds"
+	CodeInvalidSignature     = "invalid_signature"
+	CodeInvalidNonce         = "invalid_nonce"
+	CodeWalletFrozen         = "wallet_frozen"
+	CodeWalletNotFound       = "wallet_not_found"
+	CodeTransactionsNotFound = "transactions_not_found"
+	CodeSnapshotNotFound     = "snapshot_not_found"
+	CodeProjectionNotFound   = "projection_not_found"
+	CodeNotFound             = "not_found"
+	CodeTimeout              = "timeout"
+	CodeInternal             = "internal_error"
+)
+
+// Problem представляет тело ответа с ошибкой в формате application/problem+json (RFC 7807).
+type Problem struct {
+	Type      string               `json:"type"`                 // URI типа проблемы
+	Title     string               `json:"title"`                // Краткое описание типа проблемы
+	Status    int                  `json:"status"`               // HTTP-статус ответа
+	Detail    string               `json:"detail,omitempty"`     // Описание конкретного случая
+	Instance  string               `json:"instance,omitempty"`   // Путь запроса, вызвавшего ошибку
+	Code      string               `json:"code"`                 // Стабильный машинный код ошибки
+	RequestID string               `json:"request_id,omitempty"` // Идентификатор запроса
+	Errors    []openapi.FieldError `json:"errors,omitempty"`     // Ошибки валидации отдельных полей
+}
+
+// problemKind связывает тип ошибки errorx с машинным кодом и HTTP-статусом.
+type problemKind struct {
+	errType *errorx.Type
+	code    string
+	status  int
+}
+
+// problemKinds перечисляет известные типы ошибок сервисного слоя и слоя хранения.
+// Порядок важен: более специфичные типы должны идти раньше своих родителей.
+var problemKinds = []problemKind{
+	{services.ErrSameWallet, CodeSameWallet, http.StatusBadRequest},
+	{services.ErrInsufficientFunds, CodeInsufficientFunds, http.StatusBadRequest},
+	{services.ErrInvalidSignature, CodeInvalidSignature, http.StatusForbidden},
+	{services.ErrInvalidNonce, CodeInvalidNonce, http.StatusConflict},
+	{services.ErrWalletFrozen, CodeWalletFrozen, http.StatusConflict},
+	{services.ErrIdempotencyKeyReused, middleware.CodeIdempotencyKeyReused, http.StatusUnprocessableEntity},
+	{services.ErrIdempotencyKeyInUse, middleware.CodeIdempotencyKeyInUse, http.StatusConflict},
+	{services.ErrUnauthenticated, middleware.CodeUnauthenticated, http.StatusUnauthorized},
+	{services.ErrForbidden, middleware.CodeForbidden, http.StatusForbidden},
+	{services.ErrProjectionNotFound, CodeProjectionNotFound, http.StatusNotFound},
+	{services.ErrInvalid, CodeInvalidRequest, http.StatusBadRequest},
+	{storage.ErrWalletNotFound, CodeWalletNotFound, http.StatusNotFound},
+	{storage.ErrTransactionsNotFound, CodeTransactionsNotFound, http.StatusNotFound},
+	{storage.ErrSnapshotNotFound, CodeSnapshotNotFound, http.StatusNotFound},
+	{storage.ErrNotFound, CodeNotFound, http.StatusNotFound},
+}
+
+// HTTPError отправляет ответ об ошибке в формате application/problem+json.
 //
 // Параметры:
 //   - w: http.ResponseWriter для записи ответа.
-//   - message: текст ошибки, который будет отправлен в теле ответа.
+//   - r: входящий запрос; из него берутся путь и идентификатор запроса.
 //   - statusCode: HTTP-статус, соответствующий ошибке.
-func HTTPError(w http.ResponseWriter, message string, statusCode int) {
-	log.Printf("HTTP %d: %s", statusCode, message)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(map[string]string{"error": message})
+//   - code: стабильный машинный код ошибки.
+//   - detail: описание ошибки, которое будет отправлено клиенту.
+func HTTPError(w http.ResponseWriter, r *http.Request, statusCode int, code, detail string) {
+	writeProblem(w, r, Problem{Status: statusCode, Code: code, Detail: detail})
+}
+
+// ValidationError отправляет ответ HTTP 400 для запроса, не прошедшего проверку по спецификации OpenAPI.
+//
+// Параметры:
+//   - w: http.ResponseWriter для записи ответа.
+//   - r: входящий запрос.
+//   - errs: список нарушений спецификации, передаваемый клиенту в поле errors.
+func ValidationError(w http.ResponseWriter, r *http.Request, errs []openapi.FieldError) {
+	writeProblem(w, r, Problem{
+		Status: http.StatusBadRequest,
+		Code:   CodeValidationFailed,
+		Detail: "Request does not match the API specification",
+		Errors: errs,
+	})
 }
 
 // handleServiceError обрабатывает ошибку, возвращаемую сервисом, и преобразует её в HTTP-ответ.
 //
-// Ошибка анализируется с использованием библиотеки errorx и сопоставляется с типами:
+// Код и статус определяются по первому типу из problemKinds, найденному в цепочке ошибок errorx.
+// Если время на обработку запроса истекло (см. middleware.Timeout), отправляется HTTP 503 с кодом timeout.
+// Если конкретный тип не найден, используются трейты:
 //   - services.IsNotFoundErr → HTTP 404
 //   - services.IsClientErr   → HTTP 400
-//   - иначе                  → HTTP 500
+//   - иначе (в том числе для ошибок не из errorx) → HTTP 500 без раскрытия деталей
 //
 // Параметры:
 //   - w: http.ResponseWriter для записи ответа.
+//   - r: входящий запрос.
 //   - err: ошибка, возвращённая из сервисного слоя.
-func handleServiceError(w http.ResponseWriter, err error) {
+func handleServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(r.Context().Err(), context.DeadlineExceeded) {
+		slog.WarnContext(r.Context(), "request timed out", "error", err)
+		HTTPError(w, r, http.StatusServiceUnavailable, CodeTimeout, "Request timed out")
+		return
+	}
+
 	var errorxErr *errorx.Error
-	if errors.As(err, &errorxErr) {
-		switch {
-		case services.IsNotFoundErr(errorxErr):
-			HTTPError(w, errorxErr.Error(), http.StatusNotFound)
-		case services.IsClientErr(errorxErr):
-			HTTPError(w, errorxErr.Error(), http.StatusBadRequest)
-		default:
-			HTTPError(w, "Internal server error", http.StatusInternalServerError)
+	if !errors.As(err, &errorxErr) {
+		slog.ErrorContext(r.Context(), "unexpected error", "error", err)
+		HTTPError(w, r, http.StatusInternalServerError, CodeInternal, "Internal server error")
+		return
+	}
+
+	if kind, cause, ok := findProblemKind(errorxErr); ok {
+		HTTPError(w, r, kind.status, kind.code, cause.Message())
+		return
+	}
+
+	switch {
+	case services.IsNotFoundErr(errorxErr):
+		HTTPError(w, r, http.StatusNotFound, CodeNotFound, errorxErr.Message())
+	case services.IsClientErr(errorxErr):
+		HTTPError(w, r, http.StatusBadRequest, CodeInvalidRequest, errorxErr.Message())
+	default:
+		slog.ErrorContext(r.Context(), "internal error", "error", fmt.Sprintf("%+v", errorxErr))
+		HTTPError(w, r, http.StatusInternalServerError, CodeInternal, "Internal server error")
+	}
+}
+
+// findProblemKind ищет в цепочке причин ошибки первый известный тип из problemKinds.
+//
+// Возвращает:
+//   - найденное описание типа;
+//   - ошибку цепочки, имеющую этот тип (её сообщение передаётся клиенту);
+//   - признак успешного поиска.
+func findProblemKind(err *errorx.Error) (problemKind, *errorx.Error, bool) {
+	for current := err; current != nil; current = errorx.Cast(current.Cause()) {
+		for _, kind := range problemKinds {
+			if current.IsOfType(kind.errType) {
+				return kind, current, true
+			}
 		}
 	}
+	return problemKind{}, nil, false
+}
+
+// writeProblem дополняет описание проблемы общими полями и отправляет его клиенту.
+func writeProblem(w http.ResponseWriter, r *http.Request, problem Problem) {
+	problem.Type = "urn:problem-type:" + problem.Code
+	problem.Title = http.StatusText(problem.Status)
+	problem.Instance = r.URL.Path
+	problem.RequestID = middleware.RequestIDFromContext(r.Context())
+
+	slog.InfoContext(r.Context(), "request failed", "status", problem.Status, "code", problem.Code, "detail", problem.Detail)
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
 }
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/api/middleware"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
+)
+
+// serviceError отправляет ответ на ошибку сервиса и возвращает HTTP-статус и тело ответа.
+func serviceError(t *testing.T, r *http.Request, err error) (int, Problem) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handleServiceError(rec, r, err)
+
+	if contentType := rec.Header().Get("Content-Type"); contentType != ProblemContentType {
+		t.Errorf("Content-Type = %q, want %q", contentType, ProblemContentType)
+	}
+	var problem Problem
+	if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+	return rec.Code, problem
+}
+
+func TestHandleServiceError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		detail string
+	}{
+		{"insufficient funds", services.ErrInsufficientFunds.New("insufficient funds"),
+			http.StatusBadRequest, CodeInsufficientFunds, "insufficient funds"},
+		{"invalid nonce", services.ErrInvalidNonce.New("nonce already used"),
+			http.StatusConflict, CodeInvalidNonce, "nonce already used"},
+		{"idempotency key reused", services.ErrIdempotencyKeyReused.New("key used with another request"),
+			http.StatusUnprocessableEntity, middleware.CodeIdempotencyKeyReused, "key used with another request"},
+		{"idempotency key in use", services.ErrIdempotencyKeyInUse.New("request is being processed"),
+			http.StatusConflict, middleware.CodeIdempotencyKeyInUse, "request is being processed"},
+		{"forbidden", services.ErrForbidden.New("role admin required"),
+			http.StatusForbidden, middleware.CodeForbidden, "role admin required"},
+		{"wrapped wallet not found", services.ErrFailedToGet.Wrap(storage.ErrWalletNotFound.New("wallet not found"), "failed to get balance"),
+			http.StatusNotFound, CodeWalletNotFound, "wallet not found"},
+		{"wallet not found", storage.ErrWalletNotFound.New("wallet not found"),
+			http.StatusNotFound, CodeWalletNotFound, "wallet not found"},
+		{"snapshot not found", storage.ErrSnapshotNotFound.New("snapshot not found"),
+			http.StatusNotFound, CodeSnapshotNotFound, "snapshot not found"},
+		{"unknown not found error", storage.ErrAPIKeyNotFound.New("api key not found"),
+			http.StatusNotFound, CodeNotFound, "api key not found"},
+		{"internal error", services.ErrFailedToInsert.New("database is locked"),
+			http.StatusInternalServerError, CodeInternal, "Internal server error"},
+		{"plain error", errors.New("database is locked"),
+			http.StatusInternalServerError, CodeInternal, "Internal server error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, problem := serviceError(t, httptest.NewRequest(http.MethodPost, "/api/send", nil), tt.err)
+			if status != tt.status || problem.Status != tt.status {
+				t.Errorf("status %d (body %d), want %d", status, problem.Status, tt.status)
+			}
+			if problem.Code != tt.code {
+				t.Errorf("code %q, want %q", problem.Code, tt.code)
+			}
+			if problem.Type != "urn:problem-type:"+tt.code {
+				t.Errorf("type %q does not match the code", problem.Type)
+			}
+			if problem.Detail != tt.detail {
+				t.Errorf("detail %q, want %q", problem.Detail, tt.detail)
+			}
+			if problem.Instance != "/api/send" {
+				t.Errorf("instance %q, want the request path", problem.Instance)
+			}
+		})
+	}
+}
+
+func TestHandleServiceErrorTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	r := httptest.NewRequest(http.MethodGet, "/api/wallet/aa/balance", nil).WithContext(ctx)
+	status, problem := serviceError(t, r, services.ErrFailedToGet.New("context deadline exceeded"))
+	if status != http.StatusServiceUnavailable || problem.Code != CodeTimeout {
+		t.Errorf("status %d, code %q, want %d %q", status, problem.Code, http.StatusServiceUnavailable, CodeTimeout)
+	}
+}
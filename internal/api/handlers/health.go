@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+)
+
+// Healthz обрабатывает проверку работоспособности процесса: отвечает HTTP 200, пока процесс
+// обрабатывает запросы, не обращаясь к базе данных.
+//
+// Пример ответа:
+//
+//	HTTP/1.1 200 OK
+//	{"status": "ok"}
+func Healthz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, r, http.StatusOK, dto.HealthResp{Status: dto.HealthStatusOK})
+	}
+}
+
+// Readyz обрабатывает проверку готовности сервера принимать запросы (см. HealthInteractor.Ready).
+// Возвращает HTTP 200, если пройдены все проверки, иначе HTTP 503 с результатами проверок.
+//
+// Параметры:
+//   - healthService: интерфейс проверок состояния сервера.
+//
+// Пример ответа:
+//
+//	HTTP/1.1 503 Service Unavailable
+//	{"status": "fail", "checks": [{"name": "shutdown", "status": "fail", "detail": "server is shutting down"}, ...]}
+func Readyz(healthService services.HealthInteractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp := healthService.Ready(r.Context())
+		status := http.StatusOK
+		if resp.Status != dto.HealthStatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, r, status, resp)
+	}
+}
+
+// Version возвращает сведения о сборке сервера: версию, коммит и версию Go.
+//
+// Параметры:
+//   - healthService: интерфейс проверок состояния сервера.
+//
+// Пример ответа:
+//
+//	HTTP/1.1 200 OK
+//	{"version": "v1.4.0", "commit": "2dfeb8f...", "go_version": "go1.24.5"}
+func Version(healthService services.HealthInteractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, r, http.StatusOK, healthService.Version())
+	}
+}
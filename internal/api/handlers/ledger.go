@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+)
+
+// VerifyLedger обрабатывает HTTP-запрос на проверку целостности журнала транзакций.
+//
+// Возвращает JSON с результатом проверки: признаком целостности, количеством проверенных
+// транзакций и контрольных точек и первым найденным нарушением. Нарушение целостности
+// не является ошибкой запроса, поэтому ответ всегда имеет статус 200.
+//
+// Параметры:
+//   - ledgerService: интерфейс проверки журнала транзакций.
+//
+// Пример запроса:
+//
+//	GET 127.0.0.1:8080/api/ledger/verify
+func VerifyLedger(ledgerService services.LedgerInteractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp, err := ledgerService.Verify(r.Context())
+		if err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+		writeJSON(w, r, http.StatusOK, resp)
+	}
+}
+
+// GetCheckpoints обрабатывает HTTP-запрос на получение последних N подписанных контрольных точек
+// журнала транзакций вместе с открытым ключом сервера.
+//
+// Параметры:
+//   - ledgerService: интерфейс работы с контрольными точками.
+//
+// Пример запроса:
+//
+//	GET 127.0.0.1:8080/api/ledger/checkpoints?count=10
+func GetCheckpoints(ledgerService services.LedgerInteractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		count, err := strconv.Atoi(r.URL.Query().Get("count"))
+		if err != nil || count <= 0 {
+			HTTPError(w, r, http.StatusBadRequest, CodeInvalidRequest, "Invalid count parameter")
+			return
+		}
+
+		resp, err := ledgerService.GetCheckpoints(r.Context(), count)
+		if err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+		writeJSON(w, r, http.StatusOK, resp)
+	}
+}
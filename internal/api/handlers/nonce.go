@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+)
+
+// GetNonce возвращает http.HandlerFunc, обрабатывающий запрос на получение последнего
+// использованного nonce кошелька. Клиент подписывает следующий перевод с nonce, большим этого значения.
+//
+// Параметры:
+//   - transferService: интерфейс, предоставляющий метод получения nonce.
+//
+// Пример пути: 127.0.0.1:8080/api/wallet/{address}/nonce
+func GetNonce(transferService services.TransferInteractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Извлекаем адрес из пути
+		address := r.PathValue("address")
+		if address == "" {
+			HTTPError(w, r, http.StatusBadRequest, CodeInvalidRequest, "Wallet address is required")
+			return
+		}
+
+		// Получаем nonce через сервис
+		nonceResp, err := transferService.GetNonce(r.Context(), dto.BalanceReq{Address: address})
+		if err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+
+		// Формируем ответ
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(nonceResp); err != nil {
+			HTTPError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to encode response")
+		}
+	}
+}
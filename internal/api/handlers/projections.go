@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+)
+
+// GetDailyVolumes обрабатывает HTTP-запрос на получение оборотов по видам записей журнала
+// за каждые сутки периода. Данные могут отставать от журнала до следующего обновления проекций.
+//
+// Параметры:
+//   - projectionService: интерфейс движка проекций журнала транзакций.
+//
+// Пример запроса:
+//
+//	GET 127.0.0.1:8080/api/stats/daily?from=2025-01-01&to=2025-01-31
+func GetDailyVolumes(projectionService services.ProjectionInteractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		volumes, err := projectionService.GetDailyVolumes(r.Context(), dto.DailyVolumesReq{
+			From: query.Get("from"),
+			To:   query.Get("to"),
+		})
+		if err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+		writeJSON(w, r, http.StatusOK, volumes)
+	}
+}
+
+// GetCounterparties обрабатывает HTTP-запрос на получение самых частых контрагентов кошелька.
+// Данные могут отставать от журнала до следующего обновления проекций.
+//
+// Параметры:
+//   - projectionService: интерфейс движка проекций журнала транзакций.
+//
+// Пример запроса:
+//
+//	GET 127.0.0.1:8080/api/wallet/{address}/counterparties?count=10
+func GetCounterparties(projectionService services.ProjectionInteractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		count, err := strconv.Atoi(r.URL.Query().Get("count"))
+		if err != nil || count <= 0 {
+			HTTPError(w, r, http.StatusBadRequest, CodeInvalidRequest, "Invalid count parameter")
+			return
+		}
+
+		counterparties, err := projectionService.GetCounterparties(r.Context(), dto.CounterpartiesReq{
+			Address: r.PathValue("address"),
+			Count:   count,
+		})
+		if err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+		writeJSON(w, r, http.StatusOK, counterparties)
+	}
+}
+
+// GetProjections обрабатывает HTTP-запрос на получение состояния проекций журнала транзакций.
+//
+// Параметры:
+//   - projectionService: интерфейс движка проекций журнала транзакций.
+//
+// Пример запроса:
+//
+//	GET 127.0.0.1:8080/admin/projections
+func GetProjections(projectionService services.ProjectionInteractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		statuses, err := projectionService.GetStatus(r.Context())
+		if err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+		writeJSON(w, r, http.StatusOK, statuses)
+	}
+}
+
+// RebuildProjection обрабатывает HTTP-запрос на перестроение проекции с нуля воспроизведением
+// всего журнала транзакций. На время перестроения запись в журнал приостанавливается.
+//
+// Параметры:
+//   - projectionService: интерфейс движка проекций журнала транзакций.
+//
+// Пример запроса:
+//
+//	POST 127.0.0.1:8080/admin/projections/counterparty_stats/rebuild
+//	{"reason": "OPS-12: counterparty stats schema changed"}
+func RebuildProjection(projectionService services.ProjectionInteractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.ProjectionRebuildReq
+		if !decodeJSON(w, r, &req) {
+			return
+		}
+		req.Name = r.PathValue("name")
+
+		resp, err := projectionService.Rebuild(r.Context(), req)
+		if err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+		writeJSON(w, r, http.StatusOK, resp)
+	}
+}
@@ -1,9 +1,9 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
 
+	"github.com/coffee-realist/infotecs_transaction_system/internal/api/middleware"
 	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
 	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
 )
@@ -26,14 +26,15 @@ func Send(transferService services.TransferInteractor) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Декодируем запрос
 		var req dto.TransactionReq
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			HTTPError(w, "Invalid request payload", http.StatusBadRequest)
+		if !decodeJSON(w, r, &req) {
 			return
 		}
 
+		middleware.LogWallets(r.Context(), req.From, req.To)
+
 		// Выполняем перевод через сервис
-		if err := transferService.Send(req); err != nil {
-			handleServiceError(w, err)
+		if err := transferService.Send(r.Context(), req); err != nil {
+			handleServiceError(w, r, err)
 			return
 		}
 
@@ -41,7 +42,7 @@ func Send(transferService services.TransferInteractor) http.HandlerFunc {
 		w.WriteHeader(http.StatusCreated)
 		_, err := w.Write([]byte("Transaction successful"))
 		if err != nil {
-			HTTPError(w, "Failed to write response", http.StatusInternalServerError)
+			HTTPError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to write response")
 		}
 	}
 }
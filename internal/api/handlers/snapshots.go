@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+)
+
+// GetSnapshots обрабатывает HTTP-запрос на получение последних N снимков балансов
+// с корнями деревьев Меркла.
+//
+// Параметры:
+//   - snapshotService: интерфейс работы со снимками балансов.
+//
+// Пример запроса:
+//
+//	GET 127.0.0.1:8080/api/snapshots?count=10
+func GetSnapshots(snapshotService services.SnapshotInteractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		count, err := strconv.Atoi(r.URL.Query().Get("count"))
+		if err != nil || count <= 0 {
+			HTTPError(w, r, http.StatusBadRequest, CodeInvalidRequest, "Invalid count parameter")
+			return
+		}
+
+		snapshots, err := snapshotService.GetSnapshots(r.Context(), count)
+		if err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+		writeJSON(w, r, http.StatusOK, snapshots)
+	}
+}
+
+// GetBalanceProof обрабатывает HTTP-запрос на получение доказательства включения баланса
+// кошелька в снимок (по умолчанию — в последний).
+//
+// Параметры:
+//   - snapshotService: интерфейс работы со снимками балансов.
+//
+// Пример запроса:
+//
+//	GET 127.0.0.1:8080/api/wallet/{address}/proof?snapshot=3
+func GetBalanceProof(snapshotService services.SnapshotInteractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := dto.BalanceProofReq{Address: r.PathValue("address")}
+		if raw := r.URL.Query().Get("snapshot"); raw != "" {
+			id, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil || id <= 0 {
+				HTTPError(w, r, http.StatusBadRequest, CodeInvalidRequest, "Invalid snapshot parameter")
+				return
+			}
+			req.SnapshotID = id
+		}
+
+		resp, err := snapshotService.GetProof(r.Context(), req)
+		if err != nil {
+			handleServiceError(w, r, err)
+			return
+		}
+		writeJSON(w, r, http.StatusOK, resp)
+	}
+}
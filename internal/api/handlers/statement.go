@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+)
+
+// statementCSVHeader — заголовок выписки в формате CSV. Первая строка данных (type=opening)
+// содержит входящий остаток, последняя (type=closing) — итоговое изменение, сумму комиссий
+// и исходящий остаток.
+var statementCSVHeader = []string{
+	"type", "id", "created_at", "kind", "direction", "counterparty", "amount", "fee", "balance",
+}
+
+// GetStatement обрабатывает HTTP-запрос на получение выписки по кошельку за период.
+//
+// Параметры строки запроса:
+//   - from — начало периода в формате RFC 3339 (включительно), обязательный;
+//   - to — конец периода в формате RFC 3339 (не включительно), по умолчанию текущий момент;
+//   - format — csv или json (по умолчанию).
+//
+// Выписка передаётся клиенту потоком по мере чтения журнала. Ошибки, возникшие до начала
+// передачи, возвращаются в формате application/problem+json; ошибка во время передачи
+// записывается в журнал сервера, а ответ обрывается.
+//
+// Параметры:
+//   - transferService: интерфейс, предоставляющий выписку по кошельку.
+//
+// Пример запроса:
+//
+//	GET 127.0.0.1:8080/api/wallet/{address}/statement?from=2025-01-01T00:00:00Z&to=2025-02-01T00:00:00Z&format=csv
+func GetStatement(transferService services.TransferInteractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		req := dto.StatementReq{Address: r.PathValue("address"), To: time.Now().UTC()}
+
+		var err error
+		if req.From, err = time.Parse(time.RFC3339, query.Get("from")); err != nil {
+			HTTPError(w, r, http.StatusBadRequest, CodeInvalidRequest, "Invalid from parameter")
+			return
+		}
+		if query.Has("to") {
+			if req.To, err = time.Parse(time.RFC3339, query.Get("to")); err != nil {
+				HTTPError(w, r, http.StatusBadRequest, CodeInvalidRequest, "Invalid to parameter")
+				return
+			}
+		}
+
+		var writer statementWriter
+		switch format := query.Get("format"); format {
+		case "", dto.StatementFormatJSON:
+			writer = &jsonStatementWriter{w: w}
+		case dto.StatementFormatCSV:
+			writer = &csvStatementWriter{w: w, csv: csv.NewWriter(w)}
+		default:
+			HTTPError(w, r, http.StatusBadRequest, CodeInvalidRequest, "Invalid format parameter")
+			return
+		}
+
+		if err := transferService.WriteStatement(r.Context(), req, writer); err != nil {
+			if !writer.started() {
+				handleServiceError(w, r, err)
+				return
+			}
+			slog.ErrorContext(r.Context(), "statement interrupted", "error", err)
+		}
+	}
+}
+
+// statementWriter — получатель выписки, записывающий её в ответ HTTP.
+type statementWriter interface {
+	services.StatementWriter
+	// started сообщает, начата ли передача ответа клиенту.
+	started() bool
+}
+
+// statementFilename возвращает имя файла выписки для заголовка Content-Disposition.
+func statementFilename(header dto.StatementHeader, extension string) string {
+	return fmt.Sprintf("statement-%.8s-%s-%s.%s",
+		header.Address, header.From.Format("20060102"), header.To.Format("20060102"), extension)
+}
+
+// jsonStatementWriter записывает выписку в формате JSON (см. dto.StatementResp): поля начала выписки,
+// массив entries и поля итогов одного объекта.
+type jsonStatementWriter struct {
+	w       http.ResponseWriter
+	begun   bool
+	entries int
+}
+
+func (j *jsonStatementWriter) started() bool {
+	return j.begun
+}
+
+func (j *jsonStatementWriter) WriteHeader(header dto.StatementHeader) error {
+	raw, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+	j.begun = true
+	j.w.Header().Set("Content-Type", "application/json")
+	j.w.Header().Set("Content-Disposition", `attachment; filename="`+statementFilename(header, "json")+`"`)
+	// Объект начала выписки открывает общий объект: отбрасываем закрывающую скобку и открываем массив
+	_, err = fmt.Fprintf(j.w, `%s,"entries":[`, raw[:len(raw)-1])
+	return err
+}
+
+func (j *jsonStatementWriter) WriteEntry(entry dto.StatementEntry) error {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if j.entries > 0 {
+		raw = append([]byte{','}, raw...)
+	}
+	j.entries++
+	_, err = j.w.Write(raw)
+	return err
+}
+
+func (j *jsonStatementWriter) WriteSummary(summary dto.StatementSummary) error {
+	raw, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	// Закрываем массив и продолжаем общий объект полями итогов
+	_, err = fmt.Fprintf(j.w, "],%s\n", raw[1:])
+	return err
+}
+
+// csvStatementWriter записывает выписку в формате CSV с заголовком statementCSVHeader.
+type csvStatementWriter struct {
+	w      http.ResponseWriter
+	csv    *csv.Writer
+	header *dto.StatementHeader
+}
+
+func (c *csvStatementWriter) started() bool {
+	return c.header != nil
+}
+
+func (c *csvStatementWriter) WriteHeader(header dto.StatementHeader) error {
+	c.header = &header
+	c.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	c.w.Header().Set("Content-Disposition", `attachment; filename="`+statementFilename(header, "csv")+`"`)
+	if err := c.csv.Write(statementCSVHeader); err != nil {
+		return err
+	}
+	return c.csv.Write([]string{
+		"opening", strconv.FormatInt(header.OpeningTxID, 10), header.From.Format(time.RFC3339Nano),
+		"", "", "", "", "", header.OpeningBalance.String(),
+	})
+}
+
+func (c *csvStatementWriter) WriteEntry(entry dto.StatementEntry) error {
+	return c.csv.Write([]string{
+		"transaction", strconv.FormatInt(entry.ID, 10), entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		entry.Kind, entry.Direction, entry.Counterparty,
+		entry.Amount.String(), entry.Fee.String(), entry.Balance.String(),
+	})
+}
+
+func (c *csvStatementWriter) WriteSummary(summary dto.StatementSummary) error {
+	if err := c.csv.Write([]string{
+		"closing", strconv.FormatInt(summary.ClosingTxID, 10), c.header.To.Format(time.RFC3339Nano),
+		"", "", "", summary.TotalIn.Sub(summary.TotalOut).String(), summary.TotalFees.String(),
+		summary.ClosingBalance.String(),
+	}); err != nil {
+		return err
+	}
+	c.csv.Flush()
+	return c.csv.Error()
+}
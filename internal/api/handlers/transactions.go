@@ -27,21 +27,21 @@ func GetTransactions(transferService services.TransferInteractor) http.HandlerFu
 		countParam := r.URL.Query().Get("count")
 		count, err := strconv.Atoi(countParam)
 		if err != nil || count <= 0 {
-			HTTPError(w, "Invalid count parameter", http.StatusBadRequest)
+			HTTPError(w, r, http.StatusBadRequest, CodeInvalidRequest, "Invalid count parameter")
 			return
 		}
 
 		// Получаем транзакции через сервис
-		transactions, err := transferService.GetLastN(count)
+		transactions, err := transferService.GetLastN(r.Context(), count)
 		if err != nil {
-			handleServiceError(w, err)
+			handleServiceError(w, r, err)
 			return
 		}
 
 		// Формируем ответ
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(transactions); err != nil {
-			HTTPError(w, "Failed to encode response", http.StatusInternalServerError)
+			HTTPError(w, r, http.StatusInternalServerError, CodeInternal, "Failed to encode response")
 		}
 	}
 }
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// accessEntryKey — ключ контекста для хранения сведений о запросе, дополняемых обработчиками.
+type accessEntryKey struct{}
+
+// accessEntry собирает сведения о запросе для записи журнала доступа.
+type accessEntry struct {
+	mu      sync.Mutex
+	wallets []string
+}
+
+// AccessLog возвращает middleware, записывающий в журнал одну запись на каждый запрос: метод, путь,
+// код ответа, размер ответа, длительность обработки и адреса кошельков, затронутых запросом
+// (см. LogWallets). Запросы с кодом ответа 5xx записываются на уровне error, остальные — на уровне info.
+//
+// Middleware должен располагаться внутри RequestID, чтобы запись содержала идентификатор запроса.
+func AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		entry := &accessEntry{}
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		ctx := context.WithValue(r.Context(), accessEntryKey{}, entry)
+
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		level := slog.LevelInfo
+		if recorder.status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		entry.mu.Lock()
+		wallets := entry.wallets
+		entry.mu.Unlock()
+		slog.LogAttrs(ctx, level, "http request",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", recorder.status),
+			slog.Int64("bytes", recorder.bytes),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("remote_addr", r.RemoteAddr),
+			slog.Any("wallets", wallets),
+		)
+	})
+}
+
+// LogWallets добавляет адреса кошельков, затронутых запросом, в запись журнала доступа.
+// Пустые и уже добавленные адреса пропускаются; вне AccessLog вызов ничего не делает.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//   - addresses: адреса кошельков.
+func LogWallets(ctx context.Context, addresses ...string) {
+	entry, ok := ctx.Value(accessEntryKey{}).(*accessEntry)
+	if !ok {
+		return
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	for _, address := range addresses {
+		if address != "" && !slices.Contains(entry.wallets, address) {
+			entry.wallets = append(entry.wallets, address)
+		}
+	}
+}
+
+// statusRecorder запоминает код и размер ответа.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+// WriteHeader запоминает код ответа и передаёт его клиенту.
+func (s *statusRecorder) WriteHeader(status int) {
+	if !s.wroteHeader {
+		s.status, s.wroteHeader = status, true
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Write передаёт тело ответа клиенту, учитывая его размер.
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	s.wroteHeader = true
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap возвращает исходный ResponseWriter для http.ResponseController.
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// Flush отправляет клиенту буферизованные данные, если исходный ResponseWriter это поддерживает.
+func (s *statusRecorder) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
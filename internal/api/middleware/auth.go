@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/auth"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+	"github.com/joomcode/errorx"
+)
+
+// APIKeyHeader — заголовок, в котором клиент передаёт API-ключ.
+const APIKeyHeader = "X-API-Key"
+
+// bearerPrefix — префикс JWT в заголовке Authorization (RFC 6750).
+const bearerPrefix = "bearer "
+
+// Машинные коды ошибок аутентификации и авторизации.
+const (
+	CodeUnauthenticated = "unauthenticated"
+	CodeForbidden       = "forbidden"
+	CodeWalletForbidden = "wallet_forbidden"
+	codeInternal        = "internal_error"
+)
+
+// ErrorWriter формирует ответ об ошибке. Совпадает по сигнатуре с handlers.HTTPError.
+type ErrorWriter func(w http.ResponseWriter, r *http.Request, statusCode int, code, detail string)
+
+// Authenticate возвращает middleware, аутентифицирующий клиента по заголовку X-API-Key,
+// по JWT в заголовке "Authorization: Bearer" или по клиентскому сертификату TLS, проверенному
+// при рукопожатии (mTLS). Если клиент предъявил несколько, используется первый из них в этом порядке.
+//
+// Если клиент не предъявил ни одного, запрос передаётся дальше без клиента в контексте —
+// решение о допуске принимает RequireScope конкретного маршрута.
+// Неизвестный или отозванный ключ и непрошедший проверку токен отклоняются с HTTP 401.
+//
+// Аргументы:
+//   - authService: сервис аутентификации.
+//   - onError: функция формирования ответа об ошибке.
+func Authenticate(authService services.AuthInteractor, onError ErrorWriter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var (
+				principal *auth.Principal
+				err       error
+			)
+			key, authorization := r.Header.Get(APIKeyHeader), r.Header.Get("Authorization")
+			switch {
+			case key != "":
+				principal, err = authService.AuthenticateAPIKey(key)
+			case len(authorization) > len(bearerPrefix) && strings.EqualFold(authorization[:len(bearerPrefix)], bearerPrefix):
+				principal, err = authService.AuthenticateToken(strings.TrimSpace(authorization[len(bearerPrefix):]))
+				if errorx.IsOfType(err, services.ErrUnauthenticated) {
+					w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				}
+			case r.TLS != nil && len(r.TLS.VerifiedChains) > 0:
+				principal, err = authService.AuthenticateCertificate(r.TLS.VerifiedChains[0][0])
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if err != nil {
+				if errorx.IsOfType(err, services.ErrUnauthenticated) {
+					onError(w, r, http.StatusUnauthorized, CodeUnauthenticated, errorx.Cast(err).Message())
+				} else {
+					onError(w, r, http.StatusInternalServerError, codeInternal, "Internal server error")
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// RequireScope возвращает middleware, допускающий к маршруту только клиентов с указанной областью доступа.
+//
+// Аргументы:
+//   - scope: требуемая область доступа.
+//   - onError: функция формирования ответа об ошибке.
+//
+// Ответы:
+//   - HTTP 401, если клиент не аутентифицирован;
+//   - HTTP 403, если у клиента нет требуемой области доступа.
+func RequireScope(scope auth.Scope, onError ErrorWriter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := auth.FromContext(r.Context())
+			switch {
+			case principal == nil:
+				onError(w, r, http.StatusUnauthorized, CodeUnauthenticated, "authentication required")
+			case !principal.HasScope(scope):
+				onError(w, r, http.StatusForbidden, CodeForbidden, "scope "+string(scope)+" is required")
+			default:
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// AuthorizeDebit возвращает middleware, проверяющий право клиента списывать средства
+// с кошелька, указанного в поле "from" тела запроса.
+//
+// Тело запроса читается полностью и восстанавливается для следующего обработчика.
+// Некорректное тело пропускается дальше: его отклонит валидация или сам обработчик.
+//
+// Аргументы:
+//   - onError: функция формирования ответа об ошибке.
+func AuthorizeDebit(onError ErrorWriter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			from, ok := peekFrom(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !auth.FromContext(r.Context()).CanDebit(from) {
+				onError(w, r, http.StatusForbidden, CodeWalletForbidden, "not allowed to debit wallet "+from)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// peekFrom читает поле "from" из JSON-тела запроса и восстанавливает тело для следующего обработчика.
+//
+// Возвращает:
+//   - адрес кошелька отправителя и false, если тело не удалось прочитать или разобрать.
+func peekFrom(r *http.Request) (string, bool) {
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(raw))
+
+	var body struct {
+		From string `json:"from"`
+	}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return "", false
+	}
+	return body.From, true
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// CodeRequestTooLarge — машинный код ошибки слишком большого тела запроса.
+const CodeRequestTooLarge = "request_too_large"
+
+// LimitBody возвращает middleware, ограничивающий размер тела запроса.
+//
+// Запрос с заголовком Content-Length больше лимита отклоняется с HTTP 413 до чтения тела.
+// Тело без Content-Length (chunked) оборачивается http.MaxBytesReader: чтение сверх лимита
+// завершается ошибкой *http.MaxBytesError, а сервер закрывает соединение после ответа.
+//
+// Аргументы:
+//   - limit: максимальный размер тела в байтах.
+//   - onError: функция формирования ответа об ошибке.
+func LimitBody(limit int64, onError ErrorWriter) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				onError(w, r, http.StatusRequestEntityTooLarge, CodeRequestTooLarge,
+					fmt.Sprintf("Request body must not exceed %d bytes", limit))
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
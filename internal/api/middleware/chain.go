@@ -0,0 +1,22 @@
+package middleware
+
+import "net/http"
+
+// Middleware оборачивает обработчик дополнительной логикой.
+type Middleware func(http.Handler) http.Handler
+
+// Chain объединяет middleware в один: первый в списке оказывается внешним,
+// то есть первым получает запрос и последним — ответ.
+//
+// Пример:
+//
+//	Chain(RequestID, AccessLog, Recover(onError))(mux)
+//	// эквивалентно RequestID(AccessLog(Recover(onError)(mux)))
+func Chain(middlewares ...Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Заголовки CORS, которые браузер может отправлять и читать в запросах к API.
+const (
+	corsAllowMethods  = "GET, POST"
+	corsAllowHeaders  = "Authorization, Content-Type, X-API-Key, X-Request-ID, Idempotency-Key, traceparent, tracestate"
+	corsExposeHeaders = "X-Request-ID, Idempotent-Replayed, Retry-After, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy"
+	corsMaxAge        = "600"
+)
+
+// CORS возвращает middleware, разрешающий запросы к API из браузера со страниц указанных источников.
+//
+// Для разрешённого источника в ответ добавляются заголовки Access-Control-Allow-Origin и
+// Access-Control-Expose-Headers; предварительный запрос (OPTIONS с заголовком
+// Access-Control-Request-Method) завершается ответом HTTP 204 с разрешёнными методами и заголовками.
+// Запросы с других источников обрабатываются без заголовков CORS, и браузер не передаёт ответ странице.
+//
+// Аргументы:
+//   - origins: разрешённые источники через запятую, например https://wallet.example.com;
+//     значение "*" разрешает любые источники, пустая строка отключает CORS.
+func CORS(origins string) Middleware {
+	var allowed []string
+	for _, origin := range strings.Split(origins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			allowed = append(allowed, strings.TrimSuffix(origin, "/"))
+		}
+	}
+	if len(allowed) == 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	anyOrigin := slices.Contains(allowed, "*")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			w.Header().Add("Vary", "Origin")
+			if origin == "" || !(anyOrigin || slices.Contains(allowed, origin)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if anyOrigin {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+				w.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+				w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			w.Header().Set("Access-Control-Expose-Headers", corsExposeHeaders)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
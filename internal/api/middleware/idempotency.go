@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+	"github.com/joomcode/errorx"
+)
+
+// Заголовки идемпотентных запросов (draft-ietf-httpapi-idempotency-key-header).
+const (
+	// IdempotencyKeyHeader — заголовок, в котором клиент передаёт ключ идемпотентности запроса.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader — заголовок ответа, повторённого из сохранённого ответа на первый запрос с ключом.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// Машинные коды ошибок запросов с ключом идемпотентности.
+const (
+	CodeIdempotencyKeyReused = "idempotency_key_reused"
+	CodeIdempotencyKeyInUse  = "idempotency_key_in_use"
+	codeInvalidRequest       = "invalid_request"
+)
+
+// Idempotency возвращает middleware, выполняющий запрос с заголовком Idempotency-Key не более одного раза.
+//
+// Ответ на первый запрос клиента с ключом сохраняется, и повторные запросы с тем же ключом получают его
+// без повторного выполнения операции (с заголовком Idempotent-Replayed: true). Так клиент может безопасно
+// повторить запрос, ответ на который не получил из-за сбоя соединения.
+// Ответы 5xx и 429 не сохраняются: ключ освобождается, и повтор запроса выполняет операцию заново.
+//
+// Запрос с тем же ключом, но другим методом, путём или телом отклоняется с HTTP 422,
+// а повтор запроса, первый запрос с ключом которого ещё обрабатывается, — с HTTP 409.
+// Запросы без заголовка обрабатываются как обычно.
+//
+// Аргументы:
+//   - idempotencyService: сервис ключей идемпотентности.
+//   - operationID: идентификатор операции; ключ, использованный с одной операцией, нельзя использовать с другой.
+//   - onError: функция формирования ответа об ошибке.
+func Idempotency(idempotencyService services.IdempotencyInteractor, operationID string, onError ErrorWriter) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				onError(w, r, http.StatusBadRequest, codeInvalidRequest, "failed to read request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			record, started, err := idempotencyService.Begin(r.Context(), dto.IdempotencyRecord{
+				Subject:     clientKey(r),
+				Key:         key,
+				Operation:   operationID,
+				RequestHash: requestHash(r, body),
+			})
+			switch {
+			case errorx.IsOfType(err, services.ErrIdempotencyKeyReused):
+				onError(w, r, http.StatusUnprocessableEntity, CodeIdempotencyKeyReused, errorx.Cast(err).Message())
+				return
+			case errorx.IsOfType(err, services.ErrIdempotencyKeyInUse):
+				w.Header().Set("Retry-After", "1")
+				onError(w, r, http.StatusConflict, CodeIdempotencyKeyInUse, errorx.Cast(err).Message())
+				return
+			case err != nil:
+				slog.ErrorContext(r.Context(), "failed to begin idempotent request", "error", err)
+				onError(w, r, http.StatusInternalServerError, codeInternal, "Internal server error")
+				return
+			}
+
+			if !started {
+				if record.ContentType != "" {
+					w.Header().Set("Content-Type", record.ContentType)
+				}
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				w.WriteHeader(record.StatusCode)
+				_, _ = w.Write(record.Body)
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w}
+			// Ключ сохраняется и после отмены запроса, иначе повтор выполнил бы операцию ещё раз
+			ctx := context.WithoutCancel(r.Context())
+			saved := false
+			defer func() {
+				// Обработчик не завершился (паника): ответ неизвестен, ключ освобождается
+				if !saved {
+					releaseKey(ctx, idempotencyService, record)
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+
+			saved = true
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+				releaseKey(ctx, idempotencyService, record)
+				return
+			}
+
+			record.StatusCode = status
+			record.ContentType = w.Header().Get("Content-Type")
+			record.Body = recorder.body.Bytes()
+			if record.ContentType == "" && len(record.Body) > 0 {
+				// Тип содержимого, не заданный обработчиком, http.Server определяет по телу ответа
+				record.ContentType = http.DetectContentType(record.Body)
+			}
+			if err := idempotencyService.Complete(ctx, record); err != nil {
+				slog.ErrorContext(ctx, "failed to save response of idempotent request", "error", err)
+				releaseKey(ctx, idempotencyService, record)
+			}
+		})
+	}
+}
+
+// requestHash вычисляет хеш метода, пути со строкой запроса и тела запроса.
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// releaseKey освобождает ключ идемпотентности, записывая ошибку в журнал.
+func releaseKey(ctx context.Context, idempotencyService services.IdempotencyInteractor, record dto.IdempotencyRecord) {
+	if err := idempotencyService.Release(ctx, record.Subject, record.Key); err != nil {
+		slog.ErrorContext(ctx, "failed to release idempotency key", "error", err)
+	}
+}
+
+// responseRecorder передаёт ответ клиенту и запоминает его код и тело для сохранения.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader запоминает код ответа и передаёт его клиенту.
+func (rr *responseRecorder) WriteHeader(status int) {
+	if rr.status == 0 {
+		rr.status = status
+	}
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+// Write передаёт тело ответа клиенту и запоминает его.
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.status == 0 {
+		rr.status = http.StatusOK
+	}
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
+
+// Unwrap возвращает исходный ResponseWriter для http.ResponseController.
+func (rr *responseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
+}
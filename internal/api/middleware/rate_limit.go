@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/auth"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/ratelimit"
+)
+
+// CodeRateLimited — машинный код ошибки превышения лимита запросов.
+const CodeRateLimited = "rate_limited"
+
+// Заголовки ответа с состоянием лимита (draft-ietf-httpapi-ratelimit-headers).
+const (
+	rateLimitLimitHeader     = "RateLimit-Limit"
+	rateLimitRemainingHeader = "RateLimit-Remaining"
+	rateLimitResetHeader     = "RateLimit-Reset"
+	rateLimitPolicyHeader    = "RateLimit-Policy"
+)
+
+// LimitClient возвращает middleware, ограничивающий частоту запросов клиента к операции API.
+//
+// Клиент определяется аутентифицированным субъектом (API-ключ, пользователь SSO),
+// а для запросов без аутентификации — IP-адресом.
+//
+// Аргументы:
+//   - store: хранилище корзин.
+//   - operationID: идентификатор операции; у каждой операции своя корзина.
+//   - limit: лимит запросов; если ограничение снято, middleware ничего не делает.
+//   - onError: функция формирования ответа об ошибке.
+func LimitClient(store ratelimit.Store, operationID string, limit ratelimit.Limit, onError ErrorWriter) Middleware {
+	return func(next http.Handler) http.Handler {
+		if limit.Unlimited() {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := "client:" + operationID + ":" + clientKey(r)
+			if limitRequest(w, r, store, key, limit, onError, "too many requests") {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// LimitIP возвращает middleware, ограничивающий частоту всех запросов к API с одного IP-адреса.
+//
+// Middleware следует ставить перед Authenticate: запросы с неверными учётными данными отклоняются
+// до LimitClient и иначе не ограничивались бы, а лимит LimitClient для аутентифицированных клиентов
+// ведётся по субъекту и не ограничивает адрес, с которого приходят запросы.
+//
+// Аргументы:
+//   - store: хранилище корзин.
+//   - limit: лимит запросов; если ограничение снято, middleware ничего не делает.
+//   - onError: функция формирования ответа об ошибке.
+func LimitIP(store ratelimit.Store, limit ratelimit.Limit, onError ErrorWriter) Middleware {
+	return func(next http.Handler) http.Handler {
+		if limit.Unlimited() {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limitRequest(w, r, store, "ip:"+remoteIP(r), limit, onError, "too many requests from this address") {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// LimitWallet возвращает middleware, ограничивающий частоту переводов с кошелька,
+// указанного в поле "from" тела запроса, независимо от того, какой клиент их отправляет.
+//
+// Middleware следует ставить после AuthorizeDebit, чтобы запросы клиентов без права на списание
+// не расходовали лимит чужого кошелька.
+//
+// Аргументы:
+//   - store: хранилище корзин.
+//   - limit: лимит переводов; если ограничение снято, middleware ничего не делает.
+//   - onError: функция формирования ответа об ошибке.
+func LimitWallet(store ratelimit.Store, limit ratelimit.Limit, onError ErrorWriter) Middleware {
+	return func(next http.Handler) http.Handler {
+		if limit.Unlimited() {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			from, ok := peekFrom(r)
+			if !ok || from == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if limitRequest(w, r, store, "wallet:"+from, limit, onError, "too many transfers from wallet "+from) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// limitRequest берёт токен из корзины key, записывает заголовки RateLimit-* и, если лимит исчерпан,
+// отвечает HTTP 429 с заголовком Retry-After.
+//
+// Ошибка хранилища не должна останавливать обработку запросов: она записывается в журнал,
+// а запрос пропускается.
+//
+// Возвращает:
+//   - true, если запрос можно обрабатывать дальше.
+func limitRequest(
+	w http.ResponseWriter, r *http.Request, store ratelimit.Store, key string, limit ratelimit.Limit,
+	onError ErrorWriter, detail string,
+) bool {
+	result, err := store.Take(r.Context(), key, limit)
+	if err != nil {
+		slog.WarnContext(r.Context(), "rate limit store failed", "key", key, "error", err)
+		return true
+	}
+
+	// Если запрос проходит через несколько лимитов, заголовки описывают самый строгий из них.
+	header := w.Header()
+	remaining, err := strconv.Atoi(header.Get(rateLimitRemainingHeader))
+	if err != nil || result.Remaining <= remaining || !result.Allowed {
+		header.Set(rateLimitLimitHeader, strconv.Itoa(result.Limit))
+		header.Set(rateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+		header.Set(rateLimitResetHeader, ceilSeconds(result.Reset))
+		header.Set(rateLimitPolicyHeader, fmt.Sprintf("%d;w=%s", limit.Requests, ceilSeconds(limit.Period)))
+	}
+	if result.Allowed {
+		return true
+	}
+
+	header.Set("Retry-After", ceilSeconds(result.RetryAfter))
+	onError(w, r, http.StatusTooManyRequests, CodeRateLimited,
+		fmt.Sprintf("%s, retry in %s", detail, result.RetryAfter.Round(time.Millisecond)))
+	return false
+}
+
+// clientKey возвращает идентификатор клиента: субъект аутентификации или IP-адрес.
+func clientKey(r *http.Request) string {
+	if principal := auth.FromContext(r.Context()); principal != nil {
+		return principal.Subject
+	}
+	return "ip:" + remoteIP(r)
+}
+
+// remoteIP возвращает IP-адрес, с которого получен запрос.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ceilSeconds записывает длительность целым числом секунд, округляя вверх.
+func ceilSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
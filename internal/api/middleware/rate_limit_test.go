@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/auth"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/ratelimit"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+)
+
+// keyAuth принимает API-ключи вида "key-<имя>"; остальные ключи отклоняются как неизвестные.
+type keyAuth struct {
+	services.AuthInteractor
+}
+
+// AuthenticateAPIKey возвращает клиента ключа или ошибку ErrUnauthenticated.
+func (keyAuth) AuthenticateAPIKey(key string) (*auth.Principal, error) {
+	name, ok := strings.CutPrefix(key, "key-")
+	if !ok {
+		return nil, services.ErrUnauthenticated.New("unknown API key")
+	}
+	return &auth.Principal{Subject: "api_key:" + name, Method: auth.MethodAPIKey}, nil
+}
+
+// writeError отвечает кодом статуса и машинным кодом ошибки.
+func writeError(w http.ResponseWriter, _ *http.Request, statusCode int, code, _ string) {
+	w.WriteHeader(statusCode)
+	_, _ = w.Write([]byte(code))
+}
+
+// newLimitedAPI собирает цепочку, как в api.InitRoutes: лимит IP-адреса перед аутентификацией
+// и лимит клиента к операции после неё.
+func newLimitedAPI(ip, client ratelimit.Limit) http.Handler {
+	store := ratelimit.NewMemoryStore()
+	handler := LimitClient(store, "getBalance", client, writeError)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	return Chain(LimitIP(store, ip, writeError), Authenticate(keyAuth{}, writeError))(handler)
+}
+
+// request отправляет запрос с API-ключом key с адреса addr и возвращает ответ.
+func request(api http.Handler, addr, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/wallet/aa/balance", nil)
+	req.RemoteAddr = addr
+	req.Header.Set(APIKeyHeader, key)
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLimitIPLimitsFailedAuthentication(t *testing.T) {
+	api := newLimitedAPI(ratelimit.Limit{Requests: 3, Period: time.Minute}, ratelimit.Limit{Requests: 100, Period: time.Minute})
+
+	for i := range 3 {
+		if rec := request(api, "192.0.2.1:1000", "wrong"); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("request %d: status %d, want %d", i+1, rec.Code, http.StatusUnauthorized)
+		}
+	}
+	rec := request(api, "192.0.2.1:1001", "wrong")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status %d after exhausting the address limit, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("Retry-After header is missing")
+	}
+
+	// Лимит исчерпан для адреса, а не для ключа: с другого адреса ключ по-прежнему проверяется
+	if rec := request(api, "192.0.2.2:1000", "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("another address: status %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestLimitIPLimitsAuthenticatedClients(t *testing.T) {
+	api := newLimitedAPI(ratelimit.Limit{Requests: 4, Period: time.Minute}, ratelimit.Limit{Requests: 3, Period: time.Minute})
+
+	// У каждого ключа свой лимит клиента, но запросы с одного адреса расходуют общий лимит адреса
+	for i, key := range []string{"key-a", "key-b", "key-c", "key-d"} {
+		if rec := request(api, "192.0.2.1:1000", key); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d, want %d", i+1, rec.Code, http.StatusOK)
+		}
+	}
+	if rec := request(api, "192.0.2.1:1000", "key-e"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("status %d after exhausting the address limit, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+
+	// Лимит клиента по-прежнему действует по ключу независимо от адреса
+	for i := range 3 {
+		if rec := request(api, "192.0.2.10:1000", "key-z"); rec.Code != http.StatusOK {
+			t.Fatalf("client request %d: status %d, want %d", i+1, rec.Code, http.StatusOK)
+		}
+	}
+	if rec := request(api, "192.0.2.11:1000", "key-z"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("status %d after exhausting the client limit, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+}
+
+func TestLimitIPUnlimited(t *testing.T) {
+	api := newLimitedAPI(ratelimit.Limit{}, ratelimit.Limit{})
+
+	for i := range 50 {
+		if rec := request(api, "192.0.2.1:1000", "wrong"); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("request %d: status %d, want %d", i+1, rec.Code, http.StatusUnauthorized)
+		}
+	}
+}
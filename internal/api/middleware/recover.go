@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recover возвращает middleware, перехватывающий панику в обработчике: паника записывается в журнал
+// со стеком вызовов, а клиенту отправляется ответ HTTP 500 вместо разрыва соединения.
+// Паника http.ErrAbortHandler, которой обработчик намеренно прерывает ответ, передаётся дальше.
+//
+// Аргументы:
+//   - onError: функция формирования ответа об ошибке.
+func Recover(onError ErrorWriter) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+
+				slog.ErrorContext(r.Context(), "handler panicked",
+					"panic", fmt.Sprint(recovered), "stack", string(debug.Stack()))
+				onError(w, r, http.StatusInternalServerError, codeInternal, "Internal server error")
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/logging"
+)
+
+// RequestIDHeader — заголовок, в котором передаётся идентификатор запроса.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength ограничивает длину идентификатора, принятого от клиента.
+const maxRequestIDLength = 128
+
+// RequestID возвращает middleware, назначающий каждому запросу идентификатор.
+//
+// Если клиент передал корректный заголовок X-Request-ID, используется его значение,
+// иначе генерируется новый идентификатор. Идентификатор сохраняется в контексте запроса,
+// добавляется ко всем записям журнала, сделанным с этим контекстом (см. logging.New),
+// и возвращается клиенту в одноимённом заголовке ответа.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+	})
+}
+
+// WithRequestID возвращает копию контекста с указанным идентификатором запроса.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return logging.WithRequestID(ctx, id)
+}
+
+// RequestIDFromContext возвращает идентификатор запроса из контекста или пустую строку.
+func RequestIDFromContext(ctx context.Context) string {
+	return logging.RequestID(ctx)
+}
+
+// validRequestID проверяет, что идентификатор непуст, ограничен по длине
+// и состоит только из печатных ASCII-символов.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID генерирует случайный идентификатор запроса из 16 байт в hex-представлении.
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// timeoutGrace — запас времени на отправку ответа об истечении таймаута.
+const timeoutGrace = time.Second
+
+// Timeout возвращает middleware, ограничивающий время обработки запроса.
+//
+// Контекст запроса отменяется по истечении timeout: запросы к базе данных, выполняемые с этим
+// контекстом, прерываются, и обработчик отвечает ошибкой. Срок записи ответа соединения
+// устанавливается равным timeout с небольшим запасом, поэтому маршрут может работать дольше
+// общего таймаута записи сервера (server.write_timeout) — например, при выдаче длинной выписки.
+//
+// Аргументы:
+//   - timeout: время на обработку запроса.
+func Timeout(timeout time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			// Ошибка означает, что ResponseWriter не поддерживает сроки записи; тогда действует таймаут сервера
+			_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(timeout + timeoutGrace))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Document — корневой объект спецификации OpenAPI 3.0.
+type Document struct {
+	OpenAPI    string                        `json:"openapi"`
+	Info       Info                          `json:"info"`
+	Paths      map[string]map[string]*PathOp `json:"paths"`
+	Components Components                    `json:"components"`
+
+	onError func(w http.ResponseWriter, r *http.Request, errs []FieldError)
+	mu      sync.Mutex
+	encoded []byte
+}
+
+// Info содержит метаданные API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Components содержит переиспользуемые схемы документа и схемы аутентификации.
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme описывает способ аутентификации клиентов.
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// PathOp — описание операции в разделе paths спецификации.
+type PathOp struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter описывает параметр пути, строки запроса или заголовка.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody описывает тело запроса.
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response описывает ответ операции.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType связывает тип содержимого со схемой.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Operation — входные данные для регистрации маршрута в спецификации.
+//
+// Поля Body и Responses[*].Body задаются значениями DTO (например, dto.TransactionReq{}),
+// по которым схема строится через рефлексию, поэтому спецификация всегда соответствует типам dto.
+type Operation struct {
+	Method    string
+	Path      string
+	ID        string
+	Summary   string
+	Params    []Parameter
+	Body      any
+	Responses map[int]Reply
+	Security  []string // Имена схем аутентификации, любая из которых допускает вызов
+}
+
+// Reply описывает вариант ответа операции.
+type Reply struct {
+	Description string
+	ContentType string
+	Body        any
+}
+
+// PathParam создаёт обязательный параметр пути.
+func PathParam(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "path", Description: description, Required: true, Schema: schema}
+}
+
+// QueryParam создаёт параметр строки запроса.
+func QueryParam(name, description string, required bool, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Required: required, Schema: schema}
+}
+
+// HeaderParam создаёт параметр заголовка запроса.
+func HeaderParam(name, description string, required bool, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "header", Description: description, Required: required, Schema: schema}
+}
+
+// New создаёт пустой документ спецификации.
+//
+// Аргументы:
+//   - info: метаданные API.
+//   - onError: функция, формирующая ответ на запрос, не прошедший валидацию.
+//
+// Возвращает:
+//   - указатель на Document.
+func New(info Info, onError func(w http.ResponseWriter, r *http.Request, errs []FieldError)) *Document {
+	return &Document{
+		OpenAPI:    "3.0.3",
+		Info:       info,
+		Paths:      map[string]map[string]*PathOp{},
+		Components: Components{Schemas: map[string]*Schema{}},
+		onError:    onError,
+	}
+}
+
+// Register добавляет операцию в спецификацию и возвращает обработчик,
+// проверяющий входящие запросы на соответствие ей перед вызовом next.
+//
+// Аргументы:
+//   - op: описание операции.
+//   - next: обработчик маршрута.
+//
+// Возвращает:
+//   - http.Handler с валидацией запроса.
+func (d *Document) Register(op Operation, next http.Handler) http.Handler {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	pathOp := &PathOp{
+		OperationID: op.ID,
+		Summary:     op.Summary,
+		Parameters:  op.Params,
+		Responses:   map[string]Response{},
+	}
+	for _, scheme := range op.Security {
+		pathOp.Security = append(pathOp.Security, map[string][]string{scheme: {}})
+	}
+	if op.Body != nil {
+		pathOp.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				"application/json": {Schema: d.schemaFor(reflect.TypeOf(op.Body))},
+			},
+		}
+	}
+	for status, reply := range op.Responses {
+		response := Response{Description: reply.Description}
+		if reply.Body != nil {
+			contentType := reply.ContentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			response.Content = map[string]MediaType{
+				contentType: {Schema: d.schemaFor(reflect.TypeOf(reply.Body))},
+			}
+		}
+		pathOp.Responses[strconv.Itoa(status)] = response
+	}
+
+	if d.Paths[op.Path] == nil {
+		d.Paths[op.Path] = map[string]*PathOp{}
+	}
+	d.Paths[op.Path][strings.ToLower(op.Method)] = pathOp
+	d.encoded = nil
+
+	return d.validator(pathOp, next)
+}
+
+// AddSecurityScheme регистрирует в документе схему аутентификации.
+//
+// Аргументы:
+//   - name: имя схемы, на которое ссылаются операции в поле Security.
+//   - scheme: описание схемы.
+func (d *Document) AddSecurityScheme(name string, scheme SecurityScheme) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.Components.SecuritySchemes == nil {
+		d.Components.SecuritySchemes = map[string]SecurityScheme{}
+	}
+	d.Components.SecuritySchemes[name] = scheme
+	d.encoded = nil
+}
+
+// Pattern возвращает шаблон маршрута для http.ServeMux, соответствующий операции.
+func (op Operation) Pattern() string {
+	return op.Method + " " + op.Path
+}
+
+// ServeHTTP отдаёт спецификацию в формате JSON.
+func (d *Document) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	d.mu.Lock()
+	if d.encoded == nil {
+		encoded, err := json.Marshal(d)
+		if err != nil {
+			d.mu.Unlock()
+			http.Error(w, "Failed to encode specification", http.StatusInternalServerError)
+			return
+		}
+		d.encoded = encoded
+	}
+	encoded := d.encoded
+	d.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(encoded)
+}
@@ -0,0 +1,187 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Schema описывает JSON-схему в формате OpenAPI 3.0 (используемое в проекте подмножество).
+type Schema struct {
+	Ref              string             `json:"$ref,omitempty"`
+	Type             string             `json:"type,omitempty"`
+	Format           string             `json:"format,omitempty"`
+	Pattern          string             `json:"pattern,omitempty"`
+	Description      string             `json:"description,omitempty"`
+	Minimum          *float64           `json:"minimum,omitempty"`
+	ExclusiveMinimum bool               `json:"exclusiveMinimum,omitempty"`
+	Properties       map[string]*Schema `json:"properties,omitempty"`
+	Required         []string           `json:"required,omitempty"`
+	Items            *Schema            `json:"items,omitempty"`
+	OneOf            []*Schema          `json:"oneOf,omitempty"`
+}
+
+const (
+	// FormatDecimal — формат десятичных сумм в строковой записи (см. DecimalSchema).
+	FormatDecimal = "decimal"
+	// FormatAddress — формат адреса кошелька: 64 шестнадцатеричных символа.
+	FormatAddress = "wallet-address"
+	// FormatDateTime — формат момента времени RFC 3339.
+	FormatDateTime = "date-time"
+	// FormatDate — формат даты RFC 3339 (YYYY-MM-DD).
+	FormatDate = "date"
+
+	// FormatSignature — формат подписи Ed25519: 128 шестнадцатеричных символов.
+	FormatSignature = "ed25519-signature"
+
+	addressPattern   = "^[0-9a-fA-F]{64}$"
+	signaturePattern = "^[0-9a-fA-F]{128}$"
+
+	idempotencyKeyPattern = "^[!-~]{1,255}$"
+)
+
+var (
+	decimalType = reflect.TypeOf(decimal.Decimal{})
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// DecimalSchema возвращает схему десятичной суммы: строка в формате decimal или JSON-число.
+// Нижняя граница (validate:"positive") задаётся в самой схеме и относится к значению суммы
+// в любой из записей.
+func DecimalSchema() *Schema {
+	return &Schema{OneOf: []*Schema{{Type: "string", Format: FormatDecimal}, {Type: "number"}}}
+}
+
+// isDecimal сообщает, описывает ли схема десятичную сумму (см. DecimalSchema).
+func (s *Schema) isDecimal() bool {
+	return len(s.OneOf) > 0 && s.OneOf[0].Format == FormatDecimal
+}
+
+// AddressSchema возвращает схему строки с адресом кошелька.
+func AddressSchema() *Schema {
+	return &Schema{Type: "string", Format: FormatAddress, Pattern: addressPattern}
+}
+
+// SignatureSchema возвращает схему строки с подписью перевода.
+func SignatureSchema() *Schema {
+	return &Schema{Type: "string", Format: FormatSignature, Pattern: signaturePattern}
+}
+
+// DateTimeSchema возвращает схему строки с моментом времени в формате RFC 3339.
+func DateTimeSchema() *Schema {
+	return &Schema{Type: "string", Format: FormatDateTime}
+}
+
+// DateSchema возвращает схему строки с датой в формате YYYY-MM-DD.
+func DateSchema() *Schema {
+	return &Schema{Type: "string", Format: FormatDate}
+}
+
+// IdempotencyKeySchema возвращает схему ключа идемпотентности: от 1 до 255 печатных символов ASCII.
+func IdempotencyKeySchema() *Schema {
+	return &Schema{Type: "string", Pattern: idempotencyKeyPattern}
+}
+
+// IntegerSchema возвращает схему целого числа не меньше min.
+func IntegerSchema(min float64) *Schema {
+	return &Schema{Type: "integer", Format: "int64", Minimum: &min}
+}
+
+// schemaFor строит схему для типа t, регистрируя структуры в components и возвращая ссылку на них.
+//
+// Имена и обязательность полей берутся из тегов json и validate:
+//   - validate:"required" — поле обязательно;
+//   - validate:"address"  — строка в формате адреса кошелька;
+//   - validate:"signature" — строка в формате подписи Ed25519;
+//   - validate:"positive" — число строго больше нуля.
+func (d *Document) schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case decimalType:
+		return DecimalSchema()
+	case timeType:
+		return DateTimeSchema()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: d.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Struct:
+		return d.structRef(t)
+	}
+
+	return &Schema{}
+}
+
+// structRef регистрирует схему структуры в components (однократно) и возвращает ссылку на неё.
+func (d *Document) structRef(t reflect.Type) *Schema {
+	name := t.Name()
+	ref := &Schema{Ref: "#/components/schemas/" + name}
+	if _, ok := d.Components.Schemas[name]; ok {
+		return ref
+	}
+
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	// Резервируем имя до обхода полей, чтобы не зациклиться на рекурсивных типах
+	d.Components.Schemas[name] = schema
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			jsonName, _, _ := strings.Cut(tag, ",")
+			if jsonName == "-" {
+				continue
+			}
+			if jsonName != "" {
+				name = jsonName
+			}
+		}
+
+		property := d.schemaFor(field.Type)
+		for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+			switch rule {
+			case "required":
+				schema.Required = append(schema.Required, name)
+			case "address":
+				property = AddressSchema()
+			case "signature":
+				property = SignatureSchema()
+			case "positive":
+				zero := 0.0
+				property.Minimum, property.ExclusiveMinimum = &zero, true
+			}
+		}
+		schema.Properties[name] = property
+	}
+
+	return ref
+}
+
+// resolve возвращает схему, на которую ссылается s, либо саму s.
+func (d *Document) resolve(s *Schema) *Schema {
+	if s == nil || s.Ref == "" {
+		return s
+	}
+	return d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+}
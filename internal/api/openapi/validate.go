@@ -0,0 +1,290 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FieldError описывает нарушение спецификации в одном поле запроса.
+type FieldError struct {
+	In      string `json:"in"`      // Расположение поля: path, query, header или body
+	Field   string `json:"field"`   // Имя поля (для вложенных полей — путь через точку)
+	Message string `json:"message"` // Описание нарушения
+}
+
+// Error реализует интерфейс error.
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.In, e.Field, e.Message)
+}
+
+// validator оборачивает next проверкой параметров (пути, строки запроса, заголовков) и тела запроса
+// по описанию операции.
+func (d *Document) validator(op *PathOp, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var errs []FieldError
+
+		// Проверяем параметры пути и строки запроса
+		for _, param := range op.Parameters {
+			var value string
+			var present bool
+			switch param.In {
+			case "path":
+				value = r.PathValue(param.Name)
+				present = value != ""
+			case "query":
+				present = r.URL.Query().Has(param.Name)
+				value = r.URL.Query().Get(param.Name)
+			case "header":
+				value = r.Header.Get(param.Name)
+				present = value != ""
+			}
+
+			if !present {
+				if param.Required {
+					errs = append(errs, FieldError{In: param.In, Field: param.Name, Message: "is required"})
+				}
+				continue
+			}
+			if msg := d.checkParam(param.Schema, value); msg != "" {
+				errs = append(errs, FieldError{In: param.In, Field: param.Name, Message: msg})
+			}
+		}
+
+		// Проверяем тело запроса, сохраняя его для обработчика
+		if op.RequestBody != nil {
+			raw, err := io.ReadAll(r.Body)
+			var tooLarge *http.MaxBytesError
+			switch {
+			case errors.As(err, &tooLarge):
+				errs = append(errs, FieldError{In: "body", Message: fmt.Sprintf("must not exceed %d bytes", tooLarge.Limit)})
+			case err != nil:
+				errs = append(errs, FieldError{In: "body", Message: "failed to read request body"})
+			default:
+				r.Body = io.NopCloser(bytes.NewReader(raw))
+				errs = append(errs, d.checkBody(op.RequestBody.Content["application/json"].Schema, raw)...)
+			}
+		}
+
+		if len(errs) > 0 {
+			d.onError(w, r, errs)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checkParam проверяет строковое значение параметра по схеме и возвращает описание нарушения.
+func (d *Document) checkParam(schema *Schema, value string) string {
+	schema = d.resolve(schema)
+	if schema.isDecimal() {
+		return checkDecimal(schema, value)
+	}
+	switch schema.Type {
+	case "integer":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "must be an integer"
+		}
+		return checkMinimum(schema, decimal.NewFromInt(n))
+	case "string":
+		return checkString(schema, value)
+	}
+	return ""
+}
+
+// checkBody разбирает JSON-тело и проверяет его по схеме.
+func (d *Document) checkBody(schema *Schema, raw []byte) []FieldError {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return []FieldError{{In: "body", Message: "malformed JSON"}}
+	}
+
+	var errs []FieldError
+	d.checkValue(schema, value, "", &errs)
+	return errs
+}
+
+// checkValue рекурсивно проверяет значение, полученное из JSON, по схеме.
+func (d *Document) checkValue(schema *Schema, value any, path string, errs *[]FieldError) {
+	schema = d.resolve(schema)
+	fail := func(msg string) {
+		*errs = append(*errs, FieldError{In: "body", Field: path, Message: msg})
+	}
+
+	// Десятичные суммы допускается передавать как JSON-числом, так и строкой
+	if schema.isDecimal() {
+		var text string
+		switch v := value.(type) {
+		case json.Number:
+			text = v.String()
+		case string:
+			text = v
+		default:
+			fail("must be a decimal number")
+			return
+		}
+		if msg := checkDecimal(schema, text); msg != "" {
+			fail(msg)
+		}
+		return
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			fail("must be an object")
+			return
+		}
+		for _, name := range schema.Required {
+			if v, ok := object[name]; !ok || v == nil {
+				*errs = append(*errs, FieldError{In: "body", Field: join(path, name), Message: "is required"})
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if property, ok := schema.Properties[name]; ok && object[name] != nil {
+				d.checkValue(property, object[name], join(path, name), errs)
+			}
+		}
+
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			fail("must be an array")
+			return
+		}
+		for i, item := range items {
+			d.checkValue(schema.Items, item, join(path, strconv.Itoa(i)), errs)
+		}
+
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			fail("must be an integer")
+			return
+		}
+		n, err := strconv.ParseInt(number.String(), 10, 64)
+		if err != nil {
+			fail("must be an integer")
+			return
+		}
+		if msg := checkMinimum(schema, decimal.NewFromInt(n)); msg != "" {
+			fail(msg)
+		}
+
+	case "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			fail("must be a number")
+			return
+		}
+		if msg := checkDecimal(schema, number.String()); msg != "" {
+			fail(msg)
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("must be a boolean")
+		}
+
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			fail("must be a string")
+			return
+		}
+		if msg := checkString(schema, text); msg != "" {
+			fail(msg)
+		}
+	}
+}
+
+// checkString проверяет строку по шаблону схемы.
+func checkString(schema *Schema, value string) string {
+	if schema.Format == FormatDateTime {
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return "must be an RFC 3339 timestamp"
+		}
+		return ""
+	}
+	if schema.Format == FormatDate {
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return "must be a date in YYYY-MM-DD format"
+		}
+		return ""
+	}
+	if schema.Pattern != "" && !compiled(schema.Pattern).MatchString(value) {
+		switch schema.Format {
+		case FormatAddress:
+			return "must be a wallet address (64 hex characters)"
+		case FormatSignature:
+			return "must be an ed25519 signature (128 hex characters)"
+		}
+		return "must match pattern " + schema.Pattern
+	}
+	return ""
+}
+
+// patterns кэширует скомпилированные регулярные выражения схем.
+var patterns sync.Map
+
+// compiled возвращает скомпилированное регулярное выражение для шаблона схемы.
+func compiled(pattern string) *regexp.Regexp {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile(pattern)
+	patterns.Store(pattern, re)
+	return re
+}
+
+// checkDecimal проверяет, что строка является десятичным числом, удовлетворяющим ограничениям схемы.
+func checkDecimal(schema *Schema, value string) string {
+	number, err := decimal.NewFromString(value)
+	if err != nil {
+		return "must be a decimal number"
+	}
+	return checkMinimum(schema, number)
+}
+
+// checkMinimum проверяет нижнюю границу числа.
+func checkMinimum(schema *Schema, value decimal.Decimal) string {
+	if schema.Minimum == nil {
+		return ""
+	}
+	minimum := decimal.NewFromFloat(*schema.Minimum)
+	if schema.ExclusiveMinimum && !value.GreaterThan(minimum) {
+		return "must be greater than " + minimum.String()
+	}
+	if !schema.ExclusiveMinimum && value.LessThan(minimum) {
+		return "must be at least " + minimum.String()
+	}
+	return ""
+}
+
+// join формирует путь к вложенному полю.
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,152 @@
+package openapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+)
+
+const (
+	testAddress   = "2f1c6e7a4b5d8e9f0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f6071"
+	testSignature = testAddress + testAddress
+)
+
+// newTestMux регистрирует в документе операцию перевода и операцию чтения с параметрами пути,
+// строки запроса и заголовка. Обработчик операции записывает полученное тело в ответ,
+// а ответ на запрос, не прошедший валидацию, — код 400 и список нарушений.
+func newTestMux(t *testing.T) *http.ServeMux {
+	t.Helper()
+
+	d := New(Info{Title: "test", Version: "1"}, func(w http.ResponseWriter, _ *http.Request, errs []FieldError) {
+		w.WriteHeader(http.StatusBadRequest)
+		for _, err := range errs {
+			_, _ = io.WriteString(w, err.Error()+"\n")
+		}
+	})
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(w, r.Body)
+	})
+
+	mux := http.NewServeMux()
+	send := Operation{Method: http.MethodPost, Path: "/send", ID: "send", Body: dto.TransactionReq{}}
+	mux.Handle(send.Pattern(), d.Register(send, echo))
+
+	get := Operation{
+		Method: http.MethodGet,
+		Path:   "/wallet/{address}/balance",
+		ID:     "getBalance",
+		Params: []Parameter{
+			PathParam("address", "", AddressSchema()),
+			QueryParam("count", "", true, IntegerSchema(1)),
+			QueryParam("at", "", false, DateTimeSchema()),
+			HeaderParam("Idempotency-Key", "", false, IdempotencyKeySchema()),
+		},
+	}
+	mux.Handle(get.Pattern(), d.Register(get, echo))
+	return mux
+}
+
+func TestValidatorAcceptsValidRequests(t *testing.T) {
+	mux := newTestMux(t)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		header string
+	}{
+		{"transfer", http.MethodPost, "/send",
+			`{"from":"` + testAddress + `","to":"` + testAddress + `","amount":1.5,"nonce":1,"signature":"` + testSignature + `"}`, ""},
+		{"transfer with string amount", http.MethodPost, "/send",
+			`{"from":"` + testAddress + `","to":"` + testAddress + `","amount":"0.01","nonce":2,"signature":"` + testSignature + `"}`, ""},
+		{"required query", http.MethodGet, "/wallet/" + testAddress + "/balance?count=1", "", ""},
+		{"all parameters", http.MethodGet, "/wallet/" + testAddress + "/balance?count=10&at=2025-01-01T00:00:00Z", "", "key-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.header != "" {
+				req.Header.Set("Idempotency-Key", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d, body %s", rec.Code, rec.Body)
+			}
+			if rec.Body.String() != tt.body {
+				t.Errorf("handler received body %q, want %q", rec.Body, tt.body)
+			}
+		})
+	}
+}
+
+func TestValidatorRejectsInvalidRequests(t *testing.T) {
+	mux := newTestMux(t)
+	transfer := func(fields string) string {
+		return `{"from":"` + testAddress + `","to":"` + testAddress + `",` + fields + `}`
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		header string
+		want   string
+	}{
+		{"malformed JSON", http.MethodPost, "/send", `{"from":`, "", "body : malformed JSON"},
+		{"not an object", http.MethodPost, "/send", `[]`, "", "body : must be an object"},
+		{"missing fields", http.MethodPost, "/send", `{}`, "", "body from: is required"},
+		{"null field", http.MethodPost, "/send", transfer(`"amount":null,"nonce":1,"signature":"` + testSignature + `"`), "",
+			"body amount: is required"},
+		{"bad address", http.MethodPost, "/send",
+			`{"from":"xyz","to":"` + testAddress + `","amount":1,"nonce":1,"signature":"` + testSignature + `"}`, "",
+			"body from: must be a wallet address (64 hex characters)"},
+		{"zero amount", http.MethodPost, "/send", transfer(`"amount":0,"nonce":1,"signature":"` + testSignature + `"`), "",
+			"body amount: must be greater than 0"},
+		{"negative amount", http.MethodPost, "/send", transfer(`"amount":"-1","nonce":1,"signature":"` + testSignature + `"`), "",
+			"body amount: must be greater than 0"},
+		{"amount not a number", http.MethodPost, "/send", transfer(`"amount":"ten","nonce":1,"signature":"` + testSignature + `"`), "",
+			"body amount: must be a decimal number"},
+		{"amount of wrong type", http.MethodPost, "/send", transfer(`"amount":true,"nonce":1,"signature":"` + testSignature + `"`), "",
+			"body amount: must be a decimal number"},
+		{"fractional nonce", http.MethodPost, "/send", transfer(`"amount":1,"nonce":1.5,"signature":"` + testSignature + `"`), "",
+			"body nonce: must be an integer"},
+		{"short signature", http.MethodPost, "/send", transfer(`"amount":1,"nonce":1,"signature":"abcd"`), "",
+			"body signature: must be an ed25519 signature (128 hex characters)"},
+		{"bad path address", http.MethodGet, "/wallet/xyz/balance?count=1", "", "",
+			"path address: must be a wallet address (64 hex characters)"},
+		{"missing query", http.MethodGet, "/wallet/" + testAddress + "/balance", "", "", "query count: is required"},
+		{"query below minimum", http.MethodGet, "/wallet/" + testAddress + "/balance?count=0", "", "",
+			"query count: must be at least 1"},
+		{"query not an integer", http.MethodGet, "/wallet/" + testAddress + "/balance?count=ten", "", "",
+			"query count: must be an integer"},
+		{"bad timestamp", http.MethodGet, "/wallet/" + testAddress + "/balance?count=1&at=yesterday", "", "",
+			"query at: must be an RFC 3339 timestamp"},
+		{"bad header", http.MethodGet, "/wallet/" + testAddress + "/balance?count=1", "", "key with spaces",
+			"header Idempotency-Key: must match pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.header != "" {
+				req.Header.Set("Idempotency-Key", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+			if !strings.Contains(rec.Body.String(), tt.want) {
+				t.Errorf("errors %q do not contain %q", rec.Body, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/api/openapi"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/config"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/metrics"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/ratelimit"
+	"github.com/shopspring/decimal"
+)
+
+// operations — все операции, регистрируемые InitRoutes. Проверка спецификации сверяет этот список
+// с операциями опубликованного документа, поэтому новая операция, не добавленная сюда, не пройдёт её.
+var operations = []openapi.Operation{
+	sendOperation,
+	getTransactionsOperation,
+	getBalanceOperation,
+	getNonceOperation,
+	getStatementOperation,
+	verifyLedgerOperation,
+	getCheckpointsOperation,
+	getSnapshotsOperation,
+	getBalanceProofOperation,
+	getCounterpartiesOperation,
+	getDailyVolumesOperation,
+	mintOperation,
+	adjustOperation,
+	freezeWalletOperation,
+	unfreezeWalletOperation,
+	generateWalletsOperation,
+	getAuditLogOperation,
+	reconcileOperation,
+	getProjectionsOperation,
+	rebuildProjectionOperation,
+}
+
+// fetchSpec строит маршруты InitRoutes и возвращает спецификацию, опубликованную по /api/openapi.json.
+func fetchSpec(t *testing.T) *openapi.Document {
+	t.Helper()
+
+	h := NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, metrics.New(),
+		config.HTTPConfig{Timeout: time.Second},
+		config.RateLimitConfig{},
+		ratelimit.NewMemoryStore())
+	rec := httptest.NewRecorder()
+	h.InitRoutes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/openapi.json: status %d, body %s", rec.Code, rec.Body)
+	}
+
+	var doc openapi.Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("failed to decode specification: %v", err)
+	}
+	return &doc
+}
+
+func TestSpecListsRegisteredOperations(t *testing.T) {
+	doc := fetchSpec(t)
+
+	published := make(map[string]string)
+	for path, methods := range doc.Paths {
+		for method, op := range methods {
+			published[op.OperationID] = strings.ToUpper(method) + " " + path
+		}
+	}
+
+	for _, op := range operations {
+		pattern, ok := published[op.ID]
+		if !ok {
+			t.Errorf("operation %s is not published", op.ID)
+			continue
+		}
+		if pattern != op.Pattern() {
+			t.Errorf("operation %s is published as %q, want %q", op.ID, pattern, op.Pattern())
+		}
+		delete(published, op.ID)
+	}
+	for id := range published {
+		t.Errorf("published operation %s is missing from the test list", id)
+	}
+}
+
+func TestSpecMatchesDTOTypes(t *testing.T) {
+	doc := fetchSpec(t)
+	checker := &schemaChecker{t: t, doc: doc, checked: map[reflect.Type]bool{}}
+
+	for _, op := range operations {
+		pathOp := doc.Paths[op.Path][strings.ToLower(op.Method)]
+		if pathOp == nil {
+			t.Errorf("operation %s is not published", op.ID)
+			continue
+		}
+
+		if op.Body != nil {
+			if pathOp.RequestBody == nil {
+				t.Errorf("%s: request body is not published", op.ID)
+			} else {
+				checker.check(op.ID+" request", pathOp.RequestBody.Content["application/json"].Schema, reflect.TypeOf(op.Body))
+			}
+		}
+
+		for status, reply := range op.Responses {
+			response, ok := pathOp.Responses[strconv.Itoa(status)]
+			if !ok {
+				t.Errorf("%s: response %d is not published", op.ID, status)
+				continue
+			}
+			if reply.Body == nil {
+				continue
+			}
+			contentType := reply.ContentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			media, ok := response.Content[contentType]
+			if !ok {
+				t.Errorf("%s: response %d has no %s content", op.ID, status, contentType)
+				continue
+			}
+			checker.check(op.ID+" response "+strconv.Itoa(status), media.Schema, reflect.TypeOf(reply.Body))
+		}
+	}
+}
+
+// schemaChecker сверяет схемы опубликованной спецификации с типами Go, по которым они построены.
+type schemaChecker struct {
+	t       *testing.T
+	doc     *openapi.Document
+	checked map[reflect.Type]bool
+}
+
+// check сверяет схему s с типом typ: вид значения, а для структур — каждое поле, его имя из тега json
+// и правила из тега validate.
+func (c *schemaChecker) check(where string, s *openapi.Schema, typ reflect.Type) {
+	c.t.Helper()
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if s == nil {
+		c.t.Errorf("%s: no schema for %s", where, typ)
+		return
+	}
+
+	switch {
+	case typ == reflect.TypeOf(decimal.Decimal{}):
+		c.expectDecimal(where, s)
+	case typ == reflect.TypeOf(time.Time{}):
+		c.expect(where, s, "string", openapi.FormatDateTime)
+	case typ.Kind() == reflect.String:
+		c.expect(where, s, "string", s.Format)
+	case typ.Kind() == reflect.Bool:
+		c.expect(where, s, "boolean", "")
+	case typ.Kind() >= reflect.Int && typ.Kind() <= reflect.Uint64:
+		c.expect(where, s, "integer", "int64")
+	case typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array:
+		c.expect(where, s, "array", "")
+		if s.Type == "array" {
+			c.check(where+"[]", s.Items, typ.Elem())
+		}
+	case typ.Kind() == reflect.Map:
+		c.expect(where, s, "object", "")
+	case typ.Kind() == reflect.Struct:
+		c.checkStruct(where, s, typ)
+	}
+}
+
+// expect сообщает о несовпадении типа или формата схемы.
+func (c *schemaChecker) expect(where string, s *openapi.Schema, typ, format string) {
+	c.t.Helper()
+	if s.Type != typ || s.Format != format {
+		c.t.Errorf("%s: schema is %s/%s, want %s/%s", where, s.Type, s.Format, typ, format)
+	}
+}
+
+// checkStruct сверяет ссылку на схему структуры и саму схему в components.
+func (c *schemaChecker) checkStruct(where string, s *openapi.Schema, typ reflect.Type) {
+	c.t.Helper()
+	if want := "#/components/schemas/" + typ.Name(); s.Ref != want {
+		c.t.Errorf("%s: schema is %q, want reference %q", where, s.Ref, want)
+		return
+	}
+	if c.checked[typ] {
+		return
+	}
+	c.checked[typ] = true
+
+	schema, ok := c.doc.Components.Schemas[typ.Name()]
+	if !ok {
+		c.t.Errorf("%s: component %s is not published", where, typ.Name())
+		return
+	}
+
+	fields := make(map[string]bool)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			jsonName, _, _ := strings.Cut(tag, ",")
+			if jsonName == "-" {
+				continue
+			}
+			if jsonName != "" {
+				name = jsonName
+			}
+		}
+		fields[name] = true
+		at := typ.Name() + "." + name
+
+		property, ok := schema.Properties[name]
+		if !ok {
+			c.t.Errorf("%s: property is not published", at)
+			continue
+		}
+
+		rules := strings.Split(field.Tag.Get("validate"), ",")
+		required := false
+		for _, rule := range rules {
+			switch rule {
+			case "required":
+				required = true
+			case "address":
+				c.expectPattern(at, property, openapi.AddressSchema())
+			case "signature":
+				c.expectPattern(at, property, openapi.SignatureSchema())
+			case "positive":
+				if property.Minimum == nil || *property.Minimum != 0 || !property.ExclusiveMinimum {
+					c.t.Errorf("%s: positive field has no exclusive minimum 0", at)
+				}
+			}
+		}
+		if listed := slices.Contains(schema.Required, name); listed != required {
+			c.t.Errorf("%s: required in schema = %t, validate tag requires = %t", at, listed, required)
+		}
+
+		if property.Pattern == "" {
+			c.check(at, property, field.Type)
+		}
+	}
+
+	for name := range schema.Properties {
+		if !fields[name] {
+			c.t.Errorf("%s.%s: published property has no field", typ.Name(), name)
+		}
+	}
+}
+
+// expectDecimal сообщает, если схема десятичной суммы не допускает обе записи, которые принимает сервер:
+// строку в формате decimal и JSON-число.
+func (c *schemaChecker) expectDecimal(where string, s *openapi.Schema) {
+	c.t.Helper()
+	if len(s.OneOf) != 2 || s.Type != "" {
+		c.t.Errorf("%s: decimal schema is %s/%s, want oneOf string and number", where, s.Type, s.Format)
+		return
+	}
+	c.expect(where+" (string)", s.OneOf[0], "string", openapi.FormatDecimal)
+	c.expect(where+" (number)", s.OneOf[1], "number", "")
+}
+
+// expectPattern сообщает о несовпадении схемы строки с шаблоном want.
+func (c *schemaChecker) expectPattern(at string, s, want *openapi.Schema) {
+	c.t.Helper()
+	if s.Type != want.Type || s.Format != want.Format || s.Pattern != want.Pattern {
+		c.t.Errorf("%s: schema is %s/%s %q, want %s/%s %q", at, s.Type, s.Format, s.Pattern, want.Type, want.Format, want.Pattern)
+	}
+}
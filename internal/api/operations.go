@@ -0,0 +1,344 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/api/handlers"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/api/openapi"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+)
+
+// apiInfo содержит метаданные спецификации OpenAPI.
+var apiInfo = openapi.Info{
+	Title:       "Infotecs transaction system",
+	Description: "HTTP API системы обработки транзакций платёжной системы",
+	Version:     "1.0.0",
+}
+
+// Имена схем аутентификации в спецификации.
+const (
+	apiKeyScheme = "ApiKeyAuth" // API-ключ в заголовке X-API-Key
+	bearerScheme = "BearerAuth" // JWT внутреннего SSO в заголовке Authorization
+)
+
+// problem описывает ответ с ошибкой в формате application/problem+json.
+func problem(description string) openapi.Reply {
+	return openapi.Reply{Description: description, ContentType: handlers.ProblemContentType, Body: handlers.Problem{}}
+}
+
+// Описания операций API. По ним строится спецификация /api/openapi.json
+// и выполняется валидация входящих запросов.
+var (
+	sendOperation = openapi.Operation{
+		Method:  http.MethodPost,
+		Path:    "/api/send",
+		ID:      "send",
+		Summary: "Перевод средств между кошельками",
+		Body:    dto.TransactionReq{},
+		Responses: map[int]openapi.Reply{
+			http.StatusCreated:      {Description: "Перевод выполнен", ContentType: "text/plain", Body: ""},
+			http.StatusBadRequest:   problem("Некорректный запрос или недостаточно средств"),
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа transfer, права на списание с кошелька или подпись неверна"),
+			http.StatusNotFound:     problem("Кошелёк не найден"),
+			http.StatusConflict:     problem("Nonce уже использован или кошелёк заморожен"),
+		},
+	}
+
+	getTransactionsOperation = openapi.Operation{
+		Method:  http.MethodGet,
+		Path:    "/api/transactions",
+		ID:      "getTransactions",
+		Summary: "Получение последних N транзакций",
+		Params: []openapi.Parameter{
+			openapi.QueryParam("count", "Количество транзакций", true, openapi.IntegerSchema(1)),
+		},
+		Responses: map[int]openapi.Reply{
+			http.StatusOK:           {Description: "Список транзакций", Body: dto.TransactionsResp{}},
+			http.StatusBadRequest:   problem("Некорректный параметр count"),
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа read"),
+			http.StatusNotFound:     problem("Транзакции не найдены"),
+		},
+	}
+
+	getBalanceOperation = openapi.Operation{
+		Method:  http.MethodGet,
+		Path:    "/api/wallet/{address}/balance",
+		ID:      "getBalance",
+		Summary: "Получение текущего баланса кошелька или баланса на момент в прошлом",
+		Params: []openapi.Parameter{
+			openapi.PathParam("address", "Адрес кошелька", openapi.AddressSchema()),
+			openapi.QueryParam("at", "Момент времени (RFC 3339), на который вычисляется баланс", false, openapi.DateTimeSchema()),
+			openapi.QueryParam("as_of_tx", "Идентификатор последней учитываемой транзакции", false, openapi.IntegerSchema(0)),
+		},
+		Responses: map[int]openapi.Reply{
+			http.StatusOK:           {Description: "Баланс кошелька", Body: dto.BalanceResp{}},
+			http.StatusBadRequest:   problem("Некорректный адрес, момент времени или транзакция; заданы одновременно at и as_of_tx"),
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа read"),
+			http.StatusNotFound:     problem("Кошелёк не найден"),
+		},
+	}
+
+	getNonceOperation = openapi.Operation{
+		Method:  http.MethodGet,
+		Path:    "/api/wallet/{address}/nonce",
+		ID:      "getNonce",
+		Summary: "Получение последнего использованного nonce кошелька",
+		Params: []openapi.Parameter{
+			openapi.PathParam("address", "Адрес кошелька", openapi.AddressSchema()),
+		},
+		Responses: map[int]openapi.Reply{
+			http.StatusOK:           {Description: "Последний использованный nonce", Body: dto.NonceResp{}},
+			http.StatusBadRequest:   problem("Некорректный адрес"),
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа read"),
+			http.StatusNotFound:     problem("Кошелёк не найден"),
+		},
+	}
+
+	verifyLedgerOperation = openapi.Operation{
+		Method:  http.MethodGet,
+		Path:    "/api/ledger/verify",
+		ID:      "verifyLedger",
+		Summary: "Проверка целостности цепочки хешей журнала транзакций и контрольных точек",
+		Responses: map[int]openapi.Reply{
+			http.StatusOK:           {Description: "Результат проверки", Body: dto.LedgerVerifyResp{}},
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа read"),
+		},
+	}
+
+	getCheckpointsOperation = openapi.Operation{
+		Method:  http.MethodGet,
+		Path:    "/api/ledger/checkpoints",
+		ID:      "getCheckpoints",
+		Summary: "Получение последних N подписанных контрольных точек журнала транзакций",
+		Params: []openapi.Parameter{
+			openapi.QueryParam("count", "Количество контрольных точек", true, openapi.IntegerSchema(1)),
+		},
+		Responses: map[int]openapi.Reply{
+			http.StatusOK:           {Description: "Контрольные точки и открытый ключ сервера", Body: dto.CheckpointsResp{}},
+			http.StatusBadRequest:   problem("Некорректный параметр count"),
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа read"),
+		},
+	}
+
+	getStatementOperation = openapi.Operation{
+		Method:  http.MethodGet,
+		Path:    "/api/wallet/{address}/statement",
+		ID:      "getStatement",
+		Summary: "Выписка по кошельку за период: входящий остаток, транзакции с балансом после каждой, комиссии и исходящий остаток",
+		Params: []openapi.Parameter{
+			openapi.PathParam("address", "Адрес кошелька", openapi.AddressSchema()),
+			openapi.QueryParam("from", "Начало периода (RFC 3339), включительно", true, openapi.DateTimeSchema()),
+			openapi.QueryParam("to", "Конец периода (RFC 3339), не включительно; по умолчанию текущий момент", false, openapi.DateTimeSchema()),
+			openapi.QueryParam("format", "Формат выписки: json (по умолчанию) или csv", false, &openapi.Schema{Type: "string", Pattern: "^(json|csv)$"}),
+		},
+		Responses: map[int]openapi.Reply{
+			http.StatusOK:           {Description: "Выписка; при format=csv — в формате text/csv", Body: dto.StatementResp{}},
+			http.StatusBadRequest:   problem("Некорректный адрес, период или формат"),
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа read"),
+			http.StatusNotFound:     problem("Кошелёк не найден"),
+		},
+	}
+
+	getSnapshotsOperation = openapi.Operation{
+		Method:  http.MethodGet,
+		Path:    "/api/snapshots",
+		ID:      "getSnapshots",
+		Summary: "Получение последних N снимков балансов с корнями деревьев Меркла",
+		Params: []openapi.Parameter{
+			openapi.QueryParam("count", "Количество снимков", true, openapi.IntegerSchema(1)),
+		},
+		Responses: map[int]openapi.Reply{
+			http.StatusOK:           {Description: "Снимки балансов", Body: []dto.Snapshot{}},
+			http.StatusBadRequest:   problem("Некорректный параметр count"),
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа read"),
+		},
+	}
+
+	getBalanceProofOperation = openapi.Operation{
+		Method:  http.MethodGet,
+		Path:    "/api/wallet/{address}/proof",
+		ID:      "getBalanceProof",
+		Summary: "Доказательство включения баланса кошелька в снимок",
+		Params: []openapi.Parameter{
+			openapi.PathParam("address", "Адрес кошелька", openapi.AddressSchema()),
+			openapi.QueryParam("snapshot", "Идентификатор снимка; по умолчанию последний", false, openapi.IntegerSchema(1)),
+		},
+		Responses: map[int]openapi.Reply{
+			http.StatusOK:           {Description: "Доказательство включения", Body: dto.BalanceProofResp{}},
+			http.StatusBadRequest:   problem("Некорректный адрес или параметр snapshot"),
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа read"),
+			http.StatusNotFound:     problem("Снимок не найден или кошелька нет в снимке"),
+		},
+	}
+
+	getDailyVolumesOperation = openapi.Operation{
+		Method:  http.MethodGet,
+		Path:    "/api/stats/daily",
+		ID:      "getDailyVolumes",
+		Summary: "Обороты по видам записей журнала за каждые сутки (UTC) периода",
+		Params: []openapi.Parameter{
+			openapi.QueryParam("from", "Первый день периода, включительно", true, openapi.DateSchema()),
+			openapi.QueryParam("to", "Последний день периода, включительно", true, openapi.DateSchema()),
+		},
+		Responses: map[int]openapi.Reply{
+			http.StatusOK:           {Description: "Обороты, упорядоченные по дню и виду записей", Body: []dto.DailyVolume{}},
+			http.StatusBadRequest:   problem("Некорректный период"),
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа read"),
+		},
+	}
+
+	getCounterpartiesOperation = openapi.Operation{
+		Method:  http.MethodGet,
+		Path:    "/api/wallet/{address}/counterparties",
+		ID:      "getCounterparties",
+		Summary: "Самые частые контрагенты кошелька с количеством и суммой переводов в обе стороны",
+		Params: []openapi.Parameter{
+			openapi.PathParam("address", "Адрес кошелька", openapi.AddressSchema()),
+			openapi.QueryParam("count", "Количество контрагентов", true, openapi.IntegerSchema(1)),
+		},
+		Responses: map[int]openapi.Reply{
+			http.StatusOK:           {Description: "Контрагенты по убыванию количества переводов", Body: []dto.CounterpartyStats{}},
+			http.StatusBadRequest:   problem("Некорректный адрес или параметр count"),
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа read"),
+		},
+	}
+
+	mintOperation = openapi.Operation{
+		Method:  http.MethodPost,
+		Path:    "/admin/mint",
+		ID:      "adminMint",
+		Summary: "Эмиссия средств на кошелёк",
+		Body:    dto.MintReq{},
+		Responses: map[int]openapi.Reply{
+			http.StatusOK:           {Description: "Новый баланс кошелька", Body: dto.BalanceResp{}},
+			http.StatusBadRequest:   problem("Некорректный запрос или не указано основание"),
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа admin"),
+			http.StatusNotFound:     problem("Кошелёк не найден"),
+		},
+	}
+
+	adjustOperation = openapi.Operation{
+		Method:  http.MethodPost,
+		Path:    "/admin/adjust",
+		ID:      "adminAdjust",
+		Summary: "Корректировка баланса кошелька на сумму со знаком",
+		Body:    dto.AdjustReq{},
+		Responses: map[int]openapi.Reply{
+			http.StatusOK:           {Description: "Новый баланс кошелька", Body: dto.BalanceResp{}},
+			http.StatusBadRequest:   problem("Некорректный запрос, не указано основание или баланс стал бы отрицательным"),
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа admin"),
+			http.StatusNotFound:     problem("Кошелёк не найден"),
+		},
+	}
+
+	freezeWalletOperation   = walletStateOperation("freeze", "adminFreezeWallet", "Заморозка кошелька")
+	unfreezeWalletOperation = walletStateOperation("unfreeze", "adminUnfreezeWallet", "Разморозка кошелька")
+
+	generateWalletsOperation = openapi.Operation{
+		Method:  http.MethodPost,
+		Path:    "/admin/wallets/generate",
+		ID:      "adminGenerateWallets",
+		Summary: "Создание новых кошельков с начальным балансом",
+		Body:    dto.GenerateWalletsReq{},
+		Responses: map[int]openapi.Reply{
+			http.StatusCreated:      {Description: "Ключевые пары созданных кошельков", Body: []dto.WalletKey{}},
+			http.StatusBadRequest:   problem("Некорректный запрос или не указано основание"),
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа admin"),
+		},
+	}
+
+	getAuditLogOperation = openapi.Operation{
+		Method:  http.MethodGet,
+		Path:    "/admin/audit",
+		ID:      "adminGetAuditLog",
+		Summary: "Получение последних N записей журнала административных действий",
+		Params: []openapi.Parameter{
+			openapi.QueryParam("count", "Количество записей", true, openapi.IntegerSchema(1)),
+		},
+		Responses: map[int]openapi.Reply{
+			http.StatusOK:           {Description: "Записи журнала, начиная с самой новой", Body: []dto.AuditEntry{}},
+			http.StatusBadRequest:   problem("Некорректный параметр count"),
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа admin"),
+		},
+	}
+
+	reconcileOperation = openapi.Operation{
+		Method:  http.MethodPost,
+		Path:    "/admin/reconcile",
+		ID:      "adminReconcile",
+		Summary: "Сверка балансов кошельков с журналом транзакций и, по запросу, исправление расхождений",
+		Body:    dto.ReconcileReq{},
+		Responses: map[int]openapi.Reply{
+			http.StatusOK:           {Description: "Отчёт сверки", Body: dto.ReconcileReport{}},
+			http.StatusBadRequest:   problem("Некорректный запрос или не указано основание исправления"),
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа admin"),
+		},
+	}
+
+	getProjectionsOperation = openapi.Operation{
+		Method:  http.MethodGet,
+		Path:    "/admin/projections",
+		ID:      "adminGetProjections",
+		Summary: "Состояние проекций журнала транзакций: контрольные точки и отставание от журнала",
+		Responses: map[int]openapi.Reply{
+			http.StatusOK:           {Description: "Состояние проекций", Body: []dto.ProjectionStatus{}},
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа admin"),
+		},
+	}
+
+	rebuildProjectionOperation = openapi.Operation{
+		Method:  http.MethodPost,
+		Path:    "/admin/projections/{name}/rebuild",
+		ID:      "adminRebuildProjection",
+		Summary: "Перестроение проекции с нуля воспроизведением журнала транзакций",
+		Params: []openapi.Parameter{
+			openapi.PathParam("name", "Имя проекции", &openapi.Schema{Type: "string"}),
+		},
+		Body: dto.ProjectionRebuildReq{},
+		Responses: map[int]openapi.Reply{
+			http.StatusOK:           {Description: "Результат перестроения", Body: dto.ProjectionRebuildResp{}},
+			http.StatusBadRequest:   problem("Не указано основание или начальный баланс кошелька неизвестен"),
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа admin"),
+			http.StatusNotFound:     problem("Проекция не зарегистрирована"),
+		},
+	}
+)
+
+// walletStateOperation описывает операцию изменения состояния кошелька /admin/wallets/{address}/<action>.
+func walletStateOperation(action, id, summary string) openapi.Operation {
+	return openapi.Operation{
+		Method:  http.MethodPost,
+		Path:    "/admin/wallets/{address}/" + action,
+		ID:      id,
+		Summary: summary,
+		Params: []openapi.Parameter{
+			openapi.PathParam("address", "Адрес кошелька", openapi.AddressSchema()),
+		},
+		Body: dto.FreezeReq{},
+		Responses: map[int]openapi.Reply{
+			http.StatusOK:           {Description: "Состояние кошелька", Body: dto.WalletStatusResp{}},
+			http.StatusBadRequest:   problem("Некорректный адрес или не указано основание"),
+			http.StatusUnauthorized: problem("Клиент не аутентифицирован"),
+			http.StatusForbidden:    problem("Нет области доступа admin"),
+			http.StatusNotFound:     problem("Кошелёк не найден"),
+		},
+	}
+}
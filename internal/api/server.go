@@ -2,8 +2,9 @@ package api
 
 import (
 	"context"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/config"
 	"net/http"
-	"time"
+	"strconv"
 )
 
 // Server инкапсулирует HTTP-сервер и методы управления его жизненным циклом.
@@ -11,23 +12,36 @@ type Server struct {
 	httpServer *http.Server
 }
 
-// Run запускает HTTP-сервер на указанном порту с заданным обработчиком.
+// Run запускает HTTP-сервер с заданным обработчиком.
+//
+// Если в tlsCfg задан сертификат, сервер принимает только соединения TLS; заменённые файлы
+// сертификата, ключа и корневых сертификатов клиентов применяются без перезапуска.
 //
 // Аргументы:
-//   - port: строка с номером порта (например, "8080").
+//   - cfg: параметры сервера — порт, таймауты чтения и записи, максимальный размер заголовков.
+//   - tlsCfg: параметры TLS и проверки клиентских сертификатов.
 //   - handler: http.Handler, обрабатывающий входящие запросы.
 //
 // Возвращает:
 //   - ошибку, если запуск сервера завершился с ошибкой.
-func (s *Server) Run(port string, handler http.Handler) error {
+func (s *Server) Run(cfg config.ServerConfig, tlsCfg config.TLSConfig, handler http.Handler) error {
 	s.httpServer = &http.Server{
-		Addr:           ":" + port,
+		Addr:           ":" + strconv.Itoa(cfg.Port),
 		Handler:        handler,
-		MaxHeaderBytes: 1 << 20,
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
+		MaxHeaderBytes: cfg.MaxHeaderBytes,
+		ReadTimeout:    cfg.ReadTimeout,
+		WriteTimeout:   cfg.WriteTimeout,
+	}
+	if !tlsCfg.Enabled() {
+		return s.httpServer.ListenAndServe()
+	}
+
+	tlsConfig, err := newTLSConfig(tlsCfg)
+	if err != nil {
+		return err
 	}
-	return s.httpServer.ListenAndServe()
+	s.httpServer.TLSConfig = tlsConfig
+	return s.httpServer.ListenAndServeTLS("", "")
 }
 
 // ShutDown завершает работу HTTP-сервера с учётом контекста завершения.
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/config"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// tlsReloader хранит настройки TLS, загруженные из файлов, и перечитывает файлы после их замены.
+//
+// Изменение файлов определяется по времени модификации при каждом рукопожатии, поэтому обновлённые
+// сертификаты (например, выпущенные cert-manager или certbot) применяются к новым соединениям без
+// перезапуска сервера. Если новые файлы не удалось загрузить, сервер продолжает работать со старыми.
+type tlsReloader struct {
+	cfg config.TLSConfig
+
+	mu       sync.Mutex
+	modTimes []time.Time
+	current  *tls.Config
+}
+
+// newTLSConfig загружает сертификат сервера и корневые сертификаты клиентов.
+//
+// Аргументы:
+//   - cfg: параметры TLS.
+//
+// Возвращает:
+//   - *tls.Config, перечитывающий файлы после их замены.
+//   - ошибку, если файлы не удалось загрузить.
+func newTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	reloader := &tlsReloader{cfg: cfg}
+	modTimes, err := reloader.stat()
+	if err != nil {
+		return nil, err
+	}
+	if err := reloader.load(modTimes); err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetConfigForClient: reloader.configForClient,
+	}, nil
+}
+
+// files перечисляет файлы, из которых загружаются настройки TLS.
+func (r *tlsReloader) files() []string {
+	files := []string{r.cfg.Cert, r.cfg.Key}
+	if r.cfg.ClientCA != "" {
+		files = append(files, r.cfg.ClientCA)
+	}
+	return files
+}
+
+// stat возвращает время модификации файлов настроек TLS.
+func (r *tlsReloader) stat() ([]time.Time, error) {
+	files := r.files()
+	modTimes := make([]time.Time, len(files))
+	for i, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+// load загружает файлы и заменяет текущие настройки TLS.
+func (r *tlsReloader) load(modTimes []time.Time) error {
+	certificate, err := tls.LoadX509KeyPair(r.cfg.Cert, r.cfg.Key)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate: %w", err)
+	}
+
+	current := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{certificate},
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+	if r.cfg.ClientCA != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCA)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in client CA bundle %s", r.cfg.ClientCA)
+		}
+		current.ClientCAs = pool
+		current.ClientAuth = tls.VerifyClientCertIfGiven
+		if r.cfg.ClientAuth == config.TLSClientAuthRequire {
+			current.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	r.current, r.modTimes = current, modTimes
+	return nil
+}
+
+// configForClient возвращает настройки TLS для нового соединения, предварительно перечитав
+// изменившиеся файлы. Вызывается при каждом рукопожатии (tls.Config.GetConfigForClient).
+func (r *tlsReloader) configForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modTimes, err := r.stat()
+	if err != nil || slices.EqualFunc(modTimes, r.modTimes, time.Time.Equal) {
+		return r.current, nil
+	}
+
+	if err := r.load(modTimes); err != nil {
+		// Запоминаем время модификации, чтобы не повторять загрузку и запись в журнал при каждом
+		// рукопожатии: следующая запись файлов снова изменит его.
+		r.modTimes = modTimes
+		slog.Error("failed to reload tls certificate, keeping the previous one", "error", err)
+		return r.current, nil
+	}
+	slog.Info("tls certificate reloaded", "cert", r.cfg.Cert)
+	return r.current, nil
+}
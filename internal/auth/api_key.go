@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// MethodAPIKey — способ аутентификации по API-ключу.
+const MethodAPIKey = "api_key"
+
+// HashAPIKey возвращает SHA-256 хеш значения API-ключа в hex-представлении.
+//
+// В базе данных хранится только хеш: значения ключей имеют высокую энтропию,
+// поэтому медленные функции хеширования паролей для них не требуются.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"crypto/x509"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// MethodCertificate — способ аутентификации по клиентскому сертификату TLS (mTLS).
+const MethodCertificate = "certificate"
+
+// walletURIPrefix — префикс URI в расширении subjectAltName, которым в сертификате
+// перечисляются кошельки клиента, например "urn:wallet:<адрес>".
+const walletURIPrefix = "urn:wallet:"
+
+// ErrInvalidCertificate — клиентский сертификат не позволяет определить клиента.
+var ErrInvalidCertificate = errors.New("invalid client certificate")
+
+// CertificatePrincipal сопоставляет проверенному клиентскому сертификату описание клиента.
+//
+// Клиент определяется субъектом сертификата: идентификатор — полное имя субъекта, имя — Common Name.
+// Роли берутся из атрибутов Organizational Unit (OU=viewer, OU=operator, OU=admin), неизвестные
+// значения игнорируются. Кошельки, с которых клиенту разрешено списание, перечисляются
+// URI вида urn:wallet:<адрес> в subjectAltName; роль admin разрешает списание с любого кошелька.
+//
+// Цепочка сертификата должна быть проверена до вызова (tls.Config.ClientCAs).
+//
+// Аргументы:
+//   - cert: клиентский сертификат.
+//
+// Возвращает:
+//   - *Principal для сертификата.
+//   - ErrInvalidCertificate, если в субъекте сертификата нет Common Name.
+func CertificatePrincipal(cert *x509.Certificate) (*Principal, error) {
+	name := cert.Subject.CommonName
+	if name == "" {
+		return nil, ErrInvalidCertificate
+	}
+
+	var roles []Role
+	for _, unit := range cert.Subject.OrganizationalUnit {
+		role := Role(unit)
+		if _, known := roleScopes[role]; known && !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+
+	var wallets []string
+	for _, uri := range cert.URIs {
+		if address, ok := strings.CutPrefix(uri.String(), walletURIPrefix); ok && address != "" {
+			wallets = append(wallets, address)
+		}
+	}
+
+	return &Principal{
+		Subject:    MethodCertificate + ":" + cert.Subject.String(),
+		Name:       name,
+		Method:     MethodCertificate,
+		Roles:      roles,
+		Scopes:     ScopesForRoles(roles),
+		Wallets:    wallets,
+		AllWallets: slices.Contains(roles, RoleAdmin),
+	}, nil
+}
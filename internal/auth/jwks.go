@@ -0,0 +1,245 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrKeySetUnavailable — набор ключей JWKS не удалось загрузить.
+	ErrKeySetUnavailable = errors.New("jwks is unavailable")
+	// ErrUnknownKey — в наборе JWKS нет ключа с указанным идентификатором.
+	ErrUnknownKey = errors.New("unknown signing key")
+)
+
+const (
+	// jwksRefreshInterval — период планового обновления набора ключей.
+	jwksRefreshInterval = 10 * time.Minute
+	// jwksMinRefreshInterval — минимальный интервал между внеплановыми обновлениями
+	// при встрече неизвестного kid; защищает источник JWKS от лавины запросов.
+	jwksMinRefreshInterval = 30 * time.Second
+	// jwksMaxSize ограничивает размер загружаемого документа JWKS.
+	jwksMaxSize = 1 << 20
+)
+
+// JWK — открытый ключ в формате JSON Web Key (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS — набор открытых ключей (RFC 7517, раздел 5).
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeySet загружает и кэширует открытые ключи JWKS из файла или по URL.
+//
+// Источник, начинающийся с http:// или https://, загружается по сети, иначе читается как путь к файлу.
+// Набор обновляется раз в jwksRefreshInterval, а также при встрече неизвестного kid
+// (не чаще, чем раз в jwksMinRefreshInterval), что позволяет ротировать ключи без перезапуска.
+type KeySet struct {
+	source string
+	client *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// NewKeySet создаёт набор ключей и выполняет первоначальную загрузку.
+//
+// Аргументы:
+//   - source: путь к файлу JWKS или URL.
+//
+// Возвращает:
+//   - указатель на KeySet.
+//   - ошибку, если набор не удалось загрузить или разобрать.
+func NewKeySet(source string) (*KeySet, error) {
+	ks := &KeySet{source: source, client: &http.Client{Timeout: 10 * time.Second}}
+	if err := ks.refresh(); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Key возвращает открытый ключ по идентификатору kid.
+//
+// Если kid пуст, а набор содержит ровно один ключ, возвращается этот ключ.
+//
+// Возвращает:
+//   - открытый ключ (*rsa.PublicKey, *ecdsa.PublicKey или ed25519.PublicKey).
+//   - ErrUnknownKey, если ключ не найден; ErrKeySetUnavailable, если обновление набора не удалось.
+func (ks *KeySet) Key(kid string) (crypto.PublicKey, error) {
+	ks.mu.RLock()
+	key, ok := ks.lookup(kid)
+	stale := time.Since(ks.fetchedAt) > jwksRefreshInterval
+	canRefresh := time.Since(ks.fetchedAt) > jwksMinRefreshInterval
+	ks.mu.RUnlock()
+
+	if ok && !stale {
+		return key, nil
+	}
+	if !ok && !canRefresh && !stale {
+		return nil, ErrUnknownKey
+	}
+
+	if err := ks.refresh(); err != nil {
+		// При недоступности источника продолжаем пользоваться ранее загруженным ключом
+		if ok {
+			return key, nil
+		}
+		return nil, err
+	}
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	if key, ok := ks.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// lookup ищет ключ в загруженном наборе. Вызывается под блокировкой.
+func (ks *KeySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(ks.keys) == 1 {
+		for _, key := range ks.keys {
+			return key, true
+		}
+	}
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+// refresh загружает набор ключей из источника и заменяет кэш.
+func (ks *KeySet) refresh() error {
+	raw, err := ks.fetch()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrKeySetUnavailable, err)
+	}
+
+	var set JWKS
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return fmt.Errorf("%w: malformed jwks: %v", ErrKeySetUnavailable, err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.PublicKey()
+		if err != nil {
+			return fmt.Errorf("%w: key %q: %v", ErrKeySetUnavailable, jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+
+	ks.mu.Lock()
+	ks.keys, ks.fetchedAt = keys, time.Now()
+	ks.mu.Unlock()
+	return nil
+}
+
+// fetch читает документ JWKS из файла или по URL.
+func (ks *KeySet) fetch() ([]byte, error) {
+	if !strings.HasPrefix(ks.source, "http://") && !strings.HasPrefix(ks.source, "https://") {
+		return os.ReadFile(ks.source)
+	}
+
+	resp, err := ks.client.Get(ks.source)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, jwksMaxSize))
+}
+
+// PublicKey преобразует JWK в открытый ключ Go.
+//
+// Поддерживаются ключи RSA, EC (P-256, P-384, P-521) и OKP (Ed25519).
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBase64URL(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBase64URL(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBase64URL(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBase64URL(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("point is not on curve")
+		}
+		return key, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBase64URL(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+// decodeBase64URL декодирует base64url без дополнения, как того требует RFC 7518.
+func decodeBase64URL(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(value)
+}
@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// MethodJWT — способ аутентификации по JWT внутреннего SSO.
+const MethodJWT = "jwt"
+
+// ErrInvalidToken — токен не прошёл проверку: неверная подпись, истёк срок действия,
+// не совпадает издатель или аудитория.
+var ErrInvalidToken = errors.New("invalid token")
+
+// jwtAlgorithms — допустимые алгоритмы подписи токенов. Симметричные алгоритмы (HS*)
+// исключены: ключи JWKS открытые и не должны использоваться как общий секрет.
+var jwtAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// JWTConfig задаёт параметры проверки JWT.
+type JWTConfig struct {
+	JWKS         string // Путь к файлу JWKS или URL
+	Issuer       string // Ожидаемый издатель (claim iss); пусто — не проверяется
+	Audience     string // Ожидаемая аудитория (claim aud); пусто — не проверяется
+	RolesClaim   string // Claim со списком ролей; допускается путь через точку, например realm_access.roles
+	WalletsClaim string // Claim со списком кошельков, принадлежащих пользователю
+}
+
+// JWTVerifier проверяет JWT и сопоставляет их claims с ролями и кошельками клиента.
+type JWTVerifier struct {
+	config JWTConfig
+	keys   *KeySet
+	parser *jwt.Parser
+}
+
+// NewJWTVerifier создаёт проверяющий объект и загружает набор ключей JWKS.
+//
+// Аргументы:
+//   - config: параметры проверки. Пустые RolesClaim и WalletsClaim заменяются на "roles" и "wallets".
+//
+// Возвращает:
+//   - указатель на JWTVerifier.
+//   - ошибку, если набор ключей не удалось загрузить.
+func NewJWTVerifier(config JWTConfig) (*JWTVerifier, error) {
+	if config.RolesClaim == "" {
+		config.RolesClaim = "roles"
+	}
+	if config.WalletsClaim == "" {
+		config.WalletsClaim = "wallets"
+	}
+
+	keys, err := NewKeySet(config.JWKS)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(jwtAlgorithms),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30 * time.Second),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+
+	return &JWTVerifier{config: config, keys: keys, parser: jwt.NewParser(options...)}, nil
+}
+
+// Verify проверяет токен и формирует описание клиента.
+//
+// Роли берутся из claim RolesClaim (массив строк или строка с ролями через пробел или запятую),
+// неизвестные роли игнорируются. Кошельки из claim WalletsClaim становятся кошельками,
+// с которых клиенту разрешено списание; роль admin разрешает списание с любого кошелька.
+//
+// Аргументы:
+//   - token: компактное представление JWT из заголовка Authorization.
+//
+// Возвращает:
+//   - *Principal для корректного токена.
+//   - ошибку, оборачивающую ErrInvalidToken, если токен не прошёл проверку,
+//     либо ErrKeySetUnavailable, если не удалось загрузить ключи.
+func (v *JWTVerifier) Verify(token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.Key(kid)
+	})
+	if err != nil {
+		if errors.Is(err, ErrKeySetUnavailable) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: sub claim is required", ErrInvalidToken)
+	}
+
+	var roles []Role
+	for _, name := range stringsClaim(claims, v.config.RolesClaim) {
+		role := Role(name)
+		if _, known := roleScopes[role]; known && !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+
+	name := subject
+	for _, claim := range []string{"preferred_username", "name", "email"} {
+		if value, ok := claims[claim].(string); ok && value != "" {
+			name = value
+			break
+		}
+	}
+
+	return &Principal{
+		Subject:    MethodJWT + ":" + subject,
+		Name:       name,
+		Method:     MethodJWT,
+		Roles:      roles,
+		Scopes:     ScopesForRoles(roles),
+		Wallets:    stringsClaim(claims, v.config.WalletsClaim),
+		AllWallets: slices.Contains(roles, RoleAdmin),
+	}, nil
+}
+
+// stringsClaim извлекает список строк из claim по пути через точку.
+//
+// Поддерживаются массивы строк и строки со значениями через пробел или запятую.
+func stringsClaim(claims jwt.MapClaims, path string) []string {
+	var value any = map[string]any(claims)
+	for _, part := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[part]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return strings.FieldsFunc(v, func(r rune) bool { return r == ' ' || r == ',' })
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,305 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testIssuer   = "https://sso.example.com"
+	testAudience = "transactions"
+)
+
+// testKey — ключ подписи тестового SSO.
+type testKey struct {
+	kid     string
+	private ed25519.PrivateKey
+}
+
+// newTestKey генерирует ключ Ed25519 с идентификатором kid.
+func newTestKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return testKey{kid: kid, private: private}
+}
+
+// jwk возвращает открытую часть ключа в формате JWK.
+func (k testKey) jwk() JWK {
+	x := k.private.Public().(ed25519.PublicKey)
+	return JWK{Kty: "OKP", Crv: "Ed25519", Kid: k.kid, Use: "sig", Alg: "EdDSA", X: base64.RawURLEncoding.EncodeToString(x)}
+}
+
+// sign выпускает токен, подписанный ключом.
+func (k testKey) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = k.kid
+	signed, err := token.SignedString(k.private)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+// jwksServer отдаёт набор ключей, который можно заменить во время проверки, и считает запросы к нему.
+type jwksServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	set      JWKS
+	down     bool
+	requests atomic.Int32
+}
+
+// newJWKSServer запускает сервер JWKS с указанными ключами.
+func newJWKSServer(t *testing.T, keys ...testKey) *jwksServer {
+	t.Helper()
+	s := &jwksServer{}
+	s.publish(keys...)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		s.requests.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.down {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(s.set)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// publish заменяет публикуемый набор ключей.
+func (s *jwksServer) publish(keys ...testKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set = JWKS{Keys: make([]JWK, 0, len(keys))}
+	for _, key := range keys {
+		s.set.Keys = append(s.set.Keys, key.jwk())
+	}
+}
+
+// setDown включает или выключает ответ 503 вместо набора ключей.
+func (s *jwksServer) setDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+// newTestVerifier создаёт проверяющий объект с набором ключей сервера.
+func newTestVerifier(t *testing.T, server *jwksServer) *JWTVerifier {
+	t.Helper()
+	v, err := NewJWTVerifier(JWTConfig{JWKS: server.URL, Issuer: testIssuer, Audience: testAudience})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier: %v", err)
+	}
+	return v
+}
+
+// validClaims возвращает claims токена, проходящего все проверки.
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":                "user-1",
+		"iss":                testIssuer,
+		"aud":                testAudience,
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"preferred_username": "alice",
+		"roles":              []string{"operator", "unknown"},
+		"wallets":            "aaaa,bbbb",
+	}
+}
+
+// withClaims возвращает validClaims с заменёнными значениями; nil удаляет claim.
+func withClaims(changes jwt.MapClaims) jwt.MapClaims {
+	claims := validClaims()
+	for name, value := range changes {
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+	}
+	return claims
+}
+
+// setFetchedAt сдвигает время последней загрузки набора ключей.
+func setFetchedAt(ks *KeySet, at time.Time) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.fetchedAt = at
+}
+
+func TestVerifyValidToken(t *testing.T) {
+	key := newTestKey(t, "key-1")
+	v := newTestVerifier(t, newJWKSServer(t, key))
+
+	principal, err := v.Verify(key.sign(t, validClaims()))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if principal.Subject != "jwt:user-1" || principal.Name != "alice" || principal.Method != MethodJWT {
+		t.Errorf("principal = %+v", principal)
+	}
+	if !slices.Equal(principal.Roles, []Role{RoleOperator}) {
+		t.Errorf("roles = %v, want only known roles", principal.Roles)
+	}
+	if !slices.Equal(principal.Wallets, []string{"aaaa", "bbbb"}) || principal.AllWallets {
+		t.Errorf("wallets = %v, all = %t", principal.Wallets, principal.AllWallets)
+	}
+
+	admin, err := v.Verify(key.sign(t, withClaims(jwt.MapClaims{"roles": "admin"})))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !admin.AllWallets {
+		t.Error("admin role does not allow debiting any wallet")
+	}
+
+	if _, err := v.Verify(key.sign(t, withClaims(jwt.MapClaims{"aud": []string{"other", testAudience}}))); err != nil {
+		t.Errorf("Verify with audience list: %v", err)
+	}
+}
+
+func TestVerifyRejectsDisallowedAlgorithms(t *testing.T) {
+	key := newTestKey(t, "key-1")
+	v := newTestVerifier(t, newJWKSServer(t, key))
+
+	// Открытый ключ из JWKS, использованный как общий секрет HMAC
+	hs256 := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+	hs256.Header["kid"] = key.kid
+	hs256Token, err := hs256.SignedString([]byte(key.private.Public().(ed25519.PublicKey)))
+	if err != nil {
+		t.Fatalf("failed to sign HS256 token: %v", err)
+	}
+
+	none := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims())
+	none.Header["kid"] = key.kid
+	noneToken, err := none.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("failed to sign unsecured token: %v", err)
+	}
+
+	for name, token := range map[string]string{"HS256": hs256Token, "none": noneToken} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := v.Verify(token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Verify error = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsInvalidClaims(t *testing.T) {
+	key := newTestKey(t, "key-1")
+	v := newTestVerifier(t, newJWKSServer(t, key))
+	now := time.Now()
+
+	tests := map[string]jwt.MapClaims{
+		"missing exp":         {"exp": nil},
+		"expired":             {"exp": now.Add(-time.Hour).Unix()},
+		"expired past leeway": {"exp": now.Add(-40 * time.Second).Unix()},
+		"not yet valid":       {"nbf": now.Add(40 * time.Second).Unix()},
+		"wrong issuer":        {"iss": "https://evil.example.com"},
+		"missing issuer":      {"iss": nil},
+		"wrong audience":      {"aud": "other-service"},
+		"missing subject":     {"sub": nil},
+	}
+	for name, changes := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := v.Verify(key.sign(t, withClaims(changes))); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Verify error = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestVerifyAcceptsClockSkewWithinLeeway(t *testing.T) {
+	key := newTestKey(t, "key-1")
+	v := newTestVerifier(t, newJWKSServer(t, key))
+	now := time.Now()
+
+	for name, changes := range map[string]jwt.MapClaims{
+		"expired within leeway":    {"exp": now.Add(-20 * time.Second).Unix()},
+		"not before within leeway": {"nbf": now.Add(20 * time.Second).Unix()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := v.Verify(key.sign(t, withClaims(changes))); err != nil {
+				t.Errorf("Verify: %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsUnknownKey(t *testing.T) {
+	key := newTestKey(t, "key-1")
+	server := newJWKSServer(t, key)
+	v := newTestVerifier(t, server)
+
+	// Токен подписан ключом с известным kid, но другим закрытым ключом
+	forged := testKey{kid: key.kid, private: newTestKey(t, "").private}
+	if _, err := v.Verify(forged.sign(t, validClaims())); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("forged signature: Verify error = %v, want ErrInvalidToken", err)
+	}
+
+	unknown := newTestKey(t, "key-2")
+	if _, err := v.Verify(unknown.sign(t, validClaims())); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("unknown kid: Verify error = %v, want ErrInvalidToken", err)
+	}
+	if requests := server.requests.Load(); requests != 1 {
+		t.Errorf("jwks fetched %d times, want 1: refresh on unknown kid must be rate-limited", requests)
+	}
+}
+
+func TestKeySetRefresh(t *testing.T) {
+	oldKey, newKey := newTestKey(t, "key-1"), newTestKey(t, "key-2")
+	server := newJWKSServer(t, oldKey)
+	v := newTestVerifier(t, server)
+
+	// Ключ ротирован: новый kid подхватывается внеплановым обновлением после jwksMinRefreshInterval
+	server.publish(oldKey, newKey)
+	token := newKey.sign(t, validClaims())
+	if _, err := v.Verify(token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Verify before refresh interval: error = %v, want ErrInvalidToken", err)
+	}
+	setFetchedAt(v.keys, time.Now().Add(-jwksMinRefreshInterval-time.Second))
+	if _, err := v.Verify(token); err != nil {
+		t.Fatalf("Verify after key rotation: %v", err)
+	}
+	if requests := server.requests.Load(); requests != 2 {
+		t.Errorf("jwks fetched %d times, want 2", requests)
+	}
+
+	// Устаревший набор обновляется плановым обновлением; отозванный ключ больше не принимается
+	server.publish(newKey)
+	setFetchedAt(v.keys, time.Now().Add(-jwksRefreshInterval-time.Second))
+	if _, err := v.Verify(oldKey.sign(t, validClaims())); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify with removed key: error = %v, want ErrInvalidToken", err)
+	}
+
+	// При недоступности источника используются ранее загруженные ключи
+	server.setDown(true)
+	setFetchedAt(v.keys, time.Now().Add(-jwksRefreshInterval-time.Second))
+	if _, err := v.Verify(newKey.sign(t, validClaims())); err != nil {
+		t.Errorf("Verify with unavailable jwks and cached key: %v", err)
+	}
+
+	// Неизвестный ключ при недоступном источнике — ошибка источника, а не токена
+	setFetchedAt(v.keys, time.Now().Add(-jwksMinRefreshInterval-time.Second))
+	if _, err := v.Verify(newTestKey(t, "key-3").sign(t, validClaims())); !errors.Is(err, ErrKeySetUnavailable) {
+		t.Errorf("Verify with unavailable jwks and unknown key: error = %v, want ErrKeySetUnavailable", err)
+	}
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// Scope — область доступа, выдаваемая аутентифицированному клиенту.
+type Scope string
+
+const (
+	// ScopeRead разрешает операции чтения: баланс, история транзакций.
+	ScopeRead Scope = "read"
+	// ScopeTransfer разрешает перевод средств с разрешённых кошельков.
+	ScopeTransfer Scope = "transfer"
+	// ScopeAdmin разрешает административные операции: эмиссию, корректировку, заморозку кошельков.
+	ScopeAdmin Scope = "admin"
+)
+
+// Scopes перечисляет все известные области доступа.
+var Scopes = []Scope{ScopeRead, ScopeTransfer, ScopeAdmin}
+
+// ValidScope проверяет, является ли строка известной областью доступа.
+func ValidScope(scope string) bool {
+	return slices.Contains(Scopes, Scope(scope))
+}
+
+// Role — роль пользователя внутреннего SSO, определяющая набор областей доступа.
+type Role string
+
+const (
+	// RoleViewer — просмотр балансов и истории.
+	RoleViewer Role = "viewer"
+	// RoleOperator — просмотр и переводы с принадлежащих пользователю кошельков.
+	RoleOperator Role = "operator"
+	// RoleAdmin — полный доступ, включая переводы с любого кошелька и административные операции.
+	RoleAdmin Role = "admin"
+)
+
+// roleScopes сопоставляет ролям выдаваемые области доступа.
+var roleScopes = map[Role][]Scope{
+	RoleViewer:   {ScopeRead},
+	RoleOperator: {ScopeRead, ScopeTransfer},
+	RoleAdmin:    {ScopeRead, ScopeTransfer, ScopeAdmin},
+}
+
+// ScopesForRoles возвращает объединение областей доступа указанных ролей.
+// Неизвестные роли игнорируются.
+func ScopesForRoles(roles []Role) []Scope {
+	var scopes []Scope
+	for _, role := range roles {
+		for _, scope := range roleScopes[role] {
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// Principal описывает аутентифицированного клиента API.
+type Principal struct {
+	Subject    string   // Идентификатор клиента, например "api_key:3" или "jwt:alice"
+	Name       string   // Человекочитаемое имя клиента
+	Method     string   // Способ аутентификации, например "api_key" или "jwt"
+	Roles      []Role   // Роли пользователя SSO (для API-ключей не заполняются)
+	Scopes     []Scope  // Разрешённые области доступа
+	Wallets    []string // Кошельки, с которых разрешено списание
+	AllWallets bool     // Разрешено ли списание с любого кошелька
+}
+
+// HasRole проверяет, назначена ли клиенту указанная роль.
+func (p *Principal) HasRole(role Role) bool {
+	return p != nil && slices.Contains(p.Roles, role)
+}
+
+// HasScope проверяет, выдана ли клиенту указанная область доступа.
+func (p *Principal) HasScope(scope Scope) bool {
+	return p != nil && slices.Contains(p.Scopes, scope)
+}
+
+// CanDebit проверяет, разрешено ли клиенту списывать средства с указанного кошелька.
+func (p *Principal) CanDebit(address string) bool {
+	if p == nil || !p.HasScope(ScopeTransfer) {
+		return false
+	}
+	return p.AllWallets || slices.Contains(p.Wallets, address)
+}
+
+// principalKey — ключ контекста для хранения аутентифицированного клиента.
+type principalKey struct{}
+
+// WithPrincipal возвращает копию контекста с указанным клиентом.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext возвращает аутентифицированного клиента из контекста или nil.
+func FromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
@@ -0,0 +1,329 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+)
+
+// Config — конфигурация приложения.
+//
+// Каждое поле описывается тегами:
+//   - key — имя ключа в файле конфигурации; для вложенных полей ключ и имя флага образуются
+//     через точку, например server.port;
+//   - env — переменная окружения;
+//   - usage — описание, выводимое в справке по флагам.
+//
+// Значения собираются из источников в порядке возрастания приоритета: значения по умолчанию,
+// файл конфигурации (YAML или TOML), переменные окружения, флаги командной строки (см. Load).
+type Config struct {
+	Server      ServerConfig      `key:"server"`
+	TLS         TLSConfig         `key:"tls"`
+	HTTP        HTTPConfig        `key:"http"`
+	RateLimit   RateLimitConfig   `key:"rate_limit"`
+	Idempotency IdempotencyConfig `key:"idempotency"`
+	Database    DatabaseConfig    `key:"database"`
+	Auth        AuthConfig        `key:"auth"`
+	Ledger      LedgerConfig      `key:"ledger"`
+	Snapshots   SnapshotsConfig   `key:"snapshots"`
+	Projections ProjectionsConfig `key:"projections"`
+	Backup      BackupConfig      `key:"backup"`
+	Seed        SeedConfig        `key:"seed"`
+	Tracing     TracingConfig     `key:"tracing"`
+	Logging     LoggingConfig     `key:"logging"`
+
+	// sources хранит источник итогового значения каждого ключа для Print
+	sources map[string]string
+}
+
+// ServerConfig — параметры HTTP-сервера.
+type ServerConfig struct {
+	Port            int           `key:"port" env:"PORT" usage:"порт HTTP-сервера"`
+	ReadTimeout     time.Duration `key:"read_timeout" env:"SERVER_READ_TIMEOUT" usage:"таймаут чтения запроса"`
+	WriteTimeout    time.Duration `key:"write_timeout" env:"SERVER_WRITE_TIMEOUT" usage:"таймаут записи ответа"`
+	MaxHeaderBytes  int           `key:"max_header_bytes" env:"SERVER_MAX_HEADER_BYTES" usage:"максимальный размер заголовков запроса в байтах"`
+	ShutdownTimeout time.Duration `key:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT" usage:"время на завершение обработки запросов при остановке"`
+	DrainDelay      time.Duration `key:"drain_delay" env:"SERVER_DRAIN_DELAY" usage:"пауза между сигналом завершения и остановкой сервера, в течение которой /readyz не проходит"`
+}
+
+// Режимы проверки клиентских сертификатов.
+const (
+	TLSClientAuthOptional = "optional"
+	TLSClientAuthRequire  = "require"
+)
+
+// TLSConfig — параметры TLS HTTP-сервера. Если сертификат не задан, сервер принимает соединения без TLS.
+type TLSConfig struct {
+	Cert       string `key:"cert" env:"TLS_CERT" usage:"файл сертификата сервера (PEM, с цепочкой промежуточных); пусто — TLS отключён"`
+	Key        string `key:"key" env:"TLS_KEY" usage:"файл закрытого ключа сервера (PEM)"`
+	ClientCA   string `key:"client_ca" env:"TLS_CLIENT_CA" usage:"файл корневых сертификатов для проверки клиентских сертификатов (mTLS); пусто — клиентские сертификаты не запрашиваются"`
+	ClientAuth string `key:"client_auth" env:"TLS_CLIENT_AUTH" usage:"проверка клиентских сертификатов: optional — если предъявлен, require — обязательно"`
+}
+
+// Enabled сообщает, включён ли TLS.
+func (c TLSConfig) Enabled() bool {
+	return c.Cert != ""
+}
+
+// HTTPConfig — параметры обработки запросов к API.
+type HTTPConfig struct {
+	MaxBodyBytes  int            `key:"max_body_bytes" env:"HTTP_MAX_BODY_BYTES" usage:"максимальный размер тела запроса в байтах"`
+	Timeout       time.Duration  `key:"timeout" env:"HTTP_TIMEOUT" usage:"время на обработку запроса к API"`
+	RouteTimeouts RouteDurations `key:"route_timeouts" env:"HTTP_ROUTE_TIMEOUTS" usage:"время на обработку запросов к отдельным операциям: <operationId>=<длительность>,..."`
+	CORSOrigins   string         `key:"cors_origins" env:"HTTP_CORS_ORIGINS" usage:"источники, которым разрешены запросы из браузера (CORS), через запятую; * — любые; пусто — CORS отключён"`
+	Gzip          bool           `key:"gzip" env:"HTTP_GZIP" usage:"сжимать ответы gzip, если клиент это поддерживает"`
+}
+
+// RateLimitConfig — лимиты запросов к API.
+type RateLimitConfig struct {
+	Enabled bool            `key:"enabled" env:"RATE_LIMIT_ENABLED" usage:"ограничивать частоту запросов к API"`
+	Client  RateLimit       `key:"client" env:"RATE_LIMIT_CLIENT" usage:"лимит запросов клиента (API-ключа, пользователя SSO или IP-адреса) к операции: <запросов>/<период>"`
+	Routes  RouteRateLimits `key:"routes" env:"RATE_LIMIT_ROUTES" usage:"лимиты запросов клиента к отдельным операциям: <operationId>=<запросов>/<период>,..."`
+	Wallet  RateLimit       `key:"wallet" env:"RATE_LIMIT_WALLET" usage:"лимит переводов с одного кошелька: <запросов>/<период>"`
+	IP      RateLimit       `key:"ip" env:"RATE_LIMIT_IP" usage:"лимит всех запросов к API с одного IP-адреса, в том числе с неверными учётными данными: <запросов>/<период>"`
+}
+
+// DatabaseConfig — параметры базы данных.
+type DatabaseConfig struct {
+	Path        string        `key:"path" env:"DB_PATH" usage:"путь к файлу базы данных SQLite"`
+	Migrations  string        `key:"migrations" env:"DB_MIGRATIONS" usage:"каталог миграций"`
+	BusyTimeout time.Duration `key:"busy_timeout" env:"DB_BUSY_TIMEOUT" usage:"время ожидания блокировки базы данных"`
+}
+
+// AuthConfig — параметры аутентификации по JWT внутреннего SSO.
+type AuthConfig struct {
+	JWKS         string `key:"jwks" env:"JWKS" usage:"путь к файлу JWKS или URL; пусто — аутентификация по JWT отключена"`
+	Issuer       string `key:"issuer" env:"JWT_ISSUER" usage:"ожидаемый издатель JWT"`
+	Audience     string `key:"audience" env:"JWT_AUDIENCE" usage:"ожидаемая аудитория JWT"`
+	RolesClaim   string `key:"roles_claim" env:"JWT_ROLES_CLAIM" usage:"claim JWT со списком ролей"`
+	WalletsClaim string `key:"wallets_claim" env:"JWT_WALLETS_CLAIM" usage:"claim JWT со списком кошельков"`
+}
+
+// LedgerConfig — параметры контрольных точек журнала транзакций.
+type LedgerConfig struct {
+	Key                string        `key:"key" env:"LEDGER_KEY" usage:"файл ключа подписи контрольных точек"`
+	CheckpointInterval time.Duration `key:"checkpoint_interval" env:"LEDGER_CHECKPOINT_INTERVAL" usage:"интервал создания контрольных точек"`
+}
+
+// IdempotencyConfig — параметры хранения ответов на запросы с ключом идемпотентности (заголовок Idempotency-Key).
+type IdempotencyConfig struct {
+	TTL             time.Duration `key:"ttl" env:"IDEMPOTENCY_TTL" usage:"время хранения ответов на запросы с ключом идемпотентности"`
+	CleanupInterval time.Duration `key:"cleanup_interval" env:"IDEMPOTENCY_CLEANUP_INTERVAL" usage:"интервал удаления устаревших ключей идемпотентности"`
+}
+
+// SnapshotsConfig — параметры снимков балансов.
+type SnapshotsConfig struct {
+	Interval time.Duration `key:"interval" env:"SNAPSHOT_INTERVAL" usage:"интервал создания снимков балансов"`
+}
+
+// ProjectionsConfig — параметры проекций журнала транзакций.
+type ProjectionsConfig struct {
+	Interval time.Duration `key:"interval" env:"PROJECTIONS_INTERVAL" usage:"интервал обновления проекций"`
+}
+
+// BackupConfig — параметры резервного копирования базы данных.
+type BackupConfig struct {
+	Dir      string        `key:"dir" env:"BACKUP_DIR" usage:"каталог резервных копий; пусто — резервное копирование сервером отключено"`
+	Interval time.Duration `key:"interval" env:"BACKUP_INTERVAL" usage:"интервал создания резервных копий"`
+	Keep     int           `key:"keep" env:"BACKUP_KEEP" usage:"количество хранимых резервных копий; 0 — не удалять"`
+}
+
+// SeedConfig — параметры начального заполнения базы данных кошельками: при первом запуске сервера
+// и командой seed.
+type SeedConfig struct {
+	Count        int             `key:"count" env:"SEED_COUNT" usage:"количество кошельков, создаваемых в пустой базе; 0 — не создавать"`
+	Balance      decimal.Decimal `key:"balance" env:"SEED_BALANCE" usage:"средний начальный баланс создаваемых кошельков"`
+	Distribution string          `key:"distribution" env:"SEED_DISTRIBUTION" usage:"распределение начальных балансов: fixed, uniform или exponential"`
+	KeySeed      string          `key:"key_seed" env:"SEED_KEY_SEED" usage:"строка для детерминированного вывода ключей и балансов командой seed (только для тестов); пусто — случайные"`
+	Fixture      string          `key:"fixture" env:"SEED_FIXTURE" usage:"файл CSV или JSON с кошельками и балансами, импортируемыми вместо создания кошельков"`
+	WalletKeys   string          `key:"wallet_keys" env:"WALLET_KEYS" usage:"файл для закрытых ключей созданных кошельков"`
+}
+
+// Экспортёры трассировок.
+const (
+	TracingExporterNone   = "none"
+	TracingExporterStdout = "stdout"
+	TracingExporterOTLP   = "otlp"
+)
+
+// TracingConfig — параметры трассировки OpenTelemetry.
+type TracingConfig struct {
+	Exporter    string `key:"exporter" env:"TRACING_EXPORTER" usage:"экспортёр трассировок: none, stdout или otlp"`
+	Endpoint    string `key:"endpoint" env:"TRACING_ENDPOINT" usage:"адрес коллектора OTLP/HTTP (host:port)"`
+	Insecure    bool   `key:"insecure" env:"TRACING_INSECURE" usage:"отправлять трассировки коллектору OTLP без TLS"`
+	ServiceName string `key:"service_name" env:"TRACING_SERVICE_NAME" usage:"имя сервиса в трассировках"`
+}
+
+// LoggingConfig — параметры журнала приложения.
+type LoggingConfig struct {
+	Level string `key:"level" env:"LOG_LEVEL" usage:"минимальный уровень записей журнала: debug, info, warn или error"`
+}
+
+// maxSeedCount ограничивает количество кошельков, создаваемых при начальном заполнении.
+const maxSeedCount = 10000
+
+// Default возвращает конфигурацию со значениями по умолчанию.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:            8080,
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    10 * time.Second,
+			MaxHeaderBytes:  1 << 20,
+			ShutdownTimeout: 5 * time.Second,
+			DrainDelay:      5 * time.Second,
+		},
+		TLS: TLSConfig{ClientAuth: TLSClientAuthOptional},
+		HTTP: HTTPConfig{
+			MaxBodyBytes: 64 << 10,
+			Timeout:      5 * time.Second,
+			RouteTimeouts: RouteDurations{
+				"getStatement":           time.Minute,
+				"adminReconcile":         time.Minute,
+				"adminRebuildProjection": 5 * time.Minute,
+			},
+			Gzip: true,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Client:  RateLimit{Requests: 100, Period: time.Second},
+			Routes: RouteRateLimits{
+				"send":                   {Requests: 20, Period: time.Second},
+				"adminGenerateWallets":   {Requests: 10, Period: time.Minute},
+				"adminReconcile":         {Requests: 5, Period: time.Minute},
+				"adminRebuildProjection": {Requests: 5, Period: time.Minute},
+			},
+			Wallet: RateLimit{Requests: 5, Period: time.Second},
+			IP:     RateLimit{Requests: 300, Period: time.Second},
+		},
+		Idempotency: IdempotencyConfig{
+			TTL:             24 * time.Hour,
+			CleanupInterval: time.Hour,
+		},
+		Database: DatabaseConfig{
+			Path:        "./database.db",
+			Migrations:  "migrations",
+			BusyTimeout: 5 * time.Second,
+		},
+		Auth: AuthConfig{
+			RolesClaim:   "roles",
+			WalletsClaim: "wallets",
+		},
+		Ledger: LedgerConfig{
+			Key:                "./ledger_key.json",
+			CheckpointInterval: time.Hour,
+		},
+		Snapshots:   SnapshotsConfig{Interval: time.Hour},
+		Projections: ProjectionsConfig{Interval: time.Minute},
+		Backup: BackupConfig{
+			Interval: 24 * time.Hour,
+			Keep:     7,
+		},
+		Seed: SeedConfig{
+			Count:        10,
+			Balance:      decimal.NewFromInt(100),
+			Distribution: dto.SeedDistributionFixed,
+			WalletKeys:   "./wallet_keys.json",
+		},
+		Tracing: TracingConfig{
+			Exporter:    TracingExporterNone,
+			Endpoint:    "localhost:4318",
+			ServiceName: "transactions",
+		},
+		Logging: LoggingConfig{Level: "info"},
+	}
+}
+
+// Validate проверяет значения конфигурации.
+//
+// Возвращает:
+//   - ошибку со списком всех некорректных ключей или nil.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: "+format, append([]any{key}, args...)...))
+		}
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "server.port", "must be between 1 and 65535")
+	check(c.Server.ReadTimeout > 0, "server.read_timeout", "must be positive")
+	check(c.Server.WriteTimeout > 0, "server.write_timeout", "must be positive")
+	check(c.Server.MaxHeaderBytes > 0, "server.max_header_bytes", "must be positive")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout", "must be positive")
+	check(c.Server.DrainDelay >= 0, "server.drain_delay", "must not be negative")
+
+	check(c.TLS.Key == "" || c.TLS.Cert != "", "tls.cert", "must be set together with tls.key")
+	check(c.TLS.Cert == "" || c.TLS.Key != "", "tls.key", "must be set together with tls.cert")
+	check(c.TLS.ClientCA == "" || c.TLS.Cert != "", "tls.client_ca", "requires tls.cert")
+	check(c.TLS.ClientAuth == TLSClientAuthOptional || c.TLS.ClientAuth == TLSClientAuthRequire,
+		"tls.client_auth", "must be one of %s, %s", TLSClientAuthOptional, TLSClientAuthRequire)
+
+	check(c.HTTP.MaxBodyBytes > 0, "http.max_body_bytes", "must be positive")
+	check(c.HTTP.Timeout > 0, "http.timeout", "must be positive")
+	for _, id := range slices.Sorted(maps.Keys(c.HTTP.RouteTimeouts)) {
+		check(c.HTTP.RouteTimeouts[id] > 0, "http.route_timeouts", "timeout of %s must be positive", id)
+	}
+
+	if err := c.RateLimit.Client.validate(); err != nil {
+		check(false, "rate_limit.client", "%v", err)
+	}
+	for _, id := range slices.Sorted(maps.Keys(c.RateLimit.Routes)) {
+		if err := c.RateLimit.Routes[id].validate(); err != nil {
+			check(false, "rate_limit.routes", "%s: %v", id, err)
+		}
+	}
+	if err := c.RateLimit.Wallet.validate(); err != nil {
+		check(false, "rate_limit.wallet", "%v", err)
+	}
+	if err := c.RateLimit.IP.validate(); err != nil {
+		check(false, "rate_limit.ip", "%v", err)
+	}
+
+	check(c.Idempotency.TTL > 0, "idempotency.ttl", "must be positive")
+	check(c.Idempotency.CleanupInterval > 0, "idempotency.cleanup_interval", "must be positive")
+
+	check(c.Database.Path != "", "database.path", "must not be empty")
+	check(c.Database.Migrations != "", "database.migrations", "must not be empty")
+	check(c.Database.BusyTimeout >= 0, "database.busy_timeout", "must not be negative")
+
+	check(c.Auth.RolesClaim != "", "auth.roles_claim", "must not be empty")
+	check(c.Auth.WalletsClaim != "", "auth.wallets_claim", "must not be empty")
+
+	check(c.Ledger.Key != "", "ledger.key", "must not be empty")
+	check(c.Ledger.CheckpointInterval > 0, "ledger.checkpoint_interval", "must be positive")
+	check(c.Snapshots.Interval > 0, "snapshots.interval", "must be positive")
+	check(c.Projections.Interval > 0, "projections.interval", "must be positive")
+	check(c.Backup.Interval > 0, "backup.interval", "must be positive")
+	check(c.Backup.Keep >= 0, "backup.keep", "must not be negative")
+
+	check(c.Seed.Count >= 0 && c.Seed.Count <= maxSeedCount, "seed.count", "must be between 0 and %d", maxSeedCount)
+	check(!c.Seed.Balance.IsNegative(), "seed.balance", "must not be negative")
+	switch c.Seed.Distribution {
+	case dto.SeedDistributionFixed, dto.SeedDistributionUniform, dto.SeedDistributionExponential:
+	default:
+		check(false, "seed.distribution", "must be one of %s, %s, %s",
+			dto.SeedDistributionFixed, dto.SeedDistributionUniform, dto.SeedDistributionExponential)
+	}
+	check(c.Seed.WalletKeys != "", "seed.wallet_keys", "must not be empty")
+
+	switch c.Tracing.Exporter {
+	case TracingExporterNone, TracingExporterStdout, TracingExporterOTLP:
+	default:
+		check(false, "tracing.exporter", "must be one of %s, %s, %s", TracingExporterNone, TracingExporterStdout, TracingExporterOTLP)
+	}
+	check(c.Tracing.Exporter != TracingExporterOTLP || c.Tracing.Endpoint != "", "tracing.endpoint", "must not be empty")
+	check(c.Tracing.ServiceName != "", "tracing.service_name", "must not be empty")
+
+	var level slog.Level
+	check(level.UnmarshalText([]byte(c.Logging.Level)) == nil, "logging.level", "must be one of debug, info, warn, error")
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,307 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileEnv — переменная окружения с путём к файлу конфигурации; флаг -config имеет приоритет над ней.
+const FileEnv = "CONFIG_FILE"
+
+// Источники значений конфигурации, выводимые Print.
+const (
+	sourceDefault = "default"
+	sourceFile    = "file"
+	sourceEnv     = "env"
+	sourceFlag    = "flag"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	decimalType  = reflect.TypeOf(decimal.Decimal{})
+
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// field — ключ конфигурации, связанный с полем структуры Config.
+type field struct {
+	key   string        // Полное имя ключа через точку, например server.port
+	env   string        // Переменная окружения
+	usage string        // Описание для справки по флагам
+	value reflect.Value // Адресуемое значение поля
+}
+
+// Load собирает конфигурацию из значений по умолчанию, файла конфигурации, переменных окружения
+// и флагов командной строки; каждый следующий источник переопределяет предыдущий.
+//
+// Файл задаётся флагом -config или переменной CONFIG_FILE; формат определяется расширением
+// (.yaml, .yml или .toml). Неизвестные ключи в файле считаются ошибкой. Пустые переменные
+// окружения не учитываются.
+//
+// Аргументы:
+//   - flags: набор флагов, в котором регистрируются -config и флаги всех ключей (например, -server.port);
+//     вызывающая сторона может заранее добавить в него собственные флаги. nil — флаги не разбираются.
+//   - args: аргументы командной строки для разбора.
+//
+// Возвращает:
+//   - проверенную конфигурацию или ошибку чтения файла, разбора значения или проверки (см. Validate).
+func Load(flags *flag.FlagSet, args []string) (*Config, error) {
+	cfg := Default()
+	fields := cfg.fields()
+	cfg.sources = make(map[string]string, len(fields))
+	for _, f := range fields {
+		cfg.sources[f.key] = sourceDefault
+	}
+
+	// Значения флагов запоминаем и применяем последними, после файла и переменных окружения
+	file := os.Getenv(FileEnv)
+	flagValues := make(map[string]string)
+	if flags != nil {
+		flags.StringVar(&file, "config", file, "файл конфигурации YAML или TOML (переменная "+FileEnv+")")
+		for _, f := range fields {
+			usage := f.usage + " (переменная " + f.env + ")"
+			set := func(value string) error {
+				flagValues[f.key] = value
+				return nil
+			}
+			// Логический флаг, как и в пакете flag, можно указать без значения: -tracing.insecure
+			if f.value.Kind() == reflect.Bool {
+				flags.BoolFunc(f.key, usage, set)
+			} else {
+				flags.Func(f.key, usage, set)
+			}
+		}
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+	}
+
+	if file != "" {
+		values, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := cfg.apply(fields, values, sourceFile+" "+file); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, f := range fields {
+		if value := os.Getenv(f.env); value != "" {
+			if err := f.set(value); err != nil {
+				return nil, fmt.Errorf("%s: invalid value %q of %s %s: %w", f.key, value, sourceEnv, f.env, err)
+			}
+			cfg.sources[f.key] = sourceEnv + " " + f.env
+		}
+	}
+
+	for _, f := range fields {
+		if value, ok := flagValues[f.key]; ok {
+			if err := f.set(value); err != nil {
+				return nil, fmt.Errorf("%s: invalid value %q of %s -%s: %w", f.key, value, sourceFlag, f.key, err)
+			}
+			cfg.sources[f.key] = sourceFlag + " -" + f.key
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
+}
+
+// apply устанавливает значения ключей из файла конфигурации.
+//
+// Аргументы:
+//   - fields: ключи конфигурации.
+//   - values: значения из файла по полным именам ключей.
+//   - source: источник значений для Print.
+//
+// Возвращает:
+//   - ошибку со списком неизвестных ключей и некорректных значений или nil.
+func (c *Config) apply(fields []field, values map[string]string, source string) error {
+	known := make(map[string]field, len(fields))
+	for _, f := range fields {
+		known[f.key] = f
+	}
+
+	var errs []error
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		f, ok := known[key]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: unknown key", key))
+			continue
+		}
+		if err := f.set(values[key]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid value %q: %w", key, values[key], err))
+			continue
+		}
+		c.sources[key] = source
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration file %s:\n%w", strings.TrimPrefix(source, sourceFile+" "), errors.Join(errs...))
+	}
+	return nil
+}
+
+// readFile читает файл конфигурации YAML или TOML.
+//
+// Возвращает:
+//   - значения скалярных ключей по полным именам через точку и ошибку чтения или разбора.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file: %w", err)
+	}
+
+	document := make(map[string]any)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &document)
+	case ".toml":
+		err = toml.Unmarshal(data, &document)
+	default:
+		return nil, fmt.Errorf("configuration file %s: unsupported format, expected .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	if err := flatten("", document, values); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// flatten переносит значения вложенных таблиц документа в values с полными именами ключей через точку.
+func flatten(prefix string, document map[string]any, values map[string]string) error {
+	for name, value := range document {
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		switch value := value.(type) {
+		case map[string]any:
+			if err := flatten(key, value, values); err != nil {
+				return err
+			}
+		case string:
+			values[key] = value
+		case int, int64, uint64, bool:
+			values[key] = fmt.Sprint(value)
+		case float64:
+			values[key] = strconv.FormatFloat(value, 'f', -1, 64)
+		case nil:
+			return fmt.Errorf("%s: value is empty", key)
+		default:
+			return fmt.Errorf("%s: expected a scalar value, got %T", key, value)
+		}
+	}
+	return nil
+}
+
+// fields возвращает ключи конфигурации в порядке объявления полей.
+func (c *Config) fields() []field {
+	var fields []field
+	var walk func(prefix string, value reflect.Value)
+	walk = func(prefix string, value reflect.Value) {
+		for i := 0; i < value.NumField(); i++ {
+			structField := value.Type().Field(i)
+			name, ok := structField.Tag.Lookup("key")
+			if !ok {
+				continue
+			}
+
+			key := name
+			if prefix != "" {
+				key = prefix + "." + name
+			}
+			if structField.Type.Kind() == reflect.Struct && structField.Type != decimalType &&
+				!reflect.PointerTo(structField.Type).Implements(textUnmarshalerType) {
+				walk(key, value.Field(i))
+				continue
+			}
+			fields = append(fields, field{
+				key:   key,
+				env:   structField.Tag.Get("env"),
+				usage: structField.Tag.Get("usage"),
+				value: value.Field(i),
+			})
+		}
+	}
+	walk("", reflect.ValueOf(c).Elem())
+	return fields
+}
+
+// set разбирает строковое значение и присваивает его полю.
+func (f field) set(value string) error {
+	switch {
+	case f.value.Type() == durationType:
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		f.value.SetInt(int64(duration))
+	case f.value.Type() == decimalType:
+		amount, err := decimal.NewFromString(value)
+		if err != nil {
+			return err
+		}
+		f.value.Set(reflect.ValueOf(amount))
+	case f.value.Kind() == reflect.Int:
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.New("expected an integer")
+		}
+		f.value.SetInt(int64(number))
+	case f.value.Kind() == reflect.Bool:
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("expected true or false")
+		}
+		f.value.SetBool(enabled)
+	case f.value.Kind() == reflect.String:
+		f.value.SetString(value)
+	case f.value.Addr().Type().Implements(textUnmarshalerType):
+		return f.value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	default:
+		return fmt.Errorf("unsupported type %s", f.value.Type())
+	}
+	return nil
+}
+
+// format возвращает значение поля в записи, пригодной для YAML и TOML.
+// Длительности и суммы выводятся строками, чтобы при обратном чтении не потерять единицы и точность.
+func (f field) format() string {
+	switch {
+	case f.value.Type() == durationType:
+		return strconv.Quote(time.Duration(f.value.Int()).String())
+	case f.value.Type() == decimalType:
+		return strconv.Quote(f.value.Interface().(decimal.Decimal).String())
+	case f.value.Kind() == reflect.Int:
+		return strconv.FormatInt(f.value.Int(), 10)
+	case f.value.Kind() == reflect.Bool:
+		return strconv.FormatBool(f.value.Bool())
+	case f.value.Type().Implements(textMarshalerType):
+		text, _ := f.value.Interface().(encoding.TextMarshaler).MarshalText()
+		return strconv.Quote(string(text))
+	default:
+		return strconv.Quote(f.value.String())
+	}
+}
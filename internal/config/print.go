@@ -0,0 +1,58 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Форматы вывода конфигурации.
+const (
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// Print выводит итоговые значения конфигурации в формате YAML или TOML.
+// Для каждого ключа в комментарии указывается источник значения: default, file <путь>,
+// env <переменная> или flag -<ключ>. Вывод можно использовать как файл конфигурации.
+//
+// Аргументы:
+//   - w: получатель вывода.
+//   - format: FormatYAML или FormatTOML.
+//
+// Возвращает:
+//   - ошибку записи или неизвестного формата.
+func (c *Config) Print(w io.Writer, format string) error {
+	if format != FormatYAML && format != FormatTOML {
+		return fmt.Errorf("unsupported format %q, expected %s or %s", format, FormatYAML, FormatTOML)
+	}
+
+	out := bufio.NewWriter(w)
+	section := ""
+	for _, f := range c.fields() {
+		prefix, name, _ := strings.Cut(f.key, ".")
+		if prefix != section {
+			if section != "" {
+				_, _ = fmt.Fprintln(out)
+			}
+			section = prefix
+			if format == FormatYAML {
+				_, _ = fmt.Fprintf(out, "%s:\n", section)
+			} else {
+				_, _ = fmt.Fprintf(out, "[%s]\n", section)
+			}
+		}
+
+		source := c.sources[f.key]
+		if source == "" {
+			source = sourceDefault
+		}
+		if format == FormatYAML {
+			_, _ = fmt.Fprintf(out, "  %s: %s # %s\n", name, f.format(), source)
+		} else {
+			_, _ = fmt.Fprintf(out, "%s = %s # %s\n", name, f.format(), source)
+		}
+	}
+	return out.Flush()
+}
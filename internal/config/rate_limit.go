@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit задаёт лимит запросов: не более Requests запросов за Period.
+// Нулевое значение Requests снимает ограничение.
+//
+// В файле конфигурации, переменной окружения и флаге записывается строкой "<запросов>/<период>",
+// например "20/1s" или "100/1m"; "0" — без ограничения.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Unlimited сообщает, снято ли ограничение.
+func (l RateLimit) Unlimited() bool {
+	return l.Requests == 0
+}
+
+// UnmarshalText разбирает запись "<запросов>/<период>" или "0".
+func (l *RateLimit) UnmarshalText(text []byte) error {
+	value := strings.TrimSpace(string(text))
+	if value == "0" {
+		*l = RateLimit{}
+		return nil
+	}
+	requests, period, ok := strings.Cut(value, "/")
+	if !ok {
+		return fmt.Errorf("expected <requests>/<period>, got %q", value)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil {
+		return fmt.Errorf("invalid number of requests %q", requests)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil {
+		return err
+	}
+	*l = RateLimit{Requests: n, Period: d}
+	return nil
+}
+
+// MarshalText записывает лимит в виде "<запросов>/<период>" или "0", если ограничение снято.
+func (l RateLimit) MarshalText() ([]byte, error) {
+	if l.Unlimited() {
+		return []byte("0"), nil
+	}
+	return []byte(strconv.Itoa(l.Requests) + "/" + l.Period.String()), nil
+}
+
+// validate проверяет, что лимит задаёт положительное число запросов за положительный период.
+func (l RateLimit) validate() error {
+	switch {
+	case l.Unlimited():
+		return nil
+	case l.Requests < 0:
+		return fmt.Errorf("number of requests must not be negative")
+	case l.Period <= 0:
+		return fmt.Errorf("period must be positive")
+	}
+	return nil
+}
+
+// RouteRateLimits задаёт лимиты запросов для отдельных операций API по их идентификаторам
+// (operationId в спецификации /api/openapi.json).
+//
+// Записывается строкой "<operationId>=<запросов>/<период>,...", например "send=10/1s,adminReconcile=5/1m".
+type RouteRateLimits map[string]RateLimit
+
+// UnmarshalText разбирает запись "<operationId>=<запросов>/<период>,...". Пустая строка задаёт пустой набор.
+func (r *RouteRateLimits) UnmarshalText(text []byte) error {
+	limits := make(RouteRateLimits)
+	for _, item := range strings.Split(string(text), ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		id, value, ok := strings.Cut(item, "=")
+		id = strings.TrimSpace(id)
+		if !ok || id == "" {
+			return fmt.Errorf("expected <operationId>=<requests>/<period>, got %q", item)
+		}
+		var limit RateLimit
+		if err := limit.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+		limits[id] = limit
+	}
+	*r = limits
+	return nil
+}
+
+// MarshalText записывает лимиты в виде "<operationId>=<запросов>/<период>,..." в порядке идентификаторов.
+func (r RouteRateLimits) MarshalText() ([]byte, error) {
+	items := make([]string, 0, len(r))
+	for _, id := range slices.Sorted(maps.Keys(r)) {
+		limit, _ := r[id].MarshalText()
+		items = append(items, id+"="+string(limit))
+	}
+	return []byte(strings.Join(items, ",")), nil
+}
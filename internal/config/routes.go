@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RouteDurations задаёт длительности для отдельных операций API по их идентификаторам
+// (operationId в спецификации /api/openapi.json).
+//
+// В файле конфигурации, переменной окружения и флаге записывается строкой
+// "<operationId>=<длительность>,...", например "getStatement=1m,adminReconcile=2m".
+type RouteDurations map[string]time.Duration
+
+// UnmarshalText разбирает запись "<operationId>=<длительность>,...". Пустая строка задаёт пустой набор.
+func (d *RouteDurations) UnmarshalText(text []byte) error {
+	durations := make(RouteDurations)
+	for _, item := range strings.Split(string(text), ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		id, value, ok := strings.Cut(item, "=")
+		id = strings.TrimSpace(id)
+		if !ok || id == "" {
+			return fmt.Errorf("expected <operationId>=<duration>, got %q", item)
+		}
+		duration, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+		durations[id] = duration
+	}
+	*d = durations
+	return nil
+}
+
+// MarshalText записывает длительности в виде "<operationId>=<длительность>,..." в порядке идентификаторов.
+func (d RouteDurations) MarshalText() ([]byte, error) {
+	items := make([]string, 0, len(d))
+	for _, id := range slices.Sorted(maps.Keys(d)) {
+		items = append(items, id+"="+d[id].String())
+	}
+	return []byte(strings.Join(items, ",")), nil
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MintReq представляет запрос на эмиссию средств на кошелёк.
+type MintReq struct {
+	Address string          `json:"address" validate:"required,address"` // Адрес кошелька-получателя
+	Amount  decimal.Decimal `json:"amount" validate:"required,positive"` // Сумма эмиссии
+	Reason  string          `json:"reason" validate:"required"`          // Основание операции
+}
+
+// AdjustReq представляет запрос на корректировку баланса кошелька.
+//
+// Положительная сумма зачисляется на кошелёк, отрицательная — списывается с него.
+type AdjustReq struct {
+	Address string          `json:"address" validate:"required,address"` // Адрес кошелька
+	Amount  decimal.Decimal `json:"amount" validate:"required"`          // Сумма корректировки со знаком
+	Reason  string          `json:"reason" validate:"required"`          // Основание операции
+}
+
+// FreezeReq представляет запрос на заморозку или разморозку кошелька.
+type FreezeReq struct {
+	Address string `json:"-"`                          // Адрес кошелька (из пути запроса)
+	Frozen  bool   `json:"-"`                          // Заморозить (true) или разморозить (false)
+	Reason  string `json:"reason" validate:"required"` // Основание операции
+}
+
+// GenerateWalletsReq представляет запрос на создание новых кошельков администратором.
+type GenerateWalletsReq struct {
+	Count  int    `json:"count"`                      // Количество кошельков; 0 — значение по умолчанию
+	Reason string `json:"reason" validate:"required"` // Основание операции
+}
+
+// WalletStatusResp представляет состояние кошелька после административной операции.
+type WalletStatusResp struct {
+	Address string `json:"address"` // Адрес кошелька
+	Frozen  bool   `json:"frozen"`  // Заморожен ли кошелёк
+}
+
+// AuditEntry представляет запись журнала административных действий.
+type AuditEntry struct {
+	ID        int64     `json:"id" db:"id"`                     // Идентификатор записи
+	Actor     string    `json:"actor" db:"actor"`               // Идентификатор выполнившего действие клиента
+	Action    string    `json:"action" db:"action"`             // Действие, например mint или freeze
+	Target    string    `json:"target,omitempty" db:"target"`   // Объект действия, например адрес кошелька
+	Details   string    `json:"details,omitempty" db:"details"` // Параметры действия в формате JSON
+	Reason    string    `json:"reason" db:"reason"`             // Основание действия
+	CreatedAt time.Time `json:"created_at" db:"created_at"`     // Время действия
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+// APIKeyReq представляет запрос на создание API-ключа.
+type APIKeyReq struct {
+	Name       string   `json:"name"`        // Название ключа (например, имя интеграции)
+	Scopes     []string `json:"scopes"`      // Разрешённые области доступа
+	Wallets    []string `json:"wallets"`     // Кошельки, с которых ключу разрешено списывать средства
+	AllWallets bool     `json:"all_wallets"` // Разрешено ли списание с любого кошелька
+}
+
+// APIKey представляет сведения о сохранённом API-ключе (без самого ключа).
+type APIKey struct {
+	ID         int64      `json:"id" db:"id"`                   // Идентификатор ключа
+	Name       string     `json:"name" db:"name"`               // Название ключа
+	KeyHash    string     `json:"-" db:"key_hash"`              // SHA-256 хеш ключа
+	Scopes     []string   `json:"scopes" db:"scopes"`           // Разрешённые области доступа
+	Wallets    []string   `json:"wallets"`                      // Кошельки, доступные для списания
+	AllWallets bool       `json:"all_wallets" db:"all_wallets"` // Разрешено ли списание с любого кошелька
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`   // Время создания ключа
+	RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at"`   // Время отзыва ключа, если он отозван
+}
+
+// APIKeyResp представляет только что созданный API-ключ.
+//
+// Открытое значение ключа возвращается один раз и нигде не сохраняется.
+type APIKeyResp struct {
+	APIKey
+	Key string `json:"key"` // Открытое значение ключа
+}
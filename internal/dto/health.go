@@ -0,0 +1,34 @@
+package dto
+
+// Состояния проверок готовности.
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusFail = "fail"
+)
+
+// HealthResp представляет ответ проверки работоспособности процесса.
+type HealthResp struct {
+	Status string `json:"status"` // Всегда ok: процесс отвечает на запросы
+}
+
+// ReadinessCheck представляет результат одной проверки готовности.
+type ReadinessCheck struct {
+	Name   string `json:"name"`             // Название проверки: database, migrations, shutdown
+	Status string `json:"status"`           // ok или fail
+	Detail string `json:"detail,omitempty"` // Причина непройденной проверки
+}
+
+// ReadinessResp представляет ответ проверки готовности сервера принимать запросы.
+type ReadinessResp struct {
+	Status string           `json:"status"` // ok, если пройдены все проверки, иначе fail
+	Checks []ReadinessCheck `json:"checks"` // Результаты отдельных проверок
+}
+
+// VersionResp представляет сведения о сборке сервера.
+type VersionResp struct {
+	Version    string `json:"version"`               // Версия сборки
+	Commit     string `json:"commit,omitempty"`      // Хеш коммита, из которого собран сервер
+	CommitTime string `json:"commit_time,omitempty"` // Время коммита
+	Modified   bool   `json:"modified,omitempty"`    // Сборка содержит незакоммиченные изменения
+	GoVersion  string `json:"go_version"`            // Версия Go
+}
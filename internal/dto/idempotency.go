@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+// IdempotencyRecord представляет запрос с ключом идемпотентности и сохранённый ответ на него.
+type IdempotencyRecord struct {
+	Subject     string    `db:"subject"`      // Клиент, отправивший запрос
+	Key         string    `db:"key"`          // Ключ из заголовка Idempotency-Key
+	Operation   string    `db:"operation"`    // Идентификатор операции API
+	RequestHash string    `db:"request_hash"` // Хеш метода, пути и тела запроса (hex)
+	StatusCode  int       `db:"status_code"`  // HTTP-статус ответа; 0, пока запрос обрабатывается
+	ContentType string    `db:"content_type"` // Тип содержимого ответа
+	Body        []byte    `db:"body"`         // Тело ответа
+	CreatedAt   time.Time `db:"created_at"`   // Время первого запроса с ключом
+}
+
+// Completed сообщает, сохранён ли ответ на запрос.
+func (r IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
@@ -0,0 +1,49 @@
+package dto
+
+import "time"
+
+// Checkpoint представляет подписанную сервером контрольную точку журнала транзакций.
+//
+// Контрольная точка фиксирует состояние цепочки хешей на момент создания: опубликованная
+// контрольная точка не позволяет незаметно переписать или удалить историю до неё.
+type Checkpoint struct {
+	ID        int64     `json:"id" db:"id"`                 // Идентификатор контрольной точки
+	LastTxID  int64     `json:"last_tx_id" db:"last_tx_id"` // Идентификатор последней транзакции
+	TxCount   int64     `json:"tx_count" db:"tx_count"`     // Количество транзакций в журнале
+	HeadHash  string    `json:"head_hash" db:"head_hash"`   // Хеш последней транзакции
+	CreatedAt time.Time `json:"created_at" db:"created_at"` // Время создания
+	PublicKey string    `json:"public_key" db:"public_key"` // Открытый ключ Ed25519 сервера в hex
+	Signature string    `json:"signature" db:"signature"`   // Подпись Ed25519 в hex (см. utils.CheckpointPayload)
+}
+
+// LedgerHead представляет последнюю запись журнала транзакций.
+type LedgerHead struct {
+	ID    int64  `json:"id" db:"id"`     // Идентификатор последней транзакции (0, если журнал пуст)
+	Hash  string `json:"hash" db:"hash"` // Хеш последней транзакции
+	Count int64  `json:"count"`          // Количество транзакций в журнале
+}
+
+// LedgerBreak описывает первое найденное нарушение целостности журнала.
+type LedgerBreak struct {
+	TransactionID int64  `json:"transaction_id,omitempty"` // Транзакция, на которой нарушена цепочка
+	CheckpointID  int64  `json:"checkpoint_id,omitempty"`  // Контрольная точка, не соответствующая журналу
+	Reason        string `json:"reason"`                   // Причина нарушения
+	Expected      string `json:"expected,omitempty"`       // Ожидаемое значение
+	Actual        string `json:"actual,omitempty"`         // Фактическое значение
+}
+
+// LedgerVerifyResp представляет результат проверки целостности журнала транзакций.
+type LedgerVerifyResp struct {
+	Valid       bool         `json:"valid"`           // Цепочка и контрольные точки корректны
+	Checked     int64        `json:"checked"`         // Количество проверенных транзакций
+	HeadID      int64        `json:"head_id"`         // Идентификатор последней проверенной транзакции
+	HeadHash    string       `json:"head_hash"`       // Хеш последней проверенной транзакции
+	Checkpoints int          `json:"checkpoints"`     // Количество проверенных контрольных точек
+	Break       *LedgerBreak `json:"break,omitempty"` // Первое нарушение, если цепочка повреждена
+}
+
+// CheckpointsResp представляет список контрольных точек и открытый ключ, которым они подписаны.
+type CheckpointsResp struct {
+	PublicKey   string       `json:"public_key,omitempty"` // Текущий открытый ключ сервера в hex
+	Checkpoints []Checkpoint `json:"checkpoints"`          // Контрольные точки, начиная с самой новой
+}
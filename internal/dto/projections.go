@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ProjectionCheckpoint представляет сохранённую контрольную точку проекции журнала транзакций.
+type ProjectionCheckpoint struct {
+	Name      string    `db:"name"`       // Имя проекции
+	LastTxID  int64     `db:"last_tx_id"` // Последняя запись журнала, применённая к проекции
+	UpdatedAt time.Time `db:"updated_at"` // Время последнего обновления проекции
+}
+
+// ProjectionStatus представляет состояние проекции журнала транзакций.
+type ProjectionStatus struct {
+	Name      string     `json:"name"`                 // Имя проекции
+	Inline    bool       `json:"inline"`               // Проекция обновляется вместе с журналом и только перестраивается
+	LastTxID  int64      `json:"last_tx_id"`           // Последняя запись журнала, применённая к проекции
+	Lag       int64      `json:"lag"`                  // Количество записей журнала, ещё не применённых к проекции
+	UpdatedAt *time.Time `json:"updated_at,omitempty"` // Время последнего обновления; отсутствует, если проекция не строилась
+}
+
+// ProjectionRebuildReq представляет запрос на перестроение проекции с нуля.
+type ProjectionRebuildReq struct {
+	Name   string `json:"-"`                          // Имя проекции (из пути запроса)
+	Reason string `json:"reason" validate:"required"` // Основание операции
+}
+
+// ProjectionRebuildResp представляет результат перестроения проекции.
+type ProjectionRebuildResp struct {
+	Name     string `json:"name"`       // Имя проекции
+	Records  int64  `json:"records"`    // Количество воспроизведённых записей журнала
+	LastTxID int64  `json:"last_tx_id"` // Последняя запись журнала, применённая к проекции
+}
+
+// DailyVolume представляет обороты за сутки (UTC) по одному виду записей журнала.
+type DailyVolume struct {
+	Day    string          `json:"day" db:"day"`       // Дата в формате YYYY-MM-DD
+	Kind   string          `json:"kind" db:"kind"`     // Вид записи: transfer, mint или adjustment
+	Count  int64           `json:"count" db:"count"`   // Количество записей
+	Volume decimal.Decimal `json:"volume" db:"volume"` // Сумма записей
+}
+
+// DailyVolumesReq представляет запрос оборотов за период.
+type DailyVolumesReq struct {
+	From string `json:"from"` // Первый день периода (YYYY-MM-DD) включительно
+	To   string `json:"to"`   // Последний день периода (YYYY-MM-DD) включительно
+}
+
+// CounterpartyStats представляет статистику переводов кошелька с одним контрагентом.
+type CounterpartyStats struct {
+	Counterparty   string          `json:"counterparty" db:"counterparty"`       // Адрес контрагента
+	SentCount      int64           `json:"sent_count" db:"sent_count"`           // Количество переводов контрагенту
+	SentVolume     decimal.Decimal `json:"sent_volume" db:"sent_volume"`         // Сумма переводов контрагенту
+	ReceivedCount  int64           `json:"received_count" db:"received_count"`   // Количество переводов от контрагента
+	ReceivedVolume decimal.Decimal `json:"received_volume" db:"received_volume"` // Сумма переводов от контрагента
+}
+
+// CounterpartiesReq представляет запрос статистики контрагентов кошелька.
+type CounterpartiesReq struct {
+	Address string `json:"address"` // Адрес кошелька
+	Count   int    `json:"count"`   // Максимальное количество контрагентов
+}
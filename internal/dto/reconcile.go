@@ -0,0 +1,33 @@
+package dto
+
+import "github.com/shopspring/decimal"
+
+// ReconcileReq представляет запрос сверки балансов кошельков с журналом транзакций.
+type ReconcileReq struct {
+	Repair bool   `json:"repair"` // Исправить расхождения корректировками в журнале транзакций
+	Reason string `json:"reason"` // Основание исправления; обязательно, если Repair = true
+}
+
+// ReconcileMismatch представляет расхождение сохранённого баланса кошелька с вычисленным по журналу.
+type ReconcileMismatch struct {
+	Address    string          `json:"address"`    // Адрес кошелька
+	Stored     decimal.Decimal `json:"stored"`     // Сохранённый баланс
+	Expected   decimal.Decimal `json:"expected"`   // Баланс по начальному балансу и журналу транзакций
+	Difference decimal.Decimal `json:"difference"` // Разница Stored - Expected
+}
+
+// ReconcileReport представляет результат сверки балансов кошельков с журналом транзакций.
+type ReconcileReport struct {
+	Consistent      bool                `json:"consistent"`                // Расхождений не найдено, все кошельки проверены
+	Transactions    int64               `json:"transactions"`              // Количество проверенных записей журнала
+	Wallets         int64               `json:"wallets"`                   // Количество кошельков
+	InitialSupply   decimal.Decimal     `json:"initial_supply"`            // Сумма начальных балансов кошельков, кроме Unseeded
+	NetIssuance     decimal.Decimal     `json:"net_issuance"`              // Эмиссия и корректировки по журналу (зачисления минус списания)
+	ExpectedSupply  decimal.Decimal     `json:"expected_supply"`           // InitialSupply + NetIssuance
+	ActualSupply    decimal.Decimal     `json:"actual_supply"`             // Сумма сохранённых балансов кошельков, кроме Unseeded
+	SupplyConserved bool                `json:"supply_conserved"`          // ActualSupply совпадает с ExpectedSupply
+	Mismatches      []ReconcileMismatch `json:"mismatches"`                // Кошельки с расхождениями
+	UnknownWallets  []string            `json:"unknown_wallets,omitempty"` // Адреса из журнала, для которых нет кошелька
+	Unseeded        []string            `json:"unseeded,omitempty"`        // Кошельки без начального баланса, которые сверить нельзя
+	Repaired        bool                `json:"repaired"`                  // Расхождения исправлены корректировками
+}
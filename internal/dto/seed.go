@@ -0,0 +1,34 @@
+package dto
+
+import "github.com/shopspring/decimal"
+
+// Распределения начальных балансов создаваемых кошельков.
+const (
+	SeedDistributionFixed       = "fixed"       // Одинаковый баланс у всех кошельков
+	SeedDistributionUniform     = "uniform"     // Равномерное распределение от 0 до удвоенного среднего
+	SeedDistributionExponential = "exponential" // Экспоненциальное распределение: много малых балансов и немного крупных
+)
+
+// SeedReq представляет запрос на начальное заполнение базы кошельками.
+type SeedReq struct {
+	Count        int             // Количество кошельков
+	Balance      decimal.Decimal // Средний начальный баланс (для распределения fixed — баланс каждого кошелька)
+	Distribution string          // Распределение балансов (SeedDistribution*); пустое — fixed
+	KeySeed      string          // Строка для детерминированного вывода ключей и балансов; пустая — случайные
+	OnlyIfEmpty  bool            // Создавать кошельки, только если в базе ещё нет кошельков
+}
+
+// SeedWallet представляет кошелёк из файла фикстуры.
+//
+// Должен быть задан адрес, закрытый ключ или оба; если заданы оба, адрес должен соответствовать ключу.
+type SeedWallet struct {
+	Address    string          `json:"address,omitempty"`     // Адрес кошелька
+	PrivateKey string          `json:"private_key,omitempty"` // Закрытый ключ кошелька (seed Ed25519 в hex)
+	Balance    decimal.Decimal `json:"balance"`               // Начальный баланс
+}
+
+// ImportWalletsReq представляет запрос на импорт кошельков из фикстуры.
+type ImportWalletsReq struct {
+	Wallets     []SeedWallet // Кошельки с начальными балансами
+	OnlyIfEmpty bool         // Импортировать, только если в базе ещё нет кошельков
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Snapshot представляет снимок балансов всех кошельков на момент определённой транзакции.
+//
+// MerkleRoot — корень дерева Меркла над парами (адрес, баланс) снимка, упорядоченными по адресу.
+// Опубликованный корень позволяет владельцу кошелька проверить, что его баланс учтён в снимке.
+type Snapshot struct {
+	ID          int64           `json:"id" db:"id"`                     // Идентификатор снимка
+	LastTxID    int64           `json:"last_tx_id" db:"last_tx_id"`     // Последняя транзакция, учтённая в снимке
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`     // Время создания
+	WalletCount int64           `json:"wallet_count" db:"wallet_count"` // Количество кошельков
+	Total       decimal.Decimal `json:"total" db:"total"`               // Сумма балансов всех кошельков
+	MerkleRoot  string          `json:"merkle_root" db:"merkle_root"`   // Корень дерева Меркла в hex
+}
+
+// SnapshotBalance представляет баланс кошелька в снимке.
+type SnapshotBalance struct {
+	Address string          `json:"address" db:"address"` // Адрес кошелька
+	Balance decimal.Decimal `json:"balance" db:"balance"` // Баланс на момент снимка
+}
+
+// MerkleProofStep представляет шаг доказательства включения в дерево Меркла.
+type MerkleProofStep struct {
+	Hash     string `json:"hash"`     // Хеш соседнего узла в hex
+	Position string `json:"position"` // Положение соседнего узла: left или right
+}
+
+// BalanceProofReq представляет запрос доказательства включения баланса кошелька в снимок.
+type BalanceProofReq struct {
+	Address    string `json:"address"`     // Адрес кошелька
+	SnapshotID int64  `json:"snapshot_id"` // Идентификатор снимка; 0 — последний снимок
+}
+
+// BalanceProofResp представляет доказательство включения баланса кошелька в снимок.
+//
+// Проверка: последовательно объединить LeafHash с хешами из Proof (см. utils.VerifyMerkleProof)
+// и сравнить результат с MerkleRoot опубликованного снимка.
+type BalanceProofResp struct {
+	SnapshotID int64             `json:"snapshot_id"` // Идентификатор снимка
+	LastTxID   int64             `json:"last_tx_id"`  // Последняя транзакция, учтённая в снимке
+	Address    string            `json:"address"`     // Адрес кошелька
+	Balance    decimal.Decimal   `json:"balance"`     // Баланс на момент снимка
+	LeafHash   string            `json:"leaf_hash"`   // Хеш листа (см. utils.MerkleLeafHash)
+	Proof      []MerkleProofStep `json:"proof"`       // Шаги доказательства от листа к корню
+	MerkleRoot string            `json:"merkle_root"` // Корень дерева Меркла снимка
+}
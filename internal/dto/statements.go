@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Форматы выписки по кошельку.
+const (
+	StatementFormatJSON = "json"
+	StatementFormatCSV  = "csv"
+)
+
+// Направление движения средств в строке выписки.
+const (
+	StatementDirectionIn  = "in"
+	StatementDirectionOut = "out"
+)
+
+// StatementReq представляет запрос выписки по кошельку за период [From, To).
+type StatementReq struct {
+	Address string    `json:"address"` // Адрес кошелька
+	From    time.Time `json:"from"`    // Начало периода (включительно)
+	To      time.Time `json:"to"`      // Конец периода (не включительно)
+}
+
+// StatementHeader представляет начало выписки: период и входящий остаток.
+type StatementHeader struct {
+	Address        string          `json:"address"`         // Адрес кошелька
+	From           time.Time       `json:"from"`            // Начало периода
+	To             time.Time       `json:"to"`              // Конец периода
+	OpeningBalance decimal.Decimal `json:"opening_balance"` // Баланс на начало периода
+	OpeningTxID    int64           `json:"opening_tx_id"`   // Последняя транзакция до начала периода
+}
+
+// StatementEntry представляет строку выписки — транзакцию кошелька за период.
+type StatementEntry struct {
+	ID           int64           `json:"id"`                     // Идентификатор транзакции
+	CreatedAt    time.Time       `json:"created_at"`             // Время транзакции
+	Kind         string          `json:"kind"`                   // Вид записи: transfer, mint или adjustment
+	Direction    string          `json:"direction"`              // Направление: in или out
+	Counterparty string          `json:"counterparty,omitempty"` // Адрес второй стороны перевода
+	Amount       decimal.Decimal `json:"amount"`                 // Изменение баланса: положительное для зачислений, отрицательное для списаний
+	Fee          decimal.Decimal `json:"fee"`                    // Комиссия
+	Balance      decimal.Decimal `json:"balance"`                // Баланс после транзакции
+}
+
+// StatementSummary представляет окончание выписки: исходящий остаток и итоги за период.
+type StatementSummary struct {
+	ClosingBalance decimal.Decimal `json:"closing_balance"` // Баланс на конец периода
+	ClosingTxID    int64           `json:"closing_tx_id"`   // Последняя транзакция журнала до конца периода
+	TotalIn        decimal.Decimal `json:"total_in"`        // Сумма зачислений
+	TotalOut       decimal.Decimal `json:"total_out"`       // Сумма списаний
+	TotalFees      decimal.Decimal `json:"total_fees"`      // Сумма комиссий
+	Count          int64           `json:"count"`           // Количество транзакций
+}
+
+// StatementResp описывает выписку в формате JSON. Выписка передаётся потоком,
+// поэтому структура используется только для описания ответа в спецификации API.
+type StatementResp struct {
+	Address        string           `json:"address"`         // Адрес кошелька
+	From           time.Time        `json:"from"`            // Начало периода
+	To             time.Time        `json:"to"`              // Конец периода
+	OpeningBalance decimal.Decimal  `json:"opening_balance"` // Баланс на начало периода
+	OpeningTxID    int64            `json:"opening_tx_id"`   // Последняя транзакция до начала периода
+	Entries        []StatementEntry `json:"entries"`         // Транзакции за период в порядке их выполнения
+	ClosingBalance decimal.Decimal  `json:"closing_balance"` // Баланс на конец периода
+	ClosingTxID    int64            `json:"closing_tx_id"`   // Последняя транзакция журнала до конца периода
+	TotalIn        decimal.Decimal  `json:"total_in"`        // Сумма зачислений
+	TotalOut       decimal.Decimal  `json:"total_out"`       // Сумма списаний
+	TotalFees      decimal.Decimal  `json:"total_fees"`      // Сумма комиссий
+	Count          int64            `json:"count"`           // Количество транзакций
+}
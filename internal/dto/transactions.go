@@ -4,18 +4,50 @@ import "time"
 import "github.com/shopspring/decimal"
 
 // TransactionReq представляет запрос на выполнение транзакции.
+//
+// Signature — подпись Ed25519 закрытым ключом отправителя над каноническим представлением
+// перевода (см. utils.TransferPayload). Nonce должен быть больше последнего использованного
+// nonce кошелька отправителя, что исключает повторное применение подписанного перевода.
 type TransactionReq struct {
-	From   string          `json:"from" db:"from_address"` // Адрес отправителя
-	To     string          `json:"to" db:"to_address"`     // Адрес получателя
-	Amount decimal.Decimal `json:"amount" db:"amount"`     // Сумма перевода
+	From      string          `json:"from" db:"from_address" validate:"required,address"`     // Адрес отправителя
+	To        string          `json:"to" db:"to_address" validate:"required,address"`         // Адрес получателя
+	Amount    decimal.Decimal `json:"amount" db:"amount" validate:"required,positive"`        // Сумма перевода
+	Nonce     uint64          `json:"nonce" db:"nonce" validate:"required,positive"`          // Порядковый номер перевода отправителя
+	Signature string          `json:"signature" db:"signature" validate:"required,signature"` // Подпись перевода в hex
+}
+
+// Виды записей журнала транзакций.
+const (
+	TransactionKindTransfer   = "transfer"   // Перевод между кошельками
+	TransactionKindMint       = "mint"       // Эмиссия средств администратором
+	TransactionKindAdjustment = "adjustment" // Корректировка баланса администратором
+)
+
+// TransactionRecord представляет запись журнала транзакций.
+//
+// У переводов заполнены обе стороны, у эмиссии — только получатель,
+// у корректировки — получатель (зачисление) или отправитель (списание).
+type TransactionRecord struct {
+	ID          int64           `db:"id"`           // Идентификатор записи (заполняется при чтении)
+	Kind        string          `db:"kind"`         // Вид записи (TransactionKind*)
+	From        string          `db:"from_address"` // Адрес отправителя
+	To          string          `db:"to_address"`   // Адрес получателя
+	Amount      decimal.Decimal `db:"amount"`       // Сумма
+	Nonce       uint64          `db:"nonce"`        // Nonce перевода (только для переводов)
+	Signature   string          `db:"signature"`    // Подпись перевода (только для переводов)
+	InitiatedBy string          `db:"initiated_by"` // Идентификатор аутентифицированного инициатора
+	CreatedAt   time.Time       `db:"created_at"`   // Время создания записи
+	PrevHash    string          `db:"prev_hash"`    // Хеш предыдущей записи журнала
+	Hash        string          `db:"hash"`         // Хеш записи (см. utils.HashTransaction)
 }
 
 // TransactionResp представляет ответ с информацией о транзакции.
 type TransactionResp struct {
-	From      string          `json:"from" db:"from_address"`     // Адрес отправителя
-	To        string          `json:"to" db:"to_address"`         // Адрес получателя
-	Amount    decimal.Decimal `json:"amount" db:"amount"`         // Сумма перевода
-	CreatedAt time.Time       `json:"created_at" db:"created_at"` // Время создания транзакции
+	Kind      string          `json:"kind" db:"kind"`                   // Вид записи: transfer, mint или adjustment
+	From      string          `json:"from,omitempty" db:"from_address"` // Адрес отправителя
+	To        string          `json:"to,omitempty" db:"to_address"`     // Адрес получателя
+	Amount    decimal.Decimal `json:"amount" db:"amount"`               // Сумма перевода
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`       // Время создания транзакции
 }
 
 // TransactionsResp представляет список транзакций.
@@ -1,10 +1,15 @@
 package dto
 
-import "github.com/shopspring/decimal"
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
 
 // BalanceResp представляет ответ с балансом кошелька.
 type BalanceResp struct {
-	Amount decimal.Decimal `json:"amount" db:"amount"` // Текущий баланс
+	Amount decimal.Decimal `json:"amount" db:"amount"` // Баланс кошелька
+	AsOfTx *int64          `json:"as_of_tx,omitempty"` // Последняя учтённая транзакция; только для баланса на момент в прошлом
 }
 
 // BalanceReq представляет запрос для получения баланса по адресу кошелька.
@@ -12,6 +17,15 @@ type BalanceReq struct {
 	Address string `json:"address" db:"address"` // Адрес кошелька
 }
 
+// BalanceAtReq представляет запрос баланса кошелька на момент в прошлом.
+//
+// Должно быть задано ровно одно из полей At и AsOfTx.
+type BalanceAtReq struct {
+	Address string     `json:"address"`            // Адрес кошелька
+	At      *time.Time `json:"at,omitempty"`       // Момент времени: учитываются транзакции, созданные не позже него
+	AsOfTx  *int64     `json:"as_of_tx,omitempty"` // Идентификатор последней учитываемой транзакции
+}
+
 // BalanceUpdateReq представляет запрос на обновление баланса кошелька.
 type BalanceUpdateReq struct {
 	Address string          `json:"address" db:"address"` // Адрес кошелька
@@ -23,3 +37,21 @@ type WalletReq struct {
 	Address string          `json:"address" db:"address"` // Адрес кошелька
 	Balance decimal.Decimal `json:"balance" db:"balance"` // Баланс кошелька
 }
+
+// WalletState представляет текущий и начальный балансы кошелька.
+type WalletState struct {
+	Address        string              `json:"address" db:"address"`                 // Адрес кошелька
+	Balance        decimal.Decimal     `json:"balance" db:"balance"`                 // Текущий баланс
+	InitialBalance decimal.NullDecimal `json:"initial_balance" db:"initial_balance"` // Баланс при создании; не задан для кошельков, созданных до его учёта
+}
+
+// NonceResp представляет ответ с последним использованным nonce кошелька.
+type NonceResp struct {
+	Nonce uint64 `json:"nonce" db:"nonce"` // Последний использованный nonce; следующий перевод должен использовать большее значение
+}
+
+// WalletKey представляет ключевую пару созданного кошелька.
+type WalletKey struct {
+	Address    string `json:"address"`     // Адрес кошелька (открытый ключ Ed25519 в hex)
+	PrivateKey string `json:"private_key"` // Закрытый ключ (seed Ed25519 в hex)
+}
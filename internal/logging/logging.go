@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/config"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestIDKey — ключ контекста для хранения идентификатора запроса.
+type requestIDKey struct{}
+
+// Setup устанавливает журнал по умолчанию: записи в формате JSON выводятся в стандартный поток ошибок.
+// Через него же на уровне error выводятся сообщения стандартного пакета log — в приложении
+// через него остаются только сообщения о фатальных ошибках (log.Fatal).
+//
+// Аргументы:
+//   - cfg: параметры журнала.
+func Setup(cfg config.LoggingConfig) {
+	var level slog.Level
+	// Уровень проверяется config.Validate, поэтому ошибка здесь невозможна
+	_ = level.UnmarshalText([]byte(cfg.Level))
+
+	slog.SetDefault(New(os.Stderr, level))
+	slog.SetLogLoggerLevel(slog.LevelError)
+}
+
+// New создаёт журнал, записывающий записи в формате JSON. К каждой записи, сделанной с контекстом
+// (slog.InfoContext и т. п.), добавляются идентификатор запроса из контекста (поле request_id)
+// и идентификаторы трассировки и спана (поля trace_id и span_id).
+//
+// Аргументы:
+//   - w: получатель записей.
+//   - level: минимальный уровень записей.
+func New(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})})
+}
+
+// WithRequestID возвращает копию контекста с указанным идентификатором запроса.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID возвращает идентификатор запроса из контекста или пустую строку.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// contextHandler дополняет записи журнала сведениями о запросе из контекста.
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle добавляет к записи идентификаторы запроса и трассировки и передаёт её дальше.
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs возвращает обработчик с дополнительными атрибутами, сохраняющий сведения о запросе.
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup возвращает обработчик с группой атрибутов, сохраняющий сведения о запросе.
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,28 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// ObserveQuery записывает в журнал выполненный SQL-запрос: успешные — на уровне debug, завершившиеся
+// ошибкой — на уровне warn. Совпадает по сигнатуре со storage.QueryObserver; запросы, выполненные
+// с контекстом HTTP-запроса, записываются с его идентификатором.
+//
+// Аргументы:
+//   - ctx: контекст, привязанный к исполнителю запросов.
+//   - name: имя SQL-файла запроса, например wallets/get_balance.
+//
+// Возвращает:
+//   - функцию, записывающую запрос с его длительностью и ошибкой.
+func ObserveQuery(ctx context.Context, name string) func(err error) {
+	start := time.Now()
+	return func(err error) {
+		if err != nil {
+			slog.WarnContext(ctx, "sql query failed", "query", name, "duration", time.Since(start), "error", err)
+			return
+		}
+		slog.DebugContext(ctx, "sql query", "query", name, "duration", time.Since(start))
+	}
+}
@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"time"
+)
+
+// namespace — префикс имён метрик приложения.
+const namespace = "transactions"
+
+// Исходы переводов — значения метки outcome метрик переводов.
+const (
+	OutcomeSuccess           = "success"
+	OutcomeInsufficientFunds = "insufficient_funds"
+	OutcomeNotFound          = "not_found"
+	OutcomeRejected          = "rejected"
+	OutcomeError             = "error"
+)
+
+// Metrics собирает метрики приложения и отдаёт их в текстовом формате Prometheus.
+//
+// Метрики регистрируются в собственном реестре, а не в глобальном реестре Prometheus,
+// поэтому каждый экземпляр независим: его можно создать в проверке и прочитать через Gatherer
+// без запущенного сервера Prometheus.
+type Metrics struct {
+	registry        *prometheus.Registry
+	httpRequests    *prometheus.CounterVec
+	httpDuration    *prometheus.HistogramVec
+	transfers       *prometheus.CounterVec
+	transferVolume  *prometheus.CounterVec
+	dbQueryDuration *prometheus.HistogramVec
+}
+
+// New создаёт набор метрик приложения вместе с метриками среды выполнения Go и процесса.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Количество обработанных HTTP-запросов по маршрутам, методам и кодам ответа.",
+		}, []string{"route", "method", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Длительность обработки HTTP-запросов по маршрутам, методам и кодам ответа.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "code"}),
+		transfers: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "transfers_total",
+			Help:      "Количество переводов по исходам.",
+		}, []string{"outcome"}),
+		transferVolume: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "transfer_volume_total",
+			Help:      "Сумма переводов по исходам.",
+		}, []string{"outcome"}),
+		dbQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Длительность SQL-запросов по именам файлов запросов и результату.",
+			Buckets:   []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"query", "result"}),
+	}
+
+	m.registry.MustRegister(
+		m.httpRequests, m.httpDuration, m.transfers, m.transferVolume, m.dbQueryDuration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	// Исходы переводов известны заранее: публикуем нулевые значения до первого перевода
+	for _, outcome := range []string{OutcomeSuccess, OutcomeInsufficientFunds, OutcomeNotFound, OutcomeRejected, OutcomeError} {
+		m.transfers.WithLabelValues(outcome)
+		m.transferVolume.WithLabelValues(outcome)
+	}
+	return m
+}
+
+// Gatherer возвращает реестр метрик для чтения без HTTP, например в проверках.
+func (m *Metrics) Gatherer() prometheus.Gatherer {
+	return m.registry
+}
+
+// Handler возвращает обработчик, отдающий метрики в текстовом формате Prometheus.
+// Ошибка сбора одной метрики не мешает отдать остальные.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError})
+}
+
+// InstrumentHandler учитывает количество и длительность запросов к маршруту.
+//
+// Аргументы:
+//   - route: шаблон пути маршрута, например /api/wallet/{address}/balance; используется вместо
+//     фактического пути, чтобы количество рядов метрик не зависело от параметров запросов.
+//   - next: обработчик маршрута.
+func (m *Metrics) InstrumentHandler(route string, next http.Handler) http.Handler {
+	labels := prometheus.Labels{"route": route}
+	next = promhttp.InstrumentHandlerDuration(m.httpDuration.MustCurryWith(labels), next)
+	return promhttp.InstrumentHandlerCounter(m.httpRequests.MustCurryWith(labels), next)
+}
+
+// ObserveTransfer учитывает перевод с указанным исходом.
+//
+// Аргументы:
+//   - outcome: исход перевода (Outcome*).
+//   - amount: сумма перевода.
+func (m *Metrics) ObserveTransfer(outcome string, amount decimal.Decimal) {
+	m.transfers.WithLabelValues(outcome).Inc()
+	if amount.IsPositive() {
+		volume, _ := amount.Float64()
+		m.transferVolume.WithLabelValues(outcome).Add(volume)
+	}
+}
+
+// ObserveQuery учитывает длительность SQL-запроса. Совпадает по сигнатуре со storage.QueryObserver.
+//
+// Аргументы:
+//   - name: имя SQL-файла запроса.
+//
+// Возвращает:
+//   - функцию, учитывающую длительность запроса по его завершении.
+func (m *Metrics) ObserveQuery(_ context.Context, name string) func(err error) {
+	start := time.Now()
+	return func(err error) {
+		result := "ok"
+		if err != nil {
+			result = "error"
+		}
+		m.dbQueryDuration.WithLabelValues(name, result).Observe(time.Since(start).Seconds())
+	}
+}
+
+// RegisterDB публикует статистику пула подключений к базе данных: открытые, занятые
+// и простаивающие подключения, ожидания свободного подключения и закрытые подключения.
+//
+// Аргументы:
+//   - db: подключение к базе данных.
+//   - name: имя базы данных, значение метки db_name.
+//
+// Возвращает:
+//   - ошибку, если статистика базы с таким именем уже зарегистрирована.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// RegisterTotalSupply публикует сумму балансов всех кошельков, вычисляемую при каждом сборе метрик.
+//
+// Аргументы:
+//   - supply: функция вычисления суммы балансов.
+//
+// Возвращает:
+//   - ошибку, если метрика уже зарегистрирована.
+func (m *Metrics) RegisterTotalSupply(supply SupplyFunc) error {
+	return m.registry.Register(newSupplyCollector(supply))
+}
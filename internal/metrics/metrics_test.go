@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shopspring/decimal"
+)
+
+// sample — значение ряда метрики: значение счётчика или шкалы, для гистограммы — количество наблюдений.
+type sample struct {
+	value float64
+	found bool
+}
+
+// gather собирает метрики и возвращает значения рядов семейства name по наборам меток.
+// Ключ — значения меток labels в указанном порядке через запятую.
+func gather(t *testing.T, g prometheus.Gatherer, name string, labels ...string) map[string]sample {
+	t.Helper()
+	families, err := g.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+
+	samples := make(map[string]sample)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			values := make(map[string]string)
+			for _, pair := range metric.GetLabel() {
+				values[pair.GetName()] = pair.GetValue()
+			}
+			if len(values) != len(labels) {
+				t.Errorf("%s has labels %v, want %v", name, values, labels)
+			}
+			key := ""
+			for i, label := range labels {
+				if i > 0 {
+					key += ","
+				}
+				key += values[label]
+			}
+
+			s := sample{found: true}
+			switch {
+			case metric.GetCounter() != nil:
+				s.value = metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				s.value = metric.GetGauge().GetValue()
+			case metric.GetHistogram() != nil:
+				s.value = float64(metric.GetHistogram().GetSampleCount())
+			}
+			samples[key] = s
+		}
+	}
+	return samples
+}
+
+// expectSample проверяет значение ряда key.
+func expectSample(t *testing.T, samples map[string]sample, key string, want float64) {
+	t.Helper()
+	s := samples[key]
+	if !s.found {
+		t.Errorf("series {%s} is not published", key)
+		return
+	}
+	if s.value != want {
+		t.Errorf("series {%s} = %v, want %v", key, s.value, want)
+	}
+}
+
+func TestInstrumentHandler(t *testing.T) {
+	m := New()
+	const route = "/api/wallet/{address}/balance"
+	handler := m.InstrumentHandler(route, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("missing") {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	for _, target := range []string{"/api/wallet/aa/balance", "/api/wallet/bb/balance", "/api/wallet/cc/balance?missing"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodHead, "/api/wallet/aa/balance", nil))
+
+	for _, name := range []string{"transactions_http_requests_total", "transactions_http_request_duration_seconds"} {
+		t.Run(name, func(t *testing.T) {
+			// Ряды различаются шаблоном маршрута, а не фактическим путём
+			samples := gather(t, m.Gatherer(), name, "route", "method", "code")
+			if len(samples) != 3 {
+				t.Errorf("%d series published, want 3: %v", len(samples), samples)
+			}
+			expectSample(t, samples, route+",get,200", 2)
+			expectSample(t, samples, route+",get,404", 1)
+			expectSample(t, samples, route+",head,200", 1)
+		})
+	}
+}
+
+func TestObserveTransfer(t *testing.T) {
+	m := New()
+
+	// Все исходы публикуются с нулевыми значениями до первого перевода
+	for _, name := range []string{"transactions_transfers_total", "transactions_transfer_volume_total"} {
+		samples := gather(t, m.Gatherer(), name, "outcome")
+		for _, outcome := range []string{OutcomeSuccess, OutcomeInsufficientFunds, OutcomeNotFound, OutcomeRejected, OutcomeError} {
+			expectSample(t, samples, outcome, 0)
+		}
+	}
+
+	m.ObserveTransfer(OutcomeSuccess, decimal.RequireFromString("2.5"))
+	m.ObserveTransfer(OutcomeSuccess, decimal.RequireFromString("0.25"))
+	m.ObserveTransfer(OutcomeRejected, decimal.NewFromInt(-10))
+	m.ObserveTransfer(OutcomeInsufficientFunds, decimal.NewFromInt(100))
+
+	transfers := gather(t, m.Gatherer(), "transactions_transfers_total", "outcome")
+	expectSample(t, transfers, OutcomeSuccess, 2)
+	expectSample(t, transfers, OutcomeRejected, 1)
+	expectSample(t, transfers, OutcomeInsufficientFunds, 1)
+	expectSample(t, transfers, OutcomeError, 0)
+
+	// Отрицательная сумма не уменьшает объём
+	volume := gather(t, m.Gatherer(), "transactions_transfer_volume_total", "outcome")
+	expectSample(t, volume, OutcomeSuccess, 2.75)
+	expectSample(t, volume, OutcomeRejected, 0)
+	expectSample(t, volume, OutcomeInsufficientFunds, 100)
+}
+
+func TestObserveQuery(t *testing.T) {
+	m := New()
+
+	m.ObserveQuery(context.Background(), "wallets/get_balance.sql")(nil)
+	m.ObserveQuery(context.Background(), "wallets/get_balance.sql")(nil)
+	m.ObserveQuery(context.Background(), "wallets/get_balance.sql")(errors.New("database is locked"))
+	m.ObserveQuery(context.Background(), "transactions/insert.sql")(nil)
+
+	samples := gather(t, m.Gatherer(), "transactions_db_query_duration_seconds", "query", "result")
+	if len(samples) != 3 {
+		t.Errorf("%d series published, want 3: %v", len(samples), samples)
+	}
+	expectSample(t, samples, "wallets/get_balance.sql,ok", 2)
+	expectSample(t, samples, "wallets/get_balance.sql,error", 1)
+	expectSample(t, samples, "transactions/insert.sql,ok", 1)
+}
+
+func TestTotalSupply(t *testing.T) {
+	m := New()
+	supply := decimal.RequireFromString("1500.5")
+	var supplyErr error
+	if err := m.RegisterTotalSupply(func(context.Context) (decimal.Decimal, error) {
+		return supply, supplyErr
+	}); err != nil {
+		t.Fatalf("RegisterTotalSupply: %v", err)
+	}
+
+	expectSample(t, gather(t, m.Gatherer(), "transactions_total_supply"), "", 1500.5)
+
+	// Значение вычисляется заново при каждом сборе
+	supply = decimal.NewFromInt(42)
+	expectSample(t, gather(t, m.Gatherer(), "transactions_total_supply"), "", 42)
+
+	if err := m.RegisterTotalSupply(func(context.Context) (decimal.Decimal, error) {
+		return decimal.Zero, nil
+	}); err == nil {
+		t.Error("second RegisterTotalSupply succeeded, want an error")
+	}
+
+	// Ошибка вычисления не мешает собрать остальные метрики
+	supplyErr = errors.New("database is locked")
+	families, err := m.Gatherer().Gather()
+	if err == nil {
+		t.Fatal("Gather succeeded with a failing supply function")
+	}
+	published := make(map[string]bool)
+	for _, family := range families {
+		published[family.GetName()] = true
+	}
+	if published["transactions_total_supply"] {
+		t.Error("total supply is published despite the error")
+	}
+	if !published["transactions_transfers_total"] {
+		t.Error("other metrics are not published when total supply fails")
+	}
+}
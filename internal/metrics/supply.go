@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// supplyTimeout ограничивает время вычисления суммы балансов при сборе метрик.
+const supplyTimeout = 5 * time.Second
+
+// SupplyFunc вычисляет сумму балансов всех кошельков.
+type SupplyFunc func(ctx context.Context) (decimal.Decimal, error)
+
+// supplyCollector публикует сумму балансов всех кошельков, вычисляя её при каждом сборе метрик.
+type supplyCollector struct {
+	supply SupplyFunc
+	desc   *prometheus.Desc
+}
+
+// newSupplyCollector создаёт коллектор суммы балансов.
+func newSupplyCollector(supply SupplyFunc) *supplyCollector {
+	return &supplyCollector{
+		supply: supply,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "total_supply"),
+			"Сумма балансов всех кошельков.",
+			nil, nil,
+		),
+	}
+}
+
+// Describe передаёт описание метрики.
+func (c *supplyCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect вычисляет сумму балансов; при ошибке вместо значения передаётся ошибка сбора метрики.
+func (c *supplyCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), supplyTimeout)
+	defer cancel()
+
+	total, err := c.supply(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	value, _ := total.Float64()
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, value)
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"context"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+	"github.com/joomcode/errorx"
+)
+
+// transferInteractor учитывает исходы переводов и передаёт все вызовы обёрнутому сервису.
+type transferInteractor struct {
+	services.TransferInteractor
+	metrics *Metrics
+}
+
+// InstrumentTransfers оборачивает сервис переводов: количество и сумма каждого перевода
+// учитываются по исходу (см. TransferOutcome). Остальные методы сервиса вызываются без изменений.
+//
+// Аргументы:
+//   - transferService: сервис переводов.
+//   - m: метрики приложения.
+//
+// Возвращает:
+//   - TransferInteractor с учётом переводов.
+func InstrumentTransfers(transferService services.TransferInteractor, m *Metrics) services.TransferInteractor {
+	return &transferInteractor{TransferInteractor: transferService, metrics: m}
+}
+
+// Send выполняет перевод и учитывает его исход.
+func (t *transferInteractor) Send(ctx context.Context, req dto.TransactionReq) error {
+	err := t.TransferInteractor.Send(ctx, req)
+	t.metrics.ObserveTransfer(TransferOutcome(err), req.Amount)
+	return err
+}
+
+// TransferOutcome определяет исход перевода по ошибке сервиса переводов.
+//
+// Возвращает:
+//   - OutcomeSuccess, если ошибки нет;
+//   - OutcomeInsufficientFunds при недостатке средств;
+//   - OutcomeNotFound, если кошелёк не найден;
+//   - OutcomeRejected при прочих ошибках клиента (подпись, nonce, заморозка, некорректные данные);
+//   - OutcomeError при внутренних ошибках.
+func TransferOutcome(err error) string {
+	if err == nil {
+		return OutcomeSuccess
+	}
+
+	e := errorx.Cast(err)
+	switch {
+	case e == nil:
+		return OutcomeError
+	case e.IsOfType(services.ErrInsufficientFunds):
+		return OutcomeInsufficientFunds
+	case services.IsNotFoundErr(e):
+		return OutcomeNotFound
+	case services.IsClientErr(e):
+		return OutcomeRejected
+	default:
+		return OutcomeError
+	}
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval — как часто MemoryStore удаляет полные корзины, чтобы память не росла с числом клиентов.
+const sweepInterval = time.Minute
+
+// memoryEntry — корзина вместе с лимитом, с которым к ней обращались последний раз.
+type memoryEntry struct {
+	bucket bucket
+	limit  Limit
+}
+
+// MemoryStore хранит корзины в памяти процесса. Реализует Store.
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	lastSweep time.Time
+}
+
+// NewMemoryStore создаёт пустое хранилище корзин в памяти.
+//
+// Возвращает:
+//   - Указатель на MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]*memoryEntry)}
+}
+
+// Take берёт один токен из корзины key с лимитом limit.
+//
+// Аргументы:
+//   - ctx: контекст запроса (не используется).
+//   - key: ключ корзины.
+//   - limit: лимит запросов.
+//
+// Возвращает:
+//   - результат попытки; ошибка всегда nil.
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &memoryEntry{bucket: bucket{tokens: float64(limit.Requests), updated: now}}
+		s.entries[key] = entry
+	}
+	entry.limit = limit
+	return entry.bucket.take(limit, now), nil
+}
+
+// sweep удаляет корзины, которые к моменту now наполнились: они неотличимы от отсутствующих.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, entry := range s.entries {
+		if entry.bucket.full(entry.limit, now) {
+			delete(s.entries, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,75 @@
+// Package ratelimit реализует ограничение частоты запросов алгоритмом token bucket.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit задаёт лимит запросов: не более Requests запросов за Period.
+// Нулевое значение Requests снимает ограничение.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Unlimited сообщает, снято ли ограничение.
+func (l Limit) Unlimited() bool {
+	return l.Requests == 0
+}
+
+// Result — результат попытки взять токен из корзины.
+type Result struct {
+	Allowed    bool          // Разрешён ли запрос
+	Limit      int           // Ёмкость корзины: число запросов за период
+	Remaining  int           // Число запросов, которые можно выполнить сразу
+	Reset      time.Duration // Время до полного наполнения корзины
+	RetryAfter time.Duration // Время до появления токена, если запрос не разрешён
+}
+
+// Store хранит состояние корзин по ключам.
+//
+// Реализация в памяти (MemoryStore) подходит для одного экземпляра сервера; при нескольких экземплярах
+// её можно заменить общим хранилищем, реализующим тот же интерфейс.
+type Store interface {
+	// Take берёт один токен из корзины key с лимитом limit.
+	// Корзина, к которой ещё не обращались, считается полной.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// bucket — состояние одной корзины.
+type bucket struct {
+	tokens  float64   // Число токенов на момент updated
+	updated time.Time // Время последнего обновления
+}
+
+// take пополняет корзину на момент now и берёт из неё один токен, если он есть.
+func (b *bucket) take(limit Limit, now time.Time) Result {
+	capacity := float64(limit.Requests)
+	rate := capacity / limit.Period.Seconds() // токенов в секунду
+
+	b.tokens = min(capacity, b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+
+	result := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = seconds((capacity - b.tokens) / rate)
+	return result
+}
+
+// full сообщает, наполнится ли корзина полностью к моменту now.
+func (b *bucket) full(limit Limit, now time.Time) bool {
+	capacity := float64(limit.Requests)
+	return b.tokens+now.Sub(b.updated).Seconds()*capacity/limit.Period.Seconds() >= capacity
+}
+
+// seconds переводит число секунд в time.Duration.
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/auth"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
+	"github.com/shopspring/decimal"
+	"log/slog"
+	"strings"
+)
+
+// Действия, записываемые в журнал административных действий.
+const (
+	AuditActionMint            = "mint"
+	AuditActionAdjust          = "adjust"
+	AuditActionFreeze          = "freeze"
+	AuditActionUnfreeze        = "unfreeze"
+	AuditActionGenerateWallets = "generate_wallets"
+	AuditActionReconcile       = "reconcile"
+	AuditActionRebuild         = "rebuild_projection"
+)
+
+const (
+	// maxReasonLength ограничивает длину основания административного действия.
+	maxReasonLength = 1000
+	// maxGeneratedWallets ограничивает количество кошельков, создаваемых одним запросом.
+	maxGeneratedWallets = 100
+)
+
+// AdminInteractor описывает интерфейс административных операций.
+//
+// Каждая операция требует у клиента из контекста области доступа admin и непустого основания,
+// выполняется в одной транзакции БД с записью в журнал административных действий.
+type AdminInteractor interface {
+	// Mint зачисляет на кошелёк новые средства.
+	Mint(ctx context.Context, req dto.MintReq) (dto.BalanceResp, error)
+
+	// Adjust корректирует баланс кошелька на указанную сумму со знаком.
+	Adjust(ctx context.Context, req dto.AdjustReq) (dto.BalanceResp, error)
+
+	// SetFrozen замораживает или размораживает кошелёк.
+	SetFrozen(ctx context.Context, req dto.FreezeReq) (dto.WalletStatusResp, error)
+
+	// GenerateWallets создаёт новые кошельки и возвращает их ключевые пары.
+	GenerateWallets(ctx context.Context, req dto.GenerateWalletsReq) ([]dto.WalletKey, error)
+
+	// GetAuditLog возвращает последние N записей журнала административных действий.
+	GetAuditLog(ctx context.Context, n int) ([]dto.AuditEntry, error)
+}
+
+// AdminService реализует AdminInteractor, используя репозитории и базу данных.
+type AdminService struct {
+	db              *sql.DB
+	auditRepository storage.AuditStorageInteractor
+}
+
+// NewAdminService создаёт новый экземпляр AdminService.
+//
+// Аргументы:
+//   - db: подключение к базе данных.
+//   - auditRepository: репозиторий журнала административных действий.
+//
+// Возвращает:
+//   - Указатель на AdminService.
+func NewAdminService(db *sql.DB, auditRepository storage.AuditStorageInteractor) *AdminService {
+	return &AdminService{db: db, auditRepository: auditRepository}
+}
+
+// adminTx объединяет репозитории, работающие в транзакции административной операции.
+type adminTx struct {
+	wallets      storage.WalletStorageInteractor
+	transactions storage.TransactionStorageInteractor
+	executor     storage.DBExecutor // транзакция БД для репозиториев, не перечисленных выше
+}
+
+// Mint зачисляет на кошелёк новые средства и записывает эмиссию в журнал транзакций.
+//
+// Аргументы:
+//   - ctx: контекст запроса с аутентифицированным клиентом.
+//   - req: структура dto.MintReq с адресом, суммой и основанием.
+//
+// Возвращает:
+//   - Новый баланс кошелька.
+//   - Ошибку при отсутствии прав, некорректных данных, отсутствии кошелька или сбое БД.
+func (s *AdminService) Mint(ctx context.Context, req dto.MintReq) (dto.BalanceResp, error) {
+	if req.Address == "" || !req.Amount.IsPositive() {
+		return dto.BalanceResp{}, ErrInvalid.New("address and positive amount are required")
+	}
+
+	var resp dto.BalanceResp
+	err := s.execute(ctx, AuditActionMint, req.Address, req.Reason, req, func(tx adminTx, actor string) error {
+		balance, err := changeBalance(tx.wallets, req.Address, req.Amount)
+		if err != nil {
+			return err
+		}
+		resp.Amount = balance
+
+		return appendRecord(tx.transactions, dto.TransactionRecord{
+			Kind:        dto.TransactionKindMint,
+			To:          req.Address,
+			Amount:      req.Amount,
+			InitiatedBy: actor,
+		})
+	})
+	return resp, err
+}
+
+// Adjust корректирует баланс кошелька и записывает корректировку в журнал транзакций.
+//
+// Положительная сумма зачисляется на кошелёк, отрицательная — списывается;
+// баланс после списания не может стать отрицательным.
+//
+// Аргументы:
+//   - ctx: контекст запроса с аутентифицированным клиентом.
+//   - req: структура dto.AdjustReq с адресом, суммой со знаком и основанием.
+//
+// Возвращает:
+//   - Новый баланс кошелька.
+//   - Ошибку при отсутствии прав, некорректных данных, недостатке средств, отсутствии кошелька или сбое БД.
+func (s *AdminService) Adjust(ctx context.Context, req dto.AdjustReq) (dto.BalanceResp, error) {
+	if req.Address == "" || req.Amount.IsZero() {
+		return dto.BalanceResp{}, ErrInvalid.New("address and non-zero amount are required")
+	}
+
+	var resp dto.BalanceResp
+	err := s.execute(ctx, AuditActionAdjust, req.Address, req.Reason, req, func(tx adminTx, actor string) error {
+		balance, err := changeBalance(tx.wallets, req.Address, req.Amount)
+		if err != nil {
+			return err
+		}
+		resp.Amount = balance
+
+		record := dto.TransactionRecord{
+			Kind:        dto.TransactionKindAdjustment,
+			Amount:      req.Amount.Abs(),
+			InitiatedBy: actor,
+		}
+		if req.Amount.IsPositive() {
+			record.To = req.Address
+		} else {
+			record.From = req.Address
+		}
+		return appendRecord(tx.transactions, record)
+	})
+	return resp, err
+}
+
+// SetFrozen замораживает или размораживает кошелёк. Замороженный кошелёк не может
+// участвовать в переводах ни как отправитель, ни как получатель.
+//
+// Аргументы:
+//   - ctx: контекст запроса с аутентифицированным клиентом.
+//   - req: структура dto.FreezeReq с адресом, требуемым состоянием и основанием.
+//
+// Возвращает:
+//   - Состояние кошелька после операции.
+//   - Ошибку при отсутствии прав, некорректных данных, отсутствии кошелька или сбое БД.
+func (s *AdminService) SetFrozen(ctx context.Context, req dto.FreezeReq) (dto.WalletStatusResp, error) {
+	if req.Address == "" {
+		return dto.WalletStatusResp{}, ErrInvalid.New("address is required")
+	}
+
+	action := AuditActionUnfreeze
+	if req.Frozen {
+		action = AuditActionFreeze
+	}
+
+	err := s.execute(ctx, action, req.Address, req.Reason, nil, func(tx adminTx, _ string) error {
+		if err := tx.wallets.SetFrozen(req.Address, req.Frozen); err != nil {
+			return ErrFailedToUpdate.Wrap(err, "failed to update wallet state")
+		}
+		return nil
+	})
+	if err != nil {
+		return dto.WalletStatusResp{}, err
+	}
+	return dto.WalletStatusResp{Address: req.Address, Frozen: req.Frozen}, nil
+}
+
+// GenerateWallets повторно выполняет генерацию кошельков независимо от того, есть ли кошельки в базе.
+//
+// Кошельки создаются с нулевым балансом, после чего начальный баланс зачисляется
+// эмиссией, чтобы появление новых средств было отражено в журнале транзакций.
+//
+// Аргументы:
+//   - ctx: контекст запроса с аутентифицированным клиентом.
+//   - req: структура dto.GenerateWalletsReq с количеством кошельков (0 — 10) и основанием.
+//
+// Возвращает:
+//   - Ключевые пары созданных кошельков.
+//   - Ошибку при отсутствии прав, некорректных данных или сбое генерации и записи в БД.
+func (s *AdminService) GenerateWallets(ctx context.Context, req dto.GenerateWalletsReq) ([]dto.WalletKey, error) {
+	if req.Count == 0 {
+		req.Count = defaultWalletCount
+	}
+	if req.Count < 0 || req.Count > maxGeneratedWallets {
+		return nil, ErrInvalid.New("count must be between 1 and %d", maxGeneratedWallets)
+	}
+
+	var keys []dto.WalletKey
+	err := s.execute(ctx, AuditActionGenerateWallets, "", req.Reason, req, func(tx adminTx, actor string) error {
+		var err error
+		if keys, err = createWallets(tx.wallets, req.Count, decimal.Zero); err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			if _, err := changeBalance(tx.wallets, key.Address, defaultWalletBalance); err != nil {
+				return err
+			}
+			if err := appendRecord(tx.transactions, dto.TransactionRecord{
+				Kind:        dto.TransactionKindMint,
+				To:          key.Address,
+				Amount:      defaultWalletBalance,
+				InitiatedBy: actor,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// GetAuditLog возвращает последние N записей журнала административных действий.
+//
+// Аргументы:
+//   - ctx: контекст запроса с аутентифицированным клиентом.
+//   - n: количество записей.
+//
+// Возвращает:
+//   - Записи журнала, начиная с самой новой, и ошибку при отсутствии прав или сбое БД.
+func (s *AdminService) GetAuditLog(ctx context.Context, n int) ([]dto.AuditEntry, error) {
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		return nil, ErrInvalid.New("count must be positive")
+	}
+
+	entries, err := s.auditRepository.GetLastN(n)
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to get audit log")
+	}
+	return entries, nil
+}
+
+// execute проверяет права клиента и основание, выполняет действие в транзакции БД
+// и записывает его в журнал административных действий в той же транзакции.
+//
+// Аргументы:
+//   - ctx: контекст запроса с аутентифицированным клиентом.
+//   - action, target, reason: действие, его объект и основание для журнала.
+//   - details: параметры действия, сохраняемые в журнале в формате JSON (nil — не сохраняются);
+//     сериализуются после выполнения действия, поэтому fn может дополнить их через указатель.
+//   - fn: действие; получает репозитории транзакции и идентификатор выполняющего его клиента.
+//
+// Возвращает:
+//   - ошибку проверки, действия или работы с БД; при ошибке транзакция откатывается.
+func (s *AdminService) execute(
+	ctx context.Context,
+	action, target, reason string,
+	details any,
+	fn func(tx adminTx, actor string) error,
+) (err error) {
+	principal, err := authorizeAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return ErrInvalid.New("reason is required")
+	}
+	if len(reason) > maxReasonLength {
+		return ErrInvalid.New("reason must not exceed %d characters", maxReasonLength)
+	}
+
+	tx, err := storage.BeginWrite(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	// Гарантируем откат при любой ошибке, возвращённой после начала транзакции
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.ErrorContext(ctx, "rollback failed", "error", rbErr)
+			}
+		}
+	}()
+
+	executor := storage.WithContext(ctx, tx)
+	if err := fn(adminTx{
+		wallets:      storage.NewWalletRepository(executor),
+		transactions: storage.NewTransactionRepository(executor),
+		executor:     executor,
+	}, principal.Subject); err != nil {
+		return err
+	}
+
+	entry := dto.AuditEntry{Actor: principal.Subject, Action: action, Target: target, Reason: reason}
+	if details != nil {
+		raw, err := json.Marshal(details)
+		if err != nil {
+			return ErrFailedToInsert.Wrap(err, "failed to encode audit details")
+		}
+		entry.Details = string(raw)
+	}
+
+	if _, err := storage.NewAuditRepository(executor).Insert(entry); err != nil {
+		return ErrFailedToInsert.Wrap(err, "failed to insert audit entry")
+	}
+
+	if err := storage.Commit(ctx, tx); err != nil {
+		return ErrFailedToInsert.Wrap(err, "failed to commit transaction")
+	}
+	return nil
+}
+
+// authorizeAdmin проверяет, что клиент из контекста аутентифицирован и имеет область доступа admin.
+func authorizeAdmin(ctx context.Context) (*auth.Principal, error) {
+	principal := auth.FromContext(ctx)
+	if principal == nil {
+		return nil, ErrUnauthenticated.New("authentication required")
+	}
+	if !principal.HasScope(auth.ScopeAdmin) {
+		return nil, ErrForbidden.New("scope %s is required", auth.ScopeAdmin)
+	}
+	return principal, nil
+}
+
+// changeBalance изменяет баланс кошелька на указанную сумму со знаком.
+//
+// Возвращает:
+//   - новый баланс и ErrInsufficientFunds, если баланс стал бы отрицательным.
+func changeBalance(walletRepo storage.WalletStorageInteractor, address string, delta decimal.Decimal) (decimal.Decimal, error) {
+	balanceResp, err := walletRepo.GetBalance(dto.BalanceReq{Address: address})
+	if err != nil {
+		return decimal.Decimal{}, ErrFailedToGet.Wrap(err, "failed to get balance")
+	}
+
+	balance := balanceResp.Amount.Add(delta)
+	if balance.IsNegative() {
+		return decimal.Decimal{}, ErrInsufficientFunds.New("insufficient funds")
+	}
+
+	if err := walletRepo.UpdateBalance(dto.BalanceUpdateReq{Address: address, Amount: balance}); err != nil {
+		return decimal.Decimal{}, ErrFailedToUpdate.Wrap(err, "failed to update balance")
+	}
+	return balance, nil
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/joomcode/errorx"
+	"github.com/shopspring/decimal"
+)
+
+// auditLog возвращает журнал административных действий, начиная с самой новой записи.
+func auditLog(t *testing.T, service *Service) []dto.AuditEntry {
+	t.Helper()
+	entries, err := service.AdminService.GetAuditLog(adminContext(), 100)
+	if err != nil {
+		t.Fatalf("GetAuditLog: %v", err)
+	}
+	return entries
+}
+
+func TestAdminBalanceChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		action  string
+		change  func(service *Service, address string) (dto.BalanceResp, error)
+		wantErr *errorx.Type
+		balance string
+		record  func(address string) dto.TransactionRecord
+	}{
+		{
+			name:   "mint",
+			action: AuditActionMint,
+			change: func(service *Service, address string) (dto.BalanceResp, error) {
+				return service.AdminService.Mint(adminContext(), dto.MintReq{
+					Address: address, Amount: decimal.RequireFromString("25.5"), Reason: "bonus",
+				})
+			},
+			balance: "125.5",
+			record: func(address string) dto.TransactionRecord {
+				return dto.TransactionRecord{Kind: dto.TransactionKindMint, To: address, Amount: decimal.RequireFromString("25.5")}
+			},
+		},
+		{
+			name:   "burn",
+			action: AuditActionAdjust,
+			change: func(service *Service, address string) (dto.BalanceResp, error) {
+				return service.AdminService.Adjust(adminContext(), dto.AdjustReq{
+					Address: address, Amount: decimal.NewFromInt(-40), Reason: "chargeback",
+				})
+			},
+			balance: "60",
+			record: func(address string) dto.TransactionRecord {
+				return dto.TransactionRecord{Kind: dto.TransactionKindAdjustment, From: address, Amount: decimal.NewFromInt(40)}
+			},
+		},
+		{
+			name:   "credit adjustment",
+			action: AuditActionAdjust,
+			change: func(service *Service, address string) (dto.BalanceResp, error) {
+				return service.AdminService.Adjust(adminContext(), dto.AdjustReq{
+					Address: address, Amount: decimal.NewFromInt(7), Reason: "correction",
+				})
+			},
+			balance: "107",
+			record: func(address string) dto.TransactionRecord {
+				return dto.TransactionRecord{Kind: dto.TransactionKindAdjustment, To: address, Amount: decimal.NewFromInt(7)}
+			},
+		},
+		{
+			name: "burn below zero",
+			change: func(service *Service, address string) (dto.BalanceResp, error) {
+				return service.AdminService.Adjust(adminContext(), dto.AdjustReq{
+					Address: address, Amount: decimal.NewFromInt(-101), Reason: "chargeback",
+				})
+			},
+			wantErr: ErrInsufficientFunds,
+			balance: "100",
+		},
+		{
+			name: "mint without reason",
+			change: func(service *Service, address string) (dto.BalanceResp, error) {
+				return service.AdminService.Mint(adminContext(), dto.MintReq{
+					Address: address, Amount: decimal.NewFromInt(1), Reason: "  ",
+				})
+			},
+			wantErr: ErrInvalid,
+			balance: "100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, db, wallets := newTestService(t, "100")
+			address := wallets[0].address
+
+			resp, err := tt.change(service, address)
+			if tt.wantErr != nil {
+				if !errorx.IsOfType(err, tt.wantErr) {
+					t.Fatalf("error %v, want %s", err, tt.wantErr)
+				}
+				expectBalance(t, service, address, tt.balance)
+				if records := ledgerRecords(t, db); len(records) != 0 {
+					t.Errorf("failed operation appended %d ledger records", len(records))
+				}
+				if entries := auditLog(t, service); len(entries) != 0 {
+					t.Errorf("failed operation wrote %d audit entries", len(entries))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !resp.Amount.Equal(decimal.RequireFromString(tt.balance)) {
+				t.Errorf("returned balance %s, want %s", resp.Amount, tt.balance)
+			}
+			expectBalance(t, service, address, tt.balance)
+
+			records := ledgerRecords(t, db)
+			if len(records) != 1 {
+				t.Fatalf("got %d ledger records, want 1", len(records))
+			}
+			want := tt.record(address)
+			got := records[0]
+			if got.Kind != want.Kind || got.From != want.From || got.To != want.To || !got.Amount.Equal(want.Amount) {
+				t.Errorf("ledger record = %s %.8s -> %.8s %s, want %s %.8s -> %.8s %s",
+					got.Kind, got.From, got.To, got.Amount, want.Kind, want.From, want.To, want.Amount)
+			}
+			if got.InitiatedBy != "api_key:admin" {
+				t.Errorf("record initiated by %q, want api_key:admin", got.InitiatedBy)
+			}
+
+			entries := auditLog(t, service)
+			if len(entries) != 1 {
+				t.Fatalf("got %d audit entries, want 1", len(entries))
+			}
+			entry := entries[0]
+			if entry.Actor != "api_key:admin" || entry.Action != tt.action || entry.Target != address {
+				t.Errorf("audit entry = %s %s %.8s, want api_key:admin %s %.8s",
+					entry.Actor, entry.Action, entry.Target, tt.action, address)
+			}
+			if entry.Reason == "" || entry.Details == "" {
+				t.Errorf("audit entry has no reason or details: %+v", entry)
+			}
+		})
+	}
+}
+
+func TestAdminRequiresAdminScope(t *testing.T) {
+	service, db, wallets := newTestService(t, "100")
+	address := wallets[0].address
+
+	calls := []struct {
+		name string
+		call func(ctx context.Context) error
+	}{
+		{"mint", func(ctx context.Context) error {
+			_, err := service.AdminService.Mint(ctx, dto.MintReq{Address: address, Amount: decimal.NewFromInt(5), Reason: "bonus"})
+			return err
+		}},
+		{"burn", func(ctx context.Context) error {
+			_, err := service.AdminService.Adjust(ctx, dto.AdjustReq{Address: address, Amount: decimal.NewFromInt(-5), Reason: "chargeback"})
+			return err
+		}},
+		{"freeze", func(ctx context.Context) error {
+			_, err := service.AdminService.SetFrozen(ctx, dto.FreezeReq{Address: address, Frozen: true, Reason: "fraud"})
+			return err
+		}},
+		{"generate wallets", func(ctx context.Context) error {
+			_, err := service.AdminService.GenerateWallets(ctx, dto.GenerateWalletsReq{Count: 1, Reason: "onboarding"})
+			return err
+		}},
+		{"audit log", func(ctx context.Context) error {
+			_, err := service.AdminService.GetAuditLog(ctx, 10)
+			return err
+		}},
+	}
+	principals := []struct {
+		name    string
+		ctx     context.Context
+		wantErr *errorx.Type
+	}{
+		{"anonymous", context.Background(), ErrUnauthenticated},
+		{"client without admin scope", clientContext(), ErrForbidden},
+	}
+
+	for _, principal := range principals {
+		for _, call := range calls {
+			t.Run(principal.name+"/"+call.name, func(t *testing.T) {
+				if err := call.call(principal.ctx); !errorx.IsOfType(err, principal.wantErr) {
+					t.Errorf("error %v, want %s", err, principal.wantErr)
+				}
+			})
+		}
+	}
+
+	expectBalance(t, service, address, "100")
+	if records := ledgerRecords(t, db); len(records) != 0 {
+		t.Errorf("denied operations appended %d ledger records", len(records))
+	}
+	if entries := auditLog(t, service); len(entries) != 0 {
+		t.Errorf("denied operations wrote %d audit entries", len(entries))
+	}
+	var count, frozen int
+	if err := db.QueryRow("SELECT COUNT(*), COALESCE(SUM(frozen), 0) FROM wallets").Scan(&count, &frozen); err != nil {
+		t.Fatalf("failed to count wallets: %v", err)
+	}
+	if count != 1 || frozen != 0 {
+		t.Errorf("%d wallets, %d frozen after denied operations, want 1 and 0", count, frozen)
+	}
+}
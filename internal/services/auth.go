@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"crypto/x509"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/auth"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/utils"
+	"log/slog"
+	"strings"
+)
+
+// AuthInteractor описывает интерфейс управления API-ключами и аутентификации клиентов.
+type AuthInteractor interface {
+	// CreateAPIKey создаёт новый API-ключ и возвращает его открытое значение.
+	CreateAPIKey(req dto.APIKeyReq) (dto.APIKeyResp, error)
+
+	// ListAPIKeys возвращает сведения обо всех API-ключах.
+	ListAPIKeys() ([]dto.APIKey, error)
+
+	// RevokeAPIKey отзывает API-ключ.
+	RevokeAPIKey(id int64) error
+
+	// AuthenticateAPIKey возвращает клиента, которому принадлежит ключ.
+	AuthenticateAPIKey(key string) (*auth.Principal, error)
+
+	// AuthenticateToken возвращает клиента, описанного JWT внутреннего SSO.
+	AuthenticateToken(token string) (*auth.Principal, error)
+
+	// AuthenticateCertificate возвращает клиента, которому выдан проверенный клиентский сертификат TLS.
+	AuthenticateCertificate(cert *x509.Certificate) (*auth.Principal, error)
+}
+
+// AuthService реализует AuthInteractor, используя репозитории и базу данных.
+type AuthService struct {
+	db               *sql.DB
+	walletRepository storage.WalletStorageInteractor
+	apiKeyRepository storage.APIKeyStorageInteractor
+	tokenVerifier    *auth.JWTVerifier
+}
+
+// NewAuthService создаёт новый экземпляр AuthService.
+//
+// Аргументы:
+//   - db: подключение к базе данных.
+//   - walletRepository: репозиторий для работы с кошельками.
+//   - apiKeyRepository: репозиторий для работы с API-ключами.
+//   - tokenVerifier: проверка JWT; nil, если аутентификация по JWT отключена.
+//
+// Возвращает:
+//   - Указатель на AuthService.
+func NewAuthService(
+	db *sql.DB,
+	walletRepository storage.WalletStorageInteractor,
+	apiKeyRepository storage.APIKeyStorageInteractor,
+	tokenVerifier *auth.JWTVerifier,
+) *AuthService {
+	return &AuthService{
+		db:               db,
+		walletRepository: walletRepository,
+		apiKeyRepository: apiKeyRepository,
+		tokenVerifier:    tokenVerifier,
+	}
+}
+
+// CreateAPIKey создаёт API-ключ с указанными областями доступа и привязанными кошельками.
+//
+// Значение ключа генерируется случайно, в базе сохраняется только его хеш.
+// Ключ и привязки кошельков записываются в одной транзакции.
+//
+// Аргументы:
+//   - req: структура dto.APIKeyReq с названием, областями доступа и кошельками.
+//
+// Возвращает:
+//   - dto.APIKeyResp с открытым значением ключа, которое больше нигде не сохраняется.
+//   - ошибку в случае некорректных данных, отсутствия кошелька или ошибок работы с БД.
+func (s *AuthService) CreateAPIKey(req dto.APIKeyReq) (resp dto.APIKeyResp, err error) {
+	// Валидация
+	if strings.TrimSpace(req.Name) == "" {
+		return dto.APIKeyResp{}, ErrInvalid.New("api key name is required")
+	}
+	if len(req.Scopes) == 0 {
+		return dto.APIKeyResp{}, ErrInvalid.New("at least one scope is required")
+	}
+	for _, scope := range req.Scopes {
+		if !auth.ValidScope(scope) {
+			return dto.APIKeyResp{}, ErrInvalid.New("unknown scope %q", scope)
+		}
+	}
+	for _, address := range req.Wallets {
+		if _, err := s.walletRepository.GetBalance(dto.BalanceReq{Address: address}); err != nil {
+			return dto.APIKeyResp{}, ErrFailedToGet.Wrap(err, "failed to check wallet %s", address)
+		}
+	}
+
+	key, err := utils.GenerateAPIKey()
+	if err != nil {
+		return dto.APIKeyResp{}, ErrFailedToGenerate.Wrap(err, "failed to generate api key")
+	}
+
+	tx, err := storage.BeginWrite(context.Background(), s.db)
+	if err != nil {
+		return dto.APIKeyResp{}, err
+	}
+
+	// Гарантируем откат при любой ошибке, возвращённой после начала транзакции
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.Error("rollback failed", "error", rbErr)
+			}
+		}
+	}()
+
+	apiKeyRepo := storage.NewAPIKeyRepository(tx)
+
+	resp.APIKey = dto.APIKey{
+		Name:       req.Name,
+		KeyHash:    auth.HashAPIKey(key),
+		Scopes:     req.Scopes,
+		Wallets:    req.Wallets,
+		AllWallets: req.AllWallets,
+	}
+	if resp.ID, err = apiKeyRepo.Insert(resp.APIKey); err != nil {
+		return dto.APIKeyResp{}, ErrFailedToInsert.Wrap(err, "failed to insert api key")
+	}
+	for _, address := range req.Wallets {
+		if err := apiKeyRepo.InsertWallet(resp.ID, address); err != nil {
+			return dto.APIKeyResp{}, ErrFailedToInsert.Wrap(err, "failed to bind wallet to api key")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.APIKeyResp{}, ErrFailedToInsert.Wrap(err, "failed to commit transaction")
+	}
+
+	resp.Key = key
+	return resp, nil
+}
+
+// ListAPIKeys возвращает сведения обо всех API-ключах, включая отозванные.
+//
+// Возвращает:
+//   - срез ключей без их значений и ошибку, если она возникла.
+func (s *AuthService) ListAPIKeys() ([]dto.APIKey, error) {
+	return s.apiKeyRepository.List()
+}
+
+// RevokeAPIKey отзывает API-ключ по идентификатору.
+//
+// Аргументы:
+//   - id: идентификатор ключа.
+//
+// Возвращает:
+//   - ошибку, если ключ не найден, уже отозван или возникла ошибка работы с БД.
+func (s *AuthService) RevokeAPIKey(id int64) error {
+	return s.apiKeyRepository.Revoke(id)
+}
+
+// AuthenticateAPIKey находит действующий ключ по его значению и формирует описание клиента.
+//
+// Аргументы:
+//   - key: открытое значение ключа из запроса.
+//
+// Возвращает:
+//   - *auth.Principal с областями доступа и кошельками ключа.
+//   - ErrUnauthenticated, если ключ неизвестен или отозван; иную ошибку при сбое БД.
+func (s *AuthService) AuthenticateAPIKey(key string) (*auth.Principal, error) {
+	apiKey, err := s.apiKeyRepository.GetByHash(auth.HashAPIKey(key))
+	if err != nil {
+		if storage.IsNotFoundErr(err) {
+			return nil, ErrUnauthenticated.New("invalid api key")
+		}
+		return nil, ErrFailedToGet.Wrap(err, "failed to get api key")
+	}
+	if apiKey.RevokedAt != nil {
+		return nil, ErrUnauthenticated.New("api key has been revoked")
+	}
+
+	scopes := make([]auth.Scope, 0, len(apiKey.Scopes))
+	for _, scope := range apiKey.Scopes {
+		scopes = append(scopes, auth.Scope(scope))
+	}
+
+	return &auth.Principal{
+		Subject:    fmt.Sprintf("%s:%d", auth.MethodAPIKey, apiKey.ID),
+		Name:       apiKey.Name,
+		Method:     auth.MethodAPIKey,
+		Scopes:     scopes,
+		Wallets:    apiKey.Wallets,
+		AllWallets: apiKey.AllWallets,
+	}, nil
+}
+
+// AuthenticateToken проверяет JWT внутреннего SSO и формирует описание клиента.
+//
+// Аргументы:
+//   - token: компактное представление JWT.
+//
+// Возвращает:
+//   - *auth.Principal с ролями, областями доступа и кошельками из claims токена.
+//   - ErrUnauthenticated, если токен не прошёл проверку или аутентификация по JWT отключена;
+//     ErrFailedToGet, если не удалось загрузить ключи JWKS.
+func (s *AuthService) AuthenticateToken(token string) (*auth.Principal, error) {
+	if s.tokenVerifier == nil {
+		return nil, ErrUnauthenticated.New("bearer tokens are not accepted")
+	}
+
+	principal, err := s.tokenVerifier.Verify(token)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) {
+			return nil, ErrUnauthenticated.New("invalid token")
+		}
+		return nil, ErrFailedToGet.Wrap(err, "failed to verify token")
+	}
+
+	return principal, nil
+}
+
+// AuthenticateCertificate формирует описание клиента по проверенному клиентскому сертификату TLS
+// (см. auth.CertificatePrincipal).
+//
+// Аргументы:
+//   - cert: клиентский сертификат, цепочка которого проверена при рукопожатии TLS.
+//
+// Возвращает:
+//   - *auth.Principal с ролями и кошельками из сертификата.
+//   - ErrUnauthenticated, если по сертификату нельзя определить клиента.
+func (s *AuthService) AuthenticateCertificate(cert *x509.Certificate) (*auth.Principal, error) {
+	principal, err := auth.CertificatePrincipal(cert)
+	if err != nil {
+		return nil, ErrUnauthenticated.New("client certificate has no common name")
+	}
+	return principal, nil
+}
@@ -32,6 +32,28 @@ var (
 	Client = errorx.RegisterTrait("client")
 	// ErrInvalid — тип ошибки, указывающей на некорректные входные данные (ошибка клиента).
 	ErrInvalid = ServiceErrors.NewType("invalid", Client)
+	// ErrSameWallet — тип ошибки при попытке перевода на тот же кошелёк (ошибка клиента).
+	ErrSameWallet = ServiceErrors.NewType("same_wallet", Client)
+	// ErrInsufficientFunds — тип ошибки при недостатке средств на кошельке отправителя (ошибка клиента).
+	ErrInsufficientFunds = ServiceErrors.NewType("insufficient_funds", Client)
+	// ErrInvalidSignature — тип ошибки при неверной подписи перевода (ошибка клиента).
+	ErrInvalidSignature = ServiceErrors.NewType("invalid_signature", Client)
+	// ErrInvalidNonce — тип ошибки при повторно использованном или устаревшем nonce (ошибка клиента).
+	ErrInvalidNonce = ServiceErrors.NewType("invalid_nonce", Client)
+	// ErrUnauthenticated — тип ошибки при неудачной аутентификации клиента (ошибка клиента).
+	ErrUnauthenticated = ServiceErrors.NewType("unauthenticated", Client)
+	// ErrForbidden — тип ошибки при отсутствии у клиента прав на операцию (ошибка клиента).
+	ErrForbidden = ServiceErrors.NewType("forbidden", Client)
+	// ErrWalletFrozen — тип ошибки при операции с замороженным кошельком (ошибка клиента).
+	ErrWalletFrozen = ServiceErrors.NewType("wallet_frozen", Client)
+	// ErrProjectionNotFound — тип ошибки при обращении к незарегистрированной проекции (ошибка клиента).
+	ErrProjectionNotFound = ServiceErrors.NewType("projection_not_found", Client, errorx.NotFound())
+	// ErrIdempotencyKeyReused — тип ошибки при повторном использовании ключа идемпотентности
+	// с другим запросом (ошибка клиента).
+	ErrIdempotencyKeyReused = ServiceErrors.NewType("idempotency_key_reused", Client)
+	// ErrIdempotencyKeyInUse — тип ошибки при повторе запроса, первый запрос с ключом идемпотентности
+	// которого ещё обрабатывается (ошибка клиента).
+	ErrIdempotencyKeyInUse = ServiceErrors.NewType("idempotency_key_in_use", Client)
 
 	// Server — трейд для ошибок, связанных с внутренними ошибками сервера.
 	Server = errorx.RegisterTrait("server")
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
+	"sync/atomic"
+	"time"
+)
+
+// readinessTimeout ограничивает время проверки доступности базы данных.
+const readinessTimeout = 2 * time.Second
+
+// HealthInteractor описывает интерфейс проверок состояния сервера для оркестратора.
+type HealthInteractor interface {
+	// Ready проверяет готовность сервера принимать запросы.
+	Ready(ctx context.Context) dto.ReadinessResp
+
+	// Version возвращает сведения о сборке сервера.
+	Version() dto.VersionResp
+
+	// Drain переводит сервер в состояние завершения: последующие проверки готовности не проходят.
+	Drain()
+}
+
+// HealthService реализует HealthInteractor.
+type HealthService struct {
+	db               *sql.DB
+	migrationVersion uint
+	build            dto.VersionResp
+	draining         atomic.Bool
+}
+
+// NewHealthService создаёт новый экземпляр HealthService.
+//
+// Аргументы:
+//   - db: подключение к базе данных.
+//   - migrationVersion: версия схемы, ожидаемая сервером (последняя миграция в каталоге миграций).
+//   - build: сведения о сборке сервера.
+//
+// Возвращает:
+//   - Указатель на HealthService.
+func NewHealthService(db *sql.DB, migrationVersion uint, build dto.VersionResp) *HealthService {
+	return &HealthService{db: db, migrationVersion: migrationVersion, build: build}
+}
+
+// Ready проверяет готовность сервера принимать запросы:
+//   - shutdown — сервер не получил сигнал завершения (см. Drain);
+//   - database — база данных отвечает на запросы;
+//   - migrations — схема базы данных имеет ожидаемую версию и последняя миграция завершена.
+//
+// Выполняются все проверки, даже если одна из них не прошла.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//
+// Возвращает:
+//   - общий результат и результаты отдельных проверок.
+func (s *HealthService) Ready(ctx context.Context) dto.ReadinessResp {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	resp := dto.ReadinessResp{Status: dto.HealthStatusOK}
+	check := func(name string, err error) {
+		result := dto.ReadinessCheck{Name: name, Status: dto.HealthStatusOK}
+		if err != nil {
+			result.Status, result.Detail = dto.HealthStatusFail, err.Error()
+			resp.Status = dto.HealthStatusFail
+		}
+		resp.Checks = append(resp.Checks, result)
+	}
+
+	var shutdownErr error
+	if s.draining.Load() {
+		shutdownErr = fmt.Errorf("server is shutting down")
+	}
+	check("shutdown", shutdownErr)
+	check("database", s.db.PingContext(ctx))
+	check("migrations", s.checkMigrations(ctx))
+	return resp
+}
+
+// checkMigrations сравнивает версию схемы базы данных с ожидаемой.
+func (s *HealthService) checkMigrations(ctx context.Context) error {
+	version, dirty, err := storage.MigrationVersion(storage.WithContext(ctx, s.db))
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("migration %d is dirty", version)
+	}
+	if version != s.migrationVersion {
+		return fmt.Errorf("schema version is %d, expected %d", version, s.migrationVersion)
+	}
+	return nil
+}
+
+// Version возвращает сведения о сборке сервера, переданные при создании сервиса.
+func (s *HealthService) Version() dto.VersionResp {
+	return s.build
+}
+
+// Drain переводит сервер в состояние завершения. Вызывается при получении сигнала завершения,
+// чтобы балансировщик нагрузки перестал направлять запросы до остановки HTTP-сервера.
+func (s *HealthService) Drain() {
+	s.draining.Store(true)
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
+	"time"
+)
+
+// IdempotencyInteractor описывает интерфейс работы с ключами идемпотентности запросов.
+//
+// Клиент, повторяющий запрос после сбоя соединения или ответа 5xx, передаёт в нём тот же ключ
+// (заголовок Idempotency-Key). Первый запрос с ключом выполняется, а на повторные возвращается
+// сохранённый ответ на него, поэтому операция выполняется не более одного раза.
+type IdempotencyInteractor interface {
+	// Begin начинает обработку запроса с ключом или возвращает сохранённый ответ на него.
+	Begin(ctx context.Context, req dto.IdempotencyRecord) (dto.IdempotencyRecord, bool, error)
+
+	// Complete сохраняет ответ на запрос с ключом.
+	Complete(ctx context.Context, record dto.IdempotencyRecord) error
+
+	// Release освобождает ключ запроса, ответ на который не должен сохраняться.
+	Release(ctx context.Context, subject, key string) error
+
+	// DeleteExpired удаляет ключи, сохранённые раньше ttl назад.
+	DeleteExpired(ctx context.Context, ttl time.Duration) (int64, error)
+}
+
+// IdempotencyService реализует IdempotencyInteractor, используя репозиторий ключей идемпотентности.
+type IdempotencyService struct {
+	idempotencyRepository storage.IdempotencyStorageInteractor
+	// startedAt — время запуска сервиса. Необработанные запросы, сохранённые раньше, прервала остановка
+	// сервера, и их ключи можно передать повторным запросам.
+	startedAt time.Time
+}
+
+// NewIdempotencyService создаёт новый экземпляр IdempotencyService.
+//
+// Аргументы:
+//   - idempotencyRepository: репозиторий ключей идемпотентности.
+//
+// Возвращает:
+//   - Указатель на IdempotencyService.
+func NewIdempotencyService(idempotencyRepository storage.IdempotencyStorageInteractor) *IdempotencyService {
+	return &IdempotencyService{idempotencyRepository: idempotencyRepository, startedAt: time.Now().UTC()}
+}
+
+// Begin сохраняет ключ идемпотентности нового запроса или находит первый запрос с этим ключом.
+//
+// Ключи принадлежат клиентам: одинаковые ключи разных клиентов не связаны между собой.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//   - req: запрос; используются поля Subject, Key, Operation и RequestHash.
+//
+// Возвращает:
+//   - первый запрос с ключом и сохранённый ответ на него, если запрос уже выполнен;
+//   - true, если запрос нужно выполнить (ключ использован впервые или прежний запрос прерван остановкой сервера);
+//   - ошибку ErrIdempotencyKeyReused, если ключ использован с другим запросом,
+//     ErrIdempotencyKeyInUse, если первый запрос с ключом ещё обрабатывается, или ошибку БД.
+func (s *IdempotencyService) Begin(ctx context.Context, req dto.IdempotencyRecord) (dto.IdempotencyRecord, bool, error) {
+	repo := s.idempotencyRepository.WithContext(ctx)
+	req.CreatedAt = time.Now().UTC()
+
+	inserted, err := repo.Insert(req)
+	if err != nil {
+		return dto.IdempotencyRecord{}, false, ErrFailedToInsert.Wrap(err, "failed to save idempotency key")
+	}
+	if inserted {
+		return req, true, nil
+	}
+
+	record, err := repo.Get(req.Subject, req.Key)
+	if err != nil {
+		// Ключ удалён между вставкой и чтением (освобождён или устарел): клиенту достаточно повторить запрос
+		if storage.IsNotFoundErr(err) {
+			return dto.IdempotencyRecord{}, false, ErrIdempotencyKeyInUse.New("request with this idempotency key is being processed")
+		}
+		return dto.IdempotencyRecord{}, false, ErrFailedToGet.Wrap(err, "failed to get idempotency key")
+	}
+	if record.Operation != req.Operation || record.RequestHash != req.RequestHash {
+		return dto.IdempotencyRecord{}, false, ErrIdempotencyKeyReused.New(
+			"idempotency key was already used with a different request")
+	}
+	if record.Completed() {
+		return record, false, nil
+	}
+
+	acquired, err := repo.Acquire(req, s.startedAt)
+	if err != nil {
+		return dto.IdempotencyRecord{}, false, ErrFailedToUpdate.Wrap(err, "failed to acquire idempotency key")
+	}
+	if !acquired {
+		return dto.IdempotencyRecord{}, false, ErrIdempotencyKeyInUse.New("request with this idempotency key is being processed")
+	}
+	return req, true, nil
+}
+
+// Complete сохраняет ответ на запрос, начатый Begin.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//   - record: запрос с ответом; используются поля Subject, Key, StatusCode, ContentType и Body.
+//
+// Возвращает:
+//   - ошибку при сбое БД.
+func (s *IdempotencyService) Complete(ctx context.Context, record dto.IdempotencyRecord) error {
+	if err := s.idempotencyRepository.WithContext(ctx).Complete(record); err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to save response of idempotency key")
+	}
+	return nil
+}
+
+// Release освобождает ключ запроса, начатого Begin, без сохранения ответа — например, если запрос
+// завершился ошибкой сервера или превышением лимита. Повторный запрос с ключом будет выполнен заново.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//   - subject: клиент, отправивший запрос.
+//   - key: ключ идемпотентности.
+//
+// Возвращает:
+//   - ошибку при сбое БД.
+func (s *IdempotencyService) Release(ctx context.Context, subject, key string) error {
+	if err := s.idempotencyRepository.WithContext(ctx).Release(subject, key); err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to release idempotency key")
+	}
+	return nil
+}
+
+// DeleteExpired удаляет ключи идемпотентности старше ttl. После этого повторный запрос
+// с удалённым ключом выполняется как новый.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//   - ttl: время хранения ключей.
+//
+// Возвращает:
+//   - количество удалённых ключей и ошибку при сбое БД.
+func (s *IdempotencyService) DeleteExpired(ctx context.Context, ttl time.Duration) (int64, error) {
+	deleted, err := s.idempotencyRepository.WithContext(ctx).DeleteBefore(time.Now().UTC().Add(-ttl))
+	if err != nil {
+		return 0, ErrFailedToUpdate.Wrap(err, "failed to delete expired idempotency keys")
+	}
+	return deleted, nil
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
+	"github.com/joomcode/errorx"
+)
+
+// idempotentRequest возвращает запрос перевода клиента api_key:client с ключом key и хешем тела hash.
+func idempotentRequest(key, hash string) dto.IdempotencyRecord {
+	return dto.IdempotencyRecord{
+		Subject:     "api_key:client",
+		Key:         key,
+		Operation:   "send",
+		RequestHash: hash,
+	}
+}
+
+// beginRequest начинает обработку запроса и проверяет, что его нужно выполнить.
+func beginRequest(t *testing.T, service IdempotencyInteractor, req dto.IdempotencyRecord) {
+	t.Helper()
+	if _, execute, err := service.Begin(context.Background(), req); err != nil || !execute {
+		t.Fatalf("Begin: execute = %t, error = %v", execute, err)
+	}
+}
+
+// completeRequest сохраняет ответ 200 с телом body на запрос.
+func completeRequest(t *testing.T, service IdempotencyInteractor, req dto.IdempotencyRecord, body string) {
+	t.Helper()
+	req.StatusCode, req.ContentType, req.Body = 200, "application/json", []byte(body)
+	if err := service.Complete(context.Background(), req); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+}
+
+func TestIdempotencyBegin(t *testing.T) {
+	first := idempotentRequest("key-1", "hash-1")
+
+	tests := []struct {
+		name string
+		// prepare выполняет первый запрос с ключом и возвращает сервис, обрабатывающий повторный
+		prepare     func(t *testing.T, service IdempotencyInteractor, db *sql.DB) IdempotencyInteractor
+		req         dto.IdempotencyRecord
+		wantExecute bool
+		wantBody    string
+		wantErr     *errorx.Type
+	}{
+		{
+			name:        "new key",
+			prepare:     func(_ *testing.T, service IdempotencyInteractor, _ *sql.DB) IdempotencyInteractor { return service },
+			req:         first,
+			wantExecute: true,
+		},
+		{
+			name: "replay of completed request",
+			prepare: func(t *testing.T, service IdempotencyInteractor, _ *sql.DB) IdempotencyInteractor {
+				beginRequest(t, service, first)
+				completeRequest(t, service, first, `{"ok":true}`)
+				return service
+			},
+			req:      first,
+			wantBody: `{"ok":true}`,
+		},
+		{
+			name: "key reused with another body",
+			prepare: func(t *testing.T, service IdempotencyInteractor, _ *sql.DB) IdempotencyInteractor {
+				beginRequest(t, service, first)
+				completeRequest(t, service, first, `{"ok":true}`)
+				return service
+			},
+			req:     idempotentRequest("key-1", "hash-2"),
+			wantErr: ErrIdempotencyKeyReused,
+		},
+		{
+			name: "key reused with another operation",
+			prepare: func(t *testing.T, service IdempotencyInteractor, _ *sql.DB) IdempotencyInteractor {
+				beginRequest(t, service, first)
+				return service
+			},
+			req: func() dto.IdempotencyRecord {
+				req := first
+				req.Operation = "mint"
+				return req
+			}(),
+			wantErr: ErrIdempotencyKeyReused,
+		},
+		{
+			name: "request in progress",
+			prepare: func(t *testing.T, service IdempotencyInteractor, _ *sql.DB) IdempotencyInteractor {
+				beginRequest(t, service, first)
+				return service
+			},
+			req:     first,
+			wantErr: ErrIdempotencyKeyInUse,
+		},
+		{
+			name: "same key of another client",
+			prepare: func(t *testing.T, service IdempotencyInteractor, _ *sql.DB) IdempotencyInteractor {
+				beginRequest(t, service, first)
+				return service
+			},
+			req: func() dto.IdempotencyRecord {
+				req := first
+				req.Subject = "api_key:other"
+				return req
+			}(),
+			wantExecute: true,
+		},
+		{
+			name: "released key",
+			prepare: func(t *testing.T, service IdempotencyInteractor, _ *sql.DB) IdempotencyInteractor {
+				beginRequest(t, service, first)
+				if err := service.Release(context.Background(), first.Subject, first.Key); err != nil {
+					t.Fatalf("Release: %v", err)
+				}
+				return service
+			},
+			req:         first,
+			wantExecute: true,
+		},
+		{
+			name: "request interrupted by restart",
+			prepare: func(t *testing.T, service IdempotencyInteractor, db *sql.DB) IdempotencyInteractor {
+				beginRequest(t, service, first)
+				time.Sleep(time.Millisecond)
+				return NewIdempotencyService(storage.NewIdempotencyRepository(db))
+			},
+			req:         first,
+			wantExecute: true,
+		},
+		{
+			name: "expired key",
+			prepare: func(t *testing.T, service IdempotencyInteractor, _ *sql.DB) IdempotencyInteractor {
+				beginRequest(t, service, first)
+				completeRequest(t, service, first, `{"ok":true}`)
+				time.Sleep(time.Millisecond)
+				if deleted, err := service.DeleteExpired(context.Background(), 0); err != nil || deleted != 1 {
+					t.Fatalf("DeleteExpired: deleted %d, error %v", deleted, err)
+				}
+				return service
+			},
+			req:         idempotentRequest("key-1", "hash-2"),
+			wantExecute: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			service := tt.prepare(t, NewService(db, nil, nil).IdempotencyService, db)
+
+			record, execute, err := service.Begin(context.Background(), tt.req)
+			if tt.wantErr != nil {
+				if !errorx.IsOfType(err, tt.wantErr) {
+					t.Fatalf("error %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Begin: %v", err)
+			}
+			if execute != tt.wantExecute {
+				t.Fatalf("execute = %t, want %t", execute, tt.wantExecute)
+			}
+			if tt.wantBody != "" && (record.StatusCode != 200 || string(record.Body) != tt.wantBody) {
+				t.Errorf("stored response = %d %s, want 200 %s", record.StatusCode, record.Body, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/utils"
+	"log/slog"
+	"time"
+)
+
+// Причины нарушения целостности журнала транзакций.
+const (
+	LedgerBreakMissingHash        = "missing_hash"
+	LedgerBreakPrevHashMismatch   = "prev_hash_mismatch"
+	LedgerBreakHashMismatch       = "hash_mismatch"
+	LedgerBreakBadSignature       = "checkpoint_bad_signature"
+	LedgerBreakUnknownKey         = "checkpoint_unknown_key"
+	LedgerBreakCheckpointMismatch = "checkpoint_mismatch"
+)
+
+// LedgerInteractor описывает интерфейс проверки целостности журнала транзакций
+// и работы с его контрольными точками.
+type LedgerInteractor interface {
+	// Verify пересчитывает цепочку хешей журнала и проверяет контрольные точки.
+	Verify(ctx context.Context) (dto.LedgerVerifyResp, error)
+
+	// CreateCheckpoint создаёт подписанную контрольную точку, если журнал изменился с момента предыдущей.
+	CreateCheckpoint(ctx context.Context) (dto.Checkpoint, bool, error)
+
+	// GetCheckpoints возвращает последние N контрольных точек и открытый ключ сервера.
+	GetCheckpoints(ctx context.Context, n int) (dto.CheckpointsResp, error)
+
+	// SealLegacy включает в цепочку хешей записи, созданные до её появления.
+	SealLegacy(ctx context.Context) (int, error)
+}
+
+// LedgerService реализует LedgerInteractor, используя репозитории и базу данных.
+type LedgerService struct {
+	db                    *sql.DB
+	transactionRepository storage.TransactionStorageInteractor
+	checkpointRepository  storage.CheckpointStorageInteractor
+	signingKey            ed25519.PrivateKey
+}
+
+// NewLedgerService создаёт новый экземпляр LedgerService.
+//
+// Аргументы:
+//   - db: подключение к базе данных.
+//   - transactionRepository: репозиторий журнала транзакций.
+//   - checkpointRepository: репозиторий контрольных точек.
+//   - signingKey: ключ Ed25519 для подписи контрольных точек; nil — создание контрольных точек
+//     недоступно, а их подписи проверяются только открытыми ключами из самих контрольных точек.
+//
+// Возвращает:
+//   - Указатель на LedgerService.
+func NewLedgerService(
+	db *sql.DB,
+	transactionRepository storage.TransactionStorageInteractor,
+	checkpointRepository storage.CheckpointStorageInteractor,
+	signingKey ed25519.PrivateKey,
+) *LedgerService {
+	return &LedgerService{
+		db:                    db,
+		transactionRepository: transactionRepository,
+		checkpointRepository:  checkpointRepository,
+		signingKey:            signingKey,
+	}
+}
+
+// errLedgerBroken прерывает обход журнала при первом найденном нарушении.
+var errLedgerBroken = errors.New("ledger chain is broken")
+
+// checkpointState — состояние цепочки на транзакции, зафиксированной контрольной точкой.
+type checkpointState struct {
+	hash  string
+	count int64
+}
+
+// Verify последовательно пересчитывает хеш каждой записи журнала и сверяет его с сохранённым,
+// а также проверяет, что каждая запись ссылается на хеш предыдущей. Затем проверяет подписи
+// контрольных точек и их соответствие журналу: так обнаруживается и удаление последних записей.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//
+// Возвращает:
+//   - dto.LedgerVerifyResp с признаком целостности и первым найденным нарушением.
+//   - ошибку, если журнал или контрольные точки не удалось прочитать.
+func (s *LedgerService) Verify(ctx context.Context) (dto.LedgerVerifyResp, error) {
+	checkpoints, err := s.checkpointRepository.WithContext(ctx).GetAll()
+	if err != nil {
+		return dto.LedgerVerifyResp{}, ErrFailedToGet.Wrap(err, "failed to get checkpoints")
+	}
+
+	// Запоминаем состояние цепочки на транзакциях, зафиксированных контрольными точками
+	states := make(map[int64]checkpointState, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		states[checkpoint.LastTxID] = checkpointState{}
+	}
+
+	var resp dto.LedgerVerifyResp
+	err = s.transactionRepository.WithContext(ctx).ForEachRecord(0, func(record dto.TransactionRecord) error {
+		if brk := checkRecord(record, resp.HeadHash); brk != nil {
+			resp.Break = brk
+			return errLedgerBroken
+		}
+
+		resp.Checked++
+		resp.HeadID, resp.HeadHash = record.ID, record.Hash
+		if _, ok := states[record.ID]; ok {
+			states[record.ID] = checkpointState{hash: record.Hash, count: resp.Checked}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errLedgerBroken) {
+		return dto.LedgerVerifyResp{}, ErrFailedToGet.Wrap(err, "failed to read ledger")
+	}
+	if resp.Break != nil {
+		return resp, nil
+	}
+
+	for _, checkpoint := range checkpoints {
+		if brk := s.checkCheckpoint(checkpoint, states[checkpoint.LastTxID]); brk != nil {
+			resp.Break = brk
+			return resp, nil
+		}
+		resp.Checkpoints++
+	}
+
+	resp.Valid = true
+	return resp, nil
+}
+
+// checkRecord проверяет запись журнала относительно хеша предыдущей записи.
+//
+// Возвращает:
+//   - описание нарушения или nil, если запись корректна.
+func checkRecord(record dto.TransactionRecord, prevHash string) *dto.LedgerBreak {
+	if record.Hash == "" {
+		return &dto.LedgerBreak{TransactionID: record.ID, Reason: LedgerBreakMissingHash}
+	}
+	if record.PrevHash != prevHash {
+		return &dto.LedgerBreak{
+			TransactionID: record.ID,
+			Reason:        LedgerBreakPrevHashMismatch,
+			Expected:      prevHash,
+			Actual:        record.PrevHash,
+		}
+	}
+	if expected := utils.HashTransaction(record); record.Hash != expected {
+		return &dto.LedgerBreak{
+			TransactionID: record.ID,
+			Reason:        LedgerBreakHashMismatch,
+			Expected:      expected,
+			Actual:        record.Hash,
+		}
+	}
+	return nil
+}
+
+// checkCheckpoint проверяет подпись контрольной точки и её соответствие состоянию цепочки.
+//
+// Возвращает:
+//   - описание нарушения или nil, если контрольная точка корректна.
+func (s *LedgerService) checkCheckpoint(checkpoint dto.Checkpoint, state checkpointState) *dto.LedgerBreak {
+	if !utils.VerifyCheckpoint(checkpoint) {
+		return &dto.LedgerBreak{CheckpointID: checkpoint.ID, Reason: LedgerBreakBadSignature}
+	}
+	if s.signingKey != nil {
+		if publicKey := s.publicKey(); checkpoint.PublicKey != publicKey {
+			return &dto.LedgerBreak{
+				CheckpointID: checkpoint.ID,
+				Reason:       LedgerBreakUnknownKey,
+				Expected:     publicKey,
+				Actual:       checkpoint.PublicKey,
+			}
+		}
+	}
+	if state.hash != checkpoint.HeadHash || state.count != checkpoint.TxCount {
+		return &dto.LedgerBreak{
+			TransactionID: checkpoint.LastTxID,
+			CheckpointID:  checkpoint.ID,
+			Reason:        LedgerBreakCheckpointMismatch,
+			Expected:      checkpoint.HeadHash,
+			Actual:        state.hash,
+		}
+	}
+	return nil
+}
+
+// CreateCheckpoint создаёт подписанную контрольную точку по последней записи журнала.
+//
+// Контрольная точка не создаётся, если журнал пуст или не изменился с момента предыдущей.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//
+// Возвращает:
+//   - созданную (или последнюю существующую) контрольную точку;
+//   - true, если контрольная точка создана;
+//   - ошибку, если ключ подписи не задан или возникла ошибка работы с БД.
+func (s *LedgerService) CreateCheckpoint(ctx context.Context) (dto.Checkpoint, bool, error) {
+	if s.signingKey == nil {
+		return dto.Checkpoint{}, false, ErrFailedToGenerate.New("ledger signing key is not configured")
+	}
+
+	checkpointRepository := s.checkpointRepository.WithContext(ctx)
+	head, err := s.transactionRepository.WithContext(ctx).GetHead()
+	if err != nil {
+		return dto.Checkpoint{}, false, ErrFailedToGet.Wrap(err, "failed to get ledger head")
+	}
+	if head.ID == 0 {
+		return dto.Checkpoint{}, false, nil
+	}
+
+	last, err := checkpointRepository.GetLastN(1)
+	if err != nil {
+		return dto.Checkpoint{}, false, ErrFailedToGet.Wrap(err, "failed to get last checkpoint")
+	}
+	if len(last) > 0 && last[0].LastTxID == head.ID {
+		return last[0], false, nil
+	}
+
+	checkpoint := dto.Checkpoint{
+		LastTxID:  head.ID,
+		TxCount:   head.Count,
+		HeadHash:  head.Hash,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		PublicKey: s.publicKey(),
+	}
+	checkpoint.Signature = utils.SignCheckpoint(s.signingKey, checkpoint)
+
+	if checkpoint.ID, err = checkpointRepository.Insert(checkpoint); err != nil {
+		return dto.Checkpoint{}, false, ErrFailedToInsert.Wrap(err, "failed to insert checkpoint")
+	}
+	return checkpoint, true, nil
+}
+
+// GetCheckpoints возвращает последние N контрольных точек и текущий открытый ключ сервера,
+// которым внешние стороны могут проверить их подписи.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//   - n: количество контрольных точек.
+//
+// Возвращает:
+//   - dto.CheckpointsResp и ошибку при некорректном n или сбое БД.
+func (s *LedgerService) GetCheckpoints(ctx context.Context, n int) (dto.CheckpointsResp, error) {
+	if n <= 0 {
+		return dto.CheckpointsResp{}, ErrInvalid.New("count must be positive")
+	}
+
+	checkpoints, err := s.checkpointRepository.WithContext(ctx).GetLastN(n)
+	if err != nil {
+		return dto.CheckpointsResp{}, ErrFailedToGet.Wrap(err, "failed to get checkpoints")
+	}
+
+	resp := dto.CheckpointsResp{Checkpoints: checkpoints}
+	if s.signingKey != nil {
+		resp.PublicKey = s.publicKey()
+	}
+	return resp, nil
+}
+
+// SealLegacy вычисляет хеши записей журнала, созданных до появления цепочки хешей.
+//
+// Выполняется только если ни одна запись ещё не включена в цепочку: запись без хеша,
+// появившаяся позже, считается нарушением целостности и не может быть «узаконена» повторным запуском.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//
+// Возвращает:
+//   - количество записей, включённых в цепочку, и ошибку при сбое БД.
+func (s *LedgerService) SealLegacy(ctx context.Context) (sealed int, err error) {
+	tx, err := storage.BeginWrite(ctx, s.db)
+	if err != nil {
+		return 0, err
+	}
+
+	// Гарантируем откат при любой ошибке, возвращённой после начала транзакции
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.ErrorContext(ctx, "rollback failed", "error", rbErr)
+			}
+		}
+	}()
+
+	transactionRepo := storage.NewTransactionRepository(tx)
+
+	hashed, err := transactionRepo.CountHashed()
+	if err != nil {
+		return 0, ErrFailedToGet.Wrap(err, "failed to count hashed transactions")
+	}
+	if hashed > 0 {
+		return 0, tx.Commit()
+	}
+
+	// Сначала считываем записи, затем обновляем: чтение и запись в одном соединении не чередуются
+	var records []dto.TransactionRecord
+	if err := transactionRepo.ForEachRecord(0, func(record dto.TransactionRecord) error {
+		records = append(records, record)
+		return nil
+	}); err != nil {
+		return 0, ErrFailedToGet.Wrap(err, "failed to read ledger")
+	}
+
+	var prevHash string
+	for _, record := range records {
+		record.PrevHash = prevHash
+		record.Hash = utils.HashTransaction(record)
+		if err := transactionRepo.UpdateHash(record.ID, record.PrevHash, record.Hash); err != nil {
+			return 0, ErrFailedToUpdate.Wrap(err, "failed to seal transaction %d", record.ID)
+		}
+		prevHash = record.Hash
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, ErrFailedToUpdate.Wrap(err, "failed to commit transaction")
+	}
+	return len(records), nil
+}
+
+// publicKey возвращает открытый ключ подписи контрольных точек в hex.
+func (s *LedgerService) publicKey() string {
+	return hex.EncodeToString(s.signingKey.Public().(ed25519.PublicKey))
+}
+
+// appendRecord добавляет запись в журнал транзакций, включая её в цепочку хешей.
+//
+// Должна вызываться в транзакции БД вместе с изменением балансов: хеш последней записи
+// читается и новая запись вставляется атомарно.
+//
+// Аргументы:
+//   - transactionRepo: репозиторий журнала транзакций, работающий в транзакции БД.
+//   - record: запись; поля CreatedAt, PrevHash и Hash заполняются функцией.
+//
+// Возвращает:
+//   - ошибку, если не удалось прочитать последнюю запись или вставить новую.
+func appendRecord(transactionRepo storage.TransactionStorageInteractor, record dto.TransactionRecord) error {
+	head, err := transactionRepo.GetHead()
+	if err != nil {
+		return ErrFailedToGet.Wrap(err, "failed to get ledger head")
+	}
+
+	record.CreatedAt = time.Now().UTC()
+	record.PrevHash = head.Hash
+	record.Hash = utils.HashTransaction(record)
+
+	if err := transactionRepo.Insert(record); err != nil {
+		return ErrFailedToInsert.Wrap(err, "failed to insert transaction")
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/utils"
+	"github.com/shopspring/decimal"
+)
+
+// memoryLedger — журнал транзакций в памяти. Реализует методы репозитория, которые используют
+// appendRecord и LedgerService; вызов остальных методов приводит к панике.
+type memoryLedger struct {
+	storage.TransactionStorageInteractor
+	records []dto.TransactionRecord
+}
+
+// WithContext возвращает тот же журнал: контекст в памяти не используется.
+func (l *memoryLedger) WithContext(context.Context) storage.TransactionStorageInteractor {
+	return l
+}
+
+// Insert добавляет запись с очередным идентификатором.
+func (l *memoryLedger) Insert(record dto.TransactionRecord) error {
+	record.ID = int64(len(l.records) + 1)
+	l.records = append(l.records, record)
+	return nil
+}
+
+// GetHead возвращает последнюю запись журнала.
+func (l *memoryLedger) GetHead() (dto.LedgerHead, error) {
+	if len(l.records) == 0 {
+		return dto.LedgerHead{}, nil
+	}
+	last := l.records[len(l.records)-1]
+	return dto.LedgerHead{ID: last.ID, Hash: last.Hash, Count: int64(len(l.records))}, nil
+}
+
+// ForEachRecord обходит записи в порядке хранения.
+func (l *memoryLedger) ForEachRecord(afterID int64, fn func(record dto.TransactionRecord) error) error {
+	for _, record := range l.records {
+		if record.ID > afterID {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// memoryCheckpoints — хранилище контрольных точек в памяти.
+type memoryCheckpoints struct {
+	checkpoints []dto.Checkpoint
+}
+
+// WithContext возвращает то же хранилище: контекст в памяти не используется.
+func (c *memoryCheckpoints) WithContext(context.Context) storage.CheckpointStorageInteractor {
+	return c
+}
+
+// Insert сохраняет контрольную точку.
+func (c *memoryCheckpoints) Insert(checkpoint dto.Checkpoint) (int64, error) {
+	checkpoint.ID = int64(len(c.checkpoints) + 1)
+	c.checkpoints = append(c.checkpoints, checkpoint)
+	return checkpoint.ID, nil
+}
+
+// GetLastN возвращает последние n контрольных точек, начиная с самой новой.
+func (c *memoryCheckpoints) GetLastN(n int) ([]dto.Checkpoint, error) {
+	var last []dto.Checkpoint
+	for i := len(c.checkpoints) - 1; i >= 0 && len(last) < n; i-- {
+		last = append(last, c.checkpoints[i])
+	}
+	return last, nil
+}
+
+// GetAll возвращает все контрольные точки.
+func (c *memoryCheckpoints) GetAll() ([]dto.Checkpoint, error) {
+	return c.checkpoints, nil
+}
+
+// newTestLedger создаёт журнал из count переводов, включённых в цепочку хешей,
+// и сервис проверки с ключом подписи контрольных точек.
+func newTestLedger(t *testing.T, count int) (*LedgerService, *memoryLedger, *memoryCheckpoints) {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	ledger, checkpoints := &memoryLedger{}, &memoryCheckpoints{}
+	for i := 0; i < count; i++ {
+		from, _ := utils.DeriveWalletKey("ledger-test", i)
+		to, _ := utils.DeriveWalletKey("ledger-test", i+1)
+		if err := appendRecord(ledger, dto.TransactionRecord{
+			Kind:   dto.TransactionKindTransfer,
+			From:   from,
+			To:     to,
+			Amount: decimal.NewFromInt(int64(i + 1)),
+			Nonce:  1,
+		}); err != nil {
+			t.Fatalf("appendRecord: %v", err)
+		}
+	}
+	return NewLedgerService(nil, ledger, checkpoints, key), ledger, checkpoints
+}
+
+// verify проверяет журнал и возвращает результат.
+func verify(t *testing.T, service *LedgerService) dto.LedgerVerifyResp {
+	t.Helper()
+	resp, err := service.Verify(context.Background())
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	return resp
+}
+
+// expectBreak проверяет, что журнал признан нарушенным по причине reason на транзакции txID.
+func expectBreak(t *testing.T, resp dto.LedgerVerifyResp, reason string, txID int64) {
+	t.Helper()
+	if resp.Valid || resp.Break == nil {
+		t.Fatalf("ledger is valid, want break %s at transaction %d", reason, txID)
+	}
+	if resp.Break.Reason != reason || resp.Break.TransactionID != txID {
+		t.Errorf("break = %s at transaction %d, want %s at transaction %d",
+			resp.Break.Reason, resp.Break.TransactionID, reason, txID)
+	}
+}
+
+func TestVerifyIntactLedger(t *testing.T) {
+	service, ledger, _ := newTestLedger(t, 5)
+	if _, created, err := service.CreateCheckpoint(context.Background()); err != nil || !created {
+		t.Fatalf("CreateCheckpoint: created = %t, error = %v", created, err)
+	}
+
+	resp := verify(t, service)
+	if !resp.Valid || resp.Break != nil {
+		t.Fatalf("intact ledger reported broken: %+v", resp.Break)
+	}
+	if resp.Checked != 5 || resp.Checkpoints != 1 {
+		t.Errorf("checked %d records and %d checkpoints, want 5 and 1", resp.Checked, resp.Checkpoints)
+	}
+	if resp.HeadHash != ledger.records[4].Hash {
+		t.Errorf("head hash = %s, want %s", resp.HeadHash, ledger.records[4].Hash)
+	}
+}
+
+func TestVerifyDetectsTamperedRecord(t *testing.T) {
+	service, ledger, _ := newTestLedger(t, 5)
+	ledger.records[2].Amount = decimal.NewFromInt(1000)
+
+	expectBreak(t, verify(t, service), LedgerBreakHashMismatch, 3)
+}
+
+func TestVerifyDetectsRehashedRecord(t *testing.T) {
+	service, ledger, _ := newTestLedger(t, 5)
+
+	// Хеш изменённой записи пересчитан, но следующая запись ссылается на прежний
+	ledger.records[2].To = ledger.records[0].From
+	ledger.records[2].Hash = utils.HashTransaction(ledger.records[2])
+
+	expectBreak(t, verify(t, service), LedgerBreakPrevHashMismatch, 4)
+}
+
+func TestVerifyDetectsReorderedRecords(t *testing.T) {
+	service, ledger, _ := newTestLedger(t, 5)
+	ledger.records[1], ledger.records[2] = ledger.records[2], ledger.records[1]
+
+	expectBreak(t, verify(t, service), LedgerBreakPrevHashMismatch, 3)
+}
+
+func TestVerifyDetectsDeletedRecord(t *testing.T) {
+	service, ledger, _ := newTestLedger(t, 5)
+	ledger.records = append(ledger.records[:2], ledger.records[3:]...)
+
+	expectBreak(t, verify(t, service), LedgerBreakPrevHashMismatch, 4)
+}
+
+func TestVerifyDetectsDeletedTailWithCheckpoint(t *testing.T) {
+	service, ledger, _ := newTestLedger(t, 5)
+	if _, _, err := service.CreateCheckpoint(context.Background()); err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	// Без контрольной точки удаление последних записей не нарушает цепочку
+	ledger.records = ledger.records[:3]
+
+	expectBreak(t, verify(t, service), LedgerBreakCheckpointMismatch, 5)
+}
+
+func TestVerifyDetectsMissingHash(t *testing.T) {
+	service, ledger, _ := newTestLedger(t, 3)
+	ledger.records[1].Hash = ""
+
+	expectBreak(t, verify(t, service), LedgerBreakMissingHash, 2)
+}
+
+func TestVerifyRejectsForgedCheckpoint(t *testing.T) {
+	service, _, checkpoints := newTestLedger(t, 3)
+	checkpoint, _, err := service.CreateCheckpoint(context.Background())
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	// Изменённое содержимое не соответствует подписи
+	checkpoints.checkpoints[0].TxCount = 2
+	if resp := verify(t, service); resp.Valid || resp.Break == nil || resp.Break.Reason != LedgerBreakBadSignature {
+		t.Errorf("altered checkpoint: break = %+v, want %s", resp.Break, LedgerBreakBadSignature)
+	}
+
+	// Подпись корректна, но сделана не ключом сервера
+	_, forgerKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	forged := checkpoint
+	forged.PublicKey = hex.EncodeToString(forgerKey.Public().(ed25519.PublicKey))
+	forged.Signature = utils.SignCheckpoint(forgerKey, forged)
+	checkpoints.checkpoints[0] = forged
+	if resp := verify(t, service); resp.Valid || resp.Break == nil || resp.Break.Reason != LedgerBreakUnknownKey {
+		t.Errorf("checkpoint signed by another key: break = %+v, want %s", resp.Break, LedgerBreakUnknownKey)
+	}
+}
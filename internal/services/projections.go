@@ -0,0 +1,358 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
+	"log/slog"
+	"time"
+)
+
+// Projection — проекция журнала транзакций: состояние, которое строится последовательным
+// применением записей журнала и может быть в любой момент перестроено с нуля.
+//
+// Журнал транзакций — источник истины, поэтому новая проекция добавляется реализацией
+// этого интерфейса и регистрацией в DefaultProjections, без изменения сервисов, пишущих журнал.
+type Projection interface {
+	// Name возвращает уникальное имя проекции, под которым сохраняется её контрольная точка.
+	Name() string
+
+	// Reset приводит проекцию в начальное состояние перед воспроизведением журнала с начала.
+	Reset(executor storage.DBExecutor) error
+
+	// Apply применяет к проекции пакет записей журнала, упорядоченных по идентификатору.
+	Apply(executor storage.DBExecutor, records []dto.TransactionRecord) error
+}
+
+// InlineProjection — проекция, которую сервисы обновляют в той же транзакции БД, в которой
+// пишут журнал (например, балансы кошельков). Она всегда актуальна, поэтому инкрементально
+// не догоняется, а только перестраивается с нуля.
+type InlineProjection interface {
+	Projection
+
+	// Inline отмечает проекцию как обновляемую вместе с журналом.
+	Inline()
+}
+
+// ProjectionInteractor описывает интерфейс движка проекций журнала транзакций.
+type ProjectionInteractor interface {
+	// Run применяет к проекциям записи журнала, появившиеся после их контрольных точек.
+	Run(ctx context.Context) ([]dto.ProjectionStatus, error)
+
+	// Rebuild перестраивает проекцию с нуля воспроизведением всего журнала.
+	Rebuild(ctx context.Context, req dto.ProjectionRebuildReq) (dto.ProjectionRebuildResp, error)
+
+	// GetStatus возвращает состояние зарегистрированных проекций.
+	GetStatus(ctx context.Context) ([]dto.ProjectionStatus, error)
+
+	// GetDailyVolumes возвращает обороты за период из проекции daily_volumes.
+	GetDailyVolumes(ctx context.Context, req dto.DailyVolumesReq) ([]dto.DailyVolume, error)
+
+	// GetCounterparties возвращает самых частых контрагентов кошелька из проекции counterparty_stats.
+	GetCounterparties(ctx context.Context, req dto.CounterpartiesReq) ([]dto.CounterpartyStats, error)
+}
+
+// projectionBatchSize — количество записей журнала, применяемых к проекции за один вызов Apply.
+const projectionBatchSize = 500
+
+// errBatchFull прерывает чтение журнала, когда пакет записей заполнен.
+var errBatchFull = errors.New("projection batch is full")
+
+// ProjectionService реализует ProjectionInteractor, используя репозитории и базу данных.
+type ProjectionService struct {
+	db                    *sql.DB
+	admin                 *AdminService
+	projectionRepository  storage.ProjectionStorageInteractor
+	statsRepository       storage.StatsStorageInteractor
+	transactionRepository storage.TransactionStorageInteractor
+	projections           []Projection
+}
+
+// NewProjectionService создаёт новый экземпляр ProjectionService.
+//
+// Аргументы:
+//   - db: подключение к базе данных.
+//   - admin: административный сервис, через который выполняется перестроение проекций.
+//   - projectionRepository: репозиторий контрольных точек проекций.
+//   - statsRepository: репозиторий статистических проекций.
+//   - transactionRepository: репозиторий журнала транзакций.
+//   - projections: зарегистрированные проекции с уникальными именами.
+//
+// Возвращает:
+//   - Указатель на ProjectionService.
+func NewProjectionService(
+	db *sql.DB,
+	admin *AdminService,
+	projectionRepository storage.ProjectionStorageInteractor,
+	statsRepository storage.StatsStorageInteractor,
+	transactionRepository storage.TransactionStorageInteractor,
+	projections ...Projection,
+) *ProjectionService {
+	return &ProjectionService{
+		db:                    db,
+		admin:                 admin,
+		projectionRepository:  projectionRepository,
+		statsRepository:       statsRepository,
+		transactionRepository: transactionRepository,
+		projections:           projections,
+	}
+}
+
+// Run догоняет журнал: к каждой проекции применяются записи, появившиеся после её контрольной точки.
+//
+// Каждая проекция обновляется в отдельной транзакции БД вместе с контрольной точкой, поэтому
+// прерванный запуск не применяет записи повторно. Для проекций InlineProjection контрольная точка
+// просто переносится на последнюю запись журнала.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//
+// Возвращает:
+//   - состояние проекций после запуска и ошибку первой проекции, которую не удалось обновить.
+func (s *ProjectionService) Run(ctx context.Context) ([]dto.ProjectionStatus, error) {
+	for _, projection := range s.projections {
+		if err := s.runProjection(ctx, projection); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetStatus(ctx)
+}
+
+// runProjection применяет к проекции записи журнала после её контрольной точки.
+func (s *ProjectionService) runProjection(ctx context.Context, projection Projection) (err error) {
+	tx, err := storage.BeginWrite(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	// Гарантируем откат при любой ошибке, возвращённой после начала транзакции
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.ErrorContext(ctx, "rollback failed", "error", rbErr)
+			}
+		}
+	}()
+
+	projectionRepo := storage.NewProjectionRepository(tx)
+
+	checkpoint, err := projectionRepo.GetCheckpoint(projection.Name())
+	if err != nil {
+		return ErrFailedToGet.Wrap(err, "failed to get checkpoint of projection %s", projection.Name())
+	}
+
+	lastTxID := checkpoint
+	if _, inline := projection.(InlineProjection); inline {
+		head, err := storage.NewTransactionRepository(tx).GetHead()
+		if err != nil {
+			return ErrFailedToGet.Wrap(err, "failed to get ledger head")
+		}
+		lastTxID = head.ID
+	} else if lastTxID, _, err = replayProjection(tx, projection, checkpoint); err != nil {
+		return err
+	}
+
+	if lastTxID == checkpoint {
+		return tx.Commit()
+	}
+	if err := projectionRepo.SetCheckpoint(dto.ProjectionCheckpoint{
+		Name:      projection.Name(),
+		LastTxID:  lastTxID,
+		UpdatedAt: time.Now().UTC(),
+	}); err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to save checkpoint of projection %s", projection.Name())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to commit transaction")
+	}
+	return nil
+}
+
+// Rebuild перестраивает проекцию с нуля: сбрасывает её состояние и воспроизводит весь журнал.
+//
+// Перестроение выполняется в одной транзакции БД, поэтому другие клиенты не видят
+// частично построенную проекцию, а запись в журнал на это время приостанавливается.
+// Перестроение записывается в журнал административных действий.
+//
+// Аргументы:
+//   - ctx: контекст запроса с аутентифицированным клиентом, имеющим область доступа admin.
+//   - req: структура dto.ProjectionRebuildReq с именем проекции и основанием.
+//
+// Возвращает:
+//   - dto.ProjectionRebuildResp с количеством воспроизведённых записей.
+//   - ErrProjectionNotFound, если проекция не зарегистрирована, или ошибку прав, основания или БД.
+func (s *ProjectionService) Rebuild(ctx context.Context, req dto.ProjectionRebuildReq) (dto.ProjectionRebuildResp, error) {
+	projection, err := s.find(req.Name)
+	if err != nil {
+		return dto.ProjectionRebuildResp{}, err
+	}
+
+	resp := dto.ProjectionRebuildResp{Name: projection.Name()}
+	err = s.admin.execute(ctx, AuditActionRebuild, projection.Name(), req.Reason, &resp, func(tx adminTx, _ string) error {
+		if err := projection.Reset(tx.executor); err != nil {
+			return err
+		}
+
+		var err error
+		if resp.LastTxID, resp.Records, err = replayProjection(tx.executor, projection, 0); err != nil {
+			return err
+		}
+
+		if err := storage.NewProjectionRepository(tx.executor).SetCheckpoint(dto.ProjectionCheckpoint{
+			Name:      projection.Name(),
+			LastTxID:  resp.LastTxID,
+			UpdatedAt: time.Now().UTC(),
+		}); err != nil {
+			return ErrFailedToUpdate.Wrap(err, "failed to save checkpoint of projection %s", projection.Name())
+		}
+		return nil
+	})
+	if err != nil {
+		return dto.ProjectionRebuildResp{}, err
+	}
+	return resp, nil
+}
+
+// GetStatus возвращает состояние зарегистрированных проекций в порядке регистрации:
+// контрольную точку и отставание от последней записи журнала.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//
+// Возвращает:
+//   - срез состояний проекций и ошибку при сбое БД.
+func (s *ProjectionService) GetStatus(_ context.Context) ([]dto.ProjectionStatus, error) {
+	head, err := s.transactionRepository.GetHead()
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to get ledger head")
+	}
+	checkpoints, err := s.projectionRepository.GetCheckpoints()
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to get projection checkpoints")
+	}
+
+	byName := make(map[string]dto.ProjectionCheckpoint, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		byName[checkpoint.Name] = checkpoint
+	}
+
+	statuses := make([]dto.ProjectionStatus, 0, len(s.projections))
+	for _, projection := range s.projections {
+		_, inline := projection.(InlineProjection)
+		status := dto.ProjectionStatus{Name: projection.Name(), Inline: inline, Lag: head.ID}
+		if checkpoint, ok := byName[projection.Name()]; ok {
+			status.LastTxID = checkpoint.LastTxID
+			status.Lag = max(head.ID-checkpoint.LastTxID, 0)
+			status.UpdatedAt = &checkpoint.UpdatedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// GetDailyVolumes возвращает обороты по видам записей журнала за каждые сутки периода.
+//
+// Данные берутся из проекции daily_volumes и могут отставать от журнала до следующего запуска Run.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//   - req: структура dto.DailyVolumesReq с первым и последним днём периода.
+//
+// Возвращает:
+//   - срез оборотов и ErrInvalid при некорректном периоде или ошибку при сбое БД.
+func (s *ProjectionService) GetDailyVolumes(_ context.Context, req dto.DailyVolumesReq) ([]dto.DailyVolume, error) {
+	from, err := time.Parse(dayLayout, req.From)
+	if err != nil {
+		return nil, ErrInvalid.New("from must be a date in YYYY-MM-DD format")
+	}
+	to, err := time.Parse(dayLayout, req.To)
+	if err != nil {
+		return nil, ErrInvalid.New("to must be a date in YYYY-MM-DD format")
+	}
+	if to.Before(from) {
+		return nil, ErrInvalid.New("to must not be before from")
+	}
+
+	volumes, err := s.statsRepository.GetDailyVolumes(req.From, req.To)
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to get daily volumes")
+	}
+	return volumes, nil
+}
+
+// GetCounterparties возвращает не более req.Count контрагентов кошелька, упорядоченных
+// по убыванию количества переводов.
+//
+// Данные берутся из проекции counterparty_stats и могут отставать от журнала до следующего запуска Run.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//   - req: структура dto.CounterpartiesReq с адресом кошелька и количеством контрагентов.
+//
+// Возвращает:
+//   - срез статистики контрагентов и ErrInvalid при некорректном количестве или ошибку при сбое БД.
+func (s *ProjectionService) GetCounterparties(_ context.Context, req dto.CounterpartiesReq) ([]dto.CounterpartyStats, error) {
+	if req.Count <= 0 {
+		return nil, ErrInvalid.New("count must be positive")
+	}
+
+	counterparties, err := s.statsRepository.GetCounterparties(req.Address, req.Count)
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to get counterparties")
+	}
+	return counterparties, nil
+}
+
+// find возвращает зарегистрированную проекцию по имени.
+func (s *ProjectionService) find(name string) (Projection, error) {
+	for _, projection := range s.projections {
+		if projection.Name() == name {
+			return projection, nil
+		}
+	}
+	return nil, ErrProjectionNotFound.New("projection %q is not registered", name)
+}
+
+// replayProjection применяет к проекции записи журнала с идентификатором больше afterID
+// пакетами по projectionBatchSize.
+//
+// Пакет сначала считывается целиком и только затем применяется: чтение и запись в одном
+// соединении не чередуются.
+//
+// Возвращает:
+//   - идентификатор последней применённой записи (afterID, если новых записей нет),
+//     количество применённых записей и ошибку чтения журнала или применения.
+func replayProjection(executor storage.DBExecutor, projection Projection, afterID int64) (int64, int64, error) {
+	transactionRepo := storage.NewTransactionRepository(executor)
+
+	var applied int64
+	for {
+		batch := make([]dto.TransactionRecord, 0, projectionBatchSize)
+		err := transactionRepo.ForEachRecord(afterID, func(record dto.TransactionRecord) error {
+			batch = append(batch, record)
+			if len(batch) == projectionBatchSize {
+				return errBatchFull
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errBatchFull) {
+			return 0, 0, ErrFailedToGet.Wrap(err, "failed to read ledger")
+		}
+		if len(batch) == 0 {
+			return afterID, applied, nil
+		}
+
+		if err := projection.Apply(executor, batch); err != nil {
+			return 0, 0, err
+		}
+		afterID = batch[len(batch)-1].ID
+		applied += int64(len(batch))
+
+		if len(batch) < projectionBatchSize {
+			return afterID, applied, nil
+		}
+	}
+}
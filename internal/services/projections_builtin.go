@@ -0,0 +1,222 @@
+package services
+
+import (
+	"cmp"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
+	"github.com/shopspring/decimal"
+	"maps"
+	"slices"
+)
+
+// Имена встроенных проекций журнала транзакций.
+const (
+	ProjectionWallets        = "wallets"
+	ProjectionDailyVolumes   = "daily_volumes"
+	ProjectionCounterparties = "counterparty_stats"
+)
+
+// dayLayout — формат дня в проекции daily_volumes.
+const dayLayout = "2006-01-02"
+
+// DefaultProjections возвращает встроенные проекции журнала транзакций:
+//   - wallets — балансы и nonce кошельков (обновляется вместе с журналом, только перестраивается);
+//   - daily_volumes — обороты за сутки по видам записей;
+//   - counterparty_stats — статистика переводов между парами кошельков.
+func DefaultProjections() []Projection {
+	return []Projection{walletsProjection{}, dailyVolumesProjection{}, counterpartiesProjection{}}
+}
+
+// walletsProjection строит балансы и nonce кошельков из начальных балансов и журнала транзакций.
+//
+// Кошельки создаются вне журнала, поэтому перестроение не удаляет их, а возвращает каждому
+// начальный баланс и нулевой nonce и затем воспроизводит журнал.
+type walletsProjection struct{}
+
+// Name возвращает имя проекции.
+func (walletsProjection) Name() string { return ProjectionWallets }
+
+// Inline отмечает проекцию как обновляемую вместе с журналом: переводы и административные
+// операции меняют балансы в той же транзакции БД, в которой пишут журнал.
+func (walletsProjection) Inline() {}
+
+// Reset возвращает всем кошелькам начальные балансы и нулевой nonce.
+//
+// Возвращает:
+//   - ErrInvalid, если начальный баланс какого-либо кошелька неизвестен, или ошибку БД.
+func (walletsProjection) Reset(executor storage.DBExecutor) error {
+	walletRepo := storage.NewWalletRepository(executor)
+
+	states, err := walletRepo.GetStates()
+	if err != nil {
+		return ErrFailedToGet.Wrap(err, "failed to get wallets")
+	}
+	for _, state := range states {
+		if !state.InitialBalance.Valid {
+			return ErrInvalid.New("initial balance of wallet %s is unknown: the wallet predates initial balance tracking", state.Address)
+		}
+	}
+
+	for _, state := range states {
+		if err := walletRepo.UpdateBalance(dto.BalanceUpdateReq{
+			Address: state.Address,
+			Amount:  state.InitialBalance.Decimal,
+		}); err != nil {
+			return ErrFailedToUpdate.Wrap(err, "failed to reset balance of %s", state.Address)
+		}
+		if err := walletRepo.UpdateNonce(state.Address, 0); err != nil {
+			return ErrFailedToUpdate.Wrap(err, "failed to reset nonce of %s", state.Address)
+		}
+	}
+	return nil
+}
+
+// Apply списывает суммы записей с отправителей, зачисляет получателям и переносит nonce переводов.
+//
+// Возвращает:
+//   - ошибку БД, в том числе ErrWalletNotFound, если запись журнала ссылается на неизвестный кошелёк.
+func (walletsProjection) Apply(executor storage.DBExecutor, records []dto.TransactionRecord) error {
+	walletRepo := storage.NewWalletRepository(executor)
+
+	deltas := make(map[string]decimal.Decimal)
+	nonces := make(map[string]uint64)
+	for _, record := range records {
+		if record.From != "" {
+			deltas[record.From] = deltas[record.From].Sub(record.Amount)
+			if record.Kind == dto.TransactionKindTransfer {
+				nonces[record.From] = max(nonces[record.From], record.Nonce)
+			}
+		}
+		if record.To != "" {
+			deltas[record.To] = deltas[record.To].Add(record.Amount)
+		}
+	}
+
+	for _, address := range slices.Sorted(maps.Keys(deltas)) {
+		balance, err := walletRepo.GetBalance(dto.BalanceReq{Address: address})
+		if err != nil {
+			return ErrFailedToGet.Wrap(err, "failed to get balance of %s", address)
+		}
+		if err := walletRepo.UpdateBalance(dto.BalanceUpdateReq{
+			Address: address,
+			Amount:  balance.Amount.Add(deltas[address]),
+		}); err != nil {
+			return ErrFailedToUpdate.Wrap(err, "failed to update balance of %s", address)
+		}
+	}
+
+	for _, address := range slices.Sorted(maps.Keys(nonces)) {
+		nonce, err := walletRepo.GetNonce(dto.BalanceReq{Address: address})
+		if err != nil {
+			return ErrFailedToGet.Wrap(err, "failed to get nonce of %s", address)
+		}
+		if nonces[address] <= nonce.Nonce {
+			continue
+		}
+		if err := walletRepo.UpdateNonce(address, nonces[address]); err != nil {
+			return ErrFailedToUpdate.Wrap(err, "failed to update nonce of %s", address)
+		}
+	}
+	return nil
+}
+
+// dailyVolumesProjection считает количество и сумму записей журнала за сутки (UTC) по видам записей.
+type dailyVolumesProjection struct{}
+
+// Name возвращает имя проекции.
+func (dailyVolumesProjection) Name() string { return ProjectionDailyVolumes }
+
+// Reset удаляет все обороты.
+func (dailyVolumesProjection) Reset(executor storage.DBExecutor) error {
+	if err := storage.NewStatsRepository(executor).ClearDailyVolumes(); err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to clear daily volumes")
+	}
+	return nil
+}
+
+// Apply добавляет записи к оборотам дня их создания.
+func (dailyVolumesProjection) Apply(executor storage.DBExecutor, records []dto.TransactionRecord) error {
+	statsRepo := storage.NewStatsRepository(executor)
+
+	type dayKind struct{ day, kind string }
+	totals := make(map[dayKind]dto.DailyVolume)
+	for _, record := range records {
+		key := dayKind{day: record.CreatedAt.UTC().Format(dayLayout), kind: record.Kind}
+		total := totals[key]
+		total.Count++
+		total.Volume = total.Volume.Add(record.Amount)
+		totals[key] = total
+	}
+
+	keys := slices.SortedFunc(maps.Keys(totals), func(a, b dayKind) int {
+		return cmp.Or(cmp.Compare(a.day, b.day), cmp.Compare(a.kind, b.kind))
+	})
+	for _, key := range keys {
+		volume, err := statsRepo.GetDailyVolume(key.day, key.kind)
+		if err != nil {
+			return ErrFailedToGet.Wrap(err, "failed to get daily volume")
+		}
+		volume.Count += totals[key].Count
+		volume.Volume = volume.Volume.Add(totals[key].Volume)
+		if err := statsRepo.SaveDailyVolume(volume); err != nil {
+			return ErrFailedToUpdate.Wrap(err, "failed to save daily volume")
+		}
+	}
+	return nil
+}
+
+// counterpartiesProjection считает переводы между парами кошельков: для каждого кошелька —
+// количество и сумму переводов каждому контрагенту и от него. Эмиссия и корректировки не учитываются.
+type counterpartiesProjection struct{}
+
+// Name возвращает имя проекции.
+func (counterpartiesProjection) Name() string { return ProjectionCounterparties }
+
+// Reset удаляет статистику всех контрагентов.
+func (counterpartiesProjection) Reset(executor storage.DBExecutor) error {
+	if err := storage.NewStatsRepository(executor).ClearCounterparties(); err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to clear counterparty stats")
+	}
+	return nil
+}
+
+// Apply добавляет переводы к статистике отправителя и получателя.
+func (counterpartiesProjection) Apply(executor storage.DBExecutor, records []dto.TransactionRecord) error {
+	statsRepo := storage.NewStatsRepository(executor)
+
+	type pair struct{ address, counterparty string }
+	totals := make(map[pair]dto.CounterpartyStats)
+	for _, record := range records {
+		if record.Kind != dto.TransactionKindTransfer || record.From == "" || record.To == "" {
+			continue
+		}
+
+		sent := totals[pair{record.From, record.To}]
+		sent.SentCount++
+		sent.SentVolume = sent.SentVolume.Add(record.Amount)
+		totals[pair{record.From, record.To}] = sent
+
+		received := totals[pair{record.To, record.From}]
+		received.ReceivedCount++
+		received.ReceivedVolume = received.ReceivedVolume.Add(record.Amount)
+		totals[pair{record.To, record.From}] = received
+	}
+
+	keys := slices.SortedFunc(maps.Keys(totals), func(a, b pair) int {
+		return cmp.Or(cmp.Compare(a.address, b.address), cmp.Compare(a.counterparty, b.counterparty))
+	})
+	for _, key := range keys {
+		stats, err := statsRepo.GetCounterparty(key.address, key.counterparty)
+		if err != nil {
+			return ErrFailedToGet.Wrap(err, "failed to get counterparty stats")
+		}
+		stats.SentCount += totals[key].SentCount
+		stats.SentVolume = stats.SentVolume.Add(totals[key].SentVolume)
+		stats.ReceivedCount += totals[key].ReceivedCount
+		stats.ReceivedVolume = stats.ReceivedVolume.Add(totals[key].ReceivedVolume)
+		if err := statsRepo.SaveCounterparty(key.address, stats); err != nil {
+			return ErrFailedToUpdate.Wrap(err, "failed to save counterparty stats")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/joomcode/errorx"
+	"github.com/shopspring/decimal"
+)
+
+// newProjectionHistory создаёт кошельки a и b и четыре записи журнала: переводы 10 с a на b,
+// 4 с b на a, эмиссию 6 на b и перевод 3 с a на b. Итоговые балансы — 91 и 65, nonce — 2 и 1.
+// Проекции догоняют журнал, поэтому перед возвратом они актуальны.
+func newProjectionHistory(t *testing.T) (*Service, *sql.DB, []testWallet) {
+	t.Helper()
+	service, db, wallets := newTestService(t, "100", "50")
+	a, b := wallets[0], wallets[1]
+
+	send(t, service, a, b.address, "10")
+	send(t, service, b, a.address, "4")
+	if _, err := service.AdminService.Mint(adminContext(), dto.MintReq{
+		Address: b.address, Amount: decimal.NewFromInt(6), Reason: "bonus",
+	}); err != nil {
+		t.Fatalf("Mint: %v", err)
+	}
+	send(t, service, a, b.address, "3")
+
+	if _, err := service.ProjectionService.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	return service, db, wallets
+}
+
+// execSQL выполняет запрос в обход сервисов.
+func execSQL(t *testing.T, db *sql.DB, query string, args ...any) {
+	t.Helper()
+	if _, err := db.Exec(query, args...); err != nil {
+		t.Fatalf("failed to execute %q: %v", query, err)
+	}
+}
+
+// expectNonce проверяет последний использованный nonce кошелька.
+func expectNonce(t *testing.T, service *Service, address string, want uint64) {
+	t.Helper()
+	nonce, err := service.TransferService.GetNonce(context.Background(), dto.BalanceReq{Address: address})
+	if err != nil {
+		t.Fatalf("GetNonce: %v", err)
+	}
+	if nonce.Nonce != want {
+		t.Errorf("nonce of %.8s = %d, want %d", address, nonce.Nonce, want)
+	}
+}
+
+func TestProjectionRebuild(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(t *testing.T, db *sql.DB)
+		check  func(t *testing.T, service *Service, db *sql.DB, wallets []testWallet)
+	}{
+		{
+			name: ProjectionWallets,
+			tamper: func(t *testing.T, db *sql.DB) {
+				execSQL(t, db, "UPDATE wallets SET balance = '0', nonce = 0")
+			},
+			check: func(t *testing.T, service *Service, _ *sql.DB, wallets []testWallet) {
+				expectBalance(t, service, wallets[0].address, "91")
+				expectBalance(t, service, wallets[1].address, "65")
+				expectNonce(t, service, wallets[0].address, 2)
+				expectNonce(t, service, wallets[1].address, 1)
+			},
+		},
+		{
+			name: ProjectionDailyVolumes,
+			tamper: func(t *testing.T, db *sql.DB) {
+				execSQL(t, db, "UPDATE daily_volumes SET count = count * 10")
+			},
+			check: func(t *testing.T, service *Service, db *sql.DB, _ []testWallet) {
+				records := ledgerRecords(t, db)
+				volumes, err := service.ProjectionService.GetDailyVolumes(context.Background(), dto.DailyVolumesReq{
+					From: records[0].CreatedAt.UTC().Format(dayLayout),
+					To:   records[len(records)-1].CreatedAt.UTC().Format(dayLayout),
+				})
+				if err != nil {
+					t.Fatalf("GetDailyVolumes: %v", err)
+				}
+
+				// Записи могли попасть в разные сутки, поэтому сравниваются итоги периода по видам
+				counts := make(map[string]int64)
+				totals := make(map[string]decimal.Decimal)
+				for _, volume := range volumes {
+					counts[volume.Kind] += volume.Count
+					totals[volume.Kind] = totals[volume.Kind].Add(volume.Volume)
+				}
+				if counts[dto.TransactionKindTransfer] != 3 || !totals[dto.TransactionKindTransfer].Equal(decimal.NewFromInt(17)) {
+					t.Errorf("transfers: %d for %s, want 3 for 17", counts[dto.TransactionKindTransfer], totals[dto.TransactionKindTransfer])
+				}
+				if counts[dto.TransactionKindMint] != 1 || !totals[dto.TransactionKindMint].Equal(decimal.NewFromInt(6)) {
+					t.Errorf("mints: %d for %s, want 1 for 6", counts[dto.TransactionKindMint], totals[dto.TransactionKindMint])
+				}
+			},
+		},
+		{
+			name: ProjectionCounterparties,
+			tamper: func(t *testing.T, db *sql.DB) {
+				execSQL(t, db, "DELETE FROM counterparty_stats")
+			},
+			check: func(t *testing.T, service *Service, _ *sql.DB, wallets []testWallet) {
+				stats, err := service.ProjectionService.GetCounterparties(context.Background(), dto.CounterpartiesReq{
+					Address: wallets[0].address, Count: 10,
+				})
+				if err != nil {
+					t.Fatalf("GetCounterparties: %v", err)
+				}
+				if len(stats) != 1 {
+					t.Fatalf("got %d counterparties, want 1", len(stats))
+				}
+				got := stats[0]
+				if got.Counterparty != wallets[1].address || got.SentCount != 2 || !got.SentVolume.Equal(decimal.NewFromInt(13)) ||
+					got.ReceivedCount != 1 || !got.ReceivedVolume.Equal(decimal.NewFromInt(4)) {
+					t.Errorf("counterparty stats = %+v, want 2 sent for 13 and 1 received for 4", got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, db, wallets := newProjectionHistory(t)
+			tt.tamper(t, db)
+
+			resp, err := service.ProjectionService.Rebuild(adminContext(), dto.ProjectionRebuildReq{
+				Name: tt.name, Reason: "restore after incident",
+			})
+			if err != nil {
+				t.Fatalf("Rebuild: %v", err)
+			}
+			if resp.Name != tt.name || resp.Records != 4 || resp.LastTxID != 4 {
+				t.Errorf("rebuild = %+v, want 4 records up to transaction 4", resp)
+			}
+			tt.check(t, service, db, wallets)
+
+			statuses, err := service.ProjectionService.GetStatus(context.Background())
+			if err != nil {
+				t.Fatalf("GetStatus: %v", err)
+			}
+			for _, status := range statuses {
+				if status.Name == tt.name && (status.LastTxID != 4 || status.Lag != 0) {
+					t.Errorf("status after rebuild = %+v, want checkpoint at transaction 4 without lag", status)
+				}
+			}
+
+			// Перестроение не дополняет журнал и записывается в журнал административных действий
+			if records := ledgerRecords(t, db); len(records) != 4 {
+				t.Errorf("rebuild changed ledger to %d records", len(records))
+			}
+			entries := auditLog(t, service)
+			if entries[0].Action != AuditActionRebuild || entries[0].Target != tt.name {
+				t.Errorf("last audit entry = %s %s, want %s %s", entries[0].Action, entries[0].Target, AuditActionRebuild, tt.name)
+			}
+		})
+	}
+}
+
+func TestProjectionRebuildRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, db *sql.DB, wallets []testWallet)
+		ctx     context.Context
+		req     dto.ProjectionRebuildReq
+		wantErr *errorx.Type
+	}{
+		{
+			name:    "unknown projection",
+			ctx:     adminContext(),
+			req:     dto.ProjectionRebuildReq{Name: "balances", Reason: "restore"},
+			wantErr: ErrProjectionNotFound,
+		},
+		{
+			name:    "without reason",
+			ctx:     adminContext(),
+			req:     dto.ProjectionRebuildReq{Name: ProjectionWallets},
+			wantErr: ErrInvalid,
+		},
+		{
+			name:    "without admin scope",
+			ctx:     clientContext(),
+			req:     dto.ProjectionRebuildReq{Name: ProjectionWallets, Reason: "restore"},
+			wantErr: ErrForbidden,
+		},
+		{
+			name: "wallet without initial balance",
+			prepare: func(t *testing.T, db *sql.DB, wallets []testWallet) {
+				execSQL(t, db, "UPDATE wallets SET initial_balance = NULL WHERE address = ?", wallets[1].address)
+			},
+			ctx:     adminContext(),
+			req:     dto.ProjectionRebuildReq{Name: ProjectionWallets, Reason: "restore"},
+			wantErr: ErrInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, db, wallets := newProjectionHistory(t)
+			if tt.prepare != nil {
+				tt.prepare(t, db, wallets)
+			}
+			before := len(auditLog(t, service))
+
+			if _, err := service.ProjectionService.Rebuild(tt.ctx, tt.req); !errorx.IsOfType(err, tt.wantErr) {
+				t.Fatalf("error %v, want %s", err, tt.wantErr)
+			}
+			expectBalance(t, service, wallets[0].address, "91")
+			expectBalance(t, service, wallets[1].address, "65")
+			if after := len(auditLog(t, service)); after != before {
+				t.Errorf("rejected rebuild wrote %d audit entries", after-before)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
+	"github.com/shopspring/decimal"
+	"log/slog"
+	"slices"
+)
+
+// ReconcileInteractor описывает интерфейс сверки балансов кошельков с журналом транзакций.
+type ReconcileInteractor interface {
+	// Reconcile сверяет балансы кошельков с журналом транзакций и при необходимости исправляет расхождения.
+	Reconcile(ctx context.Context, req dto.ReconcileReq) (dto.ReconcileReport, error)
+}
+
+// ReconcileService реализует ReconcileInteractor, используя базу данных и административный сервис
+// для исправлений с записью в журнал административных действий.
+type ReconcileService struct {
+	db    *sql.DB
+	admin *AdminService
+}
+
+// NewReconcileService создаёт новый экземпляр ReconcileService.
+//
+// Аргументы:
+//   - db: подключение к базе данных.
+//   - admin: административный сервис, через который выполняются исправления.
+//
+// Возвращает:
+//   - Указатель на ReconcileService.
+func NewReconcileService(db *sql.DB, admin *AdminService) *ReconcileService {
+	return &ReconcileService{db: db, admin: admin}
+}
+
+// Reconcile воспроизводит журнал транзакций от начальных балансов кошельков и сравнивает
+// результат с сохранёнными балансами, а также проверяет сохранение общей суммы средств:
+// сумма балансов должна равняться сумме начальных балансов с учётом эмиссии и корректировок.
+//
+// Балансы кошельков без начального баланса проверить нельзя: они перечисляются в отчёте (Unseeded),
+// не входят в суммы средств, и сверка не считается успешной.
+//
+// При req.Repair каждое расхождение исправляется корректировкой (kind = adjustment) в журнале
+// транзакций на разницу между сохранённым и вычисленным балансом: сохранённые балансы не меняются,
+// а история становится согласованной с ними. Сверка и исправления выполняются в одной транзакции БД
+// и записываются в журнал административных действий вместе со списком расхождений.
+//
+// Аргументы:
+//   - ctx: контекст запроса с аутентифицированным клиентом, имеющим область доступа admin.
+//   - req: структура dto.ReconcileReq; основание обязательно при исправлении.
+//
+// Возвращает:
+//   - dto.ReconcileReport с найденными (до исправления) расхождениями.
+//   - ошибку при отсутствии прав, основания или при сбое БД.
+func (s *ReconcileService) Reconcile(ctx context.Context, req dto.ReconcileReq) (dto.ReconcileReport, error) {
+	if req.Repair {
+		return s.repair(ctx, req.Reason)
+	}
+
+	if _, err := authorizeAdmin(ctx); err != nil {
+		return dto.ReconcileReport{}, err
+	}
+
+	// Балансы и журнал должны быть согласованы, поэтому читаем их в транзакции БД
+	tx, err := s.db.Begin()
+	if err != nil {
+		return dto.ReconcileReport{}, err
+	}
+
+	// Сверка только читает данные, поэтому транзакция всегда откатывается
+	defer func() {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			slog.ErrorContext(ctx, "rollback failed", "error", rbErr)
+		}
+	}()
+
+	return replayLedger(storage.NewWalletRepository(tx), storage.NewTransactionRepository(tx))
+}
+
+// repair выполняет сверку и исправляет расхождения корректировками в журнале транзакций.
+func (s *ReconcileService) repair(ctx context.Context, reason string) (dto.ReconcileReport, error) {
+	var report dto.ReconcileReport
+	details := struct {
+		Mismatches []dto.ReconcileMismatch `json:"mismatches"`
+	}{}
+
+	err := s.admin.execute(ctx, AuditActionReconcile, "", reason, &details, func(tx adminTx, actor string) error {
+		var err error
+		if report, err = replayLedger(tx.wallets, tx.transactions); err != nil {
+			return err
+		}
+
+		for _, mismatch := range report.Mismatches {
+			record := dto.TransactionRecord{
+				Kind:        dto.TransactionKindAdjustment,
+				Amount:      mismatch.Difference.Abs(),
+				InitiatedBy: actor,
+			}
+			if mismatch.Difference.IsPositive() {
+				record.To = mismatch.Address
+			} else {
+				record.From = mismatch.Address
+			}
+			if err := appendRecord(tx.transactions, record); err != nil {
+				return err
+			}
+		}
+
+		details.Mismatches = report.Mismatches
+		report.Repaired = true
+		return nil
+	})
+	if err != nil {
+		return dto.ReconcileReport{}, err
+	}
+	return report, nil
+}
+
+// replayLedger воспроизводит журнал транзакций от начальных балансов кошельков и составляет отчёт сверки.
+//
+// Каждая запись списывает сумму с отправителя и зачисляет получателю; запись без отправителя
+// (эмиссия, зачисление корректировкой) увеличивает общую сумму средств, без получателя — уменьшает.
+// Кошельки без начального баланса не сравниваются с журналом, не входят в расхождения и в суммы
+// начальных и сохранённых балансов, поэтому исправление их не затрагивает.
+//
+// Возвращает:
+//   - dto.ReconcileReport и ошибку при сбое БД.
+func replayLedger(
+	walletRepo storage.WalletStorageInteractor,
+	transactionRepo storage.TransactionStorageInteractor,
+) (dto.ReconcileReport, error) {
+	states, err := walletRepo.GetStates()
+	if err != nil {
+		return dto.ReconcileReport{}, ErrFailedToGet.Wrap(err, "failed to get wallets")
+	}
+
+	report := dto.ReconcileReport{
+		Wallets:        int64(len(states)),
+		InitialSupply:  decimal.Zero,
+		NetIssuance:    decimal.Zero,
+		ActualSupply:   decimal.Zero,
+		Mismatches:     make([]dto.ReconcileMismatch, 0),
+		UnknownWallets: make([]string, 0),
+	}
+	expected := make(map[string]decimal.Decimal, len(states))
+	for _, state := range states {
+		expected[state.Address] = state.InitialBalance.Decimal
+		if !state.InitialBalance.Valid {
+			report.Unseeded = append(report.Unseeded, state.Address)
+			continue
+		}
+		report.InitialSupply = report.InitialSupply.Add(state.InitialBalance.Decimal)
+		report.ActualSupply = report.ActualSupply.Add(state.Balance)
+	}
+
+	apply := func(address string, delta decimal.Decimal) {
+		balance, ok := expected[address]
+		if !ok && !slices.Contains(report.UnknownWallets, address) {
+			report.UnknownWallets = append(report.UnknownWallets, address)
+		}
+		expected[address] = balance.Add(delta)
+	}
+
+	if err := transactionRepo.ForEachRecord(0, func(record dto.TransactionRecord) error {
+		report.Transactions++
+		if record.From == "" {
+			report.NetIssuance = report.NetIssuance.Add(record.Amount)
+		} else {
+			apply(record.From, record.Amount.Neg())
+		}
+		if record.To == "" {
+			report.NetIssuance = report.NetIssuance.Sub(record.Amount)
+		} else {
+			apply(record.To, record.Amount)
+		}
+		return nil
+	}); err != nil {
+		return dto.ReconcileReport{}, ErrFailedToGet.Wrap(err, "failed to read ledger")
+	}
+
+	for _, state := range states {
+		if !state.InitialBalance.Valid {
+			continue
+		}
+		if balance := expected[state.Address]; !balance.Equal(state.Balance) {
+			report.Mismatches = append(report.Mismatches, dto.ReconcileMismatch{
+				Address:    state.Address,
+				Stored:     state.Balance,
+				Expected:   balance,
+				Difference: state.Balance.Sub(balance),
+			})
+		}
+	}
+
+	report.ExpectedSupply = report.InitialSupply.Add(report.NetIssuance)
+	report.SupplyConserved = report.ActualSupply.Equal(report.ExpectedSupply)
+	report.Consistent = report.SupplyConserved && len(report.Mismatches) == 0 &&
+		len(report.UnknownWallets) == 0 && len(report.Unseeded) == 0
+	slices.Sort(report.UnknownWallets)
+	return report, nil
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/joomcode/errorx"
+	"github.com/shopspring/decimal"
+)
+
+// setBalance изменяет сохранённый баланс кошелька в обход сервисов.
+func setBalance(t *testing.T, db *sql.DB, address, balance string) {
+	t.Helper()
+	if _, err := db.Exec("UPDATE wallets SET balance = ? WHERE address = ?", balance, address); err != nil {
+		t.Fatalf("failed to set balance: %v", err)
+	}
+}
+
+// reconcile выполняет сверку и возвращает отчёт.
+func reconcile(t *testing.T, service *Service, req dto.ReconcileReq) dto.ReconcileReport {
+	t.Helper()
+	report, err := service.ReconcileService.Reconcile(adminContext(), req)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	return report
+}
+
+func TestReconcileDriftAndRepair(t *testing.T) {
+	// После перевода 10 с кошелька a на кошелёк b их балансы равны 90 и 60
+	tests := []struct {
+		name            string
+		tamper          func(t *testing.T, db *sql.DB, a, b string)
+		differences     map[int]string
+		supplyConserved bool
+		unseeded        int
+	}{
+		{
+			name:            "consistent",
+			tamper:          func(*testing.T, *sql.DB, string, string) {},
+			supplyConserved: true,
+		},
+		{
+			name:        "inflated balance",
+			tamper:      func(t *testing.T, db *sql.DB, a, _ string) { setBalance(t, db, a, "100") },
+			differences: map[int]string{0: "10"},
+		},
+		{
+			name:        "deflated balance",
+			tamper:      func(t *testing.T, db *sql.DB, _, b string) { setBalance(t, db, b, "55.5") },
+			differences: map[int]string{1: "-4.5"},
+		},
+		{
+			name: "drift between wallets",
+			tamper: func(t *testing.T, db *sql.DB, a, b string) {
+				setBalance(t, db, a, "80")
+				setBalance(t, db, b, "70")
+			},
+			differences:     map[int]string{0: "-10", 1: "10"},
+			supplyConserved: true,
+		},
+		{
+			// Кошелёк b не входит в суммы средств, поэтому переведённые на него 10 нарушают их равенство
+			name: "unseeded wallet",
+			tamper: func(t *testing.T, db *sql.DB, _, b string) {
+				if _, err := db.Exec("UPDATE wallets SET initial_balance = NULL WHERE address = ?", b); err != nil {
+					t.Fatalf("failed to clear initial balance: %v", err)
+				}
+			},
+			unseeded: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, db, wallets := newTestService(t, "100", "50")
+			send(t, service, wallets[0], wallets[1].address, "10")
+			tt.tamper(t, db, wallets[0].address, wallets[1].address)
+
+			report := reconcile(t, service, dto.ReconcileReq{})
+			consistent := len(tt.differences) == 0 && tt.unseeded == 0
+			if report.Consistent != consistent || report.SupplyConserved != tt.supplyConserved || report.Repaired {
+				t.Errorf("consistent = %t, supply conserved = %t, repaired = %t, want %t, %t and false",
+					report.Consistent, report.SupplyConserved, report.Repaired, consistent, tt.supplyConserved)
+			}
+			if report.Transactions != 1 || len(report.Unseeded) != tt.unseeded {
+				t.Errorf("checked %d transactions with %d unseeded wallets, want 1 and %d",
+					report.Transactions, len(report.Unseeded), tt.unseeded)
+			}
+			if len(report.Mismatches) != len(tt.differences) {
+				t.Fatalf("got mismatches %+v, want differences %v", report.Mismatches, tt.differences)
+			}
+			for _, mismatch := range report.Mismatches {
+				index := 0
+				if mismatch.Address == wallets[1].address {
+					index = 1
+				}
+				want, ok := tt.differences[index]
+				if !ok || !mismatch.Difference.Equal(decimal.RequireFromString(want)) {
+					t.Errorf("wallet %c differs by %s, want %s", 'a'+index, mismatch.Difference, want)
+				}
+			}
+			if records := ledgerRecords(t, db); len(records) != 1 {
+				t.Errorf("reconcile without repair appended %d ledger records", len(records)-1)
+			}
+
+			stored := make([]decimal.Decimal, len(wallets))
+			for i, wallet := range wallets {
+				balance, err := service.TransferService.GetBalance(context.Background(), dto.BalanceReq{Address: wallet.address})
+				if err != nil {
+					t.Fatalf("GetBalance: %v", err)
+				}
+				stored[i] = balance.Amount
+			}
+
+			repaired := reconcile(t, service, dto.ReconcileReq{Repair: true, Reason: "monthly audit"})
+			if !repaired.Repaired || len(repaired.Mismatches) != len(tt.differences) {
+				t.Errorf("repair: repaired = %t with %d mismatches, want true with %d",
+					repaired.Repaired, len(repaired.Mismatches), len(tt.differences))
+			}
+
+			// Исправление дополняет журнал корректировками и не меняет сохранённые балансы
+			records := ledgerRecords(t, db)
+			if len(records) != 1+len(tt.differences) {
+				t.Fatalf("got %d ledger records after repair, want %d", len(records), 1+len(tt.differences))
+			}
+			for _, record := range records[1:] {
+				if record.Kind != dto.TransactionKindAdjustment || record.InitiatedBy != "api_key:admin" {
+					t.Errorf("repair record = %s by %q, want adjustment by api_key:admin", record.Kind, record.InitiatedBy)
+				}
+			}
+			for i, wallet := range wallets {
+				expectBalance(t, service, wallet.address, stored[i].String())
+			}
+
+			entries := auditLog(t, service)
+			if len(entries) != 1 || entries[0].Action != AuditActionReconcile || entries[0].Reason != "monthly audit" {
+				t.Fatalf("audit log = %+v, want a single reconcile entry", entries)
+			}
+			if strings.Count(entries[0].Details, `"address"`) != len(tt.differences) {
+				t.Errorf("audit details %s do not list %d mismatches", entries[0].Details, len(tt.differences))
+			}
+
+			after := reconcile(t, service, dto.ReconcileReq{})
+			if len(after.Mismatches) != 0 || after.SupplyConserved != (tt.unseeded == 0) || after.Consistent != (tt.unseeded == 0) {
+				t.Errorf("after repair: mismatches %+v, supply conserved = %t, consistent = %t",
+					after.Mismatches, after.SupplyConserved, after.Consistent)
+			}
+		})
+	}
+}
+
+func TestReconcileRepairRequiresReason(t *testing.T) {
+	service, db, wallets := newTestService(t, "100")
+	setBalance(t, db, wallets[0].address, "150")
+
+	if _, err := service.ReconcileService.Reconcile(adminContext(), dto.ReconcileReq{Repair: true}); !errorx.IsOfType(err, ErrInvalid) {
+		t.Errorf("repair without reason: error %v, want %s", err, ErrInvalid)
+	}
+	if _, err := service.ReconcileService.Reconcile(clientContext(), dto.ReconcileReq{}); !errorx.IsOfType(err, ErrForbidden) {
+		t.Errorf("reconcile without admin scope: error %v, want %s", err, ErrForbidden)
+	}
+	if records := ledgerRecords(t, db); len(records) != 0 {
+		t.Errorf("rejected repair appended %d ledger records", len(records))
+	}
+}
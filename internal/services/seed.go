@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/auth"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/utils"
+)
+
+// GenerateWallets создаёт кошельки для начального заполнения базы.
+//
+// Адрес каждого кошелька — открытый ключ Ed25519; закрытые ключи в базе не сохраняются
+// и возвращаются вызывающей стороне, чтобы передать их владельцам. Если задан req.KeySeed,
+// ключи и балансы выводятся из него детерминированно (см. utils.DeriveWalletKey), и одна и та же
+// строка в пустой базе всегда даёт одни и те же кошельки.
+//
+// Аргументы:
+//   - ctx: контекст запроса; аутентифицированный клиент из него записывается в эмиссии для аудита.
+//   - req: структура dto.SeedReq с количеством кошельков (0 — не создавать), средним балансом и его распределением.
+//
+// Возвращает:
+//   - Ключевые пары созданных кошельков (пустой срез, если req.OnlyIfEmpty и кошельки уже существуют).
+//   - Ошибку при некорректных параметрах, сбое генерации ключей или записи в базу.
+func (s *TransferService) GenerateWallets(ctx context.Context, req dto.SeedReq) ([]dto.WalletKey, error) {
+	if req.Count == 0 {
+		return nil, nil
+	}
+	if req.Count < 0 || req.Balance.IsNegative() {
+		return nil, ErrInvalid.New("count must be positive and balance must not be negative")
+	}
+	balance, err := seedBalances(req)
+	if err != nil {
+		return nil, err
+	}
+
+	wallets := make([]dto.SeedWallet, req.Count)
+	keys := make([]dto.WalletKey, req.Count)
+	for i := range wallets {
+		var address, privateKey string
+		if req.KeySeed != "" {
+			address, privateKey = utils.DeriveWalletKey(req.KeySeed, i)
+		} else if address, privateKey, err = utils.GenerateWalletKey(); err != nil {
+			return nil, ErrFailedToGenerate.Wrap(err, "failed to generate wallet key")
+		}
+		wallets[i] = dto.SeedWallet{Address: address, Balance: balance()}
+		keys[i] = dto.WalletKey{Address: address, PrivateKey: privateKey}
+	}
+
+	created, err := s.seedWallets(ctx, wallets, req.OnlyIfEmpty)
+	if err != nil || !created {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// ImportWallets создаёт кошельки с начальными балансами из фикстуры в одной транзакции БД:
+// при любой ошибке не создаётся ни один кошелёк.
+//
+// Аргументы:
+//   - ctx: контекст запроса; аутентифицированный клиент из него записывается в эмиссии для аудита.
+//   - req: структура dto.ImportWalletsReq с кошельками фикстуры.
+//
+// Возвращает:
+//   - Количество созданных кошельков (0, если req.OnlyIfEmpty и кошельки уже существуют).
+//   - Ошибку при некорректной фикстуре, существующем кошельке или сбое записи в базу.
+func (s *TransferService) ImportWallets(ctx context.Context, req dto.ImportWalletsReq) (int, error) {
+	wallets := make([]dto.SeedWallet, len(req.Wallets))
+	seen := make(map[string]int, len(req.Wallets))
+	for i, wallet := range req.Wallets {
+		wallet.Address = strings.ToLower(strings.TrimSpace(wallet.Address))
+		if wallet.PrivateKey != "" {
+			address, err := utils.WalletAddress(strings.TrimSpace(wallet.PrivateKey))
+			if err != nil {
+				return 0, ErrInvalid.New("wallet %d: %v", i+1, err)
+			}
+			if wallet.Address != "" && wallet.Address != address {
+				return 0, ErrInvalid.New("wallet %d: address does not match private key", i+1)
+			}
+			wallet.Address = address
+		}
+		if !utils.ValidAddress(wallet.Address) {
+			return 0, ErrInvalid.New("wallet %d: address must be 32 bytes (64 hex characters) of ed25519 public key", i+1)
+		}
+		if wallet.Balance.IsNegative() {
+			return 0, ErrInvalid.New("wallet %d: balance must not be negative", i+1)
+		}
+		if previous, ok := seen[wallet.Address]; ok {
+			return 0, ErrInvalid.New("wallet %d: duplicates wallet %d", i+1, previous)
+		}
+		seen[wallet.Address] = i + 1
+		wallets[i] = wallet
+	}
+	if len(wallets) == 0 {
+		return 0, nil
+	}
+
+	created, err := s.seedWallets(ctx, wallets, req.OnlyIfEmpty)
+	if err != nil || !created {
+		return 0, err
+	}
+	return len(wallets), nil
+}
+
+// seedWallets создаёт кошельки с начальными балансами в одной транзакции БД.
+//
+// В пустой базе начальный баланс записывается в кошелёк как исходный, без записей в журнале
+// транзакций. Если кошельки уже есть, кошелёк создаётся с нулевым балансом, а начальный баланс
+// зачисляется эмиссией, чтобы появление новых средств было отражено в журнале транзакций
+// и учитывалось при расчёте баланса на момент в прошлом и сверке балансов.
+//
+// Возвращает:
+//   - false без ошибки, если onlyIfEmpty и в базе уже есть кошельки.
+//   - Ошибку, если кошелёк уже существует, или при сбое записи в базу.
+func (s *TransferService) seedWallets(ctx context.Context, wallets []dto.SeedWallet, onlyIfEmpty bool) (created bool, err error) {
+	tx, err := storage.BeginWrite(ctx, s.db)
+	if err != nil {
+		return false, err
+	}
+
+	// Откатываем транзакцию при ошибке и если кошельки не создаются
+	defer func() {
+		if err != nil || !created {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.ErrorContext(ctx, "rollback failed", "error", rbErr)
+			}
+		}
+	}()
+
+	executor := storage.WithContext(ctx, tx)
+	walletRepo := storage.NewWalletRepository(executor)
+	transactionRepo := storage.NewTransactionRepository(executor)
+
+	existing, err := walletRepo.GetCount()
+	if err != nil {
+		return false, ErrFailedToGet.WrapWithNoMessage(err)
+	}
+	if existing > 0 && onlyIfEmpty {
+		return false, nil
+	}
+
+	var initiatedBy string
+	if principal := auth.FromContext(ctx); principal != nil {
+		initiatedBy = principal.Subject
+	}
+
+	for _, wallet := range wallets {
+		_, err := walletRepo.GetBalance(dto.BalanceReq{Address: wallet.Address})
+		if err == nil {
+			return false, ErrInvalid.New("wallet %s already exists", wallet.Address)
+		}
+		if !storage.IsNotFoundErr(err) {
+			return false, ErrFailedToGet.Wrap(err, "failed to get wallet")
+		}
+
+		if existing == 0 {
+			if err := walletRepo.Insert(dto.WalletReq{Address: wallet.Address, Balance: wallet.Balance}); err != nil {
+				return false, ErrFailedToInsert.WrapWithNoMessage(err)
+			}
+			continue
+		}
+
+		if err := walletRepo.Insert(dto.WalletReq{Address: wallet.Address, Balance: decimal.Zero}); err != nil {
+			return false, ErrFailedToInsert.WrapWithNoMessage(err)
+		}
+		if !wallet.Balance.IsPositive() {
+			continue
+		}
+		if _, err := changeBalance(walletRepo, wallet.Address, wallet.Balance); err != nil {
+			return false, err
+		}
+		if err := appendRecord(transactionRepo, dto.TransactionRecord{
+			Kind:        dto.TransactionKindMint,
+			To:          wallet.Address,
+			Amount:      wallet.Balance,
+			InitiatedBy: initiatedBy,
+		}); err != nil {
+			return false, err
+		}
+	}
+
+	if err := storage.Commit(ctx, tx); err != nil {
+		return false, ErrFailedToInsert.Wrap(err, "failed to commit seeded wallets")
+	}
+	return true, nil
+}
+
+// seedBalances возвращает генератор начальных балансов по распределению req.Distribution
+// со средним req.Balance. Балансы округляются до сотых.
+//
+// Если задан req.KeySeed, генератор детерминирован: последовательность балансов зависит только от него.
+func seedBalances(req dto.SeedReq) (func() decimal.Decimal, error) {
+	var rng *rand.Rand
+	if req.KeySeed != "" {
+		rng = rand.New(rand.NewChaCha8(sha256.Sum256([]byte("balances\n" + req.KeySeed))))
+	} else {
+		rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+
+	switch req.Distribution {
+	case "", dto.SeedDistributionFixed:
+		return func() decimal.Decimal { return req.Balance }, nil
+	case dto.SeedDistributionUniform:
+		return func() decimal.Decimal {
+			return req.Balance.Mul(decimal.NewFromFloat(2 * rng.Float64())).Round(2)
+		}, nil
+	case dto.SeedDistributionExponential:
+		return func() decimal.Decimal {
+			return req.Balance.Mul(decimal.NewFromFloat(rng.ExpFloat64())).Round(2)
+		}, nil
+	default:
+		return nil, ErrInvalid.New("unknown balance distribution %q, expected %s, %s or %s", req.Distribution,
+			dto.SeedDistributionFixed, dto.SeedDistributionUniform, dto.SeedDistributionExponential)
+	}
+}
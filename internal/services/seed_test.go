@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/utils"
+	"github.com/joomcode/errorx"
+	"github.com/shopspring/decimal"
+)
+
+// walletCount возвращает количество кошельков в базе данных.
+func walletCount(t *testing.T, db *sql.DB) int {
+	t.Helper()
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM wallets").Scan(&count); err != nil {
+		t.Fatalf("failed to count wallets: %v", err)
+	}
+	return count
+}
+
+func TestImportWalletsRollsBackOnBadRow(t *testing.T) {
+	valid, validKey := utils.DeriveWalletKey("fixture", 0)
+	other, _ := utils.DeriveWalletKey("fixture", 1)
+
+	tests := []struct {
+		name string
+		// existing — начальные балансы кошельков, созданных до импорта
+		existing []string
+		// bad — строка фикстуры, следующая за корректной строкой с адресом valid
+		bad func(existing []testWallet) dto.SeedWallet
+	}{
+		{
+			name: "malformed address",
+			bad: func([]testWallet) dto.SeedWallet {
+				return dto.SeedWallet{Address: "abc", Balance: decimal.NewFromInt(10)}
+			},
+		},
+		{
+			name: "negative balance",
+			bad: func([]testWallet) dto.SeedWallet {
+				return dto.SeedWallet{Address: other, Balance: decimal.NewFromInt(-1)}
+			},
+		},
+		{
+			name: "address does not match private key",
+			bad: func([]testWallet) dto.SeedWallet {
+				return dto.SeedWallet{Address: other, PrivateKey: validKey, Balance: decimal.NewFromInt(10)}
+			},
+		},
+		{
+			name: "duplicate wallet",
+			bad: func([]testWallet) dto.SeedWallet {
+				return dto.SeedWallet{Address: strings.ToUpper(valid), Balance: decimal.NewFromInt(10)}
+			},
+		},
+		{
+			// Строка проходит проверку и отклоняется уже после создания первого кошелька и его эмиссии
+			name:     "existing wallet",
+			existing: []string{"100"},
+			bad: func(existing []testWallet) dto.SeedWallet {
+				return dto.SeedWallet{Address: existing[0].address, Balance: decimal.NewFromInt(10)}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, db, existing := newTestService(t, tt.existing...)
+
+			created, err := service.TransferService.ImportWallets(context.Background(), dto.ImportWalletsReq{
+				Wallets: []dto.SeedWallet{
+					{Address: valid, Balance: decimal.NewFromInt(50)},
+					tt.bad(existing),
+				},
+			})
+			if !errorx.IsOfType(err, ErrInvalid) || created != 0 {
+				t.Fatalf("ImportWallets: created %d, error %v, want 0 and %s", created, err, ErrInvalid)
+			}
+			if count := walletCount(t, db); count != len(tt.existing) {
+				t.Errorf("%d wallets after failed import, want %d", count, len(tt.existing))
+			}
+			if records := ledgerRecords(t, db); len(records) != 0 {
+				t.Errorf("failed import appended %d ledger records", len(records))
+			}
+			for i, wallet := range existing {
+				expectBalance(t, service, wallet.address, tt.existing[i])
+			}
+		})
+	}
+}
+
+func TestGenerateWalletsKeySeedIsReproducible(t *testing.T) {
+	// generate создаёт кошельки в новой базе данных и возвращает их ключи и сохранённые балансы
+	generate := func(t *testing.T, req dto.SeedReq) ([]dto.WalletKey, []decimal.Decimal) {
+		t.Helper()
+		service, _, _ := newTestService(t)
+		keys, err := service.TransferService.GenerateWallets(context.Background(), req)
+		if err != nil {
+			t.Fatalf("GenerateWallets: %v", err)
+		}
+		if len(keys) != req.Count {
+			t.Fatalf("generated %d wallets, want %d", len(keys), req.Count)
+		}
+		balances := make([]decimal.Decimal, len(keys))
+		for i, key := range keys {
+			balance, err := service.TransferService.GetBalance(context.Background(), dto.BalanceReq{Address: key.Address})
+			if err != nil {
+				t.Fatalf("GetBalance: %v", err)
+			}
+			balances[i] = balance.Amount
+		}
+		return keys, balances
+	}
+
+	tests := []string{dto.SeedDistributionFixed, dto.SeedDistributionUniform, dto.SeedDistributionExponential}
+
+	for _, distribution := range tests {
+		t.Run(distribution, func(t *testing.T) {
+			req := dto.SeedReq{
+				Count:        5,
+				Balance:      decimal.NewFromInt(100),
+				Distribution: distribution,
+				KeySeed:      "staging",
+			}
+			keys, balances := generate(t, req)
+			again, againBalances := generate(t, req)
+
+			for i := range keys {
+				if keys[i] != again[i] {
+					t.Errorf("wallet %d: key %.8s, then %.8s from the same seed", i, keys[i].Address, again[i].Address)
+				}
+				if !balances[i].Equal(againBalances[i]) {
+					t.Errorf("wallet %d: balance %s, then %s from the same seed", i, balances[i], againBalances[i])
+				}
+				if address, err := utils.WalletAddress(keys[i].PrivateKey); err != nil || address != keys[i].Address {
+					t.Errorf("wallet %d: private key does not match address %.8s", i, keys[i].Address)
+				}
+			}
+
+			req.KeySeed = "production"
+			other, _ := generate(t, req)
+			if other[0].Address == keys[0].Address {
+				t.Errorf("seeds staging and production derive the same wallet %.8s", keys[0].Address)
+			}
+		})
+	}
+}
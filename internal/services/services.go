@@ -1,21 +1,47 @@
 package services
 
 import (
+	"crypto/ed25519"
 	"database/sql"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/auth"
 	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
 )
 
 // Service агрегирует основные сервисы приложения.
 type Service struct {
-	TransferService TransferInteractor
+	TransferService    TransferInteractor
+	AuthService        AuthInteractor
+	AdminService       AdminInteractor
+	LedgerService      LedgerInteractor
+	SnapshotService    SnapshotInteractor
+	ReconcileService   ReconcileInteractor
+	ProjectionService  ProjectionInteractor
+	IdempotencyService IdempotencyInteractor
 }
 
 // NewService создаёт и возвращает новый экземпляр Service,
 // инициализируя все необходимые сервисы и репозитории.
-// В качестве параметра принимает подключение к базе данных *sql.DB.
-func NewService(db *sql.DB) *Service {
+// В качестве параметров принимает подключение к базе данных *sql.DB,
+// проверку JWT (nil, если аутентификация по JWT отключена)
+// и ключ подписи контрольных точек журнала (nil, если создание контрольных точек не требуется).
+func NewService(db *sql.DB, tokenVerifier *auth.JWTVerifier, ledgerKey ed25519.PrivateKey) *Service {
 	repository := storage.NewRepository(db)
+	adminService := NewAdminService(db, repository.AuditRepository)
 	return &Service{
-		TransferService: NewTransferService(db, repository.WalletRepository, repository.TransactionRepository),
+		TransferService: NewTransferService(
+			db, repository.WalletRepository, repository.TransactionRepository, repository.SnapshotRepository,
+		),
+		AuthService:  NewAuthService(db, repository.WalletRepository, repository.APIKeyRepository, tokenVerifier),
+		AdminService: adminService,
+		LedgerService: NewLedgerService(
+			db, repository.TransactionRepository, repository.CheckpointRepository, ledgerKey,
+		),
+		SnapshotService:  NewSnapshotService(db, repository.SnapshotRepository),
+		ReconcileService: NewReconcileService(db, adminService),
+		ProjectionService: NewProjectionService(
+			db, adminService, repository.ProjectionRepository, repository.StatsRepository,
+			repository.TransactionRepository, DefaultProjections()...,
+		),
+		IdempotencyService: NewIdempotencyService(repository.IdempotencyRepository),
 	}
 }
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/auth"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/utils"
+	"github.com/shopspring/decimal"
+)
+
+// testKeySeed — строка, из которой выводятся ключи кошельков тестовой базы данных.
+const testKeySeed = "services-test"
+
+// testWallet — кошелёк тестовой базы данных с закрытым ключом для подписи переводов.
+type testWallet struct {
+	address    string
+	privateKey string
+}
+
+// newTestDB создаёт базу данных SQLite во временном каталоге и применяет к ней миграции.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := storage.Connect(filepath.Join(t.TempDir(), "database.db"), 5*time.Second)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := storage.ApplyMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("ApplyMigrations: %v", err)
+	}
+	return db
+}
+
+// newTestService создаёт сервисы поверх новой тестовой базы данных с кошельками,
+// начальные балансы которых перечислены в balances.
+func newTestService(t *testing.T, balances ...string) (*Service, *sql.DB, []testWallet) {
+	t.Helper()
+	db := newTestDB(t)
+	service := NewService(db, nil, nil)
+
+	wallets := make([]testWallet, len(balances))
+	seed := make([]dto.SeedWallet, len(balances))
+	for i, balance := range balances {
+		wallets[i].address, wallets[i].privateKey = utils.DeriveWalletKey(testKeySeed, i)
+		seed[i] = dto.SeedWallet{Address: wallets[i].address, Balance: decimal.RequireFromString(balance)}
+	}
+	if len(seed) > 0 {
+		if _, err := service.TransferService.ImportWallets(context.Background(), dto.ImportWalletsReq{Wallets: seed}); err != nil {
+			t.Fatalf("ImportWallets: %v", err)
+		}
+	}
+	return service, db, wallets
+}
+
+// adminContext возвращает контекст с клиентом, имеющим область доступа admin.
+func adminContext() context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{
+		Subject: "api_key:admin",
+		Method:  auth.MethodAPIKey,
+		Scopes:  []auth.Scope{auth.ScopeAdmin},
+	})
+}
+
+// clientContext возвращает контекст с клиентом, которому разрешены чтение и переводы, но не администрирование.
+func clientContext() context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{
+		Subject:    "api_key:client",
+		Method:     auth.MethodAPIKey,
+		Scopes:     []auth.Scope{auth.ScopeRead, auth.ScopeTransfer},
+		AllWallets: true,
+	})
+}
+
+// expectBalance проверяет сохранённый баланс кошелька.
+func expectBalance(t *testing.T, service *Service, address, want string) {
+	t.Helper()
+	balance, err := service.TransferService.GetBalance(context.Background(), dto.BalanceReq{Address: address})
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if !balance.Amount.Equal(decimal.RequireFromString(want)) {
+		t.Errorf("balance of %.8s = %s, want %s", address, balance.Amount, want)
+	}
+}
+
+// ledgerRecords возвращает все записи журнала транзакций в порядке добавления.
+func ledgerRecords(t *testing.T, db *sql.DB) []dto.TransactionRecord {
+	t.Helper()
+	var records []dto.TransactionRecord
+	if err := storage.NewTransactionRepository(db).ForEachRecord(0, func(record dto.TransactionRecord) error {
+		records = append(records, record)
+		return nil
+	}); err != nil {
+		t.Fatalf("ForEachRecord: %v", err)
+	}
+	return records
+}
+
+// send подписывает и выполняет перевод amount с кошелька from на адрес to со следующим nonce отправителя.
+func send(t *testing.T, service *Service, from testWallet, to, amount string) {
+	t.Helper()
+	ctx := context.Background()
+	nonce, err := service.TransferService.GetNonce(ctx, dto.BalanceReq{Address: from.address})
+	if err != nil {
+		t.Fatalf("GetNonce: %v", err)
+	}
+	value := decimal.RequireFromString(amount)
+	signature, err := utils.SignTransfer(from.privateKey, from.address, to, value, nonce.Nonce+1)
+	if err != nil {
+		t.Fatalf("SignTransfer: %v", err)
+	}
+	if err := service.TransferService.Send(ctx, dto.TransactionReq{
+		From:      from.address,
+		To:        to,
+		Amount:    value,
+		Nonce:     nonce.Nonce + 1,
+		Signature: signature,
+	}); err != nil {
+		t.Fatalf("Send %s from %.8s to %.8s: %v", amount, from.address, to, err)
+	}
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/utils"
+	"github.com/shopspring/decimal"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// SnapshotInteractor описывает интерфейс работы со снимками балансов и доказательствами
+// включения балансов кошельков в снимки.
+type SnapshotInteractor interface {
+	// CreateSnapshot создаёт снимок балансов, если журнал изменился с момента предыдущего снимка.
+	CreateSnapshot(ctx context.Context) (dto.Snapshot, bool, error)
+
+	// GetSnapshots возвращает последние N снимков.
+	GetSnapshots(ctx context.Context, n int) ([]dto.Snapshot, error)
+
+	// GetProof возвращает доказательство включения баланса кошелька в снимок.
+	GetProof(ctx context.Context, req dto.BalanceProofReq) (dto.BalanceProofResp, error)
+}
+
+// SnapshotService реализует SnapshotInteractor, используя репозитории и базу данных.
+type SnapshotService struct {
+	db                 *sql.DB
+	snapshotRepository storage.SnapshotStorageInteractor
+}
+
+// NewSnapshotService создаёт новый экземпляр SnapshotService.
+//
+// Аргументы:
+//   - db: подключение к базе данных.
+//   - snapshotRepository: репозиторий снимков балансов.
+//
+// Возвращает:
+//   - Указатель на SnapshotService.
+func NewSnapshotService(db *sql.DB, snapshotRepository storage.SnapshotStorageInteractor) *SnapshotService {
+	return &SnapshotService{db: db, snapshotRepository: snapshotRepository}
+}
+
+// CreateSnapshot фиксирует балансы всех кошельков на момент последней транзакции журнала
+// и вычисляет корень дерева Меркла над ними.
+//
+// Балансы и последняя транзакция читаются в одной транзакции БД, поэтому снимок согласован
+// с журналом. Снимок не создаётся, если журнал не изменился с момента предыдущего снимка.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//
+// Возвращает:
+//   - созданный (или последний существующий) снимок;
+//   - true, если снимок создан;
+//   - ошибку при сбое БД.
+func (s *SnapshotService) CreateSnapshot(ctx context.Context) (snapshot dto.Snapshot, created bool, err error) {
+	tx, err := storage.BeginWrite(ctx, s.db)
+	if err != nil {
+		return dto.Snapshot{}, false, err
+	}
+
+	// Гарантируем откат при любой ошибке, возвращённой после начала транзакции
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.ErrorContext(ctx, "rollback failed", "error", rbErr)
+			}
+		}
+	}()
+
+	executor := storage.WithContext(ctx, tx)
+	walletRepo := storage.NewWalletRepository(executor)
+	transactionRepo := storage.NewTransactionRepository(executor)
+	snapshotRepo := storage.NewSnapshotRepository(executor)
+
+	head, err := transactionRepo.GetHead()
+	if err != nil {
+		return dto.Snapshot{}, false, ErrFailedToGet.Wrap(err, "failed to get ledger head")
+	}
+
+	last, err := snapshotRepo.GetLastN(1)
+	if err != nil {
+		return dto.Snapshot{}, false, ErrFailedToGet.Wrap(err, "failed to get last snapshot")
+	}
+	if len(last) > 0 && last[0].LastTxID == head.ID {
+		return last[0], false, tx.Commit()
+	}
+
+	wallets, err := walletRepo.GetAll()
+	if err != nil {
+		return dto.Snapshot{}, false, ErrFailedToGet.Wrap(err, "failed to get wallets")
+	}
+
+	balances := make([]dto.SnapshotBalance, len(wallets))
+	for i, wallet := range wallets {
+		balances[i] = dto.SnapshotBalance{Address: wallet.Address, Balance: wallet.Balance}
+	}
+
+	snapshot = dto.Snapshot{
+		LastTxID:    head.ID,
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+		WalletCount: int64(len(balances)),
+		Total:       snapshotTotal(balances),
+		MerkleRoot:  snapshotTree(balances).Root(),
+	}
+
+	if snapshot.ID, err = snapshotRepo.Insert(snapshot); err != nil {
+		return dto.Snapshot{}, false, ErrFailedToInsert.Wrap(err, "failed to insert snapshot")
+	}
+	if err = snapshotRepo.InsertBalances(snapshot.ID, balances); err != nil {
+		return dto.Snapshot{}, false, ErrFailedToInsert.Wrap(err, "failed to insert snapshot balances")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return dto.Snapshot{}, false, ErrFailedToInsert.Wrap(err, "failed to commit transaction")
+	}
+	return snapshot, true, nil
+}
+
+// GetSnapshots возвращает последние N снимков балансов, начиная с самого нового.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//   - n: количество снимков.
+//
+// Возвращает:
+//   - срез снимков и ошибку при некорректном n или сбое БД.
+func (s *SnapshotService) GetSnapshots(ctx context.Context, n int) ([]dto.Snapshot, error) {
+	if n <= 0 {
+		return nil, ErrInvalid.New("count must be positive")
+	}
+
+	snapshots, err := s.snapshotRepository.WithContext(ctx).GetLastN(n)
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to get snapshots")
+	}
+	return snapshots, nil
+}
+
+// GetProof строит доказательство включения баланса кошелька в снимок.
+//
+// Дерево Меркла перестраивается по сохранённым балансам снимка; если его корень не совпадает
+// с сохранённым, балансы снимка были изменены, и доказательство не выдаётся.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//   - req: адрес кошелька и идентификатор снимка (0 — последний снимок).
+//
+// Возвращает:
+//   - dto.BalanceProofResp с балансом, хешем листа, шагами доказательства и корнем снимка.
+//   - ошибку, если снимок или кошелёк в нём не найдены или возникла ошибка работы с БД.
+func (s *SnapshotService) GetProof(ctx context.Context, req dto.BalanceProofReq) (dto.BalanceProofResp, error) {
+	if req.SnapshotID < 0 {
+		return dto.BalanceProofResp{}, ErrInvalid.New("snapshot id must not be negative")
+	}
+
+	snapshotRepository := s.snapshotRepository.WithContext(ctx)
+	snapshot, err := getSnapshot(snapshotRepository, req.SnapshotID)
+	if err != nil {
+		return dto.BalanceProofResp{}, err
+	}
+
+	balances, err := snapshotRepository.GetBalances(snapshot.ID)
+	if err != nil {
+		return dto.BalanceProofResp{}, ErrFailedToGet.Wrap(err, "failed to get snapshot balances")
+	}
+
+	tree := snapshotTree(balances)
+	if tree.Root() != snapshot.MerkleRoot {
+		return dto.BalanceProofResp{}, ErrFailedToGet.New("balances of snapshot %d do not match its merkle root", snapshot.ID)
+	}
+
+	for i, balance := range balances {
+		if !strings.EqualFold(balance.Address, req.Address) {
+			continue
+		}
+		return dto.BalanceProofResp{
+			SnapshotID: snapshot.ID,
+			LastTxID:   snapshot.LastTxID,
+			Address:    balance.Address,
+			Balance:    balance.Balance,
+			LeafHash:   utils.MerkleLeafHash(balance.Address, balance.Balance),
+			Proof:      tree.Proof(i),
+			MerkleRoot: snapshot.MerkleRoot,
+		}, nil
+	}
+
+	return dto.BalanceProofResp{}, ErrFailedToGet.Wrap(
+		storage.ErrWalletNotFound.New("wallet not found in snapshot %d", snapshot.ID),
+		"failed to build balance proof",
+	)
+}
+
+// getSnapshot возвращает снимок по идентификатору или последний снимок, если идентификатор равен 0.
+func getSnapshot(snapshotRepository storage.SnapshotStorageInteractor, id int64) (dto.Snapshot, error) {
+	if id != 0 {
+		snapshot, err := snapshotRepository.GetByID(id)
+		if err != nil {
+			return dto.Snapshot{}, ErrFailedToGet.Wrap(err, "failed to get snapshot")
+		}
+		return snapshot, nil
+	}
+
+	last, err := snapshotRepository.GetLastN(1)
+	if err != nil {
+		return dto.Snapshot{}, ErrFailedToGet.Wrap(err, "failed to get last snapshot")
+	}
+	if len(last) == 0 {
+		return dto.Snapshot{}, ErrFailedToGet.Wrap(storage.ErrSnapshotNotFound.New("no snapshots yet"), "failed to get last snapshot")
+	}
+	return last[0], nil
+}
+
+// snapshotTree строит дерево Меркла по балансам снимка в порядке их следования.
+func snapshotTree(balances []dto.SnapshotBalance) *utils.MerkleTree {
+	leaves := make([]string, len(balances))
+	for i, balance := range balances {
+		leaves[i] = utils.MerkleLeafHash(balance.Address, balance.Balance)
+	}
+	return utils.NewMerkleTree(leaves)
+}
+
+// snapshotTotal вычисляет сумму балансов снимка.
+func snapshotTotal(balances []dto.SnapshotBalance) decimal.Decimal {
+	total := decimal.Zero
+	for _, balance := range balances {
+		total = total.Add(balance.Balance)
+	}
+	return total
+}
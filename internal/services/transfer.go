@@ -1,28 +1,57 @@
 package services
 
 import (
+	"context"
 	"database/sql"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/auth"
 	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
 	"github.com/coffee-realist/infotecs_transaction_system/internal/storage"
 	"github.com/coffee-realist/infotecs_transaction_system/internal/utils"
+	"github.com/joomcode/errorx"
 	"github.com/shopspring/decimal"
-	"log"
+	"log/slog"
+	"time"
 )
 
 // TransferInteractor описывает интерфейс бизнес-логики для операций с транзакциями и балансами.
 type TransferInteractor interface {
 	// Send выполняет перевод между кошельками согласно данным транзакции.
-	Send(transaction dto.TransactionReq) error
+	// Аутентифицированный клиент из контекста (auth.FromContext) сохраняется вместе с транзакцией.
+	Send(ctx context.Context, transaction dto.TransactionReq) error
 
 	// GetLastN возвращает последние N транзакций.
-	GetLastN(n int) (dto.TransactionsResp, error)
+	GetLastN(ctx context.Context, n int) (dto.TransactionsResp, error)
 
 	// GetBalance возвращает баланс кошелька по адресу.
-	GetBalance(req dto.BalanceReq) (dto.BalanceResp, error)
+	GetBalance(ctx context.Context, req dto.BalanceReq) (dto.BalanceResp, error)
 
-	// GenerateWallets инициализирует 10 кошельков с дефолтным балансом,
-	// если в базе еще нет кошельков.
-	GenerateWallets() error
+	// GetBalanceAt возвращает баланс кошелька на момент в прошлом.
+	GetBalanceAt(ctx context.Context, req dto.BalanceAtReq) (dto.BalanceResp, error)
+
+	// WriteStatement формирует выписку по кошельку за период и передаёт её в w по частям.
+	WriteStatement(ctx context.Context, req dto.StatementReq, w StatementWriter) error
+
+	// GetNonce возвращает последний использованный nonce кошелька по адресу.
+	GetNonce(ctx context.Context, req dto.BalanceReq) (dto.NonceResp, error)
+
+	// GetTotalSupply возвращает сумму балансов всех кошельков.
+	GetTotalSupply(ctx context.Context) (decimal.Decimal, error)
+
+	// GenerateWallets создаёт кошельки для начального заполнения базы с балансами по заданному
+	// распределению и возвращает их ключевые пары.
+	GenerateWallets(ctx context.Context, req dto.SeedReq) ([]dto.WalletKey, error)
+
+	// ImportWallets создаёт кошельки с начальными балансами из фикстуры в одной транзакции БД.
+	ImportWallets(ctx context.Context, req dto.ImportWalletsReq) (int, error)
+}
+
+// StatementWriter принимает выписку по кошельку по мере её формирования: сначала начало выписки,
+// затем строки в порядке выполнения транзакций и в конце итоги. Ошибка любого метода
+// прекращает формирование выписки.
+type StatementWriter interface {
+	WriteHeader(header dto.StatementHeader) error
+	WriteEntry(entry dto.StatementEntry) error
+	WriteSummary(summary dto.StatementSummary) error
 }
 
 // TransferService реализует TransferInteractor, используя репозитории и базу данных.
@@ -30,6 +59,7 @@ type TransferService struct {
 	db                    *sql.DB
 	walletRepository      storage.WalletStorageInteractor
 	transactionRepository storage.TransactionStorageInteractor
+	snapshotRepository    storage.SnapshotStorageInteractor
 }
 
 // NewTransferService создаёт новый экземпляр TransferService.
@@ -38,6 +68,7 @@ type TransferService struct {
 //   - db: подключение к базе данных.
 //   - walletRepository: репозиторий для работы с кошельками.
 //   - transactionRepository: репозиторий для работы с транзакциями.
+//   - snapshotRepository: репозиторий снимков балансов для расчёта баланса на момент в прошлом.
 //
 // Возвращает:
 //   - Указатель на TransferService с инициализированными репозиториями и подключением к БД.
@@ -45,56 +76,90 @@ func NewTransferService(
 	db *sql.DB,
 	walletRepository storage.WalletStorageInteractor,
 	transactionRepository storage.TransactionStorageInteractor,
+	snapshotRepository storage.SnapshotStorageInteractor,
 ) *TransferService {
 	return &TransferService{
 		db:                    db,
 		walletRepository:      walletRepository,
 		transactionRepository: transactionRepository,
+		snapshotRepository:    snapshotRepository,
 	}
 }
 
 // Send выполняет перевод средств между кошельками.
 //
+// Перевод должен быть подписан закрытым ключом кошелька отправителя (см. utils.TransferPayload),
+// а его nonce — превышать последний использованный nonce отправителя. Проверка nonce, списание,
+// зачисление и запись транзакции выполняются в одной транзакции БД.
+//
 // Аргументы:
-//   - req: структура dto.TransactionReq с полями From, To и Amount.
+//   - ctx: контекст запроса; аутентифицированный клиент из него записывается в транзакцию для аудита.
+//   - req: структура dto.TransactionReq с полями From, To, Amount, Nonce и Signature.
 //
 // Возвращает:
-//   - Ошибку в случае некорректных данных, недостаточного баланса, ошибок работы с БД или репозиториями.
-//     В случае успеха — nil.
-func (s *TransferService) Send(req dto.TransactionReq) error {
+//   - Ошибку в случае некорректных данных, неверной подписи или nonce, недостаточного баланса,
+//     ошибок работы с БД или репозиториями. В случае успеха — nil.
+func (s *TransferService) Send(ctx context.Context, req dto.TransactionReq) (err error) {
 	// Валидация
-	if req.From == "" || req.To == "" || !req.Amount.IsPositive() {
+	if req.From == "" || req.To == "" || !req.Amount.IsPositive() || req.Nonce == 0 {
 		return ErrInvalid.New("invalid input fields")
 	}
 	if req.From == req.To {
-		return ErrInvalid.New("cannot transfer to same wallet")
+		return ErrSameWallet.New("cannot transfer to same wallet")
+	}
+	if !utils.VerifyTransfer(req.From, req.To, req.Amount, req.Nonce, req.Signature) {
+		return ErrInvalidSignature.New("signature does not match transfer or sender address")
 	}
 
-	tx, err := s.db.Begin()
+	tx, err := storage.BeginWrite(ctx, s.db)
 	if err != nil {
 		return err
 	}
 
-	// Гарантируем откат при ошибках
+	// Гарантируем откат при любой ошибке, возвращённой после начала транзакции
 	defer func() {
 		if err != nil {
 			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Printf("Rollback error: %v", rbErr)
+				slog.ErrorContext(ctx, "rollback failed", "error", rbErr)
 			}
 		}
 	}()
 
 	// Создаем репозитории, использующие транзакцию
-	walletRepo := storage.NewWalletRepository(tx)
-	transactionRepo := storage.NewTransactionRepository(tx)
+	executor := storage.WithContext(ctx, tx)
+	walletRepo := storage.NewWalletRepository(executor)
+	transactionRepo := storage.NewTransactionRepository(executor)
+
+	// Замороженные кошельки не участвуют в переводах
+	for _, address := range []string{req.From, req.To} {
+		frozen, err := walletRepo.IsFrozen(address)
+		if err != nil {
+			return ErrFailedToGet.Wrap(err, "failed to get wallet state")
+		}
+		if frozen {
+			return ErrWalletFrozen.New("wallet %s is frozen", address)
+		}
+	}
+
+	// Проверяем nonce отправителя, защищаясь от повторного применения подписанного перевода
+	nonceResp, err := walletRepo.GetNonce(dto.BalanceReq{Address: req.From})
+	if err != nil {
+		return ErrFailedToGet.Wrap(err, "failed to get nonce")
+	}
+	if req.Nonce <= nonceResp.Nonce {
+		return ErrInvalidNonce.New("nonce must be greater than %d", nonceResp.Nonce)
+	}
+	if err := walletRepo.UpdateNonce(req.From, req.Nonce); err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to update nonce")
+	}
 
 	// Проверяем и обновляем баланс отправителя
-	balanceResp, err := s.walletRepository.GetBalance(dto.BalanceReq{Address: req.From})
+	balanceResp, err := walletRepo.GetBalance(dto.BalanceReq{Address: req.From})
 	if err != nil {
 		return ErrFailedToGet.Wrap(err, "failed to get balance")
 	}
 	if balanceResp.Amount.LessThan(req.Amount) {
-		return ErrInvalid.New("insufficient funds")
+		return ErrInsufficientFunds.New("insufficient funds")
 	}
 
 	if err := walletRepo.UpdateBalance(dto.BalanceUpdateReq{
@@ -105,7 +170,7 @@ func (s *TransferService) Send(req dto.TransactionReq) error {
 	}
 
 	// Проверяем и обновляем баланс получателя
-	balanceResp, err = s.walletRepository.GetBalance(dto.BalanceReq{Address: req.To})
+	balanceResp, err = walletRepo.GetBalance(dto.BalanceReq{Address: req.To})
 	if err != nil {
 		return ErrFailedToGet.Wrap(err, "failed to get balance")
 	}
@@ -117,65 +182,308 @@ func (s *TransferService) Send(req dto.TransactionReq) error {
 		return ErrFailedToUpdate.Wrap(err, "failed to update balance")
 	}
 
-	// Создаем запись о транзакции
-	if err := transactionRepo.Insert(req); err != nil {
-		return ErrFailedToInsert.Wrap(err, "failed to insert transaction")
+	// Создаем запись о транзакции с указанием инициатора
+	var initiatedBy string
+	if principal := auth.FromContext(ctx); principal != nil {
+		initiatedBy = principal.Subject
+	}
+	if err := appendRecord(transactionRepo, dto.TransactionRecord{
+		Kind:        dto.TransactionKindTransfer,
+		From:        req.From,
+		To:          req.To,
+		Amount:      req.Amount,
+		Nonce:       req.Nonce,
+		Signature:   req.Signature,
+		InitiatedBy: initiatedBy,
+	}); err != nil {
+		return err
 	}
 
 	// Фиксируем транзакцию
-	if err := tx.Commit(); err != nil {
+	if err := storage.Commit(ctx, tx); err != nil {
 		return ErrFailedToInsert.Wrap(err, "failed to commit transaction")
 	}
 
 	return nil
 }
 
-// GenerateWallets создаёт 10 кошельков с начальным балансом 100, если в базе нет кошельков.
+// GetTotalSupply возвращает сумму балансов всех кошельков — объём средств в системе.
 //
 // Возвращает:
-//   - Ошибку при сбое проверки существующих кошельков, генерации адресов или записи в базу.
-//     В случае успеха — nil.
-func (s *TransferService) GenerateWallets() error {
-	// Проверяем наличие уже существующих кошельков
-	count, err := s.walletRepository.GetCount()
+//   - Сумму балансов.
+//   - Ошибку при сбое чтения кошельков из базы.
+func (s *TransferService) GetTotalSupply(ctx context.Context) (decimal.Decimal, error) {
+	wallets, err := s.walletRepository.WithContext(ctx).GetAll()
 	if err != nil {
-		return ErrFailedToGet.WrapWithNoMessage(err)
+		return decimal.Zero, ErrFailedToGet.Wrap(err, "failed to get wallets")
 	}
-	if count > 0 {
-		return nil // Кошельки уже существуют
+
+	total := decimal.Zero
+	for _, wallet := range wallets {
+		total = total.Add(wallet.Balance)
 	}
-	// Генерируем 10 кошельков
-	for i := 0; i < 10; i++ {
-		address, err := utils.GenerateRandomAddress()
+	return total, nil
+}
+
+// defaultWalletCount — количество кошельков, создаваемых AdminService.GenerateWallets, если оно не указано.
+const defaultWalletCount = 10
+
+// defaultWalletBalance — начальный баланс кошельков, создаваемых AdminService.GenerateWallets.
+var defaultWalletBalance = decimal.NewFromInt(100)
+
+// createWallets создаёт count кошельков с новыми ключами Ed25519 и указанным начальным балансом.
+//
+// Возвращает:
+//   - Ключевые пары созданных кошельков.
+//   - Ошибку при сбое генерации ключей или записи в базу.
+func createWallets(walletRepo storage.WalletStorageInteractor, count int, balance decimal.Decimal) ([]dto.WalletKey, error) {
+	keys := make([]dto.WalletKey, 0, count)
+	for i := 0; i < count; i++ {
+		address, privateKey, err := utils.GenerateWalletKey()
 		if err != nil {
-			return ErrFailedToGenerate.Wrap(err, "failed to generate wallet address")
+			return nil, ErrFailedToGenerate.Wrap(err, "failed to generate wallet key")
 		}
-		if err = s.walletRepository.Insert(dto.WalletReq{Address: address, Balance: decimal.NewFromInt(100)}); err != nil {
-			return ErrFailedToInsert.WrapWithNoMessage(err)
+		if err = walletRepo.Insert(dto.WalletReq{Address: address, Balance: balance}); err != nil {
+			return nil, ErrFailedToInsert.WrapWithNoMessage(err)
 		}
+		keys = append(keys, dto.WalletKey{Address: address, PrivateKey: privateKey})
 	}
 
-	return nil
+	return keys, nil
 }
 
 // GetLastN возвращает последние N транзакций.
 //
 // Аргументы:
+//   - ctx: контекст запроса.
 //   - n: количество транзакций для выборки.
 //
 // Возвращает:
 //   - Срез транзакций dto.TransactionsResp и ошибку, если она возникла.
-func (s *TransferService) GetLastN(n int) (dto.TransactionsResp, error) {
-	return s.transactionRepository.GetLastN(n)
+func (s *TransferService) GetLastN(ctx context.Context, n int) (dto.TransactionsResp, error) {
+	return s.transactionRepository.WithContext(ctx).GetLastN(n)
 }
 
 // GetBalance возвращает баланс кошелька по адресу.
 //
 // Аргументы:
+//   - ctx: контекст запроса.
 //   - req: структура dto.BalanceReq с адресом кошелька.
 //
 // Возвращает:
 //   - Баланс dto.BalanceResp и ошибку при её возникновении.
-func (s *TransferService) GetBalance(req dto.BalanceReq) (dto.BalanceResp, error) {
-	return s.walletRepository.GetBalance(req)
+func (s *TransferService) GetBalance(ctx context.Context, req dto.BalanceReq) (dto.BalanceResp, error) {
+	return s.walletRepository.WithContext(ctx).GetBalance(req)
+}
+
+// GetBalanceAt вычисляет баланс кошелька на момент в прошлом, заданный временем или
+// идентификатором последней учитываемой транзакции.
+//
+// Расчёт начинается от ближайшей к этому моменту опорной точки — снимка балансов или текущего
+// баланса — и учитывает только транзакции кошелька между опорной точкой и искомым моментом:
+// при движении вперёд они прибавляются, при движении назад — вычитаются. Кошелёк, отсутствующий
+// в снимке, на момент снимка ещё не существовал, и его баланс в нём считается нулевым.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//   - req: структура dto.BalanceAtReq с адресом кошелька и ровно одним из полей At и AsOfTx.
+//
+// Возвращает:
+//   - dto.BalanceResp с балансом и идентификатором последней учтённой транзакции.
+//   - ошибку при некорректном запросе, если кошелёк не найден или при ошибке работы с БД.
+func (s *TransferService) GetBalanceAt(ctx context.Context, req dto.BalanceAtReq) (resp dto.BalanceResp, err error) {
+	if (req.At == nil) == (req.AsOfTx == nil) {
+		return dto.BalanceResp{}, ErrInvalid.New("exactly one of at and as_of_tx must be set")
+	}
+	if req.AsOfTx != nil && *req.AsOfTx < 0 {
+		return dto.BalanceResp{}, ErrInvalid.New("as_of_tx must not be negative")
+	}
+
+	// Текущий баланс и последняя транзакция журнала должны быть согласованы, поэтому читаем их в транзакции БД
+	tx, err := s.db.Begin()
+	if err != nil {
+		return dto.BalanceResp{}, err
+	}
+
+	// Гарантируем откат при любой ошибке, возвращённой после начала транзакции
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.ErrorContext(ctx, "rollback failed", "error", rbErr)
+			}
+		}
+	}()
+
+	executor := storage.WithContext(ctx, tx)
+	walletRepo := storage.NewWalletRepository(executor)
+	transactionRepo := storage.NewTransactionRepository(executor)
+	snapshotRepo := storage.NewSnapshotRepository(executor)
+
+	current, err := walletRepo.GetBalance(dto.BalanceReq{Address: req.Address})
+	if err != nil {
+		return dto.BalanceResp{}, ErrFailedToGet.Wrap(err, "failed to get balance")
+	}
+	head, err := transactionRepo.GetHead()
+	if err != nil {
+		return dto.BalanceResp{}, ErrFailedToGet.Wrap(err, "failed to get ledger head")
+	}
+
+	var target int64
+	if req.AsOfTx != nil {
+		if target = *req.AsOfTx; target > head.ID {
+			return dto.BalanceResp{}, ErrInvalid.New("transaction %d does not exist yet", target)
+		}
+	} else if target, err = transactionRepo.GetLastIDBefore(*req.At); err != nil {
+		return dto.BalanceResp{}, ErrFailedToGet.Wrap(err, "failed to find transaction by time")
+	}
+
+	// Опорная точка по умолчанию — текущий баланс; снимок используется, если он ближе
+	balance, baseID := current.Amount, head.ID
+	snapshot, err := snapshotRepo.GetNearest(target)
+	switch {
+	case err == nil && distance(snapshot.LastTxID, target) < head.ID-target:
+		baseID = snapshot.LastTxID
+		if balance, err = snapshotRepo.GetBalance(snapshot.ID, req.Address); err != nil {
+			if !errorx.IsOfType(err, storage.ErrWalletNotFound) {
+				return dto.BalanceResp{}, ErrFailedToGet.Wrap(err, "failed to get snapshot balance")
+			}
+			balance = decimal.Zero
+		}
+	case err != nil && !errorx.IsOfType(err, storage.ErrSnapshotNotFound):
+		return dto.BalanceResp{}, ErrFailedToGet.Wrap(err, "failed to get snapshot")
+	}
+
+	if baseID < target {
+		delta, err := transactionRepo.GetWalletDelta(req.Address, baseID, target)
+		if err != nil {
+			return dto.BalanceResp{}, ErrFailedToGet.Wrap(err, "failed to get wallet transactions")
+		}
+		balance = balance.Add(delta)
+	} else if baseID > target {
+		delta, err := transactionRepo.GetWalletDelta(req.Address, target, baseID)
+		if err != nil {
+			return dto.BalanceResp{}, ErrFailedToGet.Wrap(err, "failed to get wallet transactions")
+		}
+		balance = balance.Sub(delta)
+	}
+
+	if err = storage.Commit(ctx, tx); err != nil {
+		return dto.BalanceResp{}, ErrFailedToGet.Wrap(err, "failed to commit transaction")
+	}
+	return dto.BalanceResp{Amount: balance, AsOfTx: &target}, nil
+}
+
+// statementFee — комиссия за транзакцию в выписке. Переводы в системе комиссией не облагаются.
+var statementFee = decimal.Zero
+
+// WriteStatement формирует выписку по кошельку за период [From, To): входящий остаток, каждую
+// транзакцию кошелька с балансом после неё и комиссией, итоги и исходящий остаток.
+//
+// Транзакции читаются из журнала потоком и сразу передаются в w, поэтому выписка за любой
+// период не загружается в память целиком. Период определяется диапазоном идентификаторов
+// журнала, вычисленным в начале формирования, поэтому транзакции, выполненные во время
+// формирования выписки, в неё не попадают.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//   - req: структура dto.StatementReq с адресом кошелька и периодом.
+//   - w: получатель выписки.
+//
+// Возвращает:
+//   - ошибку при некорректном периоде, если кошелёк не найден, при ошибке работы с БД
+//     или ошибку, возвращённую w.
+func (s *TransferService) WriteStatement(ctx context.Context, req dto.StatementReq, w StatementWriter) error {
+	if req.Address == "" {
+		return ErrInvalid.New("wallet address is required")
+	}
+	if !req.From.Before(req.To) {
+		return ErrInvalid.New("statement period start must be before its end")
+	}
+
+	transactionRepo := s.transactionRepository.WithContext(ctx)
+
+	// Время записей хранится с точностью до наносекунды, поэтому «строго до» означает «не позже на 1 нс раньше»
+	openingID, err := transactionRepo.GetLastIDBefore(req.From.Add(-time.Nanosecond))
+	if err != nil {
+		return ErrFailedToGet.Wrap(err, "failed to find transaction by time")
+	}
+	closingID, err := transactionRepo.GetLastIDBefore(req.To.Add(-time.Nanosecond))
+	if err != nil {
+		return ErrFailedToGet.Wrap(err, "failed to find transaction by time")
+	}
+
+	opening, err := s.GetBalanceAt(ctx, dto.BalanceAtReq{Address: req.Address, AsOfTx: &openingID})
+	if err != nil {
+		return err
+	}
+
+	if err := w.WriteHeader(dto.StatementHeader{
+		Address:        req.Address,
+		From:           req.From,
+		To:             req.To,
+		OpeningBalance: opening.Amount,
+		OpeningTxID:    openingID,
+	}); err != nil {
+		return err
+	}
+
+	summary := dto.StatementSummary{
+		ClosingBalance: opening.Amount,
+		ClosingTxID:    closingID,
+		TotalIn:        decimal.Zero,
+		TotalOut:       decimal.Zero,
+		TotalFees:      decimal.Zero,
+	}
+	var writeErr error
+	err = transactionRepo.ForEachWalletRecord(req.Address, openingID, closingID,
+		func(record dto.TransactionRecord) error {
+			entry := dto.StatementEntry{
+				ID:        record.ID,
+				CreatedAt: record.CreatedAt,
+				Kind:      record.Kind,
+				Fee:       statementFee,
+			}
+			if record.To == req.Address {
+				entry.Direction, entry.Counterparty, entry.Amount = dto.StatementDirectionIn, record.From, record.Amount
+				summary.TotalIn = summary.TotalIn.Add(record.Amount)
+			} else {
+				entry.Direction, entry.Counterparty, entry.Amount = dto.StatementDirectionOut, record.To, record.Amount.Neg()
+				summary.TotalOut = summary.TotalOut.Add(record.Amount)
+			}
+
+			summary.ClosingBalance = summary.ClosingBalance.Add(entry.Amount).Sub(entry.Fee)
+			summary.TotalFees = summary.TotalFees.Add(entry.Fee)
+			summary.Count++
+			entry.Balance = summary.ClosingBalance
+			writeErr = w.WriteEntry(entry)
+			return writeErr
+		})
+	if writeErr != nil {
+		return writeErr
+	}
+	if err != nil {
+		return ErrFailedToGet.Wrap(err, "failed to read wallet transactions")
+	}
+
+	return w.WriteSummary(summary)
+}
+
+// distance возвращает расстояние между идентификаторами записей журнала.
+func distance(a, b int64) int64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// GetNonce возвращает последний использованный nonce кошелька по адресу.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//   - req: структура dto.BalanceReq с адресом кошелька.
+//
+// Возвращает:
+//   - dto.NonceResp и ошибку при её возникновении.
+func (s *TransferService) GetNonce(ctx context.Context, req dto.BalanceReq) (dto.NonceResp, error) {
+	return s.walletRepository.WithContext(ctx).GetNonce(req)
 }
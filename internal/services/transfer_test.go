@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/utils"
+	"github.com/joomcode/errorx"
+	"github.com/shopspring/decimal"
+)
+
+// createSnapshot создаёт снимок балансов и проверяет, что он учитывает транзакцию lastTxID.
+func createSnapshot(t *testing.T, service *Service, lastTxID int64) {
+	t.Helper()
+	snapshot, created, err := service.SnapshotService.CreateSnapshot(context.Background())
+	if err != nil || !created {
+		t.Fatalf("CreateSnapshot: created = %t, error = %v", created, err)
+	}
+	if snapshot.LastTxID != lastTxID {
+		t.Fatalf("snapshot covers transaction %d, want %d", snapshot.LastTxID, lastTxID)
+	}
+}
+
+// newSnapshotHistory создаёт кошельки a и b, восемь записей журнала и два снимка: после записи 2
+// и после записи 5. Кошелёк c создаётся записью 4, поэтому в первом снимке его нет.
+func newSnapshotHistory(t *testing.T) (*Service, []dto.TransactionRecord, [3]string) {
+	t.Helper()
+	service, db, wallets := newTestService(t, "100", "50")
+	a, b := wallets[0], wallets[1]
+	var c testWallet
+	c.address, c.privateKey = utils.DeriveWalletKey(testKeySeed, 2)
+
+	send(t, service, a, b.address, "10")
+	send(t, service, b, a.address, "5")
+	createSnapshot(t, service, 2)
+	send(t, service, a, b.address, "20")
+	if _, err := service.TransferService.ImportWallets(context.Background(), dto.ImportWalletsReq{
+		Wallets: []dto.SeedWallet{{Address: c.address, Balance: decimal.NewFromInt(40)}},
+	}); err != nil {
+		t.Fatalf("ImportWallets: %v", err)
+	}
+	send(t, service, b, c.address, "15")
+	createSnapshot(t, service, 5)
+	send(t, service, c, a.address, "25")
+	send(t, service, a, b.address, "1")
+	send(t, service, a, b.address, "1")
+
+	return service, ledgerRecords(t, db), [3]string{a.address, b.address, c.address}
+}
+
+func TestGetBalanceAtAcrossSnapshots(t *testing.T) {
+	service, records, addresses := newSnapshotHistory(t)
+	if len(records) != 8 {
+		t.Fatalf("got %d ledger records, want 8", len(records))
+	}
+
+	// Балансы кошельков a, b и c после транзакции asOfTx
+	tests := []struct {
+		name     string
+		asOfTx   int64
+		balances [3]string
+	}{
+		{"before first transaction", 0, [3]string{"100", "50", "0"}},
+		{"before first snapshot", 1, [3]string{"90", "60", "0"}},
+		{"at first snapshot", 2, [3]string{"95", "55", "0"}},
+		{"after first snapshot", 3, [3]string{"75", "75", "0"}},
+		{"wallet missing from first snapshot", 4, [3]string{"75", "75", "40"}},
+		{"at second snapshot", 5, [3]string{"75", "60", "55"}},
+		{"after second snapshot", 6, [3]string{"100", "60", "30"}},
+		{"before head", 7, [3]string{"99", "61", "30"}},
+		{"head", 8, [3]string{"98", "62", "30"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Момент создания записи asOfTx; до первой записи — час до неё
+			at := records[0].CreatedAt.Add(-time.Hour)
+			if tt.asOfTx > 0 {
+				at = records[tt.asOfTx-1].CreatedAt
+			}
+
+			for i, address := range addresses {
+				requests := map[string]dto.BalanceAtReq{
+					"as_of_tx": {Address: address, AsOfTx: &tt.asOfTx},
+					"at":       {Address: address, At: &at},
+				}
+				for by, req := range requests {
+					resp, err := service.TransferService.GetBalanceAt(context.Background(), req)
+					if err != nil {
+						t.Fatalf("GetBalanceAt by %s: %v", by, err)
+					}
+					if !resp.Amount.Equal(decimal.RequireFromString(tt.balances[i])) {
+						t.Errorf("balance of wallet %c by %s = %s, want %s", 'a'+i, by, resp.Amount, tt.balances[i])
+					}
+					if resp.AsOfTx == nil || *resp.AsOfTx != tt.asOfTx {
+						t.Errorf("balance of wallet %c by %s is as of transaction %v, want %d", 'a'+i, by, resp.AsOfTx, tt.asOfTx)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGetBalanceAtRejectsInvalidRequest(t *testing.T) {
+	service, _, addresses := newSnapshotHistory(t)
+	now := time.Now()
+	negative, future, head := int64(-1), int64(9), int64(8)
+
+	tests := []struct {
+		name string
+		req  dto.BalanceAtReq
+	}{
+		{"neither at nor as_of_tx", dto.BalanceAtReq{Address: addresses[0]}},
+		{"both at and as_of_tx", dto.BalanceAtReq{Address: addresses[0], At: &now, AsOfTx: &head}},
+		{"negative transaction", dto.BalanceAtReq{Address: addresses[0], AsOfTx: &negative}},
+		{"future transaction", dto.BalanceAtReq{Address: addresses[0], AsOfTx: &future}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.TransferService.GetBalanceAt(context.Background(), tt.req); !errorx.IsOfType(err, ErrInvalid) {
+				t.Errorf("error %v, want %s", err, ErrInvalid)
+			}
+		})
+	}
+}
+
+// recordingStatement — получатель выписки, сохраняющий её в памяти.
+type recordingStatement struct {
+	header  dto.StatementHeader
+	entries []dto.StatementEntry
+	summary dto.StatementSummary
+}
+
+// WriteHeader сохраняет начало выписки.
+func (s *recordingStatement) WriteHeader(header dto.StatementHeader) error {
+	s.header = header
+	return nil
+}
+
+// WriteEntry сохраняет строку выписки.
+func (s *recordingStatement) WriteEntry(entry dto.StatementEntry) error {
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+// WriteSummary сохраняет итоги выписки.
+func (s *recordingStatement) WriteSummary(summary dto.StatementSummary) error {
+	s.summary = summary
+	return nil
+}
+
+func TestWriteStatementHalfOpenWindow(t *testing.T) {
+	service, records, addresses := newSnapshotHistory(t)
+
+	// created возвращает время создания записи журнала id
+	created := func(id int64) time.Time { return records[id-1].CreatedAt }
+
+	// Кошелёк a участвует в записях 1, 2, 3, 6, 7 и 8
+	tests := []struct {
+		name      string
+		from, to  time.Time
+		opening   string
+		openingTx int64
+		entries   []int64
+		closing   string
+		closingTx int64
+		in, out   string
+	}{
+		{
+			name: "end at record is excluded",
+			from: created(2), to: created(6),
+			opening: "90", openingTx: 1,
+			entries: []int64{2, 3},
+			closing: "75", closingTx: 5,
+			in: "5", out: "20",
+		},
+		{
+			name: "end one nanosecond after record includes it",
+			from: created(2), to: created(6).Add(time.Nanosecond),
+			opening: "90", openingTx: 1,
+			entries: []int64{2, 3, 6},
+			closing: "100", closingTx: 6,
+			in: "30", out: "20",
+		},
+		{
+			name: "start one nanosecond after record excludes it",
+			from: created(2).Add(time.Nanosecond), to: created(6),
+			opening: "95", openingTx: 2,
+			entries: []int64{3},
+			closing: "75", closingTx: 5,
+			in: "0", out: "20",
+		},
+		{
+			name: "no transactions of wallet",
+			from: created(4), to: created(6),
+			opening: "75", openingTx: 3,
+			closing: "75", closingTx: 5,
+			in: "0", out: "0",
+		},
+		{
+			name: "whole history",
+			from: created(1).Add(-time.Hour), to: created(8).Add(time.Nanosecond),
+			opening: "100", openingTx: 0,
+			entries: []int64{1, 2, 3, 6, 7, 8},
+			closing: "98", closingTx: 8,
+			in: "30", out: "32",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var statement recordingStatement
+			if err := service.TransferService.WriteStatement(context.Background(), dto.StatementReq{
+				Address: addresses[0], From: tt.from, To: tt.to,
+			}, &statement); err != nil {
+				t.Fatalf("WriteStatement: %v", err)
+			}
+
+			header, summary := statement.header, statement.summary
+			if !header.OpeningBalance.Equal(decimal.RequireFromString(tt.opening)) || header.OpeningTxID != tt.openingTx {
+				t.Errorf("opening balance %s as of transaction %d, want %s as of %d",
+					header.OpeningBalance, header.OpeningTxID, tt.opening, tt.openingTx)
+			}
+			if len(statement.entries) != len(tt.entries) {
+				t.Fatalf("got %d entries, want transactions %v", len(statement.entries), tt.entries)
+			}
+			for i, entry := range statement.entries {
+				if entry.ID != tt.entries[i] {
+					t.Errorf("entry %d is transaction %d, want %d", i, entry.ID, tt.entries[i])
+				}
+			}
+			if !summary.ClosingBalance.Equal(decimal.RequireFromString(tt.closing)) || summary.ClosingTxID != tt.closingTx {
+				t.Errorf("closing balance %s as of transaction %d, want %s as of %d",
+					summary.ClosingBalance, summary.ClosingTxID, tt.closing, tt.closingTx)
+			}
+			if !summary.TotalIn.Equal(decimal.RequireFromString(tt.in)) || !summary.TotalOut.Equal(decimal.RequireFromString(tt.out)) {
+				t.Errorf("total in %s, out %s, want %s and %s", summary.TotalIn, summary.TotalOut, tt.in, tt.out)
+			}
+			if summary.Count != int64(len(tt.entries)) {
+				t.Errorf("summary counts %d transactions, want %d", summary.Count, len(tt.entries))
+			}
+		})
+	}
+
+	// Пустой период отклоняется
+	var statement recordingStatement
+	at := created(2)
+	if err := service.TransferService.WriteStatement(context.Background(), dto.StatementReq{
+		Address: addresses[0], From: at, To: at,
+	}, &statement); !errorx.IsOfType(err, ErrInvalid) {
+		t.Errorf("empty period: error %v, want %s", err, ErrInvalid)
+	}
+}
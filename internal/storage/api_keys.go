@@ -0,0 +1,245 @@
+package storage
+
+import (
+	"database/sql"
+	_ "embed"
+	"errors"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"log/slog"
+	"strings"
+)
+
+// APIKeyRepository реализует методы для работы с API-ключами в базе данных.
+//
+// Использует DBExecutor для выполнения SQL-запросов.
+type APIKeyRepository struct {
+	executor DBExecutor
+}
+
+// NewAPIKeyRepository создаёт новый экземпляр APIKeyRepository.
+//
+// Аргументы:
+//   - executor: объект, реализующий интерфейс DBExecutor для выполнения запросов.
+//
+// Возвращает:
+//   - указатель на APIKeyRepository.
+func NewAPIKeyRepository(executor DBExecutor) *APIKeyRepository {
+	return &APIKeyRepository{executor: observe(executor)}
+}
+
+// APIKeyStorageInteractor описывает интерфейс операций с API-ключами.
+type APIKeyStorageInteractor interface {
+	// Insert сохраняет новый ключ и возвращает его идентификатор.
+	Insert(key dto.APIKey) (int64, error)
+	// InsertWallet привязывает кошелёк к ключу.
+	InsertWallet(keyID int64, address string) error
+	// GetByHash возвращает ключ по хешу его значения.
+	GetByHash(hash string) (dto.APIKey, error)
+	// List возвращает все ключи.
+	List() ([]dto.APIKey, error)
+	// Revoke отзывает ключ.
+	Revoke(id int64) error
+}
+
+var (
+	//go:embed assets/api_keys/insert.sql
+	apiKeysInsertSQL string
+
+	//go:embed assets/api_keys/insert_wallet.sql
+	apiKeysInsertWalletSQL string
+
+	//go:embed assets/api_keys/get_by_hash.sql
+	apiKeysGetByHashSQL string
+
+	//go:embed assets/api_keys/get_wallets.sql
+	apiKeysGetWalletsSQL string
+
+	//go:embed assets/api_keys/list.sql
+	apiKeysListSQL string
+
+	//go:embed assets/api_keys/revoke.sql
+	apiKeysRevokeSQL string
+)
+
+// Insert добавляет новый API-ключ в базу данных.
+//
+// Аргументы:
+//   - key: сведения о ключе; используются поля Name, KeyHash, Scopes и AllWallets.
+//
+// Возвращает:
+//   - идентификатор созданного ключа.
+//   - ошибку, если не удалось вставить ключ в базу.
+func (r *APIKeyRepository) Insert(key dto.APIKey) (int64, error) {
+	res, err := r.executor.Exec(apiKeysInsertSQL, key.Name, key.KeyHash, strings.Join(key.Scopes, ","), key.AllWallets)
+	if err != nil {
+		return 0, ErrFailedToInsert.Wrap(err, "failed to insert api key")
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, ErrFailedToInsert.Wrap(err, "failed to get api key id")
+	}
+
+	return id, nil
+}
+
+// InsertWallet привязывает кошелёк к API-ключу, разрешая списание с него.
+//
+// Аргументы:
+//   - keyID: идентификатор ключа.
+//   - address: адрес кошелька.
+//
+// Возвращает:
+//   - ошибку, если не удалось сохранить привязку.
+func (r *APIKeyRepository) InsertWallet(keyID int64, address string) error {
+	if _, err := r.executor.Exec(apiKeysInsertWalletSQL, keyID, address); err != nil {
+		return ErrFailedToInsert.Wrap(err, "failed to bind wallet to api key")
+	}
+	return nil
+}
+
+// GetByHash возвращает API-ключ по хешу его значения вместе со списком привязанных кошельков.
+//
+// Аргументы:
+//   - hash: SHA-256 хеш значения ключа в hex-представлении.
+//
+// Возвращает:
+//   - сведения о ключе.
+//   - ошибку, если ключ не найден или возникла другая проблема.
+func (r *APIKeyRepository) GetByHash(hash string) (dto.APIKey, error) {
+	key, err := scanAPIKey(r.executor.QueryRow(apiKeysGetByHashSQL, hash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.APIKey{}, ErrAPIKeyNotFound.Wrap(err, "api key not found")
+		}
+		return dto.APIKey{}, ErrFailedToGet.Wrap(err, "failed to get api key")
+	}
+
+	if key.Wallets, err = r.getWallets(key.ID); err != nil {
+		return dto.APIKey{}, err
+	}
+
+	return key, nil
+}
+
+// List возвращает все API-ключи, включая отозванные.
+//
+// Возвращает:
+//   - срез ключей.
+//   - ошибку, если произошла проблема с запросом или данными.
+func (r *APIKeyRepository) List() ([]dto.APIKey, error) {
+	rows, err := r.executor.Query(apiKeysListSQL)
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to list api keys")
+	}
+
+	// Обеспечиваем корректное закрытие объекта rows после использования
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.WarnContext(contextOf(r.executor), "failed to close rows", "error", err)
+		}
+	}()
+
+	var keys []dto.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, ErrFailedToUnmarshal.Wrap(err, "failed to unmarshal api key")
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, UnhandledErr.Wrap(err, "error while scanning api keys through sql rows")
+	}
+
+	// Привязанные кошельки загружаем после закрытия курсора
+	for i := range keys {
+		if keys[i].Wallets, err = r.getWallets(keys[i].ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+// Revoke отзывает API-ключ. Отозванный ключ перестаёт проходить аутентификацию.
+//
+// Аргументы:
+//   - id: идентификатор ключа.
+//
+// Возвращает:
+//   - ошибку, если ключ не найден, уже отозван или операция завершилась неуспешно.
+func (r *APIKeyRepository) Revoke(id int64) error {
+	res, err := r.executor.Exec(apiKeysRevokeSQL, id)
+	if err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to revoke api key")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return ErrAPIKeyNotFound.New("active api key not found")
+	}
+
+	return nil
+}
+
+// getWallets возвращает адреса кошельков, привязанных к ключу.
+func (r *APIKeyRepository) getWallets(keyID int64) ([]string, error) {
+	rows, err := r.executor.Query(apiKeysGetWalletsSQL, keyID)
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to get api key wallets")
+	}
+
+	// Обеспечиваем корректное закрытие объекта rows после использования
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			slog.WarnContext(contextOf(r.executor), "failed to close rows", "error", err)
+		}
+	}()
+
+	var wallets []string
+	for rows.Next() {
+		var address string
+		if err := rows.Scan(&address); err != nil {
+			return nil, ErrFailedToUnmarshal.Wrap(err, "failed to unmarshal api key wallet")
+		}
+		wallets = append(wallets, address)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, UnhandledErr.Wrap(err, "error while scanning api key wallets through sql rows")
+	}
+
+	return wallets, nil
+}
+
+// rowScanner — общий интерфейс *sql.Row и *sql.Rows для чтения одной строки.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanAPIKey читает сведения о ключе из строки результата запроса.
+func scanAPIKey(row rowScanner) (dto.APIKey, error) {
+	var (
+		key       dto.APIKey
+		scopes    string
+		revokedAt sql.NullTime
+	)
+	if err := row.Scan(&key.ID, &key.Name, &key.KeyHash, &scopes, &key.AllWallets, &key.CreatedAt, &revokedAt); err != nil {
+		return dto.APIKey{}, err
+	}
+
+	if scopes != "" {
+		key.Scopes = strings.Split(scopes, ",")
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+
+	return key, nil
+}
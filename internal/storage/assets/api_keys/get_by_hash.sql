@@ -0,0 +1 @@
+SELECT id, name, key_hash, scopes, all_wallets, created_at, revoked_at FROM api_keys WHERE key_hash = ?
\ No newline at end of file
@@ -0,0 +1 @@
+SELECT address FROM api_key_wallets WHERE api_key_id = ? ORDER BY address
\ No newline at end of file
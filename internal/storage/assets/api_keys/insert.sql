@@ -0,0 +1 @@
+INSERT INTO api_keys (name, key_hash, scopes, all_wallets) VALUES (?, ?, ?, ?)
\ No newline at end of file
@@ -0,0 +1 @@
+INSERT INTO api_key_wallets (api_key_id, address) VALUES (?, ?)
\ No newline at end of file
@@ -0,0 +1 @@
+UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL
\ No newline at end of file
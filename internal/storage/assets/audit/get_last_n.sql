@@ -0,0 +1 @@
+SELECT id, actor, action, COALESCE(target, ''), COALESCE(details, ''), reason, created_at FROM admin_audit ORDER BY id DESC LIMIT ?
\ No newline at end of file
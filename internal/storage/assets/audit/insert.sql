@@ -0,0 +1 @@
+INSERT INTO admin_audit (actor, action, target, details, reason) VALUES (?, ?, ?, ?, ?)
\ No newline at end of file
@@ -0,0 +1 @@
+SELECT id, last_tx_id, tx_count, head_hash, created_at, public_key, signature FROM ledger_checkpoints ORDER BY id
\ No newline at end of file
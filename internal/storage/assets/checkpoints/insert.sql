@@ -0,0 +1 @@
+INSERT INTO ledger_checkpoints (last_tx_id, tx_count, head_hash, created_at, public_key, signature) VALUES (?, ?, ?, ?, ?, ?)
\ No newline at end of file
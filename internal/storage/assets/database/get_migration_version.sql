@@ -0,0 +1 @@
+SELECT version, dirty FROM schema_migrations LIMIT 1
\ No newline at end of file
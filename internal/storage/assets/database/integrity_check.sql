@@ -0,0 +1 @@
+PRAGMA integrity_check
\ No newline at end of file
@@ -0,0 +1 @@
+UPDATE idempotency_keys SET operation = ?, request_hash = ?, created_at = ? WHERE subject = ? AND key = ? AND status_code IS NULL AND created_at < ?
\ No newline at end of file
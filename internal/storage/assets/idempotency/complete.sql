@@ -0,0 +1 @@
+UPDATE idempotency_keys SET status_code = ?, content_type = ?, body = ? WHERE subject = ? AND key = ?
\ No newline at end of file
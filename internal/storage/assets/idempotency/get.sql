@@ -0,0 +1 @@
+SELECT subject, key, operation, request_hash, COALESCE(status_code, 0), COALESCE(content_type, ''), body, created_at FROM idempotency_keys WHERE subject = ? AND key = ?
\ No newline at end of file
@@ -0,0 +1 @@
+INSERT INTO idempotency_keys (subject, key, operation, request_hash, created_at) VALUES (?, ?, ?, ?, ?) ON CONFLICT (subject, key) DO NOTHING
\ No newline at end of file
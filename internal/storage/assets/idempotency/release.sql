@@ -0,0 +1 @@
+DELETE FROM idempotency_keys WHERE subject = ? AND key = ? AND status_code IS NULL
\ No newline at end of file
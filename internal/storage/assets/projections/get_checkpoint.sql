@@ -0,0 +1 @@
+SELECT last_tx_id FROM projection_checkpoints WHERE name = ?
\ No newline at end of file
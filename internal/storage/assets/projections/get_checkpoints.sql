@@ -0,0 +1 @@
+SELECT name, last_tx_id, updated_at FROM projection_checkpoints ORDER BY name
\ No newline at end of file
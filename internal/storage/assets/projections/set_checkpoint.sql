@@ -0,0 +1 @@
+INSERT INTO projection_checkpoints (name, last_tx_id, updated_at) VALUES (?, ?, ?) ON CONFLICT (name) DO UPDATE SET last_tx_id = excluded.last_tx_id, updated_at = excluded.updated_at
\ No newline at end of file
@@ -0,0 +1 @@
+SELECT balance FROM snapshot_balances WHERE snapshot_id = ? AND address = ?
\ No newline at end of file
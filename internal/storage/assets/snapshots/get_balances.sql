@@ -0,0 +1 @@
+SELECT address, balance FROM snapshot_balances WHERE snapshot_id = ? ORDER BY address
\ No newline at end of file
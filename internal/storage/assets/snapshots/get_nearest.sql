@@ -0,0 +1 @@
+SELECT id, last_tx_id, created_at, wallet_count, total, merkle_root FROM balance_snapshots ORDER BY ABS(last_tx_id - ?), id DESC LIMIT 1
\ No newline at end of file
@@ -0,0 +1 @@
+INSERT INTO balance_snapshots (last_tx_id, created_at, wallet_count, total, merkle_root) VALUES (?, ?, ?, ?, ?)
\ No newline at end of file
@@ -0,0 +1 @@
+INSERT INTO snapshot_balances (snapshot_id, address, balance) VALUES (?, ?, ?)
\ No newline at end of file
@@ -0,0 +1 @@
+DELETE FROM counterparty_stats
\ No newline at end of file
@@ -0,0 +1 @@
+DELETE FROM daily_volumes
\ No newline at end of file
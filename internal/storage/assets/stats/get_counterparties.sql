@@ -0,0 +1 @@
+SELECT counterparty, sent_count, sent_volume, received_count, received_volume FROM counterparty_stats WHERE address = ? ORDER BY sent_count + received_count DESC, counterparty LIMIT ?
\ No newline at end of file
@@ -0,0 +1 @@
+SELECT sent_count, sent_volume, received_count, received_volume FROM counterparty_stats WHERE address = ? AND counterparty = ?
\ No newline at end of file
@@ -0,0 +1 @@
+SELECT count, volume FROM daily_volumes WHERE day = ? AND kind = ?
\ No newline at end of file
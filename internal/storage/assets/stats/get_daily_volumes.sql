@@ -0,0 +1 @@
+SELECT day, kind, count, volume FROM daily_volumes WHERE day >= ? AND day <= ? ORDER BY day, kind
\ No newline at end of file
@@ -0,0 +1 @@
+INSERT INTO counterparty_stats (address, counterparty, sent_count, sent_volume, received_count, received_volume) VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT (address, counterparty) DO UPDATE SET sent_count = excluded.sent_count, sent_volume = excluded.sent_volume, received_count = excluded.received_count, received_volume = excluded.received_volume
\ No newline at end of file
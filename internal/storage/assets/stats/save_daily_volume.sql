@@ -0,0 +1 @@
+INSERT INTO daily_volumes (day, kind, count, volume) VALUES (?, ?, ?, ?) ON CONFLICT (day, kind) DO UPDATE SET count = excluded.count, volume = excluded.volume
\ No newline at end of file
@@ -0,0 +1 @@
+SELECT COUNT(*) FROM transactions WHERE hash IS NOT NULL
\ No newline at end of file
@@ -0,0 +1 @@
+SELECT id, COALESCE(hash, ''), (SELECT COUNT(*) FROM transactions) FROM transactions ORDER BY id DESC LIMIT 1
\ No newline at end of file
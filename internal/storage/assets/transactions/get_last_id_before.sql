@@ -0,0 +1 @@
+SELECT COALESCE(MAX(id), 0) FROM transactions WHERE created_at <= ?
\ No newline at end of file
@@ -1 +1 @@
-SELECT from_address, to_address, amount, created_at FROM transactions ORDER BY created_at DESC LIMIT ?
\ No newline at end of file
+SELECT kind, COALESCE(from_address, ''), COALESCE(to_address, ''), amount, created_at FROM transactions ORDER BY created_at DESC, id DESC LIMIT ?
\ No newline at end of file
@@ -0,0 +1 @@
+SELECT id, kind, COALESCE(from_address, ''), COALESCE(to_address, ''), amount, COALESCE(nonce, 0), COALESCE(signature, ''), COALESCE(initiated_by, ''), created_at, COALESCE(prev_hash, ''), COALESCE(hash, '') FROM transactions WHERE id > ? ORDER BY id
\ No newline at end of file
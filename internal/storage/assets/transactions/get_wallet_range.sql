@@ -0,0 +1 @@
+SELECT COALESCE(from_address, ''), COALESCE(to_address, ''), amount FROM transactions WHERE id > ? AND id <= ? AND (from_address = ? OR to_address = ?)
\ No newline at end of file
@@ -0,0 +1 @@
+SELECT id, kind, COALESCE(from_address, ''), COALESCE(to_address, ''), amount, created_at FROM transactions WHERE id > ? AND id <= ? AND (from_address = ? OR to_address = ?) ORDER BY id
\ No newline at end of file
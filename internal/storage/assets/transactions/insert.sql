@@ -1 +1 @@
-INSERT INTO transactions (from_address, to_address, amount) VALUES (?, ?, ?)
\ No newline at end of file
+INSERT INTO transactions (kind, from_address, to_address, amount, nonce, signature, initiated_by, created_at, prev_hash, hash) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
\ No newline at end of file
@@ -0,0 +1 @@
+UPDATE transactions SET prev_hash = ?, hash = ? WHERE id = ?
\ No newline at end of file
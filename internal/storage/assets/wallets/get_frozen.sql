@@ -0,0 +1 @@
+SELECT frozen FROM wallets WHERE address = ?
\ No newline at end of file
@@ -0,0 +1 @@
+SELECT nonce FROM wallets WHERE address = ?
\ No newline at end of file
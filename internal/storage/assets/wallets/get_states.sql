@@ -0,0 +1 @@
+SELECT address, balance, initial_balance FROM wallets ORDER BY address
\ No newline at end of file
@@ -1 +1 @@
-INSERT INTO wallets (address, balance) VALUES (?, ?)
\ No newline at end of file
+INSERT INTO wallets (address, balance, initial_balance) VALUES (?, ?, ?)
\ No newline at end of file
@@ -0,0 +1 @@
+UPDATE wallets SET frozen = ? WHERE address = ?
\ No newline at end of file
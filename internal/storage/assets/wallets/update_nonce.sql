@@ -0,0 +1 @@
+UPDATE wallets SET nonce = ? WHERE address = ?
\ No newline at end of file
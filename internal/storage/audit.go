@@ -0,0 +1,108 @@
+package storage
+
+import (
+	_ "embed"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"log/slog"
+)
+
+// AuditRepository реализует методы для работы с журналом административных действий.
+//
+// Использует DBExecutor для выполнения SQL-запросов.
+type AuditRepository struct {
+	executor DBExecutor
+}
+
+// NewAuditRepository создаёт новый экземпляр AuditRepository.
+//
+// Аргументы:
+//   - executor: объект, реализующий интерфейс DBExecutor для выполнения запросов.
+//
+// Возвращает:
+//   - указатель на AuditRepository.
+func NewAuditRepository(executor DBExecutor) *AuditRepository {
+	return &AuditRepository{executor: observe(executor)}
+}
+
+// AuditStorageInteractor описывает интерфейс операций с журналом административных действий.
+type AuditStorageInteractor interface {
+	// Insert добавляет запись в журнал и возвращает её идентификатор.
+	Insert(entry dto.AuditEntry) (int64, error)
+	// GetLastN возвращает последние n записей журнала.
+	GetLastN(n int) ([]dto.AuditEntry, error)
+}
+
+var (
+	//go:embed assets/audit/insert.sql
+	auditInsertSQL string
+
+	//go:embed assets/audit/get_last_n.sql
+	auditGetLastNSQL string
+)
+
+// Insert добавляет запись в журнал административных действий.
+//
+// Аргументы:
+//   - entry: запись журнала; используются поля Actor, Action, Target, Details и Reason.
+//
+// Возвращает:
+//   - идентификатор созданной записи.
+//   - ошибку, если не удалось вставить запись в базу.
+func (r *AuditRepository) Insert(entry dto.AuditEntry) (int64, error) {
+	res, err := r.executor.Exec(auditInsertSQL,
+		entry.Actor, entry.Action, nullString(entry.Target), nullString(entry.Details), entry.Reason)
+	if err != nil {
+		return 0, ErrFailedToInsert.Wrap(err, "failed to insert audit entry")
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, ErrFailedToInsert.Wrap(err, "failed to get audit entry id")
+	}
+	return id, nil
+}
+
+// GetLastN возвращает последние n записей журнала административных действий.
+//
+// Аргументы:
+//   - n: количество записей.
+//
+// Возвращает:
+//   - срез записей, начиная с самой новой (пустой, если журнал пуст).
+//   - ошибку, если произошла проблема с запросом или данными.
+func (r *AuditRepository) GetLastN(n int) ([]dto.AuditEntry, error) {
+	rows, err := r.executor.Query(auditGetLastNSQL, n)
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to get last %d audit entries", n)
+	}
+
+	// Обеспечиваем корректное закрытие объекта rows после использования
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.WarnContext(contextOf(r.executor), "failed to close rows", "error", err)
+		}
+	}()
+
+	entries := make([]dto.AuditEntry, 0, n)
+	for rows.Next() {
+		var entry dto.AuditEntry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.Actor,
+			&entry.Action,
+			&entry.Target,
+			&entry.Details,
+			&entry.Reason,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, ErrFailedToUnmarshal.Wrap(err, "failed to unmarshal audit entry")
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, UnhandledErr.Wrap(err, "error while scanning audit entries")
+	}
+
+	return entries, nil
+}
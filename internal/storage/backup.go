@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"github.com/mattn/go-sqlite3"
+	"log/slog"
+	"os"
+	"time"
+)
+
+//go:embed assets/database/integrity_check.sql
+var databaseIntegrityCheckSQL string
+
+// backupRetryDelay — пауза перед повтором шага копирования, если база данных занята записью.
+const backupRetryDelay = 50 * time.Millisecond
+
+// Backup создаёт резервную копию базы данных в новом файле через online backup API SQLite.
+//
+// Копия согласована на момент завершения копирования и создаётся без остановки сервера:
+// база копируется за один шаг, а если она в этот момент занята пишущей транзакцией,
+// шаг повторяется до её завершения. Копия сначала записывается во временный файл
+// и переименовывается только после успешного завершения.
+//
+// Аргументы:
+//   - ctx: контекст, отмена которого прерывает ожидание занятой базы данных.
+//   - db: подключение к копируемой базе данных.
+//   - path: путь к файлу копии; файл не должен существовать.
+//
+// Возвращает:
+//   - ошибку, если файл уже существует или копирование не удалось.
+func Backup(ctx context.Context, db *sql.DB, path string) (err error) {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("backup file %s already exists", path)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.Remove(tmpPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	dest, err := sql.Open("sqlite3", tmpPath)
+	if err != nil {
+		return err
+	}
+
+	// Удаляем неполную копию при любой ошибке
+	defer func() {
+		if closeErr := dest.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			if rmErr := os.Remove(tmpPath); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+				slog.Warn("failed to remove incomplete backup", "path", tmpPath, "error", rmErr)
+			}
+		}
+	}()
+
+	if err := CopyDatabase(ctx, dest, db); err != nil {
+		return err
+	}
+	if err := dest.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// CopyDatabase копирует содержимое базы данных src в базу данных dest через online backup API SQLite,
+// полностью заменяя содержимое dest.
+//
+// Аргументы:
+//   - ctx: контекст, отмена которого прерывает ожидание занятой базы данных.
+//   - dest: подключение к базе данных, в которую выполняется копирование.
+//   - src: подключение к копируемой базе данных.
+//
+// Возвращает:
+//   - ошибку, если копирование не удалось.
+func CopyDatabase(ctx context.Context, dest, src *sql.DB) error {
+	destConn, err := dest.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeConn(destConn)
+
+	srcConn, err := src.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeConn(srcConn)
+
+	return destConn.Raw(func(destDriverConn any) error {
+		return srcConn.Raw(func(srcDriverConn any) error {
+			destSQLite, ok := destDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected driver connection %T", destDriverConn)
+			}
+			srcSQLite, ok := srcDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected driver connection %T", srcDriverConn)
+			}
+
+			backup, err := destSQLite.Backup("main", srcSQLite, "main")
+			if err != nil {
+				return err
+			}
+			return stepBackup(ctx, backup)
+		})
+	})
+}
+
+// stepBackup копирует все страницы базы данных за один шаг, повторяя его, пока база занята,
+// и завершает копирование.
+func stepBackup(ctx context.Context, backup *sqlite3.SQLiteBackup) error {
+	for {
+		done, err := backup.Step(-1)
+		if err != nil {
+			_ = backup.Finish()
+			return err
+		}
+		if done {
+			return backup.Finish()
+		}
+
+		select {
+		case <-ctx.Done():
+			_ = backup.Finish()
+			return ctx.Err()
+		case <-time.After(backupRetryDelay):
+		}
+	}
+}
+
+// IntegrityCheck проверяет целостность файла базы данных командой PRAGMA integrity_check.
+//
+// Аргументы:
+//   - executor: объект, реализующий интерфейс DBExecutor для выполнения запросов.
+//
+// Возвращает:
+//   - найденные нарушения (пустой срез, если база данных цела) и ошибку при сбое запроса.
+func IntegrityCheck(executor DBExecutor) ([]string, error) {
+	rows, err := observe(executor).Query(databaseIntegrityCheckSQL)
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to check database integrity")
+	}
+
+	// Обеспечиваем корректное закрытие объекта rows после использования
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.WarnContext(contextOf(executor), "failed to close rows", "error", err)
+		}
+	}()
+
+	problems := make([]string, 0)
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			return nil, ErrFailedToUnmarshal.Wrap(err, "failed to unmarshal integrity check result")
+		}
+		if result != "ok" {
+			problems = append(problems, result)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, UnhandledErr.Wrap(err, "error while scanning integrity check results")
+	}
+	return problems, nil
+}
+
+// closeConn возвращает соединение в пул.
+func closeConn(conn *sql.Conn) {
+	if err := conn.Close(); err != nil {
+		slog.Warn("failed to close connection", "error", err)
+	}
+}
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	_ "embed"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"log/slog"
+)
+
+// CheckpointRepository реализует методы для работы с контрольными точками журнала транзакций.
+//
+// Использует DBExecutor для выполнения SQL-запросов.
+type CheckpointRepository struct {
+	executor DBExecutor
+}
+
+// NewCheckpointRepository создаёт новый экземпляр CheckpointRepository.
+//
+// Аргументы:
+//   - executor: объект, реализующий интерфейс DBExecutor для выполнения запросов.
+//
+// Возвращает:
+//   - указатель на CheckpointRepository.
+func NewCheckpointRepository(executor DBExecutor) *CheckpointRepository {
+	return &CheckpointRepository{executor: observe(executor)}
+}
+
+// CheckpointStorageInteractor описывает интерфейс операций с контрольными точками журнала.
+type CheckpointStorageInteractor interface {
+	// WithContext возвращает репозиторий, выполняющий запросы с контекстом ctx (см. storage.WithContext).
+	WithContext(ctx context.Context) CheckpointStorageInteractor
+	// Insert сохраняет контрольную точку и возвращает её идентификатор.
+	Insert(checkpoint dto.Checkpoint) (int64, error)
+	// GetLastN возвращает последние n контрольных точек.
+	GetLastN(n int) ([]dto.Checkpoint, error)
+	// GetAll возвращает все контрольные точки в порядке создания.
+	GetAll() ([]dto.Checkpoint, error)
+}
+
+var (
+	//go:embed assets/checkpoints/insert.sql
+	checkpointsInsertSQL string
+
+	//go:embed assets/checkpoints/get_last_n.sql
+	checkpointsGetLastNSQL string
+
+	//go:embed assets/checkpoints/get_all.sql
+	checkpointsGetAllSQL string
+)
+
+// WithContext возвращает репозиторий контрольных точек, выполняющий запросы с контекстом ctx.
+func (r *CheckpointRepository) WithContext(ctx context.Context) CheckpointStorageInteractor {
+	return NewCheckpointRepository(WithContext(ctx, r.executor))
+}
+
+// Insert добавляет контрольную точку в базу данных.
+//
+// Аргументы:
+//   - checkpoint: контрольная точка; поле ID не используется.
+//
+// Возвращает:
+//   - идентификатор созданной контрольной точки.
+//   - ошибку, если не удалось вставить запись в базу.
+func (r *CheckpointRepository) Insert(checkpoint dto.Checkpoint) (int64, error) {
+	res, err := r.executor.Exec(checkpointsInsertSQL,
+		checkpoint.LastTxID,
+		checkpoint.TxCount,
+		checkpoint.HeadHash,
+		checkpoint.CreatedAt,
+		checkpoint.PublicKey,
+		checkpoint.Signature,
+	)
+	if err != nil {
+		return 0, ErrFailedToInsert.Wrap(err, "failed to insert checkpoint")
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, ErrFailedToInsert.Wrap(err, "failed to get checkpoint id")
+	}
+	return id, nil
+}
+
+// GetLastN возвращает последние n контрольных точек, начиная с самой новой.
+//
+// Аргументы:
+//   - n: количество контрольных точек.
+//
+// Возвращает:
+//   - срез контрольных точек (пустой, если их нет) и ошибку при сбое запроса.
+func (r *CheckpointRepository) GetLastN(n int) ([]dto.Checkpoint, error) {
+	return r.query(checkpointsGetLastNSQL, n)
+}
+
+// GetAll возвращает все контрольные точки в порядке создания.
+//
+// Возвращает:
+//   - срез контрольных точек (пустой, если их нет) и ошибку при сбое запроса.
+func (r *CheckpointRepository) GetAll() ([]dto.Checkpoint, error) {
+	return r.query(checkpointsGetAllSQL)
+}
+
+// query выполняет запрос и считывает контрольные точки из результата.
+func (r *CheckpointRepository) query(query string, args ...interface{}) ([]dto.Checkpoint, error) {
+	rows, err := r.executor.Query(query, args...)
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to get checkpoints")
+	}
+
+	// Обеспечиваем корректное закрытие объекта rows после использования
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.WarnContext(contextOf(r.executor), "failed to close rows", "error", err)
+		}
+	}()
+
+	checkpoints := make([]dto.Checkpoint, 0)
+	for rows.Next() {
+		var checkpoint dto.Checkpoint
+		if err := rows.Scan(
+			&checkpoint.ID,
+			&checkpoint.LastTxID,
+			&checkpoint.TxCount,
+			&checkpoint.HeadHash,
+			&checkpoint.CreatedAt,
+			&checkpoint.PublicKey,
+			&checkpoint.Signature,
+		); err != nil {
+			return nil, ErrFailedToUnmarshal.Wrap(err, "failed to unmarshal checkpoint")
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, UnhandledErr.Wrap(err, "error while scanning checkpoints")
+	}
+	return checkpoints, nil
+}
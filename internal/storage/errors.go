@@ -43,6 +43,16 @@ var (
 	External = errorx.RegisterTrait("external")
 	// ErrNotFound ошибка "запись не найдена" с признаками External и NotFound.
 	ErrNotFound = Namespace.NewType("not_found", External, errorx.NotFound())
+	// ErrWalletNotFound ошибка "кошелёк не найден", подтип ErrNotFound.
+	ErrWalletNotFound = ErrNotFound.NewSubtype("wallet")
+	// ErrTransactionsNotFound ошибка "транзакции не найдены", подтип ErrNotFound.
+	ErrTransactionsNotFound = ErrNotFound.NewSubtype("transactions")
+	// ErrAPIKeyNotFound ошибка "API-ключ не найден", подтип ErrNotFound.
+	ErrAPIKeyNotFound = ErrNotFound.NewSubtype("api_key")
+	// ErrSnapshotNotFound ошибка "снимок балансов не найден", подтип ErrNotFound.
+	ErrSnapshotNotFound = ErrNotFound.NewSubtype("snapshot")
+	// ErrIdempotencyKeyNotFound ошибка "ключ идемпотентности не найден", подтип ErrNotFound.
+	ErrIdempotencyKeyNotFound = ErrNotFound.NewSubtype("idempotency_key")
 
 	// Internal признак внутренних ошибок, связанных с хранилищем.
 	Internal = errorx.RegisterTrait("internal")
@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"time"
+)
+
+// IdempotencyRepository реализует методы для работы с ключами идемпотентности запросов.
+//
+// Использует DBExecutor для выполнения SQL-запросов.
+type IdempotencyRepository struct {
+	executor DBExecutor
+}
+
+// NewIdempotencyRepository создаёт новый экземпляр IdempotencyRepository.
+//
+// Аргументы:
+//   - executor: объект, реализующий интерфейс DBExecutor для выполнения запросов.
+//
+// Возвращает:
+//   - указатель на IdempotencyRepository.
+func NewIdempotencyRepository(executor DBExecutor) *IdempotencyRepository {
+	return &IdempotencyRepository{executor: observe(executor)}
+}
+
+// IdempotencyStorageInteractor описывает интерфейс операций с ключами идемпотентности.
+type IdempotencyStorageInteractor interface {
+	// WithContext возвращает репозиторий, выполняющий запросы с контекстом ctx (см. storage.WithContext).
+	WithContext(ctx context.Context) IdempotencyStorageInteractor
+	// Insert сохраняет ключ запроса, если у клиента ещё нет такого ключа.
+	Insert(record dto.IdempotencyRecord) (bool, error)
+	// Get возвращает запрос с ключом и сохранённый ответ на него.
+	Get(subject, key string) (dto.IdempotencyRecord, error)
+	// Acquire передаёт ключ необработанного запроса, сохранённого до момента staleBefore, новому запросу.
+	Acquire(record dto.IdempotencyRecord, staleBefore time.Time) (bool, error)
+	// Complete сохраняет ответ на запрос с ключом.
+	Complete(record dto.IdempotencyRecord) error
+	// Release удаляет ключ запроса, ответ на который не сохранён.
+	Release(subject, key string) error
+	// DeleteBefore удаляет ключи, сохранённые до момента before.
+	DeleteBefore(before time.Time) (int64, error)
+}
+
+var (
+	//go:embed assets/idempotency/insert.sql
+	idempotencyInsertSQL string
+
+	//go:embed assets/idempotency/get.sql
+	idempotencyGetSQL string
+
+	//go:embed assets/idempotency/acquire.sql
+	idempotencyAcquireSQL string
+
+	//go:embed assets/idempotency/complete.sql
+	idempotencyCompleteSQL string
+
+	//go:embed assets/idempotency/release.sql
+	idempotencyReleaseSQL string
+
+	//go:embed assets/idempotency/delete_before.sql
+	idempotencyDeleteBeforeSQL string
+)
+
+// WithContext возвращает репозиторий ключей идемпотентности, выполняющий запросы с контекстом ctx.
+func (r *IdempotencyRepository) WithContext(ctx context.Context) IdempotencyStorageInteractor {
+	return NewIdempotencyRepository(WithContext(ctx, r.executor))
+}
+
+// Insert сохраняет ключ идемпотентности нового запроса без ответа.
+//
+// Аргументы:
+//   - record: запрос; используются поля Subject, Key, Operation, RequestHash и CreatedAt.
+//
+// Возвращает:
+//   - true, если ключ сохранён; false, если у клиента уже есть запрос с таким ключом.
+//   - ошибку, если запрос к базе завершился неуспешно.
+func (r *IdempotencyRepository) Insert(record dto.IdempotencyRecord) (bool, error) {
+	res, err := r.executor.Exec(idempotencyInsertSQL,
+		record.Subject, record.Key, record.Operation, record.RequestHash, record.CreatedAt)
+	if err != nil {
+		return false, ErrFailedToInsert.Wrap(err, "failed to insert idempotency key")
+	}
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return false, ErrFailedToInsert.Wrap(err, "failed to get number of inserted idempotency keys")
+	}
+	return inserted > 0, nil
+}
+
+// Get возвращает запрос с ключом идемпотентности и сохранённый ответ на него.
+//
+// Аргументы:
+//   - subject: клиент, отправивший запрос.
+//   - key: ключ идемпотентности.
+//
+// Возвращает:
+//   - запрос; StatusCode равен 0, если ответ ещё не сохранён.
+//   - ошибку ErrIdempotencyKeyNotFound, если ключа нет, или иную ошибку при сбое запроса.
+func (r *IdempotencyRepository) Get(subject, key string) (dto.IdempotencyRecord, error) {
+	var record dto.IdempotencyRecord
+	if err := r.executor.QueryRow(idempotencyGetSQL, subject, key).Scan(
+		&record.Subject,
+		&record.Key,
+		&record.Operation,
+		&record.RequestHash,
+		&record.StatusCode,
+		&record.ContentType,
+		&record.Body,
+		&record.CreatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.IdempotencyRecord{}, ErrIdempotencyKeyNotFound.New("idempotency key not found")
+		}
+		return dto.IdempotencyRecord{}, ErrFailedToGet.Wrap(err, "failed to get idempotency key")
+	}
+	return record, nil
+}
+
+// Acquire передаёт ключ запроса, ответ на который не был сохранён (например, сервер остановился
+// во время его обработки), новому запросу с этим ключом.
+//
+// Аргументы:
+//   - record: новый запрос; используются поля Subject, Key, Operation, RequestHash и CreatedAt.
+//   - staleBefore: ключ передаётся, только если прежний запрос сохранён раньше этого момента.
+//
+// Возвращает:
+//   - true, если ключ передан новому запросу.
+//   - ошибку, если запрос к базе завершился неуспешно.
+func (r *IdempotencyRepository) Acquire(record dto.IdempotencyRecord, staleBefore time.Time) (bool, error) {
+	res, err := r.executor.Exec(idempotencyAcquireSQL,
+		record.Operation, record.RequestHash, record.CreatedAt, record.Subject, record.Key, staleBefore)
+	if err != nil {
+		return false, ErrFailedToUpdate.Wrap(err, "failed to acquire idempotency key")
+	}
+	acquired, err := res.RowsAffected()
+	if err != nil {
+		return false, ErrFailedToUpdate.Wrap(err, "failed to get number of acquired idempotency keys")
+	}
+	return acquired > 0, nil
+}
+
+// Complete сохраняет ответ на запрос с ключом идемпотентности.
+//
+// Аргументы:
+//   - record: запрос; используются поля Subject, Key, StatusCode, ContentType и Body.
+//
+// Возвращает:
+//   - ошибку, если запрос к базе завершился неуспешно.
+func (r *IdempotencyRepository) Complete(record dto.IdempotencyRecord) error {
+	if _, err := r.executor.Exec(idempotencyCompleteSQL,
+		record.StatusCode, record.ContentType, record.Body, record.Subject, record.Key,
+	); err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to save response of idempotency key")
+	}
+	return nil
+}
+
+// Release удаляет ключ идемпотентности запроса, ответ на который не сохранён,
+// чтобы клиент мог повторить запрос с тем же ключом.
+//
+// Аргументы:
+//   - subject: клиент, отправивший запрос.
+//   - key: ключ идемпотентности.
+//
+// Возвращает:
+//   - ошибку, если запрос к базе завершился неуспешно.
+func (r *IdempotencyRepository) Release(subject, key string) error {
+	if _, err := r.executor.Exec(idempotencyReleaseSQL, subject, key); err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to release idempotency key")
+	}
+	return nil
+}
+
+// DeleteBefore удаляет ключи идемпотентности, сохранённые до момента before.
+//
+// Аргументы:
+//   - before: момент, до которого ключи считаются устаревшими.
+//
+// Возвращает:
+//   - количество удалённых ключей и ошибку, если запрос к базе завершился неуспешно.
+func (r *IdempotencyRepository) DeleteBefore(before time.Time) (int64, error) {
+	res, err := r.executor.Exec(idempotencyDeleteBeforeSQL, before)
+	if err != nil {
+		return 0, ErrFailedToUpdate.Wrap(err, "failed to delete expired idempotency keys")
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, ErrFailedToUpdate.Wrap(err, "failed to get number of deleted idempotency keys")
+	}
+	return deleted, nil
+}
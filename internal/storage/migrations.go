@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"database/sql"
+	_ "embed"
+	"errors"
+)
+
+//go:embed assets/database/get_migration_version.sql
+var databaseGetMigrationVersionSQL string
+
+// MigrationVersion возвращает версию схемы базы данных из таблицы schema_migrations,
+// которую ведёт golang-migrate.
+//
+// Аргументы:
+//   - executor: объект, реализующий интерфейс DBExecutor для выполнения запросов.
+//
+// Возвращает:
+//   - версию последней применённой миграции (0, если миграции не применялись);
+//   - признак незавершённой (сбойной) миграции;
+//   - ошибку при сбое запроса.
+func MigrationVersion(executor DBExecutor) (version uint, dirty bool, err error) {
+	err = observe(executor).QueryRow(databaseGetMigrationVersionSQL).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, ErrFailedToGet.Wrap(err, "failed to get migration version")
+	}
+	return version, dirty, nil
+}
@@ -0,0 +1,222 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"io/fs"
+	"path"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// UnknownQuery — имя запроса, текст которого не найден среди SQL-файлов assets.
+	UnknownQuery = "unknown"
+	// CommitQuery — имя, под которым наблюдателям передаётся фиксация транзакции (см. Commit).
+	CommitQuery = "commit"
+)
+
+// QueryObserver вызывается перед выполнением каждого SQL-запроса репозиториями, в том числе
+// внутри транзакций, и получает имя запроса.
+//
+// Аргументы:
+//   - ctx: контекст, привязанный к исполнителю запросов через WithContext, или context.Background().
+//   - name: имя SQL-файла запроса без расширения относительно assets, например wallets/get_balance,
+//     UnknownQuery или CommitQuery.
+//
+// Возвращает:
+//   - функцию, вызываемую по завершении запроса с его ошибкой: для Query и QueryRow — после получения
+//     первых строк, для Prepare — после подготовки оператора.
+type QueryObserver func(ctx context.Context, name string) (done func(err error))
+
+var (
+	//go:embed assets
+	assets embed.FS
+
+	// queryNames сопоставляет текст запроса с именем его SQL-файла
+	queryNames = loadQueryNames()
+
+	// queryObservers — наблюдатели, установленные ObserveQueries
+	queryObservers atomic.Pointer[[]QueryObserver]
+)
+
+// ObserveQueries устанавливает наблюдателей SQL-запросов, заменяя ранее установленных.
+// Вызов без аргументов отключает наблюдение.
+func ObserveQueries(observers ...QueryObserver) {
+	queryObservers.Store(&observers)
+}
+
+// QueryName возвращает имя SQL-файла запроса без расширения относительно assets
+// или UnknownQuery, если запрос не загружен из assets.
+func QueryName(query string) string {
+	if name, ok := queryNames[strings.TrimSpace(query)]; ok {
+		return name
+	}
+	return UnknownQuery
+}
+
+// loadQueryNames читает все SQL-файлы assets и сопоставляет их текст с именами файлов.
+func loadQueryNames() map[string]string {
+	names := make(map[string]string)
+	_ = fs.WalkDir(assets, "assets", func(file string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || path.Ext(file) != ".sql" {
+			return err
+		}
+		content, err := assets.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		names[strings.TrimSpace(string(content))] = strings.TrimSuffix(strings.TrimPrefix(file, "assets/"), ".sql")
+		return nil
+	})
+	return names
+}
+
+// contextExecutor — исполнитель запросов, принимающий контекст; его реализуют *sql.DB, *sql.Tx, *sql.Conn и WriteTx.
+type contextExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// observedExecutor выполняет запросы с привязанным контекстом и сообщает о них наблюдателям,
+// установленным ObserveQueries.
+type observedExecutor struct {
+	executor DBExecutor
+	ctx      context.Context
+}
+
+// WithContext привязывает контекст к исполнителю запросов: запросы репозиториев, созданных
+// с таким исполнителем, выполняются с этим контекстом (отмена контекста прерывает запрос),
+// а наблюдатели получают его вместе с именем запроса.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//   - executor: *sql.DB, *sql.Tx или другой исполнитель запросов.
+//
+// Возвращает:
+//   - исполнитель запросов с привязанным контекстом.
+func WithContext(ctx context.Context, executor DBExecutor) DBExecutor {
+	if observed, ok := executor.(observedExecutor); ok {
+		executor = observed.executor
+	}
+	return observedExecutor{executor: executor, ctx: ctx}
+}
+
+// observe оборачивает executor для передачи сведений о запросах наблюдателям.
+// Исполнитель с уже привязанным контекстом возвращается без изменений. Наблюдатели определяются
+// в момент выполнения запроса, поэтому их можно установить после создания репозиториев.
+func observe(executor DBExecutor) DBExecutor {
+	if _, ok := executor.(observedExecutor); ok {
+		return executor
+	}
+	return observedExecutor{executor: executor, ctx: context.Background()}
+}
+
+// contextOf возвращает контекст, привязанный к исполнителю запросов, или context.Background().
+// Используется для записей журнала, сделанных репозиториями.
+func contextOf(executor DBExecutor) context.Context {
+	if observed, ok := executor.(observedExecutor); ok {
+		return observed.ctx
+	}
+	return context.Background()
+}
+
+// Exec выполняет команду и сообщает о ней наблюдателям.
+func (e observedExecutor) Exec(query string, args ...interface{}) (sql.Result, error) {
+	done := notify(e.ctx, QueryName(query))
+	var (
+		result sql.Result
+		err    error
+	)
+	if executor, ok := e.executor.(contextExecutor); ok {
+		result, err = executor.ExecContext(e.ctx, query, args...)
+	} else {
+		result, err = e.executor.Exec(query, args...)
+	}
+	done(err)
+	return result, err
+}
+
+// Query выполняет запрос и сообщает о нём наблюдателям.
+func (e observedExecutor) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	done := notify(e.ctx, QueryName(query))
+	var (
+		rows *sql.Rows
+		err  error
+	)
+	if executor, ok := e.executor.(contextExecutor); ok {
+		rows, err = executor.QueryContext(e.ctx, query, args...)
+	} else {
+		rows, err = e.executor.Query(query, args...)
+	}
+	done(err)
+	return rows, err
+}
+
+// QueryRow выполняет запрос одной строки и сообщает о нём наблюдателям.
+func (e observedExecutor) QueryRow(query string, args ...interface{}) *sql.Row {
+	done := notify(e.ctx, QueryName(query))
+	var row *sql.Row
+	if executor, ok := e.executor.(contextExecutor); ok {
+		row = executor.QueryRowContext(e.ctx, query, args...)
+	} else {
+		row = e.executor.QueryRow(query, args...)
+	}
+	done(row.Err())
+	return row
+}
+
+// Prepare подготавливает оператор и сообщает о подготовке наблюдателям.
+func (e observedExecutor) Prepare(query string) (*sql.Stmt, error) {
+	done := notify(e.ctx, QueryName(query))
+	var (
+		stmt *sql.Stmt
+		err  error
+	)
+	if executor, ok := e.executor.(contextExecutor); ok {
+		stmt, err = executor.PrepareContext(e.ctx, query)
+	} else {
+		stmt, err = e.executor.Prepare(query)
+	}
+	done(err)
+	return stmt, err
+}
+
+// Commit фиксирует транзакцию и сообщает о фиксации наблюдателям под именем CommitQuery.
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//   - tx: фиксируемая транзакция.
+//
+// Возвращает:
+//   - ошибку фиксации.
+func Commit(ctx context.Context, tx Tx) error {
+	done := notify(ctx, CommitQuery)
+	err := tx.Commit()
+	done(err)
+	return err
+}
+
+// notify сообщает наблюдателям о начале запроса.
+//
+// Возвращает:
+//   - функцию, сообщающую наблюдателям о завершении запроса.
+func notify(ctx context.Context, name string) func(err error) {
+	observers := queryObservers.Load()
+	if observers == nil || len(*observers) == 0 {
+		return func(error) {}
+	}
+
+	done := make([]func(error), len(*observers))
+	for i, observer := range *observers {
+		done[i] = observer(ctx, name)
+	}
+	return func(err error) {
+		for _, finish := range done {
+			finish(err)
+		}
+	}
+}
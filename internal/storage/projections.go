@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"database/sql"
+	_ "embed"
+	"errors"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"log/slog"
+)
+
+// ProjectionRepository реализует методы для работы с контрольными точками проекций журнала транзакций.
+//
+// Использует DBExecutor для выполнения SQL-запросов.
+type ProjectionRepository struct {
+	executor DBExecutor
+}
+
+// NewProjectionRepository создаёт новый экземпляр ProjectionRepository.
+//
+// Аргументы:
+//   - executor: объект, реализующий интерфейс DBExecutor для выполнения запросов.
+//
+// Возвращает:
+//   - указатель на ProjectionRepository.
+func NewProjectionRepository(executor DBExecutor) *ProjectionRepository {
+	return &ProjectionRepository{executor: observe(executor)}
+}
+
+// ProjectionStorageInteractor описывает интерфейс операций с контрольными точками проекций.
+type ProjectionStorageInteractor interface {
+	// GetCheckpoint возвращает последнюю запись журнала, применённую к проекции.
+	GetCheckpoint(name string) (int64, error)
+	// SetCheckpoint сохраняет контрольную точку проекции.
+	SetCheckpoint(checkpoint dto.ProjectionCheckpoint) error
+	// GetCheckpoints возвращает контрольные точки всех проекций, упорядоченные по имени.
+	GetCheckpoints() ([]dto.ProjectionCheckpoint, error)
+}
+
+var (
+	//go:embed assets/projections/get_checkpoint.sql
+	projectionsGetCheckpointSQL string
+
+	//go:embed assets/projections/set_checkpoint.sql
+	projectionsSetCheckpointSQL string
+
+	//go:embed assets/projections/get_checkpoints.sql
+	projectionsGetCheckpointsSQL string
+)
+
+// GetCheckpoint возвращает идентификатор последней записи журнала, применённой к проекции.
+//
+// Аргументы:
+//   - name: имя проекции.
+//
+// Возвращает:
+//   - идентификатор записи (0, если проекция ещё не строилась) и ошибку при сбое запроса.
+func (r *ProjectionRepository) GetCheckpoint(name string) (int64, error) {
+	var lastTxID int64
+	if err := r.executor.QueryRow(projectionsGetCheckpointSQL, name).Scan(&lastTxID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, ErrFailedToGet.Wrap(err, "failed to get checkpoint of projection %s", name)
+	}
+	return lastTxID, nil
+}
+
+// SetCheckpoint сохраняет контрольную точку проекции, заменяя предыдущую.
+//
+// Аргументы:
+//   - checkpoint: контрольная точка проекции.
+//
+// Возвращает:
+//   - ошибку, если запрос завершился неуспешно.
+func (r *ProjectionRepository) SetCheckpoint(checkpoint dto.ProjectionCheckpoint) error {
+	if _, err := r.executor.Exec(projectionsSetCheckpointSQL,
+		checkpoint.Name,
+		checkpoint.LastTxID,
+		checkpoint.UpdatedAt,
+	); err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to save checkpoint of projection %s", checkpoint.Name)
+	}
+	return nil
+}
+
+// GetCheckpoints возвращает контрольные точки всех проекций, упорядоченные по имени.
+//
+// Возвращает:
+//   - срез контрольных точек (пустой, если проекции не строились) и ошибку при сбое запроса.
+func (r *ProjectionRepository) GetCheckpoints() ([]dto.ProjectionCheckpoint, error) {
+	rows, err := r.executor.Query(projectionsGetCheckpointsSQL)
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to get projection checkpoints")
+	}
+
+	// Обеспечиваем корректное закрытие объекта rows после использования
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.WarnContext(contextOf(r.executor), "failed to close rows", "error", err)
+		}
+	}()
+
+	checkpoints := make([]dto.ProjectionCheckpoint, 0)
+	for rows.Next() {
+		var checkpoint dto.ProjectionCheckpoint
+		if err := rows.Scan(&checkpoint.Name, &checkpoint.LastTxID, &checkpoint.UpdatedAt); err != nil {
+			return nil, ErrFailedToUnmarshal.Wrap(err, "failed to unmarshal projection checkpoint")
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, UnhandledErr.Wrap(err, "error while scanning projection checkpoints")
+	}
+	return checkpoints, nil
+}
@@ -0,0 +1,273 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/shopspring/decimal"
+	"log/slog"
+)
+
+// SnapshotRepository реализует методы для работы со снимками балансов в базе данных.
+//
+// Использует DBExecutor для выполнения SQL-запросов.
+type SnapshotRepository struct {
+	executor DBExecutor
+}
+
+// NewSnapshotRepository создаёт новый экземпляр SnapshotRepository.
+//
+// Аргументы:
+//   - executor: объект, реализующий интерфейс DBExecutor для выполнения запросов.
+//
+// Возвращает:
+//   - указатель на SnapshotRepository.
+func NewSnapshotRepository(executor DBExecutor) *SnapshotRepository {
+	return &SnapshotRepository{executor: observe(executor)}
+}
+
+// SnapshotStorageInteractor описывает интерфейс операций со снимками балансов.
+type SnapshotStorageInteractor interface {
+	// WithContext возвращает репозиторий, выполняющий запросы с контекстом ctx (см. storage.WithContext).
+	WithContext(ctx context.Context) SnapshotStorageInteractor
+	// Insert сохраняет снимок и возвращает его идентификатор.
+	Insert(snapshot dto.Snapshot) (int64, error)
+	// InsertBalances сохраняет балансы кошельков снимка.
+	InsertBalances(snapshotID int64, balances []dto.SnapshotBalance) error
+	// GetLastN возвращает последние n снимков.
+	GetLastN(n int) ([]dto.Snapshot, error)
+	// GetByID возвращает снимок по идентификатору.
+	GetByID(id int64) (dto.Snapshot, error)
+	// GetBalances возвращает балансы кошельков снимка, упорядоченные по адресу.
+	GetBalances(snapshotID int64) ([]dto.SnapshotBalance, error)
+	// GetNearest возвращает снимок, ближайший к записи журнала с идентификатором txID.
+	GetNearest(txID int64) (dto.Snapshot, error)
+	// GetBalance возвращает баланс кошелька в снимке.
+	GetBalance(snapshotID int64, address string) (decimal.Decimal, error)
+}
+
+var (
+	//go:embed assets/snapshots/insert.sql
+	snapshotsInsertSQL string
+
+	//go:embed assets/snapshots/insert_balance.sql
+	snapshotsInsertBalanceSQL string
+
+	//go:embed assets/snapshots/get_last_n.sql
+	snapshotsGetLastNSQL string
+
+	//go:embed assets/snapshots/get_by_id.sql
+	snapshotsGetByIDSQL string
+
+	//go:embed assets/snapshots/get_balances.sql
+	snapshotsGetBalancesSQL string
+
+	//go:embed assets/snapshots/get_nearest.sql
+	snapshotsGetNearestSQL string
+
+	//go:embed assets/snapshots/get_balance.sql
+	snapshotsGetBalanceSQL string
+)
+
+// WithContext возвращает репозиторий снимков балансов, выполняющий запросы с контекстом ctx.
+func (r *SnapshotRepository) WithContext(ctx context.Context) SnapshotStorageInteractor {
+	return NewSnapshotRepository(WithContext(ctx, r.executor))
+}
+
+// Insert добавляет снимок балансов в базу данных.
+//
+// Аргументы:
+//   - snapshot: снимок; поле ID не используется.
+//
+// Возвращает:
+//   - идентификатор созданного снимка.
+//   - ошибку, если не удалось вставить запись в базу.
+func (r *SnapshotRepository) Insert(snapshot dto.Snapshot) (int64, error) {
+	res, err := r.executor.Exec(snapshotsInsertSQL,
+		snapshot.LastTxID,
+		snapshot.CreatedAt,
+		snapshot.WalletCount,
+		snapshot.Total,
+		snapshot.MerkleRoot,
+	)
+	if err != nil {
+		return 0, ErrFailedToInsert.Wrap(err, "failed to insert snapshot")
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, ErrFailedToInsert.Wrap(err, "failed to get snapshot id")
+	}
+	return id, nil
+}
+
+// InsertBalances сохраняет балансы кошельков снимка одним подготовленным запросом.
+//
+// Аргументы:
+//   - snapshotID: идентификатор снимка.
+//   - balances: балансы кошельков.
+//
+// Возвращает:
+//   - ошибку, если не удалось вставить хотя бы одну запись.
+func (r *SnapshotRepository) InsertBalances(snapshotID int64, balances []dto.SnapshotBalance) error {
+	stmt, err := r.executor.Prepare(snapshotsInsertBalanceSQL)
+	if err != nil {
+		return ErrFailedToInsert.Wrap(err, "failed to prepare snapshot balance insert")
+	}
+
+	// Обеспечиваем корректное закрытие подготовленного запроса
+	defer func() {
+		if err := stmt.Close(); err != nil {
+			slog.WarnContext(contextOf(r.executor), "failed to close statement", "error", err)
+		}
+	}()
+
+	for _, balance := range balances {
+		if _, err := stmt.Exec(snapshotID, balance.Address, balance.Balance); err != nil {
+			return ErrFailedToInsert.Wrap(err, "failed to insert snapshot balance of %s", balance.Address)
+		}
+	}
+	return nil
+}
+
+// GetLastN возвращает последние n снимков, начиная с самого нового.
+//
+// Аргументы:
+//   - n: количество снимков.
+//
+// Возвращает:
+//   - срез снимков (пустой, если их нет) и ошибку при сбое запроса.
+func (r *SnapshotRepository) GetLastN(n int) ([]dto.Snapshot, error) {
+	rows, err := r.executor.Query(snapshotsGetLastNSQL, n)
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to get snapshots")
+	}
+
+	// Обеспечиваем корректное закрытие объекта rows после использования
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.WarnContext(contextOf(r.executor), "failed to close rows", "error", err)
+		}
+	}()
+
+	snapshots := make([]dto.Snapshot, 0)
+	for rows.Next() {
+		snapshot, err := scanSnapshot(rows)
+		if err != nil {
+			return nil, ErrFailedToUnmarshal.Wrap(err, "failed to unmarshal snapshot")
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, UnhandledErr.Wrap(err, "error while scanning snapshots")
+	}
+	return snapshots, nil
+}
+
+// GetByID возвращает снимок по идентификатору.
+//
+// Аргументы:
+//   - id: идентификатор снимка.
+//
+// Возвращает:
+//   - снимок и ErrSnapshotNotFound, если снимок не найден, или иную ошибку при сбое запроса.
+func (r *SnapshotRepository) GetByID(id int64) (dto.Snapshot, error) {
+	snapshot, err := scanSnapshot(r.executor.QueryRow(snapshotsGetByIDSQL, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.Snapshot{}, ErrSnapshotNotFound.Wrap(err, "snapshot %d not found", id)
+		}
+		return dto.Snapshot{}, ErrFailedToGet.Wrap(err, "failed to get snapshot %d", id)
+	}
+	return snapshot, nil
+}
+
+// GetBalances возвращает балансы кошельков снимка, упорядоченные по адресу.
+//
+// Аргументы:
+//   - snapshotID: идентификатор снимка.
+//
+// Возвращает:
+//   - срез балансов и ошибку при сбое запроса.
+func (r *SnapshotRepository) GetBalances(snapshotID int64) ([]dto.SnapshotBalance, error) {
+	rows, err := r.executor.Query(snapshotsGetBalancesSQL, snapshotID)
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to get balances of snapshot %d", snapshotID)
+	}
+
+	// Обеспечиваем корректное закрытие объекта rows после использования
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.WarnContext(contextOf(r.executor), "failed to close rows", "error", err)
+		}
+	}()
+
+	balances := make([]dto.SnapshotBalance, 0)
+	for rows.Next() {
+		var balance dto.SnapshotBalance
+		if err := rows.Scan(&balance.Address, &balance.Balance); err != nil {
+			return nil, ErrFailedToUnmarshal.Wrap(err, "failed to unmarshal snapshot balance")
+		}
+		balances = append(balances, balance)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, UnhandledErr.Wrap(err, "error while scanning snapshot balances")
+	}
+	return balances, nil
+}
+
+// GetNearest возвращает снимок, последняя транзакция которого ближе всего к записи журнала txID
+// (раньше или позже неё); при равном расстоянии выбирается более новый снимок.
+//
+// Аргументы:
+//   - txID: идентификатор записи журнала.
+//
+// Возвращает:
+//   - снимок и ErrSnapshotNotFound, если снимков нет, или иную ошибку при сбое запроса.
+func (r *SnapshotRepository) GetNearest(txID int64) (dto.Snapshot, error) {
+	snapshot, err := scanSnapshot(r.executor.QueryRow(snapshotsGetNearestSQL, txID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.Snapshot{}, ErrSnapshotNotFound.Wrap(err, "no snapshots")
+		}
+		return dto.Snapshot{}, ErrFailedToGet.Wrap(err, "failed to get snapshot nearest to transaction %d", txID)
+	}
+	return snapshot, nil
+}
+
+// GetBalance возвращает баланс кошелька в снимке.
+//
+// Аргументы:
+//   - snapshotID: идентификатор снимка.
+//   - address: адрес кошелька.
+//
+// Возвращает:
+//   - баланс и ErrWalletNotFound, если кошелька нет в снимке, или иную ошибку при сбое запроса.
+func (r *SnapshotRepository) GetBalance(snapshotID int64, address string) (decimal.Decimal, error) {
+	var balance decimal.Decimal
+	if err := r.executor.QueryRow(snapshotsGetBalanceSQL, snapshotID, address).Scan(&balance); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return decimal.Decimal{}, ErrWalletNotFound.Wrap(err, "wallet not found in snapshot %d", snapshotID)
+		}
+		return decimal.Decimal{}, ErrFailedToGet.Wrap(err, "failed to get balance in snapshot %d", snapshotID)
+	}
+	return balance, nil
+}
+
+// scanSnapshot считывает снимок из строки результата запроса.
+func scanSnapshot(row rowScanner) (dto.Snapshot, error) {
+	var snapshot dto.Snapshot
+	err := row.Scan(
+		&snapshot.ID,
+		&snapshot.LastTxID,
+		&snapshot.CreatedAt,
+		&snapshot.WalletCount,
+		&snapshot.Total,
+		&snapshot.MerkleRoot,
+	)
+	return snapshot, err
+}
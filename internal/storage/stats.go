@@ -0,0 +1,255 @@
+package storage
+
+import (
+	"database/sql"
+	_ "embed"
+	"errors"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/shopspring/decimal"
+	"log/slog"
+)
+
+// StatsRepository реализует методы для работы с таблицами статистических проекций журнала транзакций:
+// оборотами за сутки и статистикой контрагентов.
+//
+// Использует DBExecutor для выполнения SQL-запросов.
+type StatsRepository struct {
+	executor DBExecutor
+}
+
+// NewStatsRepository создаёт новый экземпляр StatsRepository.
+//
+// Аргументы:
+//   - executor: объект, реализующий интерфейс DBExecutor для выполнения запросов.
+//
+// Возвращает:
+//   - указатель на StatsRepository.
+func NewStatsRepository(executor DBExecutor) *StatsRepository {
+	return &StatsRepository{executor: observe(executor)}
+}
+
+// StatsStorageInteractor описывает интерфейс операций со статистическими проекциями.
+type StatsStorageInteractor interface {
+	// GetDailyVolume возвращает обороты за сутки по виду записей.
+	GetDailyVolume(day, kind string) (dto.DailyVolume, error)
+	// SaveDailyVolume сохраняет обороты за сутки по виду записей.
+	SaveDailyVolume(volume dto.DailyVolume) error
+	// ClearDailyVolumes удаляет все обороты.
+	ClearDailyVolumes() error
+	// GetDailyVolumes возвращает обороты за период, упорядоченные по дню и виду записей.
+	GetDailyVolumes(from, to string) ([]dto.DailyVolume, error)
+	// GetCounterparty возвращает статистику переводов кошелька с контрагентом.
+	GetCounterparty(address, counterparty string) (dto.CounterpartyStats, error)
+	// SaveCounterparty сохраняет статистику переводов кошелька с контрагентом.
+	SaveCounterparty(address string, stats dto.CounterpartyStats) error
+	// ClearCounterparties удаляет статистику всех контрагентов.
+	ClearCounterparties() error
+	// GetCounterparties возвращает не более n самых частых контрагентов кошелька.
+	GetCounterparties(address string, n int) ([]dto.CounterpartyStats, error)
+}
+
+var (
+	//go:embed assets/stats/get_daily_volume.sql
+	statsGetDailyVolumeSQL string
+
+	//go:embed assets/stats/save_daily_volume.sql
+	statsSaveDailyVolumeSQL string
+
+	//go:embed assets/stats/clear_daily_volumes.sql
+	statsClearDailyVolumesSQL string
+
+	//go:embed assets/stats/get_daily_volumes.sql
+	statsGetDailyVolumesSQL string
+
+	//go:embed assets/stats/get_counterparty.sql
+	statsGetCounterpartySQL string
+
+	//go:embed assets/stats/save_counterparty.sql
+	statsSaveCounterpartySQL string
+
+	//go:embed assets/stats/clear_counterparties.sql
+	statsClearCounterpartiesSQL string
+
+	//go:embed assets/stats/get_counterparties.sql
+	statsGetCounterpartiesSQL string
+)
+
+// GetDailyVolume возвращает обороты за сутки по виду записей.
+//
+// Аргументы:
+//   - day: дата в формате YYYY-MM-DD.
+//   - kind: вид записей журнала.
+//
+// Возвращает:
+//   - dto.DailyVolume (с нулевыми оборотами, если записей не было) и ошибку при сбое запроса.
+func (r *StatsRepository) GetDailyVolume(day, kind string) (dto.DailyVolume, error) {
+	volume := dto.DailyVolume{Day: day, Kind: kind, Volume: decimal.Zero}
+	if err := r.executor.QueryRow(statsGetDailyVolumeSQL, day, kind).Scan(&volume.Count, &volume.Volume); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return volume, nil
+		}
+		return dto.DailyVolume{}, ErrFailedToGet.Wrap(err, "failed to get volume of %s on %s", kind, day)
+	}
+	return volume, nil
+}
+
+// SaveDailyVolume сохраняет обороты за сутки по виду записей, заменяя предыдущие.
+//
+// Аргументы:
+//   - volume: обороты за сутки.
+//
+// Возвращает:
+//   - ошибку, если запрос завершился неуспешно.
+func (r *StatsRepository) SaveDailyVolume(volume dto.DailyVolume) error {
+	if _, err := r.executor.Exec(statsSaveDailyVolumeSQL, volume.Day, volume.Kind, volume.Count, volume.Volume); err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to save volume of %s on %s", volume.Kind, volume.Day)
+	}
+	return nil
+}
+
+// ClearDailyVolumes удаляет все обороты.
+//
+// Возвращает:
+//   - ошибку, если запрос завершился неуспешно.
+func (r *StatsRepository) ClearDailyVolumes() error {
+	if _, err := r.executor.Exec(statsClearDailyVolumesSQL); err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to clear daily volumes")
+	}
+	return nil
+}
+
+// GetDailyVolumes возвращает обороты за период, упорядоченные по дню и виду записей.
+//
+// Аргументы:
+//   - from, to: первый и последний день периода (YYYY-MM-DD) включительно.
+//
+// Возвращает:
+//   - срез оборотов (пустой, если записей за период не было) и ошибку при сбое запроса.
+func (r *StatsRepository) GetDailyVolumes(from, to string) ([]dto.DailyVolume, error) {
+	rows, err := r.executor.Query(statsGetDailyVolumesSQL, from, to)
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to get daily volumes")
+	}
+
+	// Обеспечиваем корректное закрытие объекта rows после использования
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.WarnContext(contextOf(r.executor), "failed to close rows", "error", err)
+		}
+	}()
+
+	volumes := make([]dto.DailyVolume, 0)
+	for rows.Next() {
+		var volume dto.DailyVolume
+		if err := rows.Scan(&volume.Day, &volume.Kind, &volume.Count, &volume.Volume); err != nil {
+			return nil, ErrFailedToUnmarshal.Wrap(err, "failed to unmarshal daily volume")
+		}
+		volumes = append(volumes, volume)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, UnhandledErr.Wrap(err, "error while scanning daily volumes")
+	}
+	return volumes, nil
+}
+
+// GetCounterparty возвращает статистику переводов кошелька с контрагентом.
+//
+// Аргументы:
+//   - address: адрес кошелька.
+//   - counterparty: адрес контрагента.
+//
+// Возвращает:
+//   - dto.CounterpartyStats (нулевую, если переводов не было) и ошибку при сбое запроса.
+func (r *StatsRepository) GetCounterparty(address, counterparty string) (dto.CounterpartyStats, error) {
+	stats := dto.CounterpartyStats{Counterparty: counterparty, SentVolume: decimal.Zero, ReceivedVolume: decimal.Zero}
+	err := r.executor.QueryRow(statsGetCounterpartySQL, address, counterparty).Scan(
+		&stats.SentCount,
+		&stats.SentVolume,
+		&stats.ReceivedCount,
+		&stats.ReceivedVolume,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return stats, nil
+		}
+		return dto.CounterpartyStats{}, ErrFailedToGet.Wrap(err, "failed to get counterparty stats of %s", address)
+	}
+	return stats, nil
+}
+
+// SaveCounterparty сохраняет статистику переводов кошелька с контрагентом, заменяя предыдущую.
+//
+// Аргументы:
+//   - address: адрес кошелька.
+//   - stats: статистика переводов с контрагентом stats.Counterparty.
+//
+// Возвращает:
+//   - ошибку, если запрос завершился неуспешно.
+func (r *StatsRepository) SaveCounterparty(address string, stats dto.CounterpartyStats) error {
+	if _, err := r.executor.Exec(statsSaveCounterpartySQL,
+		address,
+		stats.Counterparty,
+		stats.SentCount,
+		stats.SentVolume,
+		stats.ReceivedCount,
+		stats.ReceivedVolume,
+	); err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to save counterparty stats of %s", address)
+	}
+	return nil
+}
+
+// ClearCounterparties удаляет статистику всех контрагентов.
+//
+// Возвращает:
+//   - ошибку, если запрос завершился неуспешно.
+func (r *StatsRepository) ClearCounterparties() error {
+	if _, err := r.executor.Exec(statsClearCounterpartiesSQL); err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to clear counterparty stats")
+	}
+	return nil
+}
+
+// GetCounterparties возвращает не более n контрагентов кошелька, упорядоченных по убыванию
+// общего количества переводов.
+//
+// Аргументы:
+//   - address: адрес кошелька.
+//   - n: максимальное количество контрагентов.
+//
+// Возвращает:
+//   - срез статистики контрагентов (пустой, если переводов не было) и ошибку при сбое запроса.
+func (r *StatsRepository) GetCounterparties(address string, n int) ([]dto.CounterpartyStats, error) {
+	rows, err := r.executor.Query(statsGetCounterpartiesSQL, address, n)
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to get counterparties of %s", address)
+	}
+
+	// Обеспечиваем корректное закрытие объекта rows после использования
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.WarnContext(contextOf(r.executor), "failed to close rows", "error", err)
+		}
+	}()
+
+	counterparties := make([]dto.CounterpartyStats, 0)
+	for rows.Next() {
+		var stats dto.CounterpartyStats
+		if err := rows.Scan(
+			&stats.Counterparty,
+			&stats.SentCount,
+			&stats.SentVolume,
+			&stats.ReceivedCount,
+			&stats.ReceivedVolume,
+		); err != nil {
+			return nil, ErrFailedToUnmarshal.Wrap(err, "failed to unmarshal counterparty stats")
+		}
+		counterparties = append(counterparties, stats)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, UnhandledErr.Wrap(err, "error while scanning counterparty stats")
+	}
+	return counterparties, nil
+}
@@ -2,31 +2,109 @@ package storage
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "github.com/mattn/go-sqlite3"
-	"log"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
 )
 
 // Connect устанавливает соединение с базой данных SQLite.
 //
+// Аргументы:
+//   - path: путь к файлу базы данных; файл создаётся, если не существует.
+//   - busyTimeout: время ожидания освобождения блокировки базы данных другой транзакцией.
+//
 // Возвращает:
 //   - указатель на объект базы данных *sql.DB при успешном подключении.
 //   - ошибку, если не удалось открыть соединение с базой данных.
-func Connect() (*sql.DB, error) {
-	db, err := sql.Open("sqlite3", "./database.db")
+func Connect(path string, busyTimeout time.Duration) (*sql.DB, error) {
+	// Транзакции, начатые Begin, берут блокировку записи только при первой записи и не мешают
+	// друг другу читать; пишущие транзакции начинаются с BEGIN IMMEDIATE (см. BeginWrite).
+	// busy_timeout заставляет ожидающую транзакцию ждать освобождения блокировки, а не завершаться ошибкой.
+	dsn := "file:" + path + "?_busy_timeout=" + strconv.FormatInt(busyTimeout.Milliseconds(), 10)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// ApplyMigrations применяет к базе данных миграции из каталога dir, которые ещё не применены.
+// Обеспечивает актуальность схемы базы данных.
+//
+// Аргументы:
+//   - db: подключение к базе данных.
+//   - dir: каталог миграций.
+//
+// Возвращает:
+//   - ошибку, если миграции не удалось прочитать или применить.
+func ApplyMigrations(db *sql.DB, dir string) error {
+	// Настройка миграции для sqlite
+	driver, err := sqlite.WithInstance(db, &sqlite.Config{})
+	if err != nil {
+		return err
+	}
+
+	m, err := migrate.NewWithDatabaseInstance(
+		"file://"+dir, // Путь к миграциям
+		"sqlite", driver)
 	if err != nil {
-		log.Printf("Error opening database: %v", err)
+		return err
+	}
+
+	// Применение миграций
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	slog.Info("migrations applied")
+	return nil
+}
+
+// ConnectReadOnly открывает существующий файл базы данных SQLite только для чтения,
+// например резервную копию перед восстановлением.
+//
+// Аргументы:
+//   - path: путь к файлу базы данных.
+//
+// Возвращает:
+//   - указатель на объект базы данных *sql.DB и ошибку, если файл не существует или не открывается.
+func ConnectReadOnly(path string) (*sql.DB, error) {
+	if _, err := os.Stat(path); err != nil {
 		return nil, err
 	}
+
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
 	return db, nil
 }
 
-// Repository агрегирует репозитории для работы с кошельками и транзакциями.
+// Repository агрегирует репозитории для работы с кошельками, транзакциями, API-ключами,
+// журналом административных действий, контрольными точками журнала транзакций, снимками балансов,
+// проекциями журнала транзакций и ключами идемпотентности запросов.
 //
-// Содержит интерфейсы WalletStorageInteractor и TransactionStorageInteractor,
-// обеспечивающие доступ к методам хранения и извлечения данных.
+// Содержит интерфейсы WalletStorageInteractor, TransactionStorageInteractor, APIKeyStorageInteractor,
+// AuditStorageInteractor, CheckpointStorageInteractor, SnapshotStorageInteractor,
+// ProjectionStorageInteractor, StatsStorageInteractor и IdempotencyStorageInteractor, обеспечивающие
+// доступ к методам хранения и извлечения данных.
 type Repository struct {
 	WalletRepository      WalletStorageInteractor
 	TransactionRepository TransactionStorageInteractor
+	APIKeyRepository      APIKeyStorageInteractor
+	AuditRepository       AuditStorageInteractor
+	CheckpointRepository  CheckpointStorageInteractor
+	SnapshotRepository    SnapshotStorageInteractor
+	ProjectionRepository  ProjectionStorageInteractor
+	StatsRepository       StatsStorageInteractor
+	IdempotencyRepository IdempotencyStorageInteractor
 }
 
 // NewRepository создаёт новый экземпляр Repository, инициализируя вложенные репозитории.
@@ -35,10 +113,18 @@ type Repository struct {
 //   - db: подключение к базе данных *sql.DB.
 //
 // Возвращает:
-//   - указатель на новый Repository, содержащий репозитории кошельков и транзакций.
+//   - указатель на новый Repository, содержащий репозитории кошельков, транзакций, API-ключей,
+//     аудита, контрольных точек, снимков балансов, проекций и ключей идемпотентности.
 func NewRepository(db *sql.DB) *Repository {
 	return &Repository{
 		WalletRepository:      NewWalletRepository(db),
 		TransactionRepository: NewTransactionRepository(db),
+		APIKeyRepository:      NewAPIKeyRepository(db),
+		AuditRepository:       NewAuditRepository(db),
+		CheckpointRepository:  NewCheckpointRepository(db),
+		SnapshotRepository:    NewSnapshotRepository(db),
+		ProjectionRepository:  NewProjectionRepository(db),
+		StatsRepository:       NewStatsRepository(db),
+		IdempotencyRepository: NewIdempotencyRepository(db),
 	}
 }
@@ -1,9 +1,14 @@
 package storage
 
 import (
+	"context"
+	"database/sql"
 	_ "embed"
+	"errors"
 	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
-	"log"
+	"github.com/shopspring/decimal"
+	"log/slog"
+	"time"
 )
 
 // TransactionRepository реализует методы для работы с транзакциями в базе данных.
@@ -21,15 +26,31 @@ type TransactionRepository struct {
 // Возвращает:
 //   - указатель на TransactionRepository.
 func NewTransactionRepository(executor DBExecutor) *TransactionRepository {
-	return &TransactionRepository{executor: executor}
+	return &TransactionRepository{executor: observe(executor)}
 }
 
 // TransactionStorageInteractor описывает интерфейс операций с транзакциями.
 type TransactionStorageInteractor interface {
+	// WithContext возвращает репозиторий, выполняющий запросы с контекстом ctx (см. storage.WithContext).
+	WithContext(ctx context.Context) TransactionStorageInteractor
 	// GetLastN возвращает последние n транзакций.
 	GetLastN(n int) (dto.TransactionsResp, error)
-	// Insert вставляет новую транзакцию в базу данных.
-	Insert(req dto.TransactionReq) error
+	// Insert вставляет новую запись журнала транзакций в базу данных.
+	Insert(record dto.TransactionRecord) error
+	// GetHead возвращает последнюю запись журнала и количество записей.
+	GetHead() (dto.LedgerHead, error)
+	// ForEachRecord последовательно передаёт в fn записи журнала с идентификатором больше afterID.
+	ForEachRecord(afterID int64, fn func(record dto.TransactionRecord) error) error
+	// CountHashed возвращает количество записей журнала, включённых в цепочку хешей.
+	CountHashed() (int64, error)
+	// UpdateHash сохраняет хеши записи журнала.
+	UpdateHash(id int64, prevHash, hash string) error
+	// GetLastIDBefore возвращает идентификатор последней записи журнала, созданной не позже момента at.
+	GetLastIDBefore(at time.Time) (int64, error)
+	// GetWalletDelta возвращает изменение баланса кошелька за диапазон записей журнала.
+	GetWalletDelta(address string, afterID, uptoID int64) (decimal.Decimal, error)
+	// ForEachWalletRecord последовательно передаёт в fn записи журнала кошелька из диапазона.
+	ForEachWalletRecord(address string, afterID, uptoID int64, fn func(record dto.TransactionRecord) error) error
 }
 
 var (
@@ -38,8 +59,34 @@ var (
 
 	//go:embed assets/transactions/get_last_n.sql
 	transactionsGetLastNSQL string
+
+	//go:embed assets/transactions/get_head.sql
+	transactionsGetHeadSQL string
+
+	//go:embed assets/transactions/get_records.sql
+	transactionsGetRecordsSQL string
+
+	//go:embed assets/transactions/count_hashed.sql
+	transactionsCountHashedSQL string
+
+	//go:embed assets/transactions/update_hash.sql
+	transactionsUpdateHashSQL string
+
+	//go:embed assets/transactions/get_last_id_before.sql
+	transactionsGetLastIDBeforeSQL string
+
+	//go:embed assets/transactions/get_wallet_range.sql
+	transactionsGetWalletRangeSQL string
+
+	//go:embed assets/transactions/get_wallet_records.sql
+	transactionsGetWalletRecordsSQL string
 )
 
+// WithContext возвращает репозиторий транзакций, выполняющий запросы с контекстом ctx.
+func (r *TransactionRepository) WithContext(ctx context.Context) TransactionStorageInteractor {
+	return NewTransactionRepository(WithContext(ctx, r.executor))
+}
+
 // GetLastN возвращает последние n транзакций из базы данных.
 //
 // Аргументы:
@@ -62,7 +109,7 @@ func (r *TransactionRepository) GetLastN(n int) (dto.TransactionsResp, error) {
 	defer func() {
 		err := rows.Close()
 		if err != nil {
-			log.Println("Failed to close rows:", err)
+			slog.WarnContext(contextOf(r.executor), "failed to close rows", "error", err)
 		}
 	}()
 
@@ -70,6 +117,7 @@ func (r *TransactionRepository) GetLastN(n int) (dto.TransactionsResp, error) {
 	for rows.Next() {
 		var transaction dto.TransactionResp
 		if err := rows.Scan(
+			&transaction.Kind,
 			&transaction.From,
 			&transaction.To,
 			&transaction.Amount,
@@ -84,29 +132,270 @@ func (r *TransactionRepository) GetLastN(n int) (dto.TransactionsResp, error) {
 	// Проверяем, не возникла ли ошибка при итерации по строкам
 	if err := rows.Err(); err != nil {
 		return dto.TransactionsResp{}, UnhandledErr.Wrap(err,
-			"Error while scanning transactions through sql rows", n)
+			"Error while scanning %d transactions through sql rows", n)
 	}
 
 	// Если транзакции не найдены, отправляем ошибку
 	if len(transactions) == 0 {
-		return dto.TransactionsResp{}, ErrNotFound.New("transactions not found")
+		return dto.TransactionsResp{}, ErrTransactionsNotFound.New("transactions not found")
 	}
 
 	return transactions, nil
 }
 
-// Insert добавляет новую транзакцию в базу данных.
+// Insert добавляет новую запись в журнал транзакций.
+//
+// Пустые строковые поля записи (отправитель, получатель, подпись, инициатор, хеши) и нулевой nonce
+// сохраняются как NULL.
 //
 // Аргументы:
-//   - req: структура dto.TransactionReq с данными транзакции.
+//   - record: структура dto.TransactionRecord с данными транзакции.
 //
 // Возвращает:
 //   - ошибку, если не удалось вставить транзакцию в базу.
-func (r *TransactionRepository) Insert(req dto.TransactionReq) error {
-	_, err := r.executor.Exec(transactionsInsertSQL, req.From, req.To, req.Amount)
+func (r *TransactionRepository) Insert(record dto.TransactionRecord) error {
+	_, err := r.executor.Exec(transactionsInsertSQL,
+		record.Kind,
+		nullString(record.From),
+		nullString(record.To),
+		record.Amount,
+		sql.NullInt64{Int64: int64(record.Nonce), Valid: record.Nonce != 0},
+		nullString(record.Signature),
+		nullString(record.InitiatedBy),
+		record.CreatedAt,
+		nullString(record.PrevHash),
+		nullString(record.Hash),
+	)
 	if err != nil {
 		return ErrFailedToInsert.Wrap(err, "failed to insert transaction: %v", err)
 	}
 
 	return nil
 }
+
+// GetHead возвращает последнюю запись журнала транзакций и общее количество записей.
+//
+// Возвращает:
+//   - dto.LedgerHead; для пустого журнала — нулевое значение.
+//   - ошибку, если запрос завершился неуспешно.
+func (r *TransactionRepository) GetHead() (dto.LedgerHead, error) {
+	var head dto.LedgerHead
+	err := r.executor.QueryRow(transactionsGetHeadSQL).Scan(&head.ID, &head.Hash, &head.Count)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.LedgerHead{}, nil
+		}
+		return dto.LedgerHead{}, ErrFailedToGet.Wrap(err, "failed to get ledger head")
+	}
+	return head, nil
+}
+
+// ForEachRecord последовательно, в порядке возрастания идентификаторов, передаёт в fn
+// записи журнала транзакций с идентификатором больше afterID.
+//
+// Записи читаются потоком, поэтому журнал не загружается в память целиком.
+// Если fn возвращает ошибку, обход прекращается и ошибка возвращается без изменений.
+//
+// Аргументы:
+//   - afterID: идентификатор, после которого начинается обход (0 — с начала журнала).
+//   - fn: функция обработки записи.
+//
+// Возвращает:
+//   - ошибку запроса, чтения данных или ошибку fn.
+func (r *TransactionRepository) ForEachRecord(afterID int64, fn func(record dto.TransactionRecord) error) error {
+	rows, err := r.executor.Query(transactionsGetRecordsSQL, afterID)
+	if err != nil {
+		return ErrFailedToGet.Wrap(err, "failed to get transactions after %d", afterID)
+	}
+
+	// Обеспечиваем корректное закрытие объекта rows после использования
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.WarnContext(contextOf(r.executor), "failed to close rows", "error", err)
+		}
+	}()
+
+	for rows.Next() {
+		var record dto.TransactionRecord
+		if err := rows.Scan(
+			&record.ID,
+			&record.Kind,
+			&record.From,
+			&record.To,
+			&record.Amount,
+			&record.Nonce,
+			&record.Signature,
+			&record.InitiatedBy,
+			&record.CreatedAt,
+			&record.PrevHash,
+			&record.Hash,
+		); err != nil {
+			return ErrFailedToUnmarshal.Wrap(err, "failed to unmarshal transaction")
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return UnhandledErr.Wrap(err, "error while scanning transactions")
+	}
+	return nil
+}
+
+// CountHashed возвращает количество записей журнала, для которых вычислен хеш.
+//
+// Возвращает:
+//   - количество записей и ошибку, если запрос завершился неуспешно.
+func (r *TransactionRepository) CountHashed() (int64, error) {
+	var count int64
+	if err := r.executor.QueryRow(transactionsCountHashedSQL).Scan(&count); err != nil {
+		return 0, ErrFailedToGet.Wrap(err, "failed to count hashed transactions")
+	}
+	return count, nil
+}
+
+// UpdateHash сохраняет хеш записи журнала и хеш предыдущей записи.
+//
+// Аргументы:
+//   - id: идентификатор записи.
+//   - prevHash: хеш предыдущей записи.
+//   - hash: хеш записи.
+//
+// Возвращает:
+//   - ошибку, если обновление завершилось неуспешно или запись не найдена.
+func (r *TransactionRepository) UpdateHash(id int64, prevHash, hash string) error {
+	res, err := r.executor.Exec(transactionsUpdateHashSQL, nullString(prevHash), hash, id)
+	if err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to update transaction hash")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return ErrTransactionsNotFound.New("transaction %d not found", id)
+	}
+	return nil
+}
+
+// GetLastIDBefore возвращает идентификатор последней записи журнала, созданной не позже момента at.
+//
+// Аргументы:
+//   - at: момент времени.
+//
+// Возвращает:
+//   - идентификатор записи (0, если до этого момента записей не было) и ошибку при сбое запроса.
+func (r *TransactionRepository) GetLastIDBefore(at time.Time) (int64, error) {
+	var id int64
+	if err := r.executor.QueryRow(transactionsGetLastIDBeforeSQL, at.UTC()).Scan(&id); err != nil {
+		return 0, ErrFailedToGet.Wrap(err, "failed to get last transaction before %s", at)
+	}
+	return id, nil
+}
+
+// GetWalletDelta вычисляет изменение баланса кошелька за записи журнала с идентификаторами
+// в диапазоне (afterID, uptoID]: зачисления на кошелёк учитываются со знаком плюс,
+// списания — со знаком минус.
+//
+// Аргументы:
+//   - address: адрес кошелька.
+//   - afterID: идентификатор, после которого начинается диапазон.
+//   - uptoID: последний идентификатор диапазона.
+//
+// Возвращает:
+//   - изменение баланса и ошибку при сбое запроса.
+func (r *TransactionRepository) GetWalletDelta(address string, afterID, uptoID int64) (decimal.Decimal, error) {
+	rows, err := r.executor.Query(transactionsGetWalletRangeSQL, afterID, uptoID, address, address)
+	if err != nil {
+		return decimal.Decimal{}, ErrFailedToGet.Wrap(err, "failed to get transactions of %s", address)
+	}
+
+	// Обеспечиваем корректное закрытие объекта rows после использования
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.WarnContext(contextOf(r.executor), "failed to close rows", "error", err)
+		}
+	}()
+
+	delta := decimal.Zero
+	for rows.Next() {
+		var from, to string
+		var amount decimal.Decimal
+		if err := rows.Scan(&from, &to, &amount); err != nil {
+			return decimal.Decimal{}, ErrFailedToUnmarshal.Wrap(err, "failed to unmarshal transaction")
+		}
+		if to == address {
+			delta = delta.Add(amount)
+		}
+		if from == address {
+			delta = delta.Sub(amount)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return decimal.Decimal{}, UnhandledErr.Wrap(err, "error while scanning transactions")
+	}
+	return delta, nil
+}
+
+// ForEachWalletRecord последовательно, в порядке возрастания идентификаторов, передаёт в fn
+// записи журнала, в которых кошелёк является отправителем или получателем, с идентификаторами
+// в диапазоне (afterID, uptoID].
+//
+// Записи читаются потоком; заполняются поля ID, Kind, From, To, Amount и CreatedAt.
+// Если fn возвращает ошибку, обход прекращается и ошибка возвращается без изменений.
+//
+// Аргументы:
+//   - address: адрес кошелька.
+//   - afterID: идентификатор, после которого начинается диапазон.
+//   - uptoID: последний идентификатор диапазона.
+//   - fn: функция обработки записи.
+//
+// Возвращает:
+//   - ошибку запроса, чтения данных или ошибку fn.
+func (r *TransactionRepository) ForEachWalletRecord(
+	address string,
+	afterID, uptoID int64,
+	fn func(record dto.TransactionRecord) error,
+) error {
+	rows, err := r.executor.Query(transactionsGetWalletRecordsSQL, afterID, uptoID, address, address)
+	if err != nil {
+		return ErrFailedToGet.Wrap(err, "failed to get transactions of %s", address)
+	}
+
+	// Обеспечиваем корректное закрытие объекта rows после использования
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.WarnContext(contextOf(r.executor), "failed to close rows", "error", err)
+		}
+	}()
+
+	for rows.Next() {
+		var record dto.TransactionRecord
+		if err := rows.Scan(
+			&record.ID,
+			&record.Kind,
+			&record.From,
+			&record.To,
+			&record.Amount,
+			&record.CreatedAt,
+		); err != nil {
+			return ErrFailedToUnmarshal.Wrap(err, "failed to unmarshal transaction")
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return UnhandledErr.Wrap(err, "error while scanning transactions")
+	}
+	return nil
+}
+
+// nullString преобразует пустую строку в NULL.
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// Tx определяет транзакцию базы данных: *sql.Tx или пишущую транзакцию WriteTx.
+type Tx interface {
+	DBExecutor
+	Commit() error
+	Rollback() error
+}
+
+// WriteTx — пишущая транзакция, начатая с BEGIN IMMEDIATE (см. BeginWrite).
+//
+// Выполняется на выделенном соединении пула, которое возвращается в пул после фиксации или отката.
+// Как и *sql.Tx, после Commit или Rollback транзакция не может быть использована повторно:
+// запросы завершаются ошибкой sql.ErrConnDone.
+type WriteTx struct {
+	conn *sql.Conn
+	done bool
+}
+
+// BeginWrite начинает пишущую транзакцию с BEGIN IMMEDIATE.
+//
+// Блокировка записи берётся в начале транзакции, а не при первой записи: запись в журнал транзакций
+// читает хеш последней записи, и параллельные пишущие транзакции должны выполняться строго по очереди.
+// Ожидание блокировки ограничено busy_timeout подключения (см. Connect). Транзакции, которые только
+// читают данные, начинаются обычным Begin и не мешают друг другу.
+//
+// Аргументы:
+//   - ctx: контекст, отмена которого прерывает ожидание соединения и блокировки.
+//   - db: подключение к базе данных.
+//
+// Возвращает:
+//   - начатую транзакцию и ошибку, если соединение не получено или блокировка не взята.
+func BeginWrite(ctx context.Context, db *sql.DB) (*WriteTx, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &WriteTx{conn: conn}, nil
+}
+
+// Commit фиксирует транзакцию. Если фиксация не удалась, транзакция откатывается,
+// чтобы соединение вернулось в пул без открытой транзакции.
+func (tx *WriteTx) Commit() error {
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+	defer tx.conn.Close()
+
+	// Фиксация и откат не прерываются отменой контекста запроса, как и у *sql.Tx
+	if _, err := tx.conn.ExecContext(context.Background(), "COMMIT"); err != nil {
+		if _, rbErr := tx.conn.ExecContext(context.Background(), "ROLLBACK"); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// Rollback откатывает транзакцию.
+func (tx *WriteTx) Rollback() error {
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+	defer tx.conn.Close()
+
+	_, err := tx.conn.ExecContext(context.Background(), "ROLLBACK")
+	return err
+}
+
+// Exec выполняет команду в транзакции.
+func (tx *WriteTx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return tx.ExecContext(context.Background(), query, args...)
+}
+
+// Query выполняет запрос в транзакции.
+func (tx *WriteTx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.QueryContext(context.Background(), query, args...)
+}
+
+// QueryRow выполняет запрос одной строки в транзакции.
+func (tx *WriteTx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return tx.QueryRowContext(context.Background(), query, args...)
+}
+
+// Prepare подготавливает оператор для выполнения в транзакции.
+func (tx *WriteTx) Prepare(query string) (*sql.Stmt, error) {
+	return tx.PrepareContext(context.Background(), query)
+}
+
+// ExecContext выполняет команду в транзакции с контекстом ctx.
+func (tx *WriteTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return tx.conn.ExecContext(ctx, query, args...)
+}
+
+// QueryContext выполняет запрос в транзакции с контекстом ctx.
+func (tx *WriteTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.conn.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext выполняет запрос одной строки в транзакции с контекстом ctx.
+func (tx *WriteTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return tx.conn.QueryRowContext(ctx, query, args...)
+}
+
+// PrepareContext подготавливает оператор для выполнения в транзакции с контекстом ctx.
+func (tx *WriteTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return tx.conn.PrepareContext(ctx, query)
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// openTestDB открывает новую базу данных во временном каталоге с таблицей counter из одной строки.
+func openTestDB(t *testing.T, busyTimeout time.Duration) *sql.DB {
+	t.Helper()
+	db, err := Connect(filepath.Join(t.TempDir(), "test.db"), busyTimeout)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec("CREATE TABLE counter (value INTEGER NOT NULL); INSERT INTO counter VALUES (0)"); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	return db
+}
+
+// isBusy сообщает, завершилась ли операция ошибкой занятой базы данных.
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code == sqlite3.ErrBusy
+}
+
+func TestBeginWriteTakesWriteLock(t *testing.T) {
+	db := openTestDB(t, 50*time.Millisecond)
+	ctx := context.Background()
+
+	tx, err := BeginWrite(ctx, db)
+	if err != nil {
+		t.Fatalf("BeginWrite: %v", err)
+	}
+
+	// Блокировка взята до первой записи: вторая пишущая транзакция не начинается
+	if _, err := BeginWrite(ctx, db); !isBusy(err) {
+		t.Fatalf("second BeginWrite: error %v, want database is locked", err)
+	}
+
+	// Читающие транзакции не ждут пишущую
+	read, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	var value int
+	if err := read.QueryRow("SELECT value FROM counter").Scan(&value); err != nil {
+		t.Fatalf("read during write transaction: %v", err)
+	}
+	if err := read.Rollback(); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+
+	if _, err := tx.Exec("UPDATE counter SET value = value + 1"); err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if err := Commit(ctx, tx); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if err := tx.Commit(); !errors.Is(err, sql.ErrTxDone) {
+		t.Errorf("second Commit: error %v, want %v", err, sql.ErrTxDone)
+	}
+
+	// После фиксации блокировка снята
+	next, err := BeginWrite(ctx, db)
+	if err != nil {
+		t.Fatalf("BeginWrite after commit: %v", err)
+	}
+	if err := next.Rollback(); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+}
+
+func TestBeginWriteWaitsForWriter(t *testing.T) {
+	db := openTestDB(t, 5*time.Second)
+	ctx := context.Background()
+
+	first, err := BeginWrite(ctx, db)
+	if err != nil {
+		t.Fatalf("BeginWrite: %v", err)
+	}
+	var value int
+	if err := first.QueryRow("SELECT value FROM counter").Scan(&value); err != nil {
+		t.Fatalf("QueryRow: %v", err)
+	}
+
+	// Вторая транзакция ждёт фиксации первой и читает уже изменённое значение
+	result := make(chan int, 1)
+	go func() {
+		second, err := BeginWrite(ctx, db)
+		if err != nil {
+			t.Errorf("second BeginWrite: %v", err)
+			result <- -1
+			return
+		}
+		defer second.Rollback()
+		var value int
+		if err := second.QueryRow("SELECT value FROM counter").Scan(&value); err != nil {
+			t.Errorf("QueryRow: %v", err)
+		}
+		result <- value
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if _, err := first.Exec("UPDATE counter SET value = ?", value+1); err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if err := first.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if got := <-result; got != value+1 {
+		t.Errorf("second transaction read %d, want %d", got, value+1)
+	}
+}
+
+func TestBeginWriteRollback(t *testing.T) {
+	db := openTestDB(t, 50*time.Millisecond)
+
+	tx, err := BeginWrite(context.Background(), db)
+	if err != nil {
+		t.Fatalf("BeginWrite: %v", err)
+	}
+	if _, err := tx.Exec("UPDATE counter SET value = 10"); err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if _, err := tx.Exec("UPDATE counter SET value = 20"); err == nil {
+		t.Error("Exec succeeded after Rollback")
+	}
+
+	var value int
+	if err := db.QueryRow("SELECT value FROM counter").Scan(&value); err != nil {
+		t.Fatalf("QueryRow: %v", err)
+	}
+	if value != 0 {
+		t.Errorf("value = %d after rollback, want 0", value)
+	}
+}
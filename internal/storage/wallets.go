@@ -1,10 +1,12 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 	_ "embed"
 	"errors"
 	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"log/slog"
 )
 
 // WalletsRepository реализует методы взаимодействия с таблицей кошельков в базе данных.
@@ -22,11 +24,13 @@ type WalletsRepository struct {
 // Возвращает:
 //   - указатель на WalletsRepository.
 func NewWalletRepository(executor DBExecutor) *WalletsRepository {
-	return &WalletsRepository{executor: executor}
+	return &WalletsRepository{executor: observe(executor)}
 }
 
 // WalletStorageInteractor описывает интерфейс работы с кошельками.
 type WalletStorageInteractor interface {
+	// WithContext возвращает репозиторий, выполняющий запросы с контекстом ctx (см. storage.WithContext).
+	WithContext(ctx context.Context) WalletStorageInteractor
 	// Insert создаёт новый кошелёк.
 	Insert(req dto.WalletReq) error
 	// GetBalance возвращает баланс по адресу кошелька.
@@ -35,6 +39,18 @@ type WalletStorageInteractor interface {
 	UpdateBalance(req dto.BalanceUpdateReq) error
 	// GetCount возвращает количество зарегистрированных кошельков.
 	GetCount() (int, error)
+	// GetNonce возвращает последний использованный nonce кошелька.
+	GetNonce(req dto.BalanceReq) (dto.NonceResp, error)
+	// UpdateNonce сохраняет последний использованный nonce кошелька.
+	UpdateNonce(address string, nonce uint64) error
+	// IsFrozen проверяет, заморожен ли кошелёк.
+	IsFrozen(address string) (bool, error)
+	// SetFrozen замораживает или размораживает кошелёк.
+	SetFrozen(address string, frozen bool) error
+	// GetAll возвращает адреса и балансы всех кошельков, упорядоченные по адресу.
+	GetAll() ([]dto.WalletReq, error)
+	// GetStates возвращает текущие и начальные балансы всех кошельков, упорядоченные по адресу.
+	GetStates() ([]dto.WalletState, error)
 }
 
 var (
@@ -49,8 +65,31 @@ var (
 
 	//go:embed assets/wallets/update_balance.sql
 	walletsUpdateSQL string
+
+	//go:embed assets/wallets/get_nonce.sql
+	walletsGetNonceSQL string
+
+	//go:embed assets/wallets/update_nonce.sql
+	walletsUpdateNonceSQL string
+
+	//go:embed assets/wallets/get_frozen.sql
+	walletsGetFrozenSQL string
+
+	//go:embed assets/wallets/set_frozen.sql
+	walletsSetFrozenSQL string
+
+	//go:embed assets/wallets/get_all.sql
+	walletsGetAllSQL string
+
+	//go:embed assets/wallets/get_states.sql
+	walletsGetStatesSQL string
 )
 
+// WithContext возвращает репозиторий кошельков, выполняющий запросы с контекстом ctx.
+func (r *WalletsRepository) WithContext(ctx context.Context) WalletStorageInteractor {
+	return NewWalletRepository(WithContext(ctx, r.executor))
+}
+
 // Insert добавляет новый кошелёк в базу данных.
 //
 // Аргументы:
@@ -59,7 +98,7 @@ var (
 // Возвращает:
 //   - ошибку, если операция завершилась неуспешно.
 func (r *WalletsRepository) Insert(req dto.WalletReq) error {
-	_, err := r.executor.Exec(walletsInsertSQL, req.Address, req.Balance)
+	_, err := r.executor.Exec(walletsInsertSQL, req.Address, req.Balance, req.Balance)
 	if err != nil {
 		return ErrFailedToInsert.Wrap(err, "failed to create wallet: %v", err)
 	}
@@ -80,7 +119,7 @@ func (r *WalletsRepository) GetBalance(req dto.BalanceReq) (dto.BalanceResp, err
 	if err != nil {
 		// Обработка ошибки, если кошелек не найден или иная проблема
 		if errors.Is(err, sql.ErrNoRows) {
-			return dto.BalanceResp{}, ErrNotFound.Wrap(err, "wallet not found")
+			return dto.BalanceResp{}, ErrWalletNotFound.Wrap(err, "wallet not found")
 		} else {
 			return dto.BalanceResp{}, ErrFailedToGet.Wrap(err, "failed to get balance")
 		}
@@ -122,8 +161,163 @@ func (r *WalletsRepository) UpdateBalance(req dto.BalanceUpdateReq) error {
 	}
 
 	if rowsAffected == 0 {
-		return ErrNotFound.New("wallet not found")
+		return ErrWalletNotFound.New("wallet not found")
+	}
+
+	return nil
+}
+
+// GetNonce возвращает последний использованный nonce кошелька.
+//
+// Аргументы:
+//   - req: структура dto.BalanceReq с адресом кошелька.
+//
+// Возвращает:
+//   - dto.NonceResp с последним использованным nonce (0, если переводов ещё не было).
+//   - ошибку, если кошелёк не найден или возникла другая проблема.
+func (r *WalletsRepository) GetNonce(req dto.BalanceReq) (dto.NonceResp, error) {
+	var nonce dto.NonceResp
+	err := r.executor.QueryRow(walletsGetNonceSQL, req.Address).Scan(&nonce.Nonce)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.NonceResp{}, ErrWalletNotFound.Wrap(err, "wallet not found")
+		}
+		return dto.NonceResp{}, ErrFailedToGet.Wrap(err, "failed to get nonce")
+	}
+	return nonce, nil
+}
+
+// UpdateNonce сохраняет последний использованный nonce кошелька.
+//
+// Аргументы:
+//   - address: адрес кошелька.
+//   - nonce: новое значение nonce.
+//
+// Возвращает:
+//   - ошибку, если операция обновления завершилась неуспешно или кошелёк не найден.
+func (r *WalletsRepository) UpdateNonce(address string, nonce uint64) error {
+	res, err := r.executor.Exec(walletsUpdateNonceSQL, nonce, address)
+	if err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to update nonce")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return ErrWalletNotFound.New("wallet not found")
+	}
+
+	return nil
+}
+
+// IsFrozen проверяет, заморожен ли кошелёк.
+//
+// Аргументы:
+//   - address: адрес кошелька.
+//
+// Возвращает:
+//   - true, если кошелёк заморожен, и ошибку, если кошелёк не найден или запрос не удался.
+func (r *WalletsRepository) IsFrozen(address string) (bool, error) {
+	var frozen bool
+	err := r.executor.QueryRow(walletsGetFrozenSQL, address).Scan(&frozen)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, ErrWalletNotFound.Wrap(err, "wallet not found")
+		}
+		return false, ErrFailedToGet.Wrap(err, "failed to get wallet state")
+	}
+	return frozen, nil
+}
+
+// SetFrozen замораживает или размораживает кошелёк.
+//
+// Аргументы:
+//   - address: адрес кошелька.
+//   - frozen: true, чтобы заморозить кошелёк, false — чтобы разморозить.
+//
+// Возвращает:
+//   - ошибку, если операция обновления завершилась неуспешно или кошелёк не найден.
+func (r *WalletsRepository) SetFrozen(address string, frozen bool) error {
+	res, err := r.executor.Exec(walletsSetFrozenSQL, frozen, address)
+	if err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to update wallet state")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return ErrFailedToUpdate.Wrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return ErrWalletNotFound.New("wallet not found")
 	}
 
 	return nil
 }
+
+// GetAll возвращает адреса и балансы всех кошельков, упорядоченные по адресу.
+//
+// Возвращает:
+//   - срез кошельков (пустой, если кошельков нет) и ошибку, если запрос завершился неуспешно.
+func (r *WalletsRepository) GetAll() ([]dto.WalletReq, error) {
+	rows, err := r.executor.Query(walletsGetAllSQL)
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to get wallets")
+	}
+
+	// Обеспечиваем корректное закрытие объекта rows после использования
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.WarnContext(contextOf(r.executor), "failed to close rows", "error", err)
+		}
+	}()
+
+	wallets := make([]dto.WalletReq, 0)
+	for rows.Next() {
+		var wallet dto.WalletReq
+		if err := rows.Scan(&wallet.Address, &wallet.Balance); err != nil {
+			return nil, ErrFailedToUnmarshal.Wrap(err, "failed to unmarshal wallet")
+		}
+		wallets = append(wallets, wallet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, UnhandledErr.Wrap(err, "error while scanning wallets")
+	}
+	return wallets, nil
+}
+
+// GetStates возвращает текущие и начальные балансы всех кошельков, упорядоченные по адресу.
+//
+// Возвращает:
+//   - срез состояний кошельков (пустой, если кошельков нет) и ошибку, если запрос завершился неуспешно.
+func (r *WalletsRepository) GetStates() ([]dto.WalletState, error) {
+	rows, err := r.executor.Query(walletsGetStatesSQL)
+	if err != nil {
+		return nil, ErrFailedToGet.Wrap(err, "failed to get wallets")
+	}
+
+	// Обеспечиваем корректное закрытие объекта rows после использования
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.WarnContext(contextOf(r.executor), "failed to close rows", "error", err)
+		}
+	}()
+
+	states := make([]dto.WalletState, 0)
+	for rows.Next() {
+		var state dto.WalletState
+		if err := rows.Scan(&state.Address, &state.Balance, &state.InitialBalance); err != nil {
+			return nil, ErrFailedToUnmarshal.Wrap(err, "failed to unmarshal wallet")
+		}
+		states = append(states, state)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, UnhandledErr.Wrap(err, "error while scanning wallets")
+	}
+	return states, nil
+}
@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Атрибуты спанов SQL-запросов.
+var (
+	dbSystem    = attribute.String("db.system", "sqlite")
+	dbQueryFile = attribute.Key("db.query.file")
+)
+
+// ObserveQuery создаёт спан SQL-запроса. Совпадает по сигнатуре со storage.QueryObserver.
+//
+// Спан создаётся, только если контекст запроса уже относится к трассировке (например, запрос
+// выполняется при обработке HTTP-запроса): фоновые запросы без родительского спана не порождают
+// отдельных трассировок на каждый SQL-запрос.
+//
+// Аргументы:
+//   - ctx: контекст, привязанный к исполнителю запросов.
+//   - name: имя SQL-файла запроса, например wallets/get_balance; записывается в атрибут db.query.file.
+//
+// Возвращает:
+//   - функцию, завершающую спан с ошибкой запроса.
+func ObserveQuery(ctx context.Context, name string) func(err error) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return func(error) {}
+	}
+
+	_, span := tracer().Start(ctx, "sql "+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(dbSystem, dbQueryFile.String(name)),
+	)
+	return func(err error) {
+		end(span, err)
+	}
+}
@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/config"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+// tracerName — имя инструментирующей библиотеки в трассировках.
+const tracerName = "github.com/coffee-realist/infotecs_transaction_system/internal/tracing"
+
+// tracer возвращает трассировщик глобального поставщика, установленного Setup.
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// Setup настраивает глобального поставщика трассировок OpenTelemetry и распространение контекста
+// трассировки в заголовках W3C traceparent, tracestate и baggage.
+//
+// Аргументы:
+//   - ctx: контекст создания экспортёра.
+//   - cfg: параметры трассировки; при экспортёре none спаны не записываются, но контекст
+//     трассировки из входящих заголовков по-прежнему распространяется.
+//
+// Возвращает:
+//   - функцию, отправляющую накопленные спаны и останавливающую экспорт, и ошибку создания экспортёра.
+func Setup(ctx context.Context, cfg config.TracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch cfg.Exporter {
+	case config.TracingExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case config.TracingExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case config.TracingExporterOTLP:
+		options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, options...)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attribute.String("service.name", cfg.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// InstrumentHandler создаёт серверный спан для каждого запроса к маршруту. Если запрос содержит
+// заголовок traceparent, спан продолжает трассировку клиента.
+//
+// Аргументы:
+//   - method: HTTP-метод маршрута.
+//   - route: шаблон пути маршрута, например /api/wallet/{address}/balance.
+//   - next: обработчик маршрута.
+func InstrumentHandler(method, route string, next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, method+" "+route,
+		otelhttp.WithSpanOptions(trace.WithAttributes(attribute.String("http.route", route))),
+	)
+}
+
+// end завершает спан, отмечая его ошибкой, если она есть.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
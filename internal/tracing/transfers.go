@@ -0,0 +1,106 @@
+package tracing
+
+import (
+	"context"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/services"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Атрибуты спанов сервиса переводов.
+var (
+	walletAddress = attribute.Key("wallet.address")
+	transferFrom  = attribute.Key("transfer.from")
+	transferTo    = attribute.Key("transfer.to")
+	transferNonce = attribute.Key("transfer.nonce")
+	transferCount = attribute.Key("transfer.count")
+)
+
+// transferInteractor создаёт спан для каждого вызова сервиса переводов.
+type transferInteractor struct {
+	next services.TransferInteractor
+}
+
+// InstrumentTransfers оборачивает сервис переводов: каждый вызов его методов записывается
+// спаном TransferService.<метод> с адресами кошельков в атрибутах. SQL-запросы, выполненные
+// сервисом с контекстом вызова, становятся дочерними спанами (см. ObserveQuery).
+//
+// Аргументы:
+//   - transferService: сервис переводов.
+//
+// Возвращает:
+//   - TransferInteractor с трассировкой.
+func InstrumentTransfers(transferService services.TransferInteractor) services.TransferInteractor {
+	return &transferInteractor{next: transferService}
+}
+
+// start создаёт спан метода сервиса переводов.
+func start(ctx context.Context, method string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(ctx, "TransferService."+method, trace.WithAttributes(attributes...))
+}
+
+// Send выполняет перевод в спане TransferService.Send.
+func (t *transferInteractor) Send(ctx context.Context, req dto.TransactionReq) (err error) {
+	ctx, span := start(ctx, "Send", transferFrom.String(req.From), transferTo.String(req.To),
+		transferNonce.Int64(int64(req.Nonce)))
+	defer func() { end(span, err) }()
+	return t.next.Send(ctx, req)
+}
+
+// GetLastN возвращает последние транзакции в спане TransferService.GetLastN.
+func (t *transferInteractor) GetLastN(ctx context.Context, n int) (resp dto.TransactionsResp, err error) {
+	ctx, span := start(ctx, "GetLastN", transferCount.Int(n))
+	defer func() { end(span, err) }()
+	return t.next.GetLastN(ctx, n)
+}
+
+// GetBalance возвращает баланс кошелька в спане TransferService.GetBalance.
+func (t *transferInteractor) GetBalance(ctx context.Context, req dto.BalanceReq) (resp dto.BalanceResp, err error) {
+	ctx, span := start(ctx, "GetBalance", walletAddress.String(req.Address))
+	defer func() { end(span, err) }()
+	return t.next.GetBalance(ctx, req)
+}
+
+// GetBalanceAt возвращает баланс кошелька на момент в прошлом в спане TransferService.GetBalanceAt.
+func (t *transferInteractor) GetBalanceAt(ctx context.Context, req dto.BalanceAtReq) (resp dto.BalanceResp, err error) {
+	ctx, span := start(ctx, "GetBalanceAt", walletAddress.String(req.Address))
+	defer func() { end(span, err) }()
+	return t.next.GetBalanceAt(ctx, req)
+}
+
+// WriteStatement формирует выписку в спане TransferService.WriteStatement.
+func (t *transferInteractor) WriteStatement(ctx context.Context, req dto.StatementReq, w services.StatementWriter) (err error) {
+	ctx, span := start(ctx, "WriteStatement", walletAddress.String(req.Address))
+	defer func() { end(span, err) }()
+	return t.next.WriteStatement(ctx, req, w)
+}
+
+// GetNonce возвращает nonce кошелька в спане TransferService.GetNonce.
+func (t *transferInteractor) GetNonce(ctx context.Context, req dto.BalanceReq) (resp dto.NonceResp, err error) {
+	ctx, span := start(ctx, "GetNonce", walletAddress.String(req.Address))
+	defer func() { end(span, err) }()
+	return t.next.GetNonce(ctx, req)
+}
+
+// GetTotalSupply возвращает сумму балансов в спане TransferService.GetTotalSupply.
+func (t *transferInteractor) GetTotalSupply(ctx context.Context) (total decimal.Decimal, err error) {
+	ctx, span := start(ctx, "GetTotalSupply")
+	defer func() { end(span, err) }()
+	return t.next.GetTotalSupply(ctx)
+}
+
+// GenerateWallets создаёт кошельки в спане TransferService.GenerateWallets.
+func (t *transferInteractor) GenerateWallets(ctx context.Context, req dto.SeedReq) (keys []dto.WalletKey, err error) {
+	ctx, span := start(ctx, "GenerateWallets", transferCount.Int(req.Count))
+	defer func() { end(span, err) }()
+	return t.next.GenerateWallets(ctx, req)
+}
+
+// ImportWallets импортирует кошельки из фикстуры в спане TransferService.ImportWallets.
+func (t *transferInteractor) ImportWallets(ctx context.Context, req dto.ImportWalletsReq) (count int, err error) {
+	ctx, span := start(ctx, "ImportWallets", transferCount.Int(len(req.Wallets)))
+	defer func() { end(span, err) }()
+	return t.next.ImportWallets(ctx, req)
+}
@@ -5,21 +5,23 @@ import (
 	"encoding/hex"
 )
 
-// GenerateRandomAddress генерирует криптографически безопасный случайный адрес.
+// APIKeyPrefix — префикс значений API-ключей, упрощающий их распознавание в логах и конфигурации.
+const APIKeyPrefix = "itk_"
+
+// GenerateAPIKey генерирует новое значение API-ключа.
 //
-// Адрес состоит из 32 байт (256 бит), что соответствует 64 символам в шестнадцатеричном представлении.
+// Ключ состоит из префикса APIKeyPrefix и 32 случайных байт в hex-представлении.
 //
 // Возвращает:
-//   - строку длиной 64 символа, содержащую hex-представление случайных байтов;
+//   - строку с ключом;
 //   - ошибку, если не удалось сгенерировать случайные байты.
-func GenerateRandomAddress() (string, error) {
-	const addressLength = 32 // 32 байта = 64 hex-символа
+func GenerateAPIKey() (string, error) {
+	const keyLength = 32
 
-	randomBytes := make([]byte, addressLength)
-	_, err := rand.Read(randomBytes)
-	if err != nil {
+	randomBytes := make([]byte, keyLength)
+	if _, err := rand.Read(randomBytes); err != nil {
 		return "", err
 	}
 
-	return hex.EncodeToString(randomBytes), nil
+	return APIKeyPrefix + hex.EncodeToString(randomBytes), nil
 }
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+)
+
+const (
+	// transactionHashPrefix — домен хеша записей журнала транзакций.
+	transactionHashPrefix = "infotecs-ledger-v1"
+	// checkpointPayloadPrefix — домен подписи контрольных точек журнала.
+	checkpointPayloadPrefix = "infotecs-checkpoint-v1"
+)
+
+// TransactionPayload возвращает каноническое представление записи журнала транзакций,
+// от которого вычисляется её хеш.
+//
+// Формат (строки разделены символом \n, отсутствующие значения — пустые строки):
+//
+//	infotecs-ledger-v1
+//	kind=<вид записи>
+//	from=<адрес отправителя>
+//	to=<адрес получателя>
+//	amount=<сумма в нормализованной десятичной записи>
+//	nonce=<nonce>
+//	signature=<подпись перевода>
+//	initiated_by=<инициатор>
+//	created_at=<время создания в UTC, RFC 3339 с долями секунды>
+//	prev=<хеш предыдущей записи>
+func TransactionPayload(record dto.TransactionRecord) []byte {
+	return []byte(strings.Join([]string{
+		transactionHashPrefix,
+		"kind=" + record.Kind,
+		"from=" + strings.ToLower(record.From),
+		"to=" + strings.ToLower(record.To),
+		"amount=" + record.Amount.String(),
+		"nonce=" + strconv.FormatUint(record.Nonce, 10),
+		"signature=" + strings.ToLower(record.Signature),
+		"initiated_by=" + record.InitiatedBy,
+		"created_at=" + record.CreatedAt.UTC().Format(time.RFC3339Nano),
+		"prev=" + record.PrevHash,
+	}, "\n"))
+}
+
+// HashTransaction вычисляет хеш записи журнала транзакций: SHA-256 от TransactionPayload в hex.
+//
+// Хеш включает хеш предыдущей записи (PrevHash), поэтому изменение, удаление или вставка
+// любой записи нарушает цепочку начиная с этого места.
+func HashTransaction(record dto.TransactionRecord) string {
+	sum := sha256.Sum256(TransactionPayload(record))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckpointPayload возвращает каноническое представление контрольной точки журнала, которое подписывает сервер.
+//
+// Формат (строки разделены символом \n):
+//
+//	infotecs-checkpoint-v1
+//	last_tx_id=<идентификатор последней транзакции>
+//	tx_count=<количество транзакций>
+//	head_hash=<хеш последней транзакции>
+//	created_at=<время создания в UTC, RFC 3339>
+func CheckpointPayload(checkpoint dto.Checkpoint) []byte {
+	return []byte(strings.Join([]string{
+		checkpointPayloadPrefix,
+		"last_tx_id=" + strconv.FormatInt(checkpoint.LastTxID, 10),
+		"tx_count=" + strconv.FormatInt(checkpoint.TxCount, 10),
+		"head_hash=" + checkpoint.HeadHash,
+		"created_at=" + checkpoint.CreatedAt.UTC().Format(time.RFC3339),
+	}, "\n"))
+}
+
+// SignCheckpoint подписывает контрольную точку ключом сервера.
+//
+// Возвращает:
+//   - подпись Ed25519 в hex.
+func SignCheckpoint(key ed25519.PrivateKey, checkpoint dto.Checkpoint) string {
+	return hex.EncodeToString(ed25519.Sign(key, CheckpointPayload(checkpoint)))
+}
+
+// VerifyCheckpoint проверяет подпись контрольной точки открытым ключом из её поля PublicKey.
+//
+// Возвращает:
+//   - true, если ключ и подпись корректны и подпись соответствует содержимому контрольной точки.
+func VerifyCheckpoint(checkpoint dto.Checkpoint) bool {
+	publicKey, err := hex.DecodeString(checkpoint.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return false
+	}
+	signature, err := hex.DecodeString(checkpoint.Signature)
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return false
+	}
+	return ed25519.Verify(publicKey, CheckpointPayload(checkpoint), signature)
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/shopspring/decimal"
+)
+
+// testRecord возвращает запись перевода со всеми заполненными полями.
+func testRecord() dto.TransactionRecord {
+	from, _ := testWallet(0)
+	to, _ := testWallet(1)
+	return dto.TransactionRecord{
+		Kind:        dto.TransactionKindTransfer,
+		From:        from,
+		To:          to,
+		Amount:      decimal.RequireFromString("2.5"),
+		Nonce:       3,
+		Signature:   "ab",
+		InitiatedBy: "api-key:test",
+		CreatedAt:   time.Date(2025, 1, 2, 3, 4, 5, 6000, time.UTC),
+		PrevHash:    "00",
+	}
+}
+
+func TestHashTransactionCoversEveryField(t *testing.T) {
+	record := testRecord()
+	hash := HashTransaction(record)
+	if len(hash) != 64 {
+		t.Fatalf("hash has %d hex characters, want 64", len(hash))
+	}
+	if HashTransaction(record) != hash {
+		t.Fatal("HashTransaction is not deterministic")
+	}
+
+	// Те же значения в другой записи дают тот же хеш
+	equivalent := record
+	equivalent.Amount = decimal.RequireFromString("2.50")
+	equivalent.CreatedAt = record.CreatedAt.In(time.FixedZone("MSK", 3*60*60))
+	if HashTransaction(equivalent) != hash {
+		t.Error("hash depends on the amount notation or the time zone")
+	}
+
+	other, _ := testWallet(2)
+	tests := map[string]func(r *dto.TransactionRecord){
+		"kind":         func(r *dto.TransactionRecord) { r.Kind = dto.TransactionKindMint },
+		"sender":       func(r *dto.TransactionRecord) { r.From = other },
+		"recipient":    func(r *dto.TransactionRecord) { r.To = other },
+		"amount":       func(r *dto.TransactionRecord) { r.Amount = decimal.RequireFromString("2.51") },
+		"nonce":        func(r *dto.TransactionRecord) { r.Nonce++ },
+		"signature":    func(r *dto.TransactionRecord) { r.Signature = "ac" },
+		"initiated by": func(r *dto.TransactionRecord) { r.InitiatedBy = "api-key:other" },
+		"created at":   func(r *dto.TransactionRecord) { r.CreatedAt = r.CreatedAt.Add(time.Microsecond) },
+		"prev hash":    func(r *dto.TransactionRecord) { r.PrevHash = "01" },
+	}
+	for name, change := range tests {
+		t.Run(name, func(t *testing.T) {
+			changed := record
+			change(&changed)
+			if HashTransaction(changed) == hash {
+				t.Errorf("hash does not change with the %s", name)
+			}
+		})
+	}
+}
+
+func TestVerifyCheckpoint(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	checkpoint := dto.Checkpoint{
+		LastTxID:  10,
+		TxCount:   10,
+		HeadHash:  HashTransaction(testRecord()),
+		CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		PublicKey: hex.EncodeToString(public),
+	}
+	checkpoint.Signature = SignCheckpoint(key, checkpoint)
+	if !VerifyCheckpoint(checkpoint) {
+		t.Fatal("VerifyCheckpoint rejected a valid checkpoint")
+	}
+
+	_, forgerKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	tests := map[string]func(c *dto.Checkpoint){
+		"last transaction": func(c *dto.Checkpoint) { c.LastTxID-- },
+		"count":            func(c *dto.Checkpoint) { c.TxCount-- },
+		"head hash":        func(c *dto.Checkpoint) { c.HeadHash = "00" },
+		"created at":       func(c *dto.Checkpoint) { c.CreatedAt = c.CreatedAt.Add(time.Second) },
+		"forged signature": func(c *dto.Checkpoint) { c.Signature = SignCheckpoint(forgerKey, *c) },
+		"bad signature":    func(c *dto.Checkpoint) { c.Signature = "zz" },
+		"bad public key":   func(c *dto.Checkpoint) { c.PublicKey = c.PublicKey[:62] },
+	}
+	for name, change := range tests {
+		t.Run(name, func(t *testing.T) {
+			forged := checkpoint
+			change(&forged)
+			if VerifyCheckpoint(forged) {
+				t.Error("VerifyCheckpoint accepted an altered checkpoint")
+			}
+		})
+	}
+}
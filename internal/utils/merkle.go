@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/shopspring/decimal"
+)
+
+// Префиксы хешей дерева Меркла. Разные префиксы листьев и внутренних узлов исключают
+// подмену листа внутренним узлом (second preimage attack).
+const (
+	merkleLeafPrefix = 0x00
+	merkleNodePrefix = 0x01
+)
+
+// Положение соседнего узла в шаге доказательства включения.
+const (
+	MerkleLeft  = "left"
+	MerkleRight = "right"
+)
+
+// MerkleLeafHash вычисляет хеш листа дерева Меркла для пары (адрес, баланс):
+// SHA-256(0x00 || "address=<адрес в нижнем регистре>\nbalance=<баланс в нормализованной записи>").
+//
+// Возвращает:
+//   - хеш листа в hex.
+func MerkleLeafHash(address string, balance decimal.Decimal) string {
+	payload := "address=" + strings.ToLower(address) + "\nbalance=" + balance.String()
+	sum := sha256.Sum256(append([]byte{merkleLeafPrefix}, payload...))
+	return hex.EncodeToString(sum[:])
+}
+
+// merkleNodeHash вычисляет хеш внутреннего узла: SHA-256(0x01 || left || right), где left и right —
+// хеши дочерних узлов в двоичном виде.
+func merkleNodeHash(left, right string) string {
+	l, _ := hex.DecodeString(left)
+	r, _ := hex.DecodeString(right)
+	data := make([]byte, 0, 1+len(l)+len(r))
+	data = append(append(append(data, merkleNodePrefix), l...), r...)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// MerkleTree — дерево Меркла, хранящее все уровни хешей от листьев до корня.
+//
+// Узлы уровня объединяются попарно слева направо; узел без пары переносится
+// на следующий уровень без изменений.
+type MerkleTree struct {
+	levels [][]string
+}
+
+// NewMerkleTree строит дерево Меркла по хешам листьев.
+//
+// Аргументы:
+//   - leaves: хеши листьев в hex в порядке их следования в дереве.
+//
+// Возвращает:
+//   - указатель на MerkleTree.
+func NewMerkleTree(leaves []string) *MerkleTree {
+	levels := [][]string{leaves}
+	for level := leaves; len(level) > 1; {
+		next := make([]string, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, merkleNodeHash(level[i], level[i+1]))
+		}
+		levels = append(levels, next)
+		level = next
+	}
+	return &MerkleTree{levels: levels}
+}
+
+// Root возвращает корень дерева в hex. Для пустого дерева — хеш SHA-256 пустой строки.
+func (t *MerkleTree) Root() string {
+	top := t.levels[len(t.levels)-1]
+	if len(top) == 0 {
+		sum := sha256.Sum256(nil)
+		return hex.EncodeToString(sum[:])
+	}
+	return top[0]
+}
+
+// Proof возвращает доказательство включения листа с указанным индексом:
+// хеши соседних узлов от листа к корню и их положение относительно текущего узла.
+//
+// Уровни, на которых у узла нет пары, в доказательство не попадают.
+func (t *MerkleTree) Proof(index int) []dto.MerkleProofStep {
+	var proof []dto.MerkleProofStep
+	for _, level := range t.levels[:len(t.levels)-1] {
+		switch {
+		case index%2 == 1:
+			proof = append(proof, dto.MerkleProofStep{Hash: level[index-1], Position: MerkleLeft})
+		case index+1 < len(level):
+			proof = append(proof, dto.MerkleProofStep{Hash: level[index+1], Position: MerkleRight})
+		}
+		index /= 2
+	}
+	return proof
+}
+
+// VerifyMerkleProof проверяет доказательство включения листа в дерево с указанным корнем.
+//
+// Аргументы:
+//   - leaf: хеш листа в hex (см. MerkleLeafHash).
+//   - proof: шаги доказательства от листа к корню.
+//   - root: ожидаемый корень дерева в hex.
+//
+// Возвращает:
+//   - true, если последовательное объединение листа с узлами доказательства даёт корень.
+func VerifyMerkleProof(leaf string, proof []dto.MerkleProofStep, root string) bool {
+	hash := leaf
+	for _, step := range proof {
+		switch step.Position {
+		case MerkleLeft:
+			hash = merkleNodeHash(step.Hash, hash)
+		case MerkleRight:
+			hash = merkleNodeHash(hash, step.Hash)
+		default:
+			return false
+		}
+	}
+	return hash == root
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/shopspring/decimal"
+)
+
+// testLeaves возвращает адреса count кошельков, их балансы и хеши листьев.
+func testLeaves(count int) ([]string, []decimal.Decimal, []string) {
+	addresses := make([]string, count)
+	balances := make([]decimal.Decimal, count)
+	leaves := make([]string, count)
+	for i := range count {
+		addresses[i], _ = testWallet(i)
+		balances[i] = decimal.NewFromInt(int64(100 * (i + 1)))
+		leaves[i] = MerkleLeafHash(addresses[i], balances[i])
+	}
+	return addresses, balances, leaves
+}
+
+func TestMerkleTreeEmpty(t *testing.T) {
+	sum := sha256.Sum256(nil)
+	if root := NewMerkleTree(nil).Root(); root != hex.EncodeToString(sum[:]) {
+		t.Errorf("empty tree root = %s, want SHA-256 of empty input", root)
+	}
+}
+
+func TestMerkleTreeSingleLeaf(t *testing.T) {
+	_, _, leaves := testLeaves(1)
+	tree := NewMerkleTree(leaves)
+
+	if tree.Root() != leaves[0] {
+		t.Errorf("root = %s, want the leaf itself", tree.Root())
+	}
+	proof := tree.Proof(0)
+	if len(proof) != 0 {
+		t.Errorf("proof has %d steps, want none", len(proof))
+	}
+	if !VerifyMerkleProof(leaves[0], proof, tree.Root()) {
+		t.Error("VerifyMerkleProof rejected the only leaf")
+	}
+}
+
+func TestMerkleTreePromotesUnpairedNode(t *testing.T) {
+	_, _, leaves := testLeaves(5)
+	a, b, c, d, e := leaves[0], leaves[1], leaves[2], leaves[3], leaves[4]
+
+	if root, want := NewMerkleTree(leaves[:3]).Root(), merkleNodeHash(merkleNodeHash(a, b), c); root != want {
+		t.Errorf("3 leaves: root = %s, want %s", root, want)
+	}
+	want := merkleNodeHash(merkleNodeHash(merkleNodeHash(a, b), merkleNodeHash(c, d)), e)
+	if root := NewMerkleTree(leaves).Root(); root != want {
+		t.Errorf("5 leaves: root = %s, want %s", root, want)
+	}
+
+	// Непарный лист получает доказательство только с уровня, на котором у него появляется пара
+	proof := NewMerkleTree(leaves).Proof(4)
+	if len(proof) != 1 || proof[0].Position != MerkleLeft {
+		t.Errorf("proof of unpaired leaf = %+v, want a single left step", proof)
+	}
+}
+
+func TestMerkleProofEveryLeaf(t *testing.T) {
+	for _, count := range []int{2, 3, 5, 6, 7, 8, 13} {
+		_, _, leaves := testLeaves(count)
+		tree := NewMerkleTree(leaves)
+		for i, leaf := range leaves {
+			if !VerifyMerkleProof(leaf, tree.Proof(i), tree.Root()) {
+				t.Errorf("%d leaves: proof of leaf %d rejected", count, i)
+			}
+		}
+	}
+}
+
+func TestMerkleProofRejectsWrongData(t *testing.T) {
+	addresses, balances, leaves := testLeaves(7)
+	tree := NewMerkleTree(leaves)
+	root := tree.Root()
+	proof := tree.Proof(2)
+
+	wrongBalance := MerkleLeafHash(addresses[2], balances[2].Add(decimal.NewFromInt(1)))
+	if VerifyMerkleProof(wrongBalance, proof, root) {
+		t.Error("proof accepted for a wrong balance")
+	}
+	if VerifyMerkleProof(leaves[3], proof, root) {
+		t.Error("proof accepted for another leaf")
+	}
+
+	// Адрес нечувствителен к регистру, а баланс — к форме записи
+	sameLeaf := MerkleLeafHash(strings.ToUpper(addresses[2]), balances[2].Mul(decimal.RequireFromString("1.00")))
+	if !VerifyMerkleProof(sameLeaf, proof, root) {
+		t.Error("proof rejected for the same address and balance in another notation")
+	}
+
+	swapped := append([]dto.MerkleProofStep(nil), proof...)
+	if swapped[0].Position == MerkleLeft {
+		swapped[0].Position = MerkleRight
+	} else {
+		swapped[0].Position = MerkleLeft
+	}
+	if VerifyMerkleProof(leaves[2], swapped, root) {
+		t.Error("proof accepted with a swapped sibling position")
+	}
+
+	unknown := append([]dto.MerkleProofStep(nil), proof...)
+	unknown[0].Position = "up"
+	if VerifyMerkleProof(leaves[2], unknown, root) {
+		t.Error("proof accepted with an unknown sibling position")
+	}
+
+	if VerifyMerkleProof(leaves[2], proof[:len(proof)-1], root) {
+		t.Error("truncated proof accepted")
+	}
+	if VerifyMerkleProof(leaves[2], proof, NewMerkleTree(leaves[:6]).Root()) {
+		t.Error("proof accepted against another root")
+	}
+}
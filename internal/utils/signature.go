@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// transferPayloadPrefix — домен подписи переводов. Исключает повторное использование
+// подписи переводов в других протоколах.
+const transferPayloadPrefix = "infotecs-transfer-v1"
+
+// walletSeedPrefix — домен детерминированного вывода ключей кошельков (см. DeriveWalletKey).
+const walletSeedPrefix = "infotecs-wallet-seed-v1"
+
+// ErrInvalidPrivateKey — закрытый ключ кошелька имеет неверный формат.
+var ErrInvalidPrivateKey = errors.New("private key must be 32 bytes (64 hex characters) of ed25519 seed")
+
+// GenerateWalletKey генерирует ключевую пару Ed25519 для нового кошелька.
+//
+// Адрес кошелька — hex-представление открытого ключа (32 байта, 64 символа),
+// поэтому списывать средства может только владелец соответствующего закрытого ключа.
+//
+// Возвращает:
+//   - адрес кошелька;
+//   - закрытый ключ в виде hex-представления seed (32 байта);
+//   - ошибку, если не удалось сгенерировать ключ.
+func GenerateWalletKey() (string, string, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	return hex.EncodeToString(publicKey), hex.EncodeToString(privateKey.Seed()), nil
+}
+
+// DeriveWalletKey детерминированно выводит ключевую пару кошелька с номером index из строки seed.
+//
+// Seed Ed25519 кошелька — SHA-256 от домена, строки seed и номера, поэтому одна и та же строка
+// всегда даёт одни и те же кошельки. Такие ключи предназначены только для воспроизводимых тестов:
+// любой, кто знает строку seed, может подписывать переводы с этих кошельков.
+//
+// Возвращает:
+//   - адрес кошелька;
+//   - закрытый ключ в виде hex-представления seed (32 байта).
+func DeriveWalletKey(seed string, index int) (string, string) {
+	sum := sha256.Sum256([]byte(walletSeedPrefix + "\n" + seed + "\n" + strconv.Itoa(index)))
+	publicKey := ed25519.NewKeyFromSeed(sum[:]).Public().(ed25519.PublicKey)
+	return hex.EncodeToString(publicKey), hex.EncodeToString(sum[:])
+}
+
+// WalletAddress возвращает адрес кошелька, соответствующий закрытому ключу.
+//
+// Аргументы:
+//   - privateKey: закрытый ключ в виде hex-представления seed.
+//
+// Возвращает:
+//   - адрес кошелька (hex-представление открытого ключа);
+//   - ErrInvalidPrivateKey, если ключ имеет неверный формат.
+func WalletAddress(privateKey string) (string, error) {
+	seed, err := hex.DecodeString(privateKey)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return "", ErrInvalidPrivateKey
+	}
+	return hex.EncodeToString(ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)), nil
+}
+
+// ValidAddress проверяет, что адрес кошелька — hex-представление открытого ключа Ed25519.
+func ValidAddress(address string) bool {
+	publicKey, err := hex.DecodeString(address)
+	return err == nil && len(publicKey) == ed25519.PublicKeySize
+}
+
+// TransferPayload возвращает каноническое представление перевода, которое подписывает отправитель.
+//
+// Формат (строки разделены символом \n):
+//
+//	infotecs-transfer-v1
+//	from=<адрес отправителя в нижнем регистре>
+//	to=<адрес получателя в нижнем регистре>
+//	amount=<сумма в нормализованной десятичной записи, например 3.5>
+//	nonce=<nonce>
+func TransferPayload(from, to string, amount decimal.Decimal, nonce uint64) []byte {
+	return []byte(strings.Join([]string{
+		transferPayloadPrefix,
+		"from=" + strings.ToLower(from),
+		"to=" + strings.ToLower(to),
+		"amount=" + amount.String(),
+		"nonce=" + strconv.FormatUint(nonce, 10),
+	}, "\n"))
+}
+
+// SignTransfer подписывает перевод закрытым ключом кошелька отправителя.
+//
+// Аргументы:
+//   - privateKey: закрытый ключ в виде hex-представления seed.
+//   - from, to, amount, nonce: параметры перевода.
+//
+// Возвращает:
+//   - подпись в hex-представлении (64 байта, 128 символов);
+//   - ErrInvalidPrivateKey, если ключ имеет неверный формат.
+func SignTransfer(privateKey, from, to string, amount decimal.Decimal, nonce uint64) (string, error) {
+	seed, err := hex.DecodeString(privateKey)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return "", ErrInvalidPrivateKey
+	}
+
+	signature := ed25519.Sign(ed25519.NewKeyFromSeed(seed), TransferPayload(from, to, amount, nonce))
+	return hex.EncodeToString(signature), nil
+}
+
+// VerifyTransfer проверяет подпись перевода открытым ключом, из которого получен адрес отправителя.
+//
+// Возвращает:
+//   - true, если подпись корректна; false, если адрес или подпись имеют неверный формат
+//     либо подпись не соответствует переводу.
+func VerifyTransfer(from, to string, amount decimal.Decimal, nonce uint64, signature string) bool {
+	publicKey, err := hex.DecodeString(from)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return false
+	}
+
+	sig, err := hex.DecodeString(signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+
+	return ed25519.Verify(publicKey, TransferPayload(from, to, amount, nonce), sig)
+}
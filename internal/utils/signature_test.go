@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// testWallet возвращает детерминированную ключевую пару кошелька для проверок.
+func testWallet(index int) (string, string) {
+	return DeriveWalletKey("signature-test", index)
+}
+
+func TestTransferPayloadFormat(t *testing.T) {
+	payload := TransferPayload("ABCDEF", "012345", decimal.RequireFromString("3.50"), 7)
+
+	want := "infotecs-transfer-v1\nfrom=abcdef\nto=012345\namount=3.5\nnonce=7"
+	if string(payload) != want {
+		t.Errorf("payload = %q, want %q", payload, want)
+	}
+}
+
+func TestSignTransferRoundTrip(t *testing.T) {
+	from, key := testWallet(0)
+	to, _ := testWallet(1)
+	amount := decimal.RequireFromString("12.5")
+
+	address, err := WalletAddress(key)
+	if err != nil {
+		t.Fatalf("WalletAddress: %v", err)
+	}
+	if address != from {
+		t.Fatalf("WalletAddress = %s, want %s", address, from)
+	}
+
+	signature, err := SignTransfer(key, from, to, amount, 1)
+	if err != nil {
+		t.Fatalf("SignTransfer: %v", err)
+	}
+	if len(signature) != 128 {
+		t.Errorf("signature has %d hex characters, want 128", len(signature))
+	}
+	if !VerifyTransfer(from, to, amount, 1, signature) {
+		t.Error("VerifyTransfer rejected a valid signature")
+	}
+
+	// Нормализованная запись суммы не зависит от формы, в которой она получена
+	if !VerifyTransfer(from, to, decimal.RequireFromString("12.50"), 1, signature) {
+		t.Error("VerifyTransfer rejected the same amount with a trailing zero")
+	}
+}
+
+func TestVerifyTransferRejectsTampering(t *testing.T) {
+	from, key := testWallet(0)
+	to, _ := testWallet(1)
+	other, _ := testWallet(2)
+	amount := decimal.NewFromInt(10)
+
+	signature, err := SignTransfer(key, from, to, amount, 5)
+	if err != nil {
+		t.Fatalf("SignTransfer: %v", err)
+	}
+	flipped := []byte(signature)
+	flipped[0] ^= 1
+
+	tests := []struct {
+		name      string
+		from, to  string
+		amount    decimal.Decimal
+		nonce     uint64
+		signature string
+	}{
+		{"amount", from, to, decimal.RequireFromString("10.01"), 5, signature},
+		{"recipient", from, other, amount, 5, signature},
+		{"sender", other, to, amount, 5, signature},
+		{"nonce", from, to, amount, 6, signature},
+		{"signature", from, to, amount, 5, string(flipped)},
+		{"truncated signature", from, to, amount, 5, signature[:126]},
+		{"non-hex signature", from, to, amount, 5, strings.Repeat("z", 128)},
+		{"malformed sender", "not-an-address", to, amount, 5, signature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if VerifyTransfer(tt.from, tt.to, tt.amount, tt.nonce, tt.signature) {
+				t.Error("VerifyTransfer accepted a tampered transfer")
+			}
+		})
+	}
+}
+
+func TestVerifyTransferFoldsAddressCase(t *testing.T) {
+	from, key := testWallet(0)
+	to, _ := testWallet(1)
+	amount := decimal.NewFromInt(1)
+
+	signature, err := SignTransfer(key, strings.ToUpper(from), to, amount, 1)
+	if err != nil {
+		t.Fatalf("SignTransfer: %v", err)
+	}
+	if !VerifyTransfer(from, strings.ToUpper(to), amount, 1, signature) {
+		t.Error("signature depends on the case of the addresses")
+	}
+	if !VerifyTransfer(strings.ToUpper(from), to, amount, 1, strings.ToUpper(signature)) {
+		t.Error("upper-case sender address or signature rejected")
+	}
+}
+
+func TestSignTransferRejectsBadKey(t *testing.T) {
+	from, key := testWallet(0)
+	to, _ := testWallet(1)
+
+	for name, bad := range map[string]string{
+		"empty":     "",
+		"not hex":   strings.Repeat("z", 64),
+		"too short": key[:62],
+		"too long":  key + "00",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := SignTransfer(bad, from, to, decimal.NewFromInt(1), 1); !errors.Is(err, ErrInvalidPrivateKey) {
+				t.Errorf("SignTransfer error = %v, want ErrInvalidPrivateKey", err)
+			}
+			if _, err := WalletAddress(bad); !errors.Is(err, ErrInvalidPrivateKey) {
+				t.Errorf("WalletAddress error = %v, want ErrInvalidPrivateKey", err)
+			}
+		})
+	}
+
+	// Подпись ключом другого кошелька не проходит проверку адресом отправителя
+	_, otherKey := testWallet(2)
+	signature, err := SignTransfer(otherKey, from, to, decimal.NewFromInt(1), 1)
+	if err != nil {
+		t.Fatalf("SignTransfer: %v", err)
+	}
+	if VerifyTransfer(from, to, decimal.NewFromInt(1), 1, signature) {
+		t.Error("VerifyTransfer accepted a signature made with another wallet's key")
+	}
+}
@@ -0,0 +1,17 @@
+CREATE TABLE api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT NOT NULL,
+    all_wallets INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    revoked_at DATETIME
+);
+
+CREATE TABLE api_key_wallets (
+    api_key_id INTEGER NOT NULL,
+    address TEXT NOT NULL,
+    PRIMARY KEY (api_key_id, address),
+    FOREIGN KEY (api_key_id) REFERENCES api_keys(id),
+    FOREIGN KEY (address) REFERENCES wallets(address)
+);
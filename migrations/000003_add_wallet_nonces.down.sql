@@ -0,0 +1,4 @@
+ALTER TABLE transactions DROP COLUMN signature;
+ALTER TABLE transactions DROP COLUMN nonce;
+
+ALTER TABLE wallets DROP COLUMN nonce;
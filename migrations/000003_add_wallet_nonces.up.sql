@@ -0,0 +1,4 @@
+ALTER TABLE wallets ADD COLUMN nonce INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE transactions ADD COLUMN nonce INTEGER;
+ALTER TABLE transactions ADD COLUMN signature TEXT;
@@ -0,0 +1 @@
+ALTER TABLE transactions ADD COLUMN initiated_by TEXT;
@@ -0,0 +1,23 @@
+DROP TABLE admin_audit;
+
+ALTER TABLE wallets DROP COLUMN frozen;
+
+CREATE TABLE transactions_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    from_address TEXT NOT NULL,
+    to_address TEXT NOT NULL,
+    amount TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    nonce INTEGER,
+    signature TEXT,
+    initiated_by TEXT,
+    FOREIGN KEY (from_address) REFERENCES wallets(address),
+    FOREIGN KEY (to_address) REFERENCES wallets(address)
+);
+
+INSERT INTO transactions_old (id, from_address, to_address, amount, created_at, nonce, signature, initiated_by)
+SELECT id, from_address, to_address, amount, created_at, nonce, signature, initiated_by FROM transactions
+WHERE kind = 'transfer';
+
+DROP TABLE transactions;
+ALTER TABLE transactions_old RENAME TO transactions;
@@ -0,0 +1,33 @@
+-- Административные операции (эмиссия и корректировка) записываются в журнал транзакций
+-- с одной стороной: у эмиссии нет отправителя, у списания корректировкой — получателя.
+CREATE TABLE transactions_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL DEFAULT 'transfer' CHECK (kind IN ('transfer', 'mint', 'adjustment')),
+    from_address TEXT,
+    to_address TEXT,
+    amount TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    nonce INTEGER,
+    signature TEXT,
+    initiated_by TEXT,
+    FOREIGN KEY (from_address) REFERENCES wallets(address),
+    FOREIGN KEY (to_address) REFERENCES wallets(address)
+);
+
+INSERT INTO transactions_new (id, kind, from_address, to_address, amount, created_at, nonce, signature, initiated_by)
+SELECT id, 'transfer', from_address, to_address, amount, created_at, nonce, signature, initiated_by FROM transactions;
+
+DROP TABLE transactions;
+ALTER TABLE transactions_new RENAME TO transactions;
+
+ALTER TABLE wallets ADD COLUMN frozen INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE admin_audit (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL,
+    target TEXT,
+    details TEXT,
+    reason TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,4 @@
+DROP TABLE ledger_checkpoints;
+
+ALTER TABLE transactions DROP COLUMN hash;
+ALTER TABLE transactions DROP COLUMN prev_hash;
@@ -0,0 +1,12 @@
+ALTER TABLE transactions ADD COLUMN prev_hash TEXT;
+ALTER TABLE transactions ADD COLUMN hash TEXT;
+
+CREATE TABLE ledger_checkpoints (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    last_tx_id INTEGER NOT NULL,
+    tx_count INTEGER NOT NULL,
+    head_hash TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    public_key TEXT NOT NULL,
+    signature TEXT NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP TABLE snapshot_balances;
+DROP TABLE balance_snapshots;
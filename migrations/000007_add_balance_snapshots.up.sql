@@ -0,0 +1,16 @@
+CREATE TABLE balance_snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    last_tx_id INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    wallet_count INTEGER NOT NULL,
+    total TEXT NOT NULL,
+    merkle_root TEXT NOT NULL
+);
+
+CREATE TABLE snapshot_balances (
+    snapshot_id INTEGER NOT NULL,
+    address TEXT NOT NULL,
+    balance TEXT NOT NULL,
+    PRIMARY KEY (snapshot_id, address),
+    FOREIGN KEY (snapshot_id) REFERENCES balance_snapshots(id)
+);
@@ -0,0 +1,3 @@
+DROP INDEX idx_transactions_created_at;
+DROP INDEX idx_transactions_to_address;
+DROP INDEX idx_transactions_from_address;
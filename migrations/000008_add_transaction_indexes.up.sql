@@ -0,0 +1,3 @@
+CREATE INDEX idx_transactions_from_address ON transactions (from_address, id);
+CREATE INDEX idx_transactions_to_address ON transactions (to_address, id);
+CREATE INDEX idx_transactions_created_at ON transactions (created_at);
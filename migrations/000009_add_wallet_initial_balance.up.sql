@@ -0,0 +1,3 @@
+-- Начальный баланс, с которым кошелёк был создан. Для кошельков, созданных до миграции,
+-- его записывает миграция 000012; кошельки с NULL сверка перечисляет как непроверяемые.
+ALTER TABLE wallets ADD COLUMN initial_balance TEXT;
@@ -0,0 +1,3 @@
+DROP TABLE counterparty_stats;
+DROP TABLE daily_volumes;
+DROP TABLE projection_checkpoints;
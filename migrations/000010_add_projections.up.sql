@@ -0,0 +1,26 @@
+-- Контрольные точки проекций журнала транзакций: последняя запись журнала, применённая к проекции.
+CREATE TABLE projection_checkpoints (
+    name TEXT PRIMARY KEY,
+    last_tx_id INTEGER NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+-- Проекция daily_volumes: количество и сумма записей журнала за сутки (UTC) по видам записей.
+CREATE TABLE daily_volumes (
+    day TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    count INTEGER NOT NULL,
+    volume TEXT NOT NULL,
+    PRIMARY KEY (day, kind)
+);
+
+-- Проекция counterparty_stats: переводы кошелька каждому контрагенту и от него.
+CREATE TABLE counterparty_stats (
+    address TEXT NOT NULL,
+    counterparty TEXT NOT NULL,
+    sent_count INTEGER NOT NULL,
+    sent_volume TEXT NOT NULL,
+    received_count INTEGER NOT NULL,
+    received_volume TEXT NOT NULL,
+    PRIMARY KEY (address, counterparty)
+);
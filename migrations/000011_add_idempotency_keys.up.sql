@@ -0,0 +1,15 @@
+-- Ключи идемпотентности запросов: ответ на первый запрос с ключом повторяется на повторные запросы с ним.
+-- Пока запрос обрабатывается, status_code равен NULL.
+CREATE TABLE idempotency_keys (
+    subject TEXT NOT NULL,
+    key TEXT NOT NULL,
+    operation TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status_code INTEGER,
+    content_type TEXT,
+    body BLOB,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (subject, key)
+);
+
+CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys (created_at);
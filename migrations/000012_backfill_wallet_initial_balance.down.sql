@@ -0,0 +1,2 @@
+-- Заполненные начальные балансы неотличимы от остальных и не сбрасываются.
+SELECT 1;
@@ -0,0 +1,4 @@
+-- Кошельки, созданные до миграции 000009 при первом запуске сервера, получали баланс 100.
+-- Он принимается начальным балансом всех кошельков без него; если начальный баланс был другим
+-- (например, кошелёк создан повторной генерацией с нулевым балансом), сверка сообщит о расхождении.
+UPDATE wallets SET initial_balance = '100' WHERE initial_balance IS NULL;
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Методы административного API (/admin) требуют области доступа admin.
+// Каждое действие требует основания (поле Reason) и записывается в журнал аудита.
+
+// Mint выполняет эмиссию средств на кошелёк (POST /admin/mint).
+func (c *Client) Mint(ctx context.Context, req MintReq) (BalanceResp, error) {
+	var resp BalanceResp
+	err := c.do(ctx, http.MethodPost, "/admin/mint", nil, req, &resp)
+	return resp, err
+}
+
+// Adjust корректирует баланс кошелька на сумму со знаком (POST /admin/adjust).
+func (c *Client) Adjust(ctx context.Context, req AdjustReq) (BalanceResp, error) {
+	var resp BalanceResp
+	err := c.do(ctx, http.MethodPost, "/admin/adjust", nil, req, &resp)
+	return resp, err
+}
+
+// SetFrozen замораживает (req.Frozen = true) или размораживает кошелёк
+// (POST /admin/wallets/{address}/freeze|unfreeze).
+func (c *Client) SetFrozen(ctx context.Context, req FreezeReq) (WalletStatusResp, error) {
+	action := "unfreeze"
+	if req.Frozen {
+		action = "freeze"
+	}
+
+	var resp WalletStatusResp
+	err := c.do(ctx, http.MethodPost, "/admin/wallets/"+url.PathEscape(req.Address)+"/"+action, nil, req, &resp)
+	return resp, err
+}
+
+// GenerateWallets создаёт новые кошельки и возвращает их ключевые пары (POST /admin/wallets/generate).
+// Сервер не хранит закрытые ключи: их нужно сохранить до обработки ответа.
+func (c *Client) GenerateWallets(ctx context.Context, req GenerateWalletsReq) ([]WalletKey, error) {
+	var resp []WalletKey
+	err := c.do(ctx, http.MethodPost, "/admin/wallets/generate", nil, req, &resp)
+	return resp, err
+}
+
+// GetAuditLog возвращает последние n записей журнала административных действий (GET /admin/audit).
+func (c *Client) GetAuditLog(ctx context.Context, n int) ([]AuditEntry, error) {
+	var resp []AuditEntry
+	err := c.do(ctx, http.MethodGet, "/admin/audit", countQuery(n), nil, &resp)
+	return resp, err
+}
+
+// Reconcile сверяет балансы кошельков с журналом транзакций и, если req.Repair = true,
+// исправляет расхождения (POST /admin/reconcile).
+func (c *Client) Reconcile(ctx context.Context, req ReconcileReq) (ReconcileReport, error) {
+	var resp ReconcileReport
+	err := c.do(ctx, http.MethodPost, "/admin/reconcile", nil, req, &resp)
+	return resp, err
+}
+
+// GetProjections возвращает состояние проекций журнала транзакций (GET /admin/projections).
+func (c *Client) GetProjections(ctx context.Context) ([]ProjectionStatus, error) {
+	var resp []ProjectionStatus
+	err := c.do(ctx, http.MethodGet, "/admin/projections", nil, nil, &resp)
+	return resp, err
+}
+
+// RebuildProjection перестраивает проекцию с нуля (POST /admin/projections/{name}/rebuild).
+func (c *Client) RebuildProjection(ctx context.Context, req ProjectionRebuildReq) (ProjectionRebuildResp, error) {
+	var resp ProjectionRebuildResp
+	err := c.do(ctx, http.MethodPost, "/admin/projections/"+url.PathEscape(req.Name)+"/rebuild", nil, req, &resp)
+	return resp, err
+}
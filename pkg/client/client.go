@@ -0,0 +1,218 @@
+// Package client реализует клиент HTTP API системы транзакций.
+//
+// Методы клиента повторяют методы сервисов сервера (services.TransferInteractor и др.) и принимают
+// и возвращают те же DTO (см. псевдонимы типов в types.go), поэтому код, работающий с сервисом
+// напрямую, переносится на API без изменений.
+//
+// Запросы, завершившиеся временной ошибкой, повторяются по политике RetryPolicy. Повтор безопасен
+// и для операций, меняющих состояние: каждый запрос POST отправляется с ключом идемпотентности,
+// и сервер выполняет операцию не более одного раза (см. WithIdempotencyKey).
+//
+// Ответ сервера с ошибкой возвращается как *Error с машинным кодом ошибки, который можно
+// проверить через errors.Is:
+//
+//	err := api.Send(ctx, transaction)
+//	switch {
+//	case errors.Is(err, client.ErrInsufficientFunds):
+//		// недостаточно средств
+//	case errors.Is(err, client.ErrInvalidNonce):
+//		// nonce уже использован: запросить новый и подписать перевод заново
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultTimeout ограничивает время запроса, если клиент создан без собственного http.Client.
+const defaultTimeout = 30 * time.Second
+
+// idempotencyKeyHeader — заголовок, в котором передаётся ключ идемпотентности запроса.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// Client — клиент HTTP API. Безопасен для одновременного использования из нескольких горутин.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	apiKey     string
+	token      string
+	userAgent  string
+	retry      RetryPolicy
+}
+
+// Option настраивает Client при создании.
+type Option func(*Client)
+
+// WithAPIKey задаёт API-ключ, передаваемый в заголовке X-API-Key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithBearerToken задаёт JWT внутреннего SSO, передаваемый в заголовке Authorization.
+func WithBearerToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithHTTPClient задаёт http.Client, например с настройками TLS и клиентским сертификатом (mTLS).
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithUserAgent задаёт заголовок User-Agent запросов.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// New создаёт клиент API.
+//
+// Аргументы:
+//   - baseURL: адрес сервера, например "https://transactions.example.com".
+//   - options: параметры клиента: учётные данные, http.Client, политика повторов.
+//
+// Возвращает:
+//   - указатель на Client.
+//   - ошибку, если адрес сервера некорректен.
+func New(baseURL string, options ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid server url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid server url %q: scheme must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		userAgent:  "transactions-client",
+		retry:      DefaultRetryPolicy,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	if c.retry.MaxAttempts < 1 {
+		c.retry.MaxAttempts = 1
+	}
+	return c, nil
+}
+
+// decodeError — ошибка разбора успешного ответа сервера. Такой запрос не повторяется:
+// операция уже выполнена.
+type decodeError struct {
+	method, path string
+	err          error
+}
+
+// Error возвращает описание ошибки разбора ответа.
+func (e *decodeError) Error() string {
+	return fmt.Sprintf("failed to decode response of %s %s: %v", e.method, e.path, e.err)
+}
+
+// Unwrap возвращает исходную ошибку разбора.
+func (e *decodeError) Unwrap() error {
+	return e.err
+}
+
+// do выполняет запрос к API, повторяя его при временных ошибках по политике c.retry.
+//
+// Запрос POST отправляется с ключом идемпотентности из контекста (см. WithIdempotencyKey)
+// или со случайным ключом, общим для всех попыток.
+//
+// Аргументы:
+//   - ctx: контекст запроса; его отмена прерывает и запрос, и ожидание перед повтором.
+//   - method, path, query: метод, путь и параметры запроса.
+//   - body: тело запроса, кодируемое в JSON; nil — без тела.
+//   - out: получатель ответа: io.Writer для копирования тела как есть, иначе получатель JSON;
+//     nil — ответ не разбирается.
+//
+// Возвращает:
+//   - *Error, если сервер ответил кодом 4xx или 5xx; иную ошибку при сбое соединения или разбора ответа.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+	key := ""
+	if method == http.MethodPost {
+		key = idempotencyKey(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := c.attempt(ctx, method, path, query, payload, key, out)
+		if err == nil || attempt >= c.retry.MaxAttempts || !retryable(ctx, err) {
+			return err
+		}
+		wait, ok := c.retry.backoff(attempt, err)
+		if !ok {
+			return err
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// attempt выполняет одну попытку запроса к API (см. do).
+func (c *Client) attempt(
+	ctx context.Context, method, path string, query url.Values, payload []byte, key string, out any,
+) error {
+	u := c.baseURL.JoinPath(path)
+	u.RawQuery = query.Encode()
+
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if key != "" {
+		req.Header.Set(idempotencyKeyHeader, key)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	switch {
+	case c.apiKey != "":
+		req.Header.Set("X-API-Key", c.apiKey)
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newError(resp)
+	}
+	switch out := out.(type) {
+	case nil:
+		_, _ = io.Copy(io.Discard, resp.Body)
+	case io.Writer:
+		if _, err := io.Copy(out, resp.Body); err != nil {
+			return &decodeError{method: method, path: path, err: err}
+		}
+	default:
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return &decodeError{method: method, path: path, err: err}
+		}
+	}
+	return nil
+}
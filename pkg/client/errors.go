@@ -0,0 +1,121 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrorBody ограничивает размер тела ответа с ошибкой, читаемого клиентом.
+const maxErrorBody = 64 << 10
+
+// codeError — ошибка с машинным кодом ошибки сервера. Ответ сервера *Error с этим кодом
+// соответствует ей в errors.Is.
+type codeError string
+
+// Error возвращает машинный код ошибки.
+func (e codeError) Error() string {
+	return string(e)
+}
+
+// Ошибки, соответствующие машинным кодам ошибок сервера:
+//
+//	if errors.Is(err, client.ErrInsufficientFunds) { ... }
+var (
+	ErrInvalidRequest       error = codeError("invalid_request")
+	ErrValidationFailed     error = codeError("validation_failed")
+	ErrRequestTooLarge      error = codeError("request_too_large")
+	ErrUnauthenticated      error = codeError("unauthenticated")
+	ErrForbidden            error = codeError("forbidden")
+	ErrWalletForbidden      error = codeError("wallet_forbidden")
+	ErrSameWallet           error = codeError("same_wallet")
+	ErrInsufficientFunds    error = codeError("insufficient_funds")
+	ErrInvalidSignature     error = codeError("invalid_signature")
+	ErrInvalidNonce         error = codeError("invalid_nonce")
+	ErrWalletFrozen         error = codeError("wallet_frozen")
+	ErrWalletNotFound       error = codeError("wallet_not_found")
+	ErrTransactionsNotFound error = codeError("transactions_not_found")
+	ErrSnapshotNotFound     error = codeError("snapshot_not_found")
+	ErrProjectionNotFound   error = codeError("projection_not_found")
+	ErrNotFound             error = codeError("not_found")
+	ErrRateLimited          error = codeError("rate_limited")
+	ErrIdempotencyKeyReused error = codeError("idempotency_key_reused")
+	ErrIdempotencyKeyInUse  error = codeError("idempotency_key_in_use")
+	ErrTimeout              error = codeError("timeout")
+	ErrInternal             error = codeError("internal_error")
+)
+
+// FieldError — нарушение спецификации API в отдельном поле запроса.
+type FieldError struct {
+	In      string `json:"in"`      // Расположение поля: path, query, header или body
+	Field   string `json:"field"`   // Имя поля
+	Message string `json:"message"` // Описание нарушения
+}
+
+// Error — ответ сервера с ошибкой в формате application/problem+json (RFC 7807).
+type Error struct {
+	Status     int           `json:"status"`               // HTTP-статус ответа
+	Code       string        `json:"code"`                 // Стабильный машинный код ошибки, например "insufficient_funds"
+	Title      string        `json:"title"`                // Краткое описание типа проблемы
+	Detail     string        `json:"detail,omitempty"`     // Описание конкретного случая
+	RequestID  string        `json:"request_id,omitempty"` // Идентификатор запроса для поиска в журнале сервера
+	Errors     []FieldError  `json:"errors,omitempty"`     // Ошибки валидации отдельных полей
+	RetryAfter time.Duration `json:"-"`                    // Пауза перед повтором из заголовка Retry-After
+}
+
+// Error возвращает описание ошибки с кодом и деталями.
+func (e *Error) Error() string {
+	code := e.Code
+	if code == "" {
+		code = e.Title
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %s", e.Status, code)
+	if e.Detail != "" {
+		b.WriteString(": " + e.Detail)
+	}
+	for _, field := range e.Errors {
+		fmt.Fprintf(&b, "; %s %s: %s", field.In, field.Field, field.Message)
+	}
+	return b.String()
+}
+
+// Is сообщает, соответствует ли ошибка target — одной из ошибок Err* с машинным кодом сервера.
+func (e *Error) Is(target error) bool {
+	code, ok := target.(codeError)
+	return ok && e.Code != "" && string(code) == e.Code
+}
+
+// temporary сообщает, может ли повтор запроса завершиться успешно: сервер перегружен, недоступен
+// за балансировщиком, превышен лимит запросов или первый запрос с тем же ключом идемпотентности ещё обрабатывается.
+func (e *Error) temporary() bool {
+	switch e.Status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return e.Is(ErrIdempotencyKeyInUse)
+}
+
+// newError разбирает ответ сервера с ошибкой. Если тело не в формате problem+json
+// (например, ответ балансировщика), его текст становится описанием ошибки.
+func newError(resp *http.Response) *Error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+
+	apiErr := &Error{}
+	if err := json.Unmarshal(body, apiErr); err != nil || apiErr.Code == "" {
+		apiErr = &Error{Title: http.StatusText(resp.StatusCode), Detail: strings.TrimSpace(string(body))}
+	}
+	apiErr.Status = resp.StatusCode
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-Request-ID")
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return apiErr
+}
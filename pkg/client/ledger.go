@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// VerifyLedger проверяет целостность цепочки хешей журнала транзакций (GET /api/ledger/verify).
+func (c *Client) VerifyLedger(ctx context.Context) (LedgerVerifyResp, error) {
+	var resp LedgerVerifyResp
+	err := c.do(ctx, http.MethodGet, "/api/ledger/verify", nil, nil, &resp)
+	return resp, err
+}
+
+// GetCheckpoints возвращает последние n подписанных контрольных точек журнала и открытый ключ
+// для проверки их подписей (GET /api/ledger/checkpoints).
+func (c *Client) GetCheckpoints(ctx context.Context, n int) (CheckpointsResp, error) {
+	var resp CheckpointsResp
+	err := c.do(ctx, http.MethodGet, "/api/ledger/checkpoints", countQuery(n), nil, &resp)
+	return resp, err
+}
+
+// GetSnapshots возвращает последние n снимков балансов (GET /api/snapshots).
+func (c *Client) GetSnapshots(ctx context.Context, n int) ([]Snapshot, error) {
+	var resp []Snapshot
+	err := c.do(ctx, http.MethodGet, "/api/snapshots", countQuery(n), nil, &resp)
+	return resp, err
+}
+
+// GetProof возвращает доказательство включения баланса кошелька в снимок req.SnapshotID
+// или в последний снимок, если он не задан (GET /api/wallet/{address}/proof).
+func (c *Client) GetProof(ctx context.Context, req BalanceProofReq) (BalanceProofResp, error) {
+	query := url.Values{}
+	if req.SnapshotID != 0 {
+		query.Set("snapshot", strconv.FormatInt(req.SnapshotID, 10))
+	}
+
+	var resp BalanceProofResp
+	err := c.do(ctx, http.MethodGet, walletPath(req.Address, "proof"), query, nil, &resp)
+	return resp, err
+}
+
+// GetDailyVolumes возвращает обороты за дни периода по видам записей журнала (GET /api/stats/daily).
+func (c *Client) GetDailyVolumes(ctx context.Context, req DailyVolumesReq) ([]DailyVolume, error) {
+	var resp []DailyVolume
+	err := c.do(ctx, http.MethodGet, "/api/stats/daily", url.Values{"from": {req.From}, "to": {req.To}}, nil, &resp)
+	return resp, err
+}
+
+// GetCounterparties возвращает самых частых контрагентов кошелька
+// (GET /api/wallet/{address}/counterparties).
+func (c *Client) GetCounterparties(ctx context.Context, req CounterpartiesReq) ([]CounterpartyStats, error) {
+	var resp []CounterpartyStats
+	err := c.do(ctx, http.MethodGet, walletPath(req.Address, "counterparties"), countQuery(req.Count), nil, &resp)
+	return resp, err
+}
+
+// Version возвращает сведения о сборке сервера (GET /version).
+func (c *Client) Version(ctx context.Context) (VersionResp, error) {
+	var resp VersionResp
+	err := c.do(ctx, http.MethodGet, "/version", nil, nil, &resp)
+	return resp, err
+}
+
+// countQuery возвращает параметр запроса count.
+func countQuery(n int) url.Values {
+	return url.Values{"count": {strconv.Itoa(n)}}
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	cryptorand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy задаёт повтор запросов, завершившихся временной ошибкой: сбоем соединения,
+// ответом 502, 503, 504 или превышением лимита запросов (429).
+//
+// Повторять можно любой запрос: запросы чтения не меняют состояние, а каждый запрос POST
+// отправляется с ключом идемпотентности (заголовок Idempotency-Key), одинаковым во всех попытках,
+// поэтому сервер выполняет операцию не более одного раза.
+type RetryPolicy struct {
+	MaxAttempts int           // Максимальное количество попыток, включая первую; 1 — без повторов
+	MinBackoff  time.Duration // Пауза перед первым повтором; удваивается с каждой следующей попыткой
+	MaxBackoff  time.Duration // Максимальная пауза между попытками
+}
+
+// DefaultRetryPolicy — политика повторов по умолчанию: до четырёх попыток с паузами от 100 мс до 2 с.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 4, MinBackoff: 100 * time.Millisecond, MaxBackoff: 2 * time.Second}
+
+// NoRetries — политика без повторов запросов.
+var NoRetries = RetryPolicy{MaxAttempts: 1}
+
+// WithRetryPolicy задаёт политику повтора запросов, завершившихся временной ошибкой.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) { c.retry = policy }
+}
+
+// backoff возвращает паузу перед попыткой attempt (начиная с 1 для первого повтора).
+//
+// Пауза растёт экспоненциально и выбирается случайно в диапазоне [d/2, d], чтобы клиенты,
+// получившие ошибку одновременно, не повторяли запросы тоже одновременно. Если сервер указал
+// паузу в заголовке Retry-After, используется она, а если она больше MaxBackoff, запрос не повторяется:
+// ошибка возвращается вызывающему коду, который сам решает, ждать ли так долго.
+//
+// Возвращает:
+//   - паузу и признак того, что запрос следует повторить.
+func (p RetryPolicy) backoff(attempt int, err error) (time.Duration, bool) {
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter, apiErr.RetryAfter <= p.MaxBackoff
+	}
+
+	d := p.MinBackoff << (attempt - 1)
+	if d <= 0 || d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0, true
+	}
+	return d/2 + rand.N(d/2+1), true
+}
+
+// retryable сообщает, имеет ли смысл повторить запрос, завершившийся ошибкой err.
+// Ответы сервера повторяются только при временных ошибках; сбои соединения — всегда,
+// кроме отмены или истечения контекста запроса.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.temporary()
+	}
+	var decodeErr *decodeError
+	return !errors.As(err, &decodeErr)
+}
+
+// sleep ожидает d или отмены контекста.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// idempotencyKeyKey — ключ контекста для ключа идемпотентности, заданного вызывающим кодом.
+type idempotencyKeyKey struct{}
+
+// WithIdempotencyKey возвращает контекст, запрос POST с которым отправляется с указанным ключом
+// идемпотентности вместо случайного.
+//
+// Клиент сам повторяет запрос с одним ключом, поэтому задавать ключ нужно, только если повтор
+// выполняется за пределами клиента — например, после перезапуска процесса: тогда ключ сохраняется
+// вместе с намерением выполнить операцию, и повторный вызов с ним не выполнит операцию дважды.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+// idempotencyKey возвращает ключ идемпотентности из контекста или новый случайный ключ.
+func idempotencyKey(ctx context.Context) string {
+	if key, ok := ctx.Value(idempotencyKeyKey{}).(string); ok && key != "" {
+		return key
+	}
+	b := make([]byte, 16)
+	_, _ = cryptorand.Read(b)
+	return hex.EncodeToString(b)
+}
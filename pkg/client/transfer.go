@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+	"github.com/coffee-realist/infotecs_transaction_system/internal/utils"
+	"github.com/shopspring/decimal"
+)
+
+// Send выполняет подписанный перевод между кошельками (POST /api/send).
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//   - transaction: перевод с подписью отправителя (см. Transfer).
+//
+// Возвращает:
+//   - *Error при ответе сервера с ошибкой, иную ошибку при сбое соединения.
+func (c *Client) Send(ctx context.Context, transaction TransactionReq) error {
+	return c.do(ctx, http.MethodPost, "/api/send", nil, transaction, nil)
+}
+
+// Transfer подписывает перевод закрытым ключом отправителя со следующим nonce кошелька и выполняет его.
+//
+// Nonce запрашивается у сервера (GetNonce), поэтому одновременные переводы с одного кошелька
+// могут получить ошибку ErrInvalidNonce. Перевод, который нужно повторить после перезапуска
+// процесса, следует сохранить вместе с ключом идемпотентности и повторить через Send:
+// Transfer подписывает перевод заново, и с прежним ключом он будет отклонён (ErrIdempotencyKeyReused).
+//
+// Аргументы:
+//   - ctx: контекст запроса.
+//   - privateKey: закрытый ключ отправителя (seed Ed25519 в hex).
+//   - from, to: адреса отправителя и получателя.
+//   - amount: сумма перевода.
+//
+// Возвращает:
+//   - выполненный перевод с nonce и подписью.
+//   - ошибку подписи или выполнения перевода.
+func (c *Client) Transfer(ctx context.Context, privateKey, from, to string, amount decimal.Decimal) (TransactionReq, error) {
+	nonce, err := c.GetNonce(ctx, BalanceReq{Address: from})
+	if err != nil {
+		return TransactionReq{}, err
+	}
+
+	transaction := TransactionReq{From: from, To: to, Amount: amount, Nonce: nonce.Nonce + 1}
+	if transaction.Signature, err = utils.SignTransfer(privateKey, from, to, amount, transaction.Nonce); err != nil {
+		return TransactionReq{}, err
+	}
+	return transaction, c.Send(ctx, transaction)
+}
+
+// GetLastN возвращает последние n транзакций (GET /api/transactions).
+func (c *Client) GetLastN(ctx context.Context, n int) (TransactionsResp, error) {
+	var resp TransactionsResp
+	err := c.do(ctx, http.MethodGet, "/api/transactions", countQuery(n), nil, &resp)
+	return resp, err
+}
+
+// GetBalance возвращает текущий баланс кошелька (GET /api/wallet/{address}/balance).
+func (c *Client) GetBalance(ctx context.Context, req BalanceReq) (BalanceResp, error) {
+	var resp BalanceResp
+	err := c.do(ctx, http.MethodGet, walletPath(req.Address, "balance"), nil, nil, &resp)
+	return resp, err
+}
+
+// GetBalanceAt возвращает баланс кошелька на момент времени req.At или после транзакции req.AsOfTx
+// (GET /api/wallet/{address}/balance?at=...|as_of_tx=...).
+func (c *Client) GetBalanceAt(ctx context.Context, req BalanceAtReq) (BalanceResp, error) {
+	query := url.Values{}
+	if req.At != nil {
+		query.Set("at", req.At.UTC().Format(time.RFC3339))
+	}
+	if req.AsOfTx != nil {
+		query.Set("as_of_tx", strconv.FormatInt(*req.AsOfTx, 10))
+	}
+
+	var resp BalanceResp
+	err := c.do(ctx, http.MethodGet, walletPath(req.Address, "balance"), query, nil, &resp)
+	return resp, err
+}
+
+// GetStatement возвращает выписку по кошельку за период [req.From, req.To)
+// (GET /api/wallet/{address}/statement). Нулевой req.To означает текущий момент.
+func (c *Client) GetStatement(ctx context.Context, req StatementReq) (StatementResp, error) {
+	var resp StatementResp
+	err := c.do(ctx, http.MethodGet, walletPath(req.Address, "statement"),
+		statementQuery(req, dto.StatementFormatJSON), nil, &resp)
+	return resp, err
+}
+
+// WriteStatementCSV записывает в w выписку по кошельку за период [req.From, req.To) в формате CSV
+// по мере её получения от сервера (GET /api/wallet/{address}/statement?format=csv).
+// Нулевой req.To означает текущий момент.
+func (c *Client) WriteStatementCSV(ctx context.Context, req StatementReq, w io.Writer) error {
+	return c.do(ctx, http.MethodGet, walletPath(req.Address, "statement"),
+		statementQuery(req, dto.StatementFormatCSV), nil, w)
+}
+
+// GetNonce возвращает последний использованный nonce кошелька (GET /api/wallet/{address}/nonce).
+func (c *Client) GetNonce(ctx context.Context, req BalanceReq) (NonceResp, error) {
+	var resp NonceResp
+	err := c.do(ctx, http.MethodGet, walletPath(req.Address, "nonce"), nil, nil, &resp)
+	return resp, err
+}
+
+// walletPath возвращает путь ресурса кошелька /api/wallet/{address}/{resource}.
+func walletPath(address, resource string) string {
+	return "/api/wallet/" + url.PathEscape(address) + "/" + resource
+}
+
+// statementQuery возвращает параметры запроса выписки.
+func statementQuery(req StatementReq, format string) url.Values {
+	query := url.Values{"from": {req.From.UTC().Format(time.RFC3339)}, "format": {format}}
+	if !req.To.IsZero() {
+		query.Set("to", req.To.UTC().Format(time.RFC3339))
+	}
+	return query
+}
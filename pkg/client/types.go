@@ -0,0 +1,49 @@
+package client
+
+import "github.com/coffee-realist/infotecs_transaction_system/internal/dto"
+
+// Типы запросов и ответов API. Это псевдонимы DTO сервера: пакет internal/dto недоступен
+// за пределами модуля, а псевдонимы позволяют другим сервисам использовать те же типы.
+type (
+	TransactionReq   = dto.TransactionReq
+	TransactionResp  = dto.TransactionResp
+	TransactionsResp = dto.TransactionsResp
+	BalanceReq       = dto.BalanceReq
+	BalanceAtReq     = dto.BalanceAtReq
+	BalanceResp      = dto.BalanceResp
+	NonceResp        = dto.NonceResp
+	WalletKey        = dto.WalletKey
+
+	StatementReq     = dto.StatementReq
+	StatementResp    = dto.StatementResp
+	StatementEntry   = dto.StatementEntry
+	LedgerVerifyResp = dto.LedgerVerifyResp
+	LedgerHead       = dto.LedgerHead
+	LedgerBreak      = dto.LedgerBreak
+	Checkpoint       = dto.Checkpoint
+	CheckpointsResp  = dto.CheckpointsResp
+	Snapshot         = dto.Snapshot
+	BalanceProofReq  = dto.BalanceProofReq
+	BalanceProofResp = dto.BalanceProofResp
+	MerkleProofStep  = dto.MerkleProofStep
+
+	DailyVolumesReq   = dto.DailyVolumesReq
+	DailyVolume       = dto.DailyVolume
+	CounterpartiesReq = dto.CounterpartiesReq
+	CounterpartyStats = dto.CounterpartyStats
+
+	MintReq               = dto.MintReq
+	AdjustReq             = dto.AdjustReq
+	FreezeReq             = dto.FreezeReq
+	WalletStatusResp      = dto.WalletStatusResp
+	GenerateWalletsReq    = dto.GenerateWalletsReq
+	AuditEntry            = dto.AuditEntry
+	ReconcileReq          = dto.ReconcileReq
+	ReconcileReport       = dto.ReconcileReport
+	ReconcileMismatch     = dto.ReconcileMismatch
+	ProjectionStatus      = dto.ProjectionStatus
+	ProjectionRebuildReq  = dto.ProjectionRebuildReq
+	ProjectionRebuildResp = dto.ProjectionRebuildResp
+
+	VersionResp = dto.VersionResp
+)